@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/broadcast"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/ui"
+)
+
+// broadcastShutdownTimeout bounds closing viewer connections when the TUI exits
+const broadcastShutdownTimeout = 2 * time.Second
+
+// startBroadcast serves a live view of the app's runs on addr and returns a func that stops it
+func startBroadcast(app *ui.App, addr string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for --broadcast: %w", err)
+	}
+
+	hub := broadcast.NewHub()
+	app.SetRunListener(hub)
+	srv := &http.Server{Handler: hub.Handler()}
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			slog.Error("broadcast stopped", "error", err)
+		}
+	}()
+
+	// The alt screen hides this once the TUI starts, but it stays in the scrollback
+	fmt.Fprintf(os.Stderr, "Broadcasting on http://%s\n", listener.Addr())
+	slog.Info("broadcast started", "addr", listener.Addr().String())
+
+	return func() {
+		hub.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), broadcastShutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/mongodb"
+)
+
+// cleanCommand removes what txviewer keeps in Docker between runs: with --volumes, the MongoDB
+// data volumes created by --mongodb-volume
+func cleanCommand(args []string) int {
+	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
+	volumes := fs.Bool("volumes", false, "remove the MongoDB data volumes kept by --mongodb-volume")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if !*volumes {
+		fmt.Fprintln(os.Stderr, "usage: txviewer clean --volumes")
+		return exitUsage
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	removed, err := mongodb.RemoveDataVolumes(ctx)
+	for _, name := range removed {
+		fmt.Printf("Removed %s\n", name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	if len(removed) == 0 {
+		fmt.Println("No data volumes to remove")
+	}
+	return exitOK
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/ui"
+)
+
+// compareWidth is the width of the text comparison
+const compareWidth = 140
+
+// compareTarget is one side of a comparison: a provider and its scenario for the anomaly
+type compareTarget struct {
+	provider provider.Provider
+	scenario scenario.Scenario
+}
+
+// compareCommand runs the same anomaly scenario on two providers and renders their step logs side by side.
+// Each of --providers is a provider name, optionally followed by ":scenario" to pick a scenario other than
+// the first one demonstrating the anomaly, e.g. a custom one.
+func compareCommand(args []string) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags := registerProviderFlags(fs)
+	logs := registerLogFlags(fs)
+	anomaly := fs.String("anomaly", "", "anomaly to compare, e.g. "+scenario.AnomalyPhantomRead+" (required)")
+	providerNames := fs.String("providers", "", "two comma-separated providers, each optionally as provider:scenario (required)")
+	format := fs.String("format", "text", "output format: text, markdown or html")
+	out := fs.String("out", "-", "file to write the comparison to, or - for stdout")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+
+	_, closeLog, err := logs.setup(fs.Name())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	defer closeLog()
+
+	write := func(w io.Writer, c *report.Comparison) error {
+		_, err := io.WriteString(w, ui.RenderComparison(c, compareWidth))
+		return err
+	}
+	if *format != "text" {
+		docFormat, err := report.ParseFormat(*format)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		write = func(w io.Writer, c *report.Comparison) error {
+			return report.WriteComparison(w, c, docFormat)
+		}
+	}
+
+	names := strings.Split(*providerNames, ",")
+	if *anomaly == "" || len(names) != 2 {
+		fmt.Fprintln(os.Stderr, "compare requires --anomaly and two --providers")
+		return exitUsage
+	}
+	providers, err := flags.registry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	var targets [2]compareTarget
+	for i, name := range names {
+		if targets[i], err = lookupCompareTarget(providers, strings.TrimSpace(name), *anomaly); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	defer stopAll(providers)
+
+	var sides [2]report.Side
+	started := make(map[provider.Provider]scenario.Params)
+	for i, target := range targets {
+		p := target.provider
+		params, ok := started[p]
+		if !ok {
+			if params, err = startHeadless(ctx, p, flags.seed); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return exitFailed
+			}
+			// Steps are shown once both runs are done, so there's nothing to pace
+			params.Pacing = 0
+			started[p] = params
+		}
+
+		var run *report.Run
+		if missing := p.Capabilities().Missing(scenario.Requirements(target.scenario)); len(missing) > 0 {
+			run = report.NewRun(target.scenario)
+			run.SkipReason = fmt.Sprintf("%s does not support %v", p.Name(), missing)
+		} else {
+			run = headless.Record(ctx, target.scenario, params, nil)
+			// Alignment needs every step, including any spilled to disk
+			whole, err := run.Whole()
+			_ = run.Close()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return exitFailed
+			}
+			run = whole
+		}
+		fmt.Fprintln(os.Stderr, resultLine(run))
+		sides[i] = report.Side{Provider: p.Name(), Run: run}
+		if ctx.Err() != nil {
+			return exitFailed
+		}
+	}
+
+	c := report.NewComparison(*anomaly, sides[0], sides[1])
+	if err := writeFile(*out, func(w io.Writer) error { return write(w, c) }); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// lookupCompareTarget resolves "provider" or "provider:scenario" to the scenario to run for the anomaly
+func lookupCompareTarget(providers *provider.Registry, name, anomaly string) (compareTarget, error) {
+	providerName, scenarioName, byName := strings.Cut(name, ":")
+	p, err := lookupProvider(providers, providerName)
+	if err != nil {
+		return compareTarget{}, err
+	}
+
+	if byName {
+		s, err := lookupScenario(p, scenarioName)
+		if err != nil {
+			return compareTarget{}, err
+		}
+		return compareTarget{provider: p, scenario: s}, nil
+	}
+
+	s := p.GetScenarios().GetByAnomaly(anomaly)
+	if s == nil {
+		return compareTarget{}, fmt.Errorf("%s has no %s scenario (known anomalies: %s)", p.Name(), anomaly, strings.Join(anomalies(p), ", "))
+	}
+	return compareTarget{provider: p, scenario: s}, nil
+}
+
+// anomalies lists the anomalies the provider's scenarios demonstrate
+func anomalies(p provider.Provider) []string {
+	var keys []string
+	for _, s := range p.GetScenarios().GetAll() {
+		if key := scenario.AnomalyOf(s); key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
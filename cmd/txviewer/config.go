@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/config"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/logging"
+)
+
+// settings lists the flags that can also come from the environment or the config file.
+// Environment variable names are derived from the keys, e.g. TXVIEWER_MONGODB_TOPOLOGY.
+var settings = []config.Setting{
+	{Key: "mongodb.image", Flag: "mongodb-image"},
+	{Key: "mongodb.topology", Flag: "mongodb-topology"},
+	{Key: "mongodb.database", Flag: "mongodb-database"},
+	{Key: "mongodb.dataset", Flag: "mongodb-dataset"},
+	{Key: "mongodb.compose_project", Flag: "mongodb-compose-project"},
+	{Key: "mongodb.compose_service", Flag: "mongodb-compose-service"},
+	{Key: "mongodb.uri", Flag: "mongodb-uri"},
+	{Key: "mongodb.volume", Flag: "mongodb-volume"},
+	{Key: "fake.enabled", Flag: "with-fake-provider"},
+	{Key: "scenario.seed", Flag: "seed"},
+	{Key: "scenario.pacing", Flag: "pacing"},
+	{Key: "scenario.file", Flag: "scenario-file"},
+	{Key: "scenario.dir", Flag: "scenario-dir"},
+	{Key: "scenario.plugin_dir", Flag: "plugin-dir"},
+	{Key: "container.memory", Flag: "container-memory"},
+	{Key: "container.cpus", Flag: "container-cpus"},
+	{Key: "container.stop_timeout", Flag: "stop-timeout"},
+	{Key: "container.ready_timeout", Flag: "ready-timeout"},
+	{Key: "ui.lang", Flag: "lang"},
+	{Key: "ui.ascii", Flag: "ascii"},
+	{Key: "ui.prewarm", Flag: "prewarm"},
+	{Key: "ui.memory_steps", Flag: "memory-steps"},
+	{Key: "server.addr", Flag: "addr"},
+	{Key: "server.idle_timeout", Flag: "idle-timeout"},
+	{Key: "log.file", Flag: "log-file"},
+	{Key: "log.level", Flag: "log-level"},
+}
+
+// defaultConfigPath returns the config file used without --config, or "" without a home directory
+func defaultConfigPath() string {
+	path, _ := config.DefaultPath()
+	return envOr("TXVIEWER_CONFIG", path)
+}
+
+// parseFlags parses args and fills the flags that weren't given from the environment, then the
+// config file. Like flag parsing errors, config errors are printed to the flag set's output.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	_, _, err := parseConfigured(fs, args)
+	return err
+}
+
+// parseConfigured is parseFlags that also returns the config file path and where each setting came from
+func parseConfigured(fs *flag.FlagSet, args []string) (string, map[string]config.Source, error) {
+	path := fs.String("config", defaultConfigPath(), "config file with defaults for the flags")
+	if err := fs.Parse(args); err != nil {
+		return "", nil, err
+	}
+
+	file := config.File{}
+	if *path != "" {
+		var err error
+		if file, err = config.Load(*path, settings); err != nil {
+			fmt.Fprintln(fs.Output(), err)
+			return "", nil, err
+		}
+	}
+	sources, err := config.Apply(fs, settings, file, os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(fs.Output(), err)
+		return "", nil, err
+	}
+	return *path, sources, nil
+}
+
+// configCommand manages the config file: "config init" writes a commented default one and
+// "config show" prints the effective settings
+func configCommand(args []string) int {
+	if len(args) > 0 && args[0] == "show" {
+		return configShow(args[1:])
+	}
+	if len(args) == 0 || args[0] != "init" {
+		fmt.Fprintln(os.Stderr, "usage: txviewer config init [--path FILE] [--force]\n       txviewer config show [flags]")
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("config init", flag.ContinueOnError)
+	path := fs.String("path", defaultConfigPath(), "where to write the config file")
+	force := fs.Bool("force", false, "overwrite an existing config file")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if *path == "" {
+		fmt.Fprintln(os.Stderr, "no home directory; pass --path")
+		return exitUsage
+	}
+
+	if _, err := os.Stat(*path); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "%s already exists; pass --force to overwrite it\n", *path)
+		return exitFailed
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+
+	if err := os.MkdirAll(filepath.Dir(*path), 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create config directory: %v\n", err)
+		return exitFailed
+	}
+	if err := writeFile(*path, writeDefaultConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", *path)
+	return exitOK
+}
+
+// writeDefaultConfig writes the commented default config file for every setting
+func writeDefaultConfig(w io.Writer) error {
+	flags := flag.NewFlagSet("txviewer", flag.ContinueOnError)
+	registerProviderFlags(flags)
+	registerServerFlags(flags)
+	registerTUIFlags(flags)
+	registerLogFlags(flags)
+	return config.WriteDefault(w, flags, settings)
+}
+
+// settingRow is one line of "config show"
+type settingRow struct {
+	Key    string `json:"key"`
+	Env    string `json:"env"`
+	Flag   string `json:"flag"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// configShow prints every setting's effective value and where it came from. It accepts the same
+// flags as the other commands, so their effect on the configuration can be checked too.
+func configShow(args []string) int {
+	fs := flag.NewFlagSet("config show", flag.ContinueOnError)
+	registerProviderFlags(fs)
+	registerServerFlags(fs)
+	registerTUIFlags(fs)
+	registerLogFlags(fs)
+	format := fs.String("format", string(headless.FormatText), "output format: text or json")
+	path, sources, err := parseConfigured(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	outFormat, err := headless.ParseFormat(*format)
+	if err == nil && outFormat == headless.FormatJSONL {
+		err = fmt.Errorf("config show prints text or json, not %s", outFormat)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	rows := make([]settingRow, len(settings))
+	for i, s := range settings {
+		rows[i] = settingRow{
+			Key:  s.Key,
+			Env:  s.EnvName(),
+			Flag: "--" + s.Flag,
+			// Connection strings may carry passwords
+			Value:  logging.Redact(fs.Lookup(s.Flag).Value.String()),
+			Source: string(sources[s.Key]),
+		}
+	}
+
+	if outFormat == headless.FormatJSON {
+		if err := writeJSON(os.Stdout, rows); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailed
+		}
+		return exitOK
+	}
+
+	fmt.Printf("Config file: %s\n\n", describeConfigPath(path))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE\tENV")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Key, r.Value, r.Source, r.Env)
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// describeConfigPath notes whether the config file exists
+func describeConfigPath(path string) string {
+	if path == "" {
+		return "none"
+	}
+	if _, err := os.Stat(path); err != nil {
+		return path + " (not found)"
+	}
+	return path
+}
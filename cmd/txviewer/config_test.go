@@ -0,0 +1,21 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSettings_MatchFlags(t *testing.T) {
+	// Fails when a setting names a flag that no longer exists
+	if err := writeDefaultConfig(io.Discard); err != nil {
+		t.Fatalf("Expected every setting to have a flag, got %v", err)
+	}
+
+	envs := make(map[string]string)
+	for _, s := range settings {
+		if other, ok := envs[s.EnvName()]; ok {
+			t.Fatalf("Expected unique environment variables, got %s for %s and %s", s.EnvName(), other, s.Key)
+		}
+		envs[s.EnvName()] = s.Key
+	}
+}
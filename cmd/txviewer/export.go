@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/ui"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// exportCommand runs one scenario headlessly and saves it as a document or an asciinema cast
+func exportCommand(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags := registerProviderFlags(fs)
+	logs := registerLogFlags(fs)
+	providerName := fs.String("provider", "", "provider to start (required)")
+	scenarioName := fs.String("scenario", "", "exact name of the scenario to run (required)")
+	format := fs.String("format", "cast", "export format: cast, markdown or html")
+	out := fs.String("out", "", "file to write, or - for stdout (default: a name derived from the run)")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+
+	_, closeLog, err := logs.setup(fs.Name())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	defer closeLog()
+
+	// Casts are written by replaying the runner view; documents by the report package
+	var ext string
+	var write func(w io.Writer, suite *report.Suite) error
+	if *format == "cast" {
+		ext = ".cast"
+		write = func(w io.Writer, suite *report.Suite) error {
+			return ui.WriteCast(w, suite.Runs[0])
+		}
+	} else {
+		docFormat, err := report.ParseFormat(*format)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		ext = docFormat.Ext()
+		write = func(w io.Writer, suite *report.Suite) error {
+			return report.Write(w, suite, docFormat)
+		}
+	}
+
+	if *providerName == "" || *scenarioName == "" {
+		fmt.Fprintln(os.Stderr, "export requires --provider and --scenario")
+		return exitUsage
+	}
+	providers, err := flags.registry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	p, err := lookupProvider(providers, *providerName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	s, err := lookupScenario(p, *scenarioName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	defer stopAll(providers)
+
+	params, err := startHeadless(ctx, p, flags.seed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	if missing := p.Capabilities().Missing(scenario.Requirements(s)); len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "%s cannot run on this server: missing %v\n", s.Name(), missing)
+		return exitFailed
+	}
+
+	run := headless.Record(ctx, s, params, nil)
+	suite := report.SuiteOf(p.Name(), p.ConnectionInfo(), run)
+	defer suite.Close()
+
+	// Recordings keep the TUI's colors even when stdout isn't a terminal
+	lipgloss.SetColorProfile(termenv.TrueColor)
+
+	path := *out
+	if path == "" {
+		path = suite.FileName(ext)
+	}
+	if err := writeFile(path, func(w io.Writer) error { return write(w, suite) }); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	if path != "-" {
+		fmt.Fprintf(os.Stderr, "Saved to %s\n", path)
+	}
+
+	if run.Err != nil {
+		fmt.Fprintf(os.Stderr, "FAIL %s: %v\n", s.Name(), run.Err)
+		return exitFailed
+	}
+	return exitOK
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/config"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/logging"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/plugin"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/fake"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/mongodb"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	mongoScenarios "github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/mongodb"
+
+	"github.com/docker/go-units"
+)
+
+// providerFlags configure the providers and are shared by the TUI and every subcommand
+type providerFlags struct {
+	image          string
+	topology       string
+	dataset        string
+	stopTimeout    time.Duration
+	readyTimeout   time.Duration
+	memory         string
+	cpus           float64
+	database       string
+	composeProject string
+	composeService string
+	uri            string
+	volume         bool
+	seed           int64
+	pacing         time.Duration
+	scenarioFile   string
+	scenarioDir    string
+	pluginDir      string
+	lang           string
+	ascii          bool
+	memorySteps    int
+	fake           bool
+}
+
+// registerProviderFlags defines the provider flags on fs
+func registerProviderFlags(fs *flag.FlagSet) *providerFlags {
+	f := &providerFlags{}
+	fs.StringVar(&f.image, "mongodb-image", mongodb.DefaultImage,
+		"MongoDB image to launch, or a preset: "+mongodb.PresetNames())
+	fs.StringVar(&f.topology, "mongodb-topology", string(mongodb.TopologySingle),
+		"MongoDB topology: single (one-node replica set), replicaset (three members) or sharded")
+	fs.DurationVar(&f.stopTimeout, "stop-timeout", mongodb.DefaultStopTimeout,
+		"how long to wait for containers to stop before force-removing them")
+	fs.DurationVar(&f.readyTimeout, "ready-timeout", mongodb.DefaultReadyTimeout,
+		"how long a start waits for MongoDB to accept writes before giving up, e.g. on slow machines")
+	fs.StringVar(&f.memory, "container-memory", "",
+		"memory limit per container, e.g. 512m or 1g (default unlimited)")
+	fs.Float64Var(&f.cpus, "container-cpus", 0,
+		"CPU limit per container in cores, e.g. 1.5 (default unlimited)")
+	fs.StringVar(&f.database, "mongodb-database", mongodb.DefaultDatabase,
+		"database the MongoDB scenarios use; only their own collections are ever dropped")
+	fs.StringVar(&f.dataset, "mongodb-dataset", string(scenario.DatasetSmall),
+		"documents seeded by range scenarios: small, medium or large")
+	fs.StringVar(&f.composeProject, "mongodb-compose-project", "",
+		"attach to MongoDB from this docker compose project instead of starting a container")
+	fs.StringVar(&f.composeService, "mongodb-compose-service", "mongo",
+		"service name of MongoDB in the compose project")
+	fs.StringVar(&f.uri, "mongodb-uri", "",
+		"connect to an existing MongoDB deployment at this URI instead of starting a container")
+	fs.BoolVar(&f.volume, "mongodb-volume", false,
+		"keep the single node's data in a Docker volume between starts, skipping its initialization; remove with txviewer clean --volumes")
+	fs.BoolVar(&f.fake, "with-fake-provider", false,
+		"also offer Fake, an in-memory provider that needs no Docker, for demos and UI development")
+	fs.Int64Var(&f.seed, "seed", scenario.DefaultSeed,
+		"seed for generated data and any randomness in scenarios; reuse a recorded seed to reproduce a run")
+	fs.DurationVar(&f.pacing, "pacing", scenario.DefaultPacing,
+		"pause between narrated scenario steps (0 runs at full speed)")
+	fs.StringVar(&f.scenarioFile, "scenario-file", "",
+		"load a custom scenario from this YAML file")
+	// Without a home directory there is no default and only --scenario-file adds scenarios
+	scenarioDir, _ := config.ScenarioDir()
+	fs.StringVar(&f.scenarioDir, "scenario-dir", scenarioDir,
+		"load every custom scenario (*.yaml) in this directory; a missing directory is skipped")
+	pluginDir, _ := config.PluginDir()
+	fs.StringVar(&f.pluginDir, "plugin-dir", pluginDir,
+		"run every executable in this directory as a scenario plugin; a missing directory is skipped")
+	fs.StringVar(&f.lang, "lang", i18n.DefaultLanguage,
+		"language of the scenario narration and the TUI: "+strings.Join(i18n.Languages(), ", "))
+	fs.BoolVar(&f.ascii, "ascii", !i18n.UTF8Locale(os.LookupEnv),
+		"draw with plain ASCII instead of emoji and box drawing, e.g. for terminals without UTF-8 or CI logs (default on when the locale isn't UTF-8)")
+	fs.IntVar(&f.memorySteps, "memory-steps", report.DefaultStepLimit,
+		"steps of a run kept in memory; older ones spill to a temporary file that exports read back (0 keeps every step)")
+	return f
+}
+
+// registry validates the flags, selects the language and registers every provider
+func (f *providerFlags) registry() (*provider.Registry, error) {
+	if err := i18n.SetLanguage(f.lang); err != nil {
+		return nil, fmt.Errorf("invalid --lang: %w", err)
+	}
+	i18n.SetASCII(f.ascii)
+	if f.memorySteps < 0 {
+		return nil, fmt.Errorf("invalid --memory-steps %d: must not be negative", f.memorySteps)
+	}
+	report.SetStepLimit(f.memorySteps)
+	if f.pacing < 0 {
+		return nil, fmt.Errorf("invalid --pacing %s: must not be negative", f.pacing)
+	}
+	scenario.SetDefaultPacing(f.pacing)
+
+	topology, err := mongodb.ParseTopology(f.topology)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mongodb.ValidateDatabaseName(f.database); err != nil {
+		return nil, fmt.Errorf("invalid --mongodb-database: %w", err)
+	}
+
+	var memoryLimit int64
+	if f.memory != "" {
+		memoryLimit, err = units.RAMInBytes(f.memory)
+		if err != nil || memoryLimit <= 0 {
+			return nil, fmt.Errorf("invalid --container-memory %q: expected a size like 512m or 1g", f.memory)
+		}
+	}
+	if f.cpus < 0 {
+		return nil, fmt.Errorf("invalid --container-cpus %g: must not be negative", f.cpus)
+	}
+	if f.readyTimeout <= 0 {
+		return nil, fmt.Errorf("invalid --ready-timeout %s: must be positive", f.readyTimeout)
+	}
+
+	if f.uri != "" && f.composeProject != "" {
+		return nil, fmt.Errorf("--mongodb-uri and --mongodb-compose-project are mutually exclusive")
+	}
+	if f.volume {
+		// Only a launched single node keeps its data in the volume
+		switch {
+		case f.uri != "":
+			return nil, fmt.Errorf("--mongodb-volume and --mongodb-uri are mutually exclusive")
+		case f.composeProject != "":
+			return nil, fmt.Errorf("--mongodb-volume and --mongodb-compose-project are mutually exclusive")
+		case topology != mongodb.TopologySingle:
+			return nil, fmt.Errorf("--mongodb-volume needs --mongodb-topology %s, got %s", mongodb.TopologySingle, topology)
+		}
+	}
+	if f.image == "" {
+		return nil, fmt.Errorf("invalid --mongodb-image: must not be empty")
+	}
+
+	mongo := mongodb.NewProvider(
+		mongodb.WithImage(f.image),
+		mongodb.WithTopology(topology),
+		mongodb.WithStopTimeout(f.stopTimeout),
+		mongodb.WithReadyTimeout(f.readyTimeout),
+		mongodb.WithResourceLimits(memoryLimit, f.cpus),
+		mongodb.WithDatabase(f.database),
+		mongodb.WithComposeService(f.composeProject, f.composeService),
+		mongodb.WithURI(f.uri),
+		mongodb.WithDataVolume(f.volume),
+	)
+	if err := mongo.ApplySetting("dataset", f.dataset); err != nil {
+		return nil, fmt.Errorf("invalid --mongodb-dataset: %w", err)
+	}
+	if err := f.addScripts(mongo); err != nil {
+		return nil, err
+	}
+
+	providers := provider.NewRegistry()
+	providers.Register(mongo)
+	if f.fake {
+		providers.Register(fake.NewProvider())
+	}
+	if err := f.addPlugins(providers); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// addScripts registers the custom scenarios of --scenario-dir and --scenario-file
+func (f *providerFlags) addScripts(p *mongodb.Provider) error {
+	var scripts []*mongoScenarios.Script
+	if f.scenarioDir != "" {
+		loaded, err := mongoScenarios.LoadScriptDir(f.scenarioDir)
+		if err != nil {
+			return err
+		}
+		scripts = append(scripts, loaded...)
+	}
+	if f.scenarioFile != "" {
+		script, err := mongoScenarios.LoadScript(f.scenarioFile)
+		if err != nil {
+			return err
+		}
+		scripts = append(scripts, script)
+	}
+	return p.AddScripts(scripts)
+}
+
+// addPlugins registers the scenario plugins of --plugin-dir with the providers they name.
+// Plugins that can't be used are reported and skipped so one broken plugin doesn't block startup.
+func (f *providerFlags) addPlugins(providers *provider.Registry) error {
+	if f.pluginDir == "" {
+		return nil
+	}
+	plugins, err := plugin.Discover(context.Background(), f.pluginDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	for _, pl := range plugins {
+		p := providers.GetByName(pl.Provider)
+		if p == nil {
+			fmt.Fprintf(os.Stderr, "Warning: plugin %s: unknown provider %q (valid: %s)\n",
+				pl.Path, pl.Provider, strings.Join(providers.Names(), ", "))
+			continue
+		}
+		source, ok := p.(provider.EndpointSource)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Warning: plugin %s: provider %s does not accept plugins\n", pl.Path, p.Name())
+			continue
+		}
+		scenarios := p.GetScenarios()
+		if scenarios.GetByName(pl.Name) != nil {
+			return fmt.Errorf("plugin %s: a scenario named %q already exists", pl.Path, pl.Name)
+		}
+		scenarios.Register(plugin.NewPluginScenario(pl, source.Endpoint))
+	}
+	return nil
+}
+
+// logFlags configure the diagnostics log and are shared by the TUI and every subcommand
+type logFlags struct {
+	path  string
+	level string
+}
+
+// registerLogFlags defines the logging flags on fs
+func registerLogFlags(fs *flag.FlagSet) *logFlags {
+	f := &logFlags{}
+	// Without a home directory there is no default and logging stays off unless a path is given
+	defaultPath, _ := logging.DefaultPath()
+	fs.StringVar(&f.path, "log-file", defaultPath, "file diagnostics are appended to; empty disables logging")
+	fs.StringVar(&f.level, "log-level", "info", "log level: debug, info, warn or error")
+	return f
+}
+
+// setup installs the default logger and returns the log path, empty when logging is off,
+// and a func that closes the log. A log file that can't be opened only disables logging.
+func (f *logFlags) setup(command string) (string, func(), error) {
+	level, err := logging.ParseLevel(f.level)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid --log-level: %w", err)
+	}
+
+	slog.SetDefault(logging.Discard())
+	if f.path == "" {
+		return "", func() {}, nil
+	}
+	logger, closer, err := logging.Open(f.path, level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: logging disabled: %v\n", err)
+		return "", func() {}, nil
+	}
+	slog.SetDefault(logger)
+	slog.Info("txviewer started", "command", command, "pid", os.Getpid())
+	return f.path, func() { _ = closer.Close() }, nil
+}
+
+// lookupProvider returns the provider named by its name or slug, or an error suggesting close
+// matches or else listing the valid names
+func lookupProvider(providers *provider.Registry, name string) (provider.Provider, error) {
+	p := providers.GetByName(name)
+	if p == nil {
+		if suggestions := providers.Suggest(name); len(suggestions) > 0 {
+			return nil, fmt.Errorf("unknown provider %q (did you mean %s?)", name, strings.Join(suggestions, ", "))
+		}
+		return nil, fmt.Errorf("unknown provider %q (valid: %s)", name, strings.Join(providers.Names(), ", "))
+	}
+	return p, nil
+}
+
+// lookupScenario returns p's scenario named by its name or slug, or an error suggesting close matches
+func lookupScenario(p provider.Provider, name string) (scenario.Scenario, error) {
+	s := p.GetScenarios().GetByName(name)
+	if s == nil {
+		if suggestions := p.GetScenarios().Suggest(name); len(suggestions) > 0 {
+			return nil, fmt.Errorf("unknown scenario %q for %s (did you mean %s?)", name, p.Name(), strings.Join(suggestions, ", "))
+		}
+		return nil, fmt.Errorf("unknown scenario %q for %s", name, p.Name())
+	}
+	return s, nil
+}
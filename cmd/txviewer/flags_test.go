@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_RejectsIgnoredDataVolume(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--mongodb-uri", "mongodb://localhost:27017"}, "--mongodb-uri"},
+		{[]string{"--mongodb-compose-project", "demo"}, "--mongodb-compose-project"},
+		{[]string{"--mongodb-topology", "replicaset"}, "--mongodb-topology single"},
+		{[]string{"--mongodb-topology", "sharded"}, "--mongodb-topology single"},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := registerProviderFlags(fs)
+		args := append([]string{"--mongodb-volume", "--scenario-dir", "", "--plugin-dir", ""}, tt.args...)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := f.registry()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("Expected an error naming %s for %v, got %v", tt.want, tt.args, err)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := registerProviderFlags(fs)
+	if err := fs.Parse([]string{"--mongodb-volume", "--scenario-dir", "", "--plugin-dir", ""}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.registry(); err != nil {
+		t.Fatalf("Expected a volume for the single node, got %v", err)
+	}
+}
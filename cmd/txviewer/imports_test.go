@@ -0,0 +1,63 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// staleModule is the module path the tree was built under before it moved to its canonical one
+const staleModule = "txdemo"
+
+func TestImports_UseModulePath(t *testing.T) {
+	root := filepath.Join("..", "..")
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		t.Fatalf("Expected go.mod at the module root, got %v", err)
+	}
+	var module string
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			module = strings.Trim(strings.TrimSpace(rest), `"`)
+			break
+		}
+	}
+	if module == "" || module == staleModule {
+		t.Fatalf("Expected a canonical module path in go.mod, got %q", module)
+	}
+
+	fset := token.NewFileSet()
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, spec := range f.Imports {
+			imp, _ := strconv.Unquote(spec.Path.Value)
+			if imp == staleModule || strings.HasPrefix(imp, staleModule+"/") {
+				t.Errorf("Expected %s to import %q under %s, got %q", path, strings.TrimPrefix(imp, staleModule), module, imp)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the source tree to parse, got %v", err)
+	}
+}
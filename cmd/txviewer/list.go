@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/slug"
+)
+
+// providerListing is one row of `list providers`; Name and Slug both round-trip into --provider
+type providerListing struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Scenarios   int    `json:"scenarios"`
+}
+
+// scenarioListing is one row of `list scenarios`; Name and Slug both round-trip into
+// `run --scenario`, and Slug stays stable for scripts when a name is reworded
+type scenarioListing struct {
+	Name             string   `json:"name"`
+	Slug             string   `json:"slug"`
+	IsolationLevel   string   `json:"isolation_level"`
+	Anomaly          string   `json:"anomaly,omitempty"`
+	Tags             []string `json:"tags"`
+	EstimatedSeconds float64  `json:"estimated_seconds"`
+}
+
+// listCommand prints providers or a provider's scenarios without starting any container
+func listCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "providers" && args[0] != "scenarios") {
+		fmt.Fprintln(os.Stderr, "usage: txviewer list providers|scenarios [--provider NAME] [--format text|json]")
+		return exitUsage
+	}
+	kind := args[0]
+
+	fs := flag.NewFlagSet("list "+kind, flag.ContinueOnError)
+	flags := registerProviderFlags(fs)
+	providerName := fs.String("provider", "", "provider whose scenarios to list (required for scenarios)")
+	format := fs.String("format", string(headless.FormatText), "output format: text or json")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return exitUsage
+	}
+
+	outFormat, err := headless.ParseFormat(*format)
+	if err == nil && outFormat == headless.FormatJSONL {
+		err = fmt.Errorf("list prints text or json, not %s", outFormat)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	providers, err := flags.registry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	if kind == "providers" {
+		err = listProviders(os.Stdout, providers, outFormat)
+	} else {
+		if *providerName == "" {
+			fmt.Fprintln(os.Stderr, "list scenarios requires --provider")
+			return exitUsage
+		}
+		var p provider.Provider
+		if p, err = lookupProvider(providers, *providerName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		err = listScenarios(os.Stdout, p, outFormat)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// listProviders writes every registered provider
+func listProviders(w io.Writer, providers *provider.Registry, format headless.Format) error {
+	var rows []providerListing
+	for _, p := range providers.GetAll() {
+		rows = append(rows, providerListing{
+			Name:        p.Name(),
+			Slug:        slug.Make(p.Name()),
+			Description: p.Description(),
+			Scenarios:   len(p.GetScenarios().GetAll()),
+		})
+	}
+
+	if format == headless.FormatJSON {
+		return writeJSON(w, rows)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSLUG\tSCENARIOS\tDESCRIPTION")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", r.Name, r.Slug, r.Scenarios, r.Description)
+	}
+	return tw.Flush()
+}
+
+// listScenarios writes a provider's scenarios, which are registered before the provider starts
+func listScenarios(w io.Writer, p provider.Provider, format headless.Format) error {
+	scenarios := p.GetScenarios().GetAll()
+	if len(scenarios) == 0 && !p.IsRunning() {
+		return fmt.Errorf("%s only registers its scenarios once started; listing them would require starting a container", p.Name())
+	}
+
+	rows := make([]scenarioListing, len(scenarios))
+	for i, s := range scenarios {
+		meta := scenario.MetadataOf(s)
+		rows[i] = scenarioListing{
+			Name:             s.Name(),
+			Slug:             slug.Make(s.Name()),
+			IsolationLevel:   s.IsolationLevel(),
+			Anomaly:          scenario.AnomalyOf(s),
+			Tags:             meta.Tags,
+			EstimatedSeconds: meta.EstimatedDuration.Seconds(),
+		}
+	}
+
+	if format == headless.FormatJSON {
+		return writeJSON(w, rows)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSLUG\tISOLATION LEVEL\tANOMALY\tTAGS\tEST.")
+	for i, r := range rows {
+		est := "-"
+		if d := scenario.MetadataOf(scenarios[i]).EstimatedDuration; d > 0 {
+			est = "~" + d.String()
+		}
+		anomaly := r.Anomaly
+		if anomaly == "" {
+			anomaly = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Name, r.Slug, r.IsolationLevel, anomaly, strings.Join(r.Tags, ","), est)
+	}
+	return tw.Flush()
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
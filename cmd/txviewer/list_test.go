@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/mongodb"
+)
+
+func TestListScenarios_NamesRoundTrip(t *testing.T) {
+	p := mongodb.NewProvider()
+
+	var buf bytes.Buffer
+	if err := listScenarios(&buf, p, headless.FormatJSON); err != nil {
+		t.Fatalf("Expected listing without starting the provider, got %v", err)
+	}
+
+	var rows []scenarioListing
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if len(rows) == 0 {
+		t.Fatal("Expected scenarios to be listed")
+	}
+	for _, r := range rows {
+		s := p.GetScenarios().GetByName(r.Name)
+		if s == nil {
+			t.Fatalf("Expected %q to resolve as a --scenario value", r.Name)
+		}
+		if p.GetScenarios().GetByName(r.Slug) != s {
+			t.Fatalf("Expected slug %q to resolve to %q", r.Slug, r.Name)
+		}
+	}
+}
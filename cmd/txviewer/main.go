@@ -1,31 +1,220 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/containerruntime"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/history"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
-	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/mongodb"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/network"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+const (
+	// networkSweepTimeout bounds the orphan network sweep at startup
+	networkSweepTimeout = 5 * time.Second
+
+	// crashStopTimeout bounds stopping providers after a crash
+	crashStopTimeout = 15 * time.Second
+)
+
+// Exit codes shared by every command
+const (
+	exitOK      = 0
+	exitFailed  = 1 // The run or the TUI failed; with run --ci, an assertion failed
+	exitUsage   = 2 // Invalid flags or arguments
+	exitErrored = 2 // With run --ci, a scenario could not run to completion
+)
+
 func main() {
-	// Create provider registry
-	providers := provider.NewRegistry()
+	// Subcommands run headless; with none the TUI starts
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "run":
+			os.Exit(runCommand(os.Args[2:]))
+		case "list":
+			os.Exit(listCommand(os.Args[2:]))
+		case "report":
+			os.Exit(reportCommand(os.Args[2:]))
+		case "export":
+			os.Exit(exportCommand(os.Args[2:]))
+		case "config":
+			os.Exit(configCommand(os.Args[2:]))
+		case "serve":
+			os.Exit(serveCommand(os.Args[2:]))
+		case "compare":
+			os.Exit(compareCommand(os.Args[2:]))
+		case "clean":
+			os.Exit(cleanCommand(os.Args[2:]))
+		}
+	}
+	os.Exit(tuiCommand(os.Args[1:]))
+}
 
-	// Register MongoDB provider
-	providers.Register(mongodb.NewProvider())
+// tuiFlags configure the interactive TUI only
+type tuiFlags struct {
+	prewarm bool
+	pprof   string
+}
+
+// registerTUIFlags defines the TUI flags on fs
+func registerTUIFlags(fs *flag.FlagSet) *tuiFlags {
+	f := &tuiFlags{}
+	fs.BoolVar(&f.prewarm, "prewarm", false,
+		"start the provider used last in the background while the menus are shown, so it is ready sooner")
+	fs.StringVar(&f.pprof, "pprof", "",
+		"serve net/http/pprof on this address while the TUI runs, e.g. :6060, to profile a session")
+	return f
+}
+
+// tuiCommand runs the interactive TUI
+func tuiCommand(args []string) int {
+	fs := flag.NewFlagSet("txviewer", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: txviewer [flags]\n       txviewer run --provider NAME --scenario NAME|all [--ci] [flags]\n       txviewer list providers|scenarios [flags]\n       txviewer report --provider NAME [--out FILE] [flags]\n       txviewer export --provider NAME --scenario NAME [--format cast|markdown|html] [flags]\n       txviewer config init|show [flags]\n       txviewer serve [--addr :8080] [flags]\n       txviewer compare --anomaly KEY --providers A,B [--format text|markdown|html] [flags]\n       txviewer clean --volumes\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	flags := registerProviderFlags(fs)
+	logs := registerLogFlags(fs)
+	tui := registerTUIFlags(fs)
+	providerName := fs.String("provider", "",
+		"start this provider immediately, skipping the menus (e.g. mongodb)")
+	broadcastAddr := fs.String("broadcast", "",
+		"also serve a read-only live view of every run on this address, e.g. :9000, for an audience to open in a browser")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+
+	logPath, closeLog, err := logs.setup(fs.Name())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	defer closeLog()
+
+	providers, err := flags.registry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	// Remove networks left behind by runs that crashed before tearing down
+	sweepNetworks()
 
 	// Create the application
 	app := ui.NewApp(providers)
+	app.SetLogPath(logPath)
+	app.SetSeed(flags.seed)
+
+	// Without a home directory startup history is simply not kept
+	var store *history.Store
+	if path, err := history.DefaultPath(); err == nil {
+		store = history.NewStore(path)
+		app.SetHistory(store)
+	}
+
+	if *broadcastAddr != "" {
+		stop, err := startBroadcast(app, *broadcastAddr)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		defer stop()
+	}
+
+	if tui.pprof != "" {
+		stop, err := startPprof(tui.pprof)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		defer stop()
+	}
+
+	if *providerName != "" {
+		p, err := lookupProvider(providers, *providerName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		app.AutoStart(p)
+	} else if tui.prewarm {
+		app.Prewarm(lastUsedProvider(providers, store))
+	}
+
+	// Tear containers down if anything outside the TUI loop panics
+	defer func() {
+		if r := recover(); r != nil {
+			stopAll(providers)
+			panic(r)
+		}
+	}()
 
 	// Run the TUI
-	p := tea.NewProgram(app, tea.WithAltScreen())
+	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithReportFocus())
 
 	if _, err := p.Run(); err != nil {
+		// The TUI didn't get to clean up, so make sure no containers are left behind
+		stopAll(providers)
+		if errors.Is(err, tea.ErrProgramPanic) {
+			panic(err)
+		}
 		fmt.Printf("Error running application: %v\n", err)
-		os.Exit(1)
+		return exitFailed
+	}
+
+	// Surface teardown problems now that the alt screen is gone
+	if err := app.StopErr(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// lastUsedProvider returns the provider started most recently according to store, or the first
+// one registered when there is no history
+func lastUsedProvider(providers *provider.Registry, store *history.Store) provider.Provider {
+	if store != nil {
+		if name, err := store.LastStarted(); err == nil && name != "" {
+			if p := providers.GetByName(name); p != nil {
+				return p
+			}
+		}
+	}
+	return providers.GetAll()[0]
+}
+
+// envOr returns the environment variable's value, or fallback when it is unset
+func envOr(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return fallback
+}
+
+// stopAll stops every provider after a crash or a headless run, bounded by crashStopTimeout
+func stopAll(providers *provider.Registry) {
+	ctx, cancel := context.WithTimeout(context.Background(), crashStopTimeout)
+	defer cancel()
+	if err := providers.StopAll(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// sweepNetworks removes orphaned txviewer networks, ignoring an unreachable Docker daemon. Without
+// a container runtime there is nothing to sweep, and the Docker client isn't even created.
+func sweepNetworks() {
+	if _, err := containerruntime.NewDetector().Probe(); err != nil {
+		return
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), networkSweepTimeout)
+	defer cancel()
+	_, _ = network.Default.Sweep(ctx)
 }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+)
+
+// startPprof serves net/http/pprof on addr while the TUI runs and returns a func that stops it.
+// The handlers get a mux of their own, so nothing else served by the process exposes them.
+func startPprof(addr string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for --pprof: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			slog.Error("pprof stopped", "error", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Profiling on http://%s/debug/pprof/\n", listener.Addr())
+	slog.Info("pprof started", "addr", listener.Addr().String())
+
+	return func() { _ = srv.Close() }, nil
+}
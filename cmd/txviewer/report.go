@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+)
+
+// reportCommand runs every scenario of a provider and writes one Markdown or HTML document.
+// Failed scenarios become failed sections; the exit code is 1 if any failed.
+func reportCommand(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags := registerProviderFlags(fs)
+	logs := registerLogFlags(fs)
+	providerName := fs.String("provider", "", "provider to report on (required)")
+	out := fs.String("out", "-", "file to write the report to, or - for stdout")
+	format := fs.String("format", string(report.FormatMarkdown), "report format: markdown or html")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+
+	_, closeLog, err := logs.setup(fs.Name())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	defer closeLog()
+	reportFormat, err := report.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if *providerName == "" {
+		fmt.Fprintln(os.Stderr, "report requires --provider")
+		return exitUsage
+	}
+
+	providers, err := flags.registry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	p, err := lookupProvider(providers, *providerName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	defer stopAll(providers)
+
+	params, err := startHeadless(ctx, p, flags.seed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+
+	suite := headless.RunSuite(ctx, p, params, func(run *report.Run) {
+		fmt.Fprintf(os.Stderr, "%s %s (%s)\n", run.Verdict(), run.Scenario, run.Duration.Round(100*time.Millisecond))
+	})
+	defer suite.Close()
+
+	err = writeFile(*out, func(w io.Writer) error {
+		return report.Write(w, suite, reportFormat)
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	if suite.Failed() {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// writeFile fills path with write, or writes to stdout for "-"
+func writeFile(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
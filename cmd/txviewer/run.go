@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// scenarioAll selects every scenario of the provider
+const scenarioAll = "all"
+
+// runCommand starts a provider, runs one scenario or all of them headlessly and stops the provider again.
+// Steps go to stdout; progress and diagnostics go to stderr. With --ci the steps are replaced by one
+// PASS/FAIL/ERROR line per scenario and the exit code tells assertion failures and errors apart.
+func runCommand(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags := registerProviderFlags(fs)
+	logs := registerLogFlags(fs)
+	providerName := fs.String("provider", "", "provider to start (required)")
+	scenarioName := fs.String("scenario", "", "name or slug of the scenario to run, or \"all\" (required)")
+	format := fs.String("format", string(headless.FormatText), "output format: text, json (one object per step) or jsonl (step and run events)")
+	ci := fs.Bool("ci", false, "skip pacing, print one line per scenario and exit 1 on failed assertions, 2 on errors")
+	timeout := fs.Duration("timeout", 0, "maximum duration of each scenario; 0 uses each scenario's estimated duration plus a minute, or 2m without one")
+	strictCleanup := fs.Bool("strict-cleanup", false, "fail the run when a scenario can't clean up after itself")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+
+	_, closeLog, err := logs.setup(fs.Name())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	defer closeLog()
+
+	outFormat, err := headless.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if *providerName == "" || *scenarioName == "" {
+		fmt.Fprintln(os.Stderr, "run requires --provider and --scenario")
+		return exitUsage
+	}
+	if *timeout < 0 {
+		fmt.Fprintln(os.Stderr, "--timeout must not be negative")
+		return exitUsage
+	}
+
+	providers, err := flags.registry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	p, err := lookupProvider(providers, *providerName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	scenarios := p.GetScenarios().GetAll()
+	if !strings.EqualFold(*scenarioName, scenarioAll) {
+		s, err := lookupScenario(p, *scenarioName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		scenarios = []scenario.Scenario{s}
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	defer stopAll(providers)
+
+	params, err := startHeadless(ctx, p, flags.seed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if *ci {
+			return exitErrored
+		}
+		return exitFailed
+	}
+
+	params.Timeout = *timeout
+	var tee headless.StepWriter
+	if *ci {
+		params.Pacing = 0
+	} else {
+		tee = headless.NewStepWriter(outFormat, os.Stdout)
+	}
+
+	var failed, errored bool
+	for _, s := range scenarios {
+		if missing := p.Capabilities().Missing(scenario.Requirements(s)); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "SKIP %s: missing %v\n", s.Name(), missing)
+			// A scenario asked for by name must run
+			failed = failed || len(scenarios) == 1
+			continue
+		}
+
+		// Record times the scenario out itself; this bound only abandons one ignoring the cancellation
+		runCtx, cancelRun := context.WithTimeout(ctx, scenario.TimeoutOf(s, params)+scenario.CleanupTimeout)
+		run := headless.Record(runCtx, s, params, tee)
+		cancelRun()
+		_ = run.Close() // Only the verdict is needed from here
+
+		verdict := run.Verdict()
+		failed = failed || verdict == report.VerdictFail || (run.CleanupErr != nil && *strictCleanup)
+		errored = errored || verdict == report.VerdictError
+		if run.CleanupErr != nil {
+			fmt.Fprintf(os.Stderr, "WARN  %s: %v\n", run.Scenario, run.CleanupErr)
+		}
+
+		if *ci {
+			fmt.Println(resultLine(run))
+		} else {
+			fmt.Fprintln(os.Stderr, resultLine(run))
+		}
+		if ctx.Err() != nil {
+			errored = true
+			break
+		}
+	}
+
+	switch {
+	case errored && *ci:
+		return exitErrored
+	case errored || failed:
+		return exitFailed
+	}
+	return exitOK
+}
+
+// resultLine summarizes a run as "VERDICT name (duration)" plus why it didn't pass
+func resultLine(run *report.Run) string {
+	line := fmt.Sprintf("%-5s %s (%s)", run.Verdict(), run.Scenario, run.Duration.Round(time.Millisecond))
+	switch run.Verdict() {
+	case report.VerdictError:
+		line += ": " + run.Err.Error()
+	case report.VerdictFail:
+		a := run.FailedAssertions()[0]
+		line += ": " + a.Name
+		if a.Detail != "" {
+			line += " (" + a.Detail + ")"
+		}
+	}
+	return i18n.Render(line)
+}
+
+// signalContext is cancelled by Ctrl+C or SIGTERM, e.g. a CI job timeout; callers still
+// stop their providers afterwards so no containers are left behind
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// startHeadless starts p with its progress on stderr and returns the scenario params it is configured for,
+// seeded with seed
+func startHeadless(ctx context.Context, p provider.Provider, seed int64) (scenario.Params, error) {
+	// Attached providers create no networks, and an external database or the fake may be used
+	// without Docker
+	if a, ok := p.(provider.Attachable); provider.NeedsRuntime(p) && (!ok || a.AttachedTo() == "") {
+		sweepNetworks()
+	}
+
+	startCtx := provider.WithProgress(ctx, func(stage string) {
+		fmt.Fprintln(os.Stderr, stage)
+	})
+	if err := p.Start(startCtx); err != nil {
+		return scenario.Params{}, fmt.Errorf("failed to start %s: %w", p.Name(), err)
+	}
+
+	params := scenario.DefaultParams()
+	if ps, ok := p.(provider.ParamSource); ok {
+		params = ps.ScenarioParams()
+	}
+	params.Seed = seed
+	return params, nil
+}
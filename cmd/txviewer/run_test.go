@@ -0,0 +1,15 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRunCommand_FakeProviderNeedsNoDocker(t *testing.T) {
+	t.Setenv("TXVIEWER_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	code := runCommand([]string{"--with-fake-provider", "--provider", "fake", "--scenario", "all", "--ci", "--log-file", ""})
+	if code != exitOK {
+		t.Fatalf("Expected every Fake scenario to pass, got exit code %d", code)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/server"
+)
+
+// serverShutdownTimeout bounds waiting for open requests when the server stops
+const serverShutdownTimeout = 10 * time.Second
+
+// serverFlags configure the HTTP server of the serve command
+type serverFlags struct {
+	addr        string
+	idleTimeout time.Duration
+}
+
+// registerServerFlags defines the server flags on fs
+func registerServerFlags(fs *flag.FlagSet) *serverFlags {
+	f := &serverFlags{}
+	fs.StringVar(&f.addr, "addr", ":8080", "address serve listens on")
+	fs.DurationVar(&f.idleTimeout, "idle-timeout", server.DefaultIdleTimeout,
+		"stop a provider serve started after it had no runs for this long; 0 keeps it running")
+	return f
+}
+
+// serveCommand exposes providers and scenario runs over HTTP until interrupted
+func serveCommand(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags := registerProviderFlags(fs)
+	logs := registerLogFlags(fs)
+	serve := registerServerFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+
+	_, closeLog, err := logs.setup(fs.Name())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	defer closeLog()
+
+	if serve.idleTimeout < 0 {
+		fmt.Fprintln(os.Stderr, "--idle-timeout must not be negative")
+		return exitUsage
+	}
+	providers, err := flags.registry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	defer stopAll(providers)
+
+	start := func(ctx context.Context, p provider.Provider) (scenario.Params, error) {
+		return startHeadless(ctx, p, flags.seed)
+	}
+	srv := server.New(providers, start, serve.idleTimeout)
+	httpServer := &http.Server{Addr: serve.addr, Handler: srv.Handler()}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Serving on %s\n", serve.addr)
+
+	select {
+	case err := <-serveErr:
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	case <-ctx.Done():
+	}
+
+	// End runs and event streams first, or Shutdown would wait for the streams to finish
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), crashStopTimeout)
+	defer cancelShutdown()
+	if err := srv.Close(shutdownCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancelHTTP()
+	if err := httpServer.Shutdown(httpCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return exitOK
+}
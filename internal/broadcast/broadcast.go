@@ -0,0 +1,208 @@
+// Package broadcast mirrors the runs of a TUI session to browsers over a read-only WebSocket,
+// so an audience can follow along when a terminal is hard to read over a video call.
+package broadcast
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	// clientBuffer is how many events a viewer may fall behind before it is disconnected
+	clientBuffer = 256
+
+	// writeTimeout bounds sending one event to a viewer
+	writeTimeout = 5 * time.Second
+)
+
+//go:embed page.html.tmpl
+var pages embed.FS
+
+var pageTemplate = template.Must(template.ParseFS(pages, "page.html.tmpl"))
+
+// Hub publishes run events to every connected viewer. Viewers who join mid-run first receive
+// the events of the current run. Messages from viewers are discarded.
+type Hub struct {
+	mu       sync.Mutex
+	scenario string   // Name of the latest run
+	current  [][]byte // Encoded events of the latest run
+	clients  map[*client]struct{}
+	closed   bool
+}
+
+// client is one connected viewer
+type client struct {
+	send chan []byte
+}
+
+// NewHub creates a hub without viewers
+func NewHub() *Hub {
+	return &Hub{clients: make(map[*client]struct{})}
+}
+
+// RunStarted announces the scenario that was selected and starts a new replay history
+func (h *Hub) RunStarted(run *report.Run) error {
+	h.mu.Lock()
+	h.scenario = run.Scenario
+	h.mu.Unlock()
+
+	h.publish(headless.StartedEvent(run), true)
+	return nil
+}
+
+// WriteStep publishes a step of the current run
+func (h *Hub) WriteStep(step scenario.StepResult) error {
+	h.mu.Lock()
+	name := h.scenario
+	h.mu.Unlock()
+
+	h.publish(headless.StepEvent(name, step), false)
+	return nil
+}
+
+// RunFinished publishes the verdict of the current run
+func (h *Hub) RunFinished(run *report.Run) error {
+	h.publish(headless.FinishedEvent(run), false)
+	return nil
+}
+
+// publish encodes e and queues it for every viewer; reset starts the history of a new run
+func (h *Hub) publish(e headless.Event, reset bool) {
+	e.Time = time.Now().UTC()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if reset {
+		h.current = nil
+	}
+	h.current = append(h.current, data)
+	for c := range h.clients {
+		h.queue(c, data)
+	}
+}
+
+// queue hands data to a viewer, dropping viewers that fell too far behind; h.mu must be held
+func (h *Hub) queue(c *client, data []byte) {
+	select {
+	case c.send <- data:
+	default:
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// Handler serves the viewer page at / and the event stream at /ws
+func (h *Hub) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", h.handlePage)
+	mux.HandleFunc("GET /ws", h.handleWebSocket)
+	return mux
+}
+
+// Close disconnects every viewer
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for c := range h.clients {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+func (h *Hub) handlePage(w http.ResponseWriter, r *http.Request) {
+	colors, err := json.Marshal(report.SessionColors)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pageTemplate.Execute(w, struct{ Colors template.JS }{template.JS(colors)})
+}
+
+func (h *Hub) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+
+	// Viewers are strictly read-only: anything they send is discarded. Reading also handles
+	// control frames, and the context ends once the viewer goes away.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go discard(ctx, cancel, conn)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		conn.Close(websocket.StatusGoingAway, "broadcast ended")
+		return
+	}
+	// Room for the replay on top of the usual backlog
+	c := &client{send: make(chan []byte, len(h.current)+clientBuffer)}
+	for _, data := range h.current {
+		c.send <- data
+	}
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	slog.Info("broadcast viewer connected", "remote", r.RemoteAddr)
+
+	defer func() {
+		h.mu.Lock()
+		if _, ok := h.clients[c]; ok {
+			delete(h.clients, c)
+			close(c.send)
+		}
+		h.mu.Unlock()
+		slog.Info("broadcast viewer disconnected", "remote", r.RemoteAddr)
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			if !ok {
+				conn.Close(websocket.StatusGoingAway, "too far behind or broadcast ended")
+				return
+			}
+			if err := write(ctx, conn, data); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// discard reads and drops messages until the connection fails, then calls cancel
+func discard(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn) {
+	defer cancel()
+	for {
+		if _, _, err := conn.Read(ctx); err != nil {
+			return
+		}
+	}
+}
+
+// write sends one text message, bounded by writeTimeout
+func write(ctx context.Context, conn *websocket.Conn, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+	return conn.Write(ctx, websocket.MessageText, data)
+}
@@ -0,0 +1,57 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"github.com/coder/websocket"
+)
+
+func TestHub_ReplaysCurrentRun(t *testing.T) {
+	hub := NewHub()
+	ts := httptest.NewServer(hub.Handler())
+	defer ts.Close()
+	defer hub.Close()
+
+	run := &report.Run{Scenario: "Dirty Read", IsolationLevel: "Read Uncommitted", Started: time.Now()}
+	_ = hub.RunStarted(run)
+	_ = hub.WriteStep(scenario.StepResult{Session: "Session A", Step: 1, Description: "Begin", Success: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.CloseNow()
+
+	// Writes from viewers are ignored rather than breaking the feed
+	_ = conn.Write(ctx, websocket.MessageText, []byte("hello"))
+	_ = hub.RunFinished(run)
+
+	var types []string
+	for len(types) < 3 {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatalf("Failed to read event: %v", err)
+		}
+		var e headless.Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		types = append(types, e.Type)
+	}
+
+	want := "run-started,step,run-finished"
+	if got := strings.Join(types, ","); got != want {
+		t.Fatalf("Expected events %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,220 @@
+// Package config fills in command-line flags from environment variables and a YAML config file.
+// Flags given on the command line win over the environment, which wins over the file.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the name of every environment variable that overrides a setting
+const EnvPrefix = "TXVIEWER_"
+
+// Setting ties a config file key to the flag it feeds; the environment variable is derived from Key
+type Setting struct {
+	Key  string // Dotted path in the file, e.g. "mongodb.topology"
+	Flag string // Flag name without dashes
+}
+
+// EnvName returns the environment variable of a setting, e.g. TXVIEWER_MONGODB_TOPOLOGY for mongodb.topology
+func (s Setting) EnvName() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(s.Key, ".", "_"))
+}
+
+// Source tells where the effective value of a setting came from
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// DefaultPath returns $XDG_CONFIG_HOME/txviewer/config.yaml, falling back to ~/.config
+func DefaultPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// ScenarioDir returns $XDG_CONFIG_HOME/txviewer/scenarios, where custom scenario files are picked up
+func ScenarioDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scenarios"), nil
+}
+
+// PluginDir returns $XDG_CONFIG_HOME/txviewer/plugins, where scenario plugin executables are picked up
+func PluginDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "plugins"), nil
+}
+
+// configDir returns the txviewer directory under $XDG_CONFIG_HOME, falling back to ~/.config
+func configDir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "txviewer"), nil
+}
+
+// File holds the values of a config file by dotted key
+type File map[string]string
+
+// Load reads the config file at path. A missing file has no values; keys that aren't
+// one of settings are rejected so typos don't go unnoticed.
+func Load(path string, settings []Setting) (File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return parse(data, path, settings)
+}
+
+// parse decodes a YAML document of nested mappings into dotted keys
+func parse(data []byte, path string, settings []Setting) (File, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	known := make(map[string]bool, len(settings))
+	for _, s := range settings {
+		known[s.Key] = true
+	}
+
+	file := File{}
+	if err := flatten(file, "", doc); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	for key := range file {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown key %q in %s", key, path)
+		}
+	}
+	return file, nil
+}
+
+// flatten adds the scalar values of m to file under dotted keys
+func flatten(file File, prefix string, m map[string]any) error {
+	for k, v := range m {
+		key := prefix + k
+		switch v := v.(type) {
+		case nil:
+			// An empty section or a key without a value
+		case map[string]any:
+			if err := flatten(file, key+".", v); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("%s: lists are not supported", key)
+		default:
+			file[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// Apply sets each setting's flag that wasn't given on the command line from its environment
+// variable, or else from file. It returns where every setting's value came from.
+func Apply(fs *flag.FlagSet, settings []Setting, file File, lookupEnv func(string) (string, bool)) (map[string]Source, error) {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	sources := make(map[string]Source, len(settings))
+	for _, s := range settings {
+		// Commands only define the flags they use
+		if fs.Lookup(s.Flag) == nil {
+			continue
+		}
+
+		if given[s.Flag] {
+			sources[s.Key] = SourceFlag
+			continue
+		}
+
+		source := SourceDefault
+		value, ok := lookupEnv(s.EnvName())
+		if ok {
+			source = SourceEnv
+		} else if value, ok = file[s.Key]; ok {
+			source = SourceFile
+		}
+		if ok {
+			if err := fs.Set(s.Flag, value); err != nil {
+				return nil, fmt.Errorf("invalid %s from %s: %w", s.Key, source, err)
+			}
+		}
+		sources[s.Key] = source
+	}
+	return sources, nil
+}
+
+// WriteDefault writes a config file with every setting commented out at its default,
+// described by its flag's usage
+func WriteDefault(w io.Writer, fs *flag.FlagSet, settings []Setting) error {
+	var b strings.Builder
+	b.WriteString("# txviewer configuration\n")
+	b.WriteString("#\n")
+	b.WriteString("# Uncomment a setting to change it. Command-line flags override environment\n")
+	b.WriteString("# variables (shown next to each setting), which override this file.\n")
+
+	section := ""
+	for _, s := range settings {
+		f := fs.Lookup(s.Flag)
+		if f == nil {
+			return fmt.Errorf("setting %s has no flag --%s", s.Key, s.Flag)
+		}
+
+		parent, name := splitKey(s.Key)
+		if parent != section {
+			section = parent
+			fmt.Fprintf(&b, "\n%s:\n", section)
+		}
+		fmt.Fprintf(&b, "  # %s (--%s, %s)\n", f.Usage, s.Flag, s.EnvName())
+		fmt.Fprintf(&b, "  # %s: %s\n", name, quote(f.DefValue))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// splitKey splits "section.name" into its parts
+func splitKey(key string) (string, string) {
+	i := strings.LastIndex(key, ".")
+	return key[:i], key[i+1:]
+}
+
+// quote renders a default value as YAML, quoting empty strings
+func quote(v string) string {
+	if v == "" {
+		return `""`
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSettings = []Setting{
+	{Key: "mongodb.topology", Flag: "mongodb-topology"},
+	{Key: "mongodb.database", Flag: "mongodb-database"},
+	{Key: "mongodb.image", Flag: "mongodb-image"},
+	{Key: "container.stop_timeout", Flag: "stop-timeout"},
+}
+
+func newFlagSet() (*flag.FlagSet, map[string]*string, *time.Duration) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	values := map[string]*string{
+		"mongodb-topology": fs.String("mongodb-topology", "single", "MongoDB topology"),
+		"mongodb-database": fs.String("mongodb-database", "txdemo", "database"),
+		"mongodb-image":    fs.String("mongodb-image", "mongo:7.0", "image"),
+	}
+	stop := fs.Duration("stop-timeout", 10*time.Second, "stop timeout")
+	return fs, values, stop
+}
+
+func TestApply_Precedence(t *testing.T) {
+	fs, values, stop := newFlagSet()
+	if err := fs.Parse([]string{"--mongodb-topology", "sharded"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	file, err := parse([]byte("mongodb:\n  topology: replicaset\n  database: fromfile\ncontainer:\n  stop_timeout: 30s\n"), "config.yaml", testSettings)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	env := map[string]string{
+		"TXVIEWER_MONGODB_TOPOLOGY": "replicaset",
+		"TXVIEWER_MONGODB_DATABASE": "fromenv",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	sources, err := Apply(fs, testSettings, file, lookup)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		key, flag, want string
+		source          Source
+	}{
+		{"mongodb.topology", "mongodb-topology", "sharded", SourceFlag},
+		{"mongodb.database", "mongodb-database", "fromenv", SourceEnv},
+		{"mongodb.image", "mongodb-image", "mongo:7.0", SourceDefault},
+	}
+	for _, tt := range tests {
+		if got := *values[tt.flag]; got != tt.want {
+			t.Fatalf("Expected %s = %q, got %q", tt.key, tt.want, got)
+		}
+		if sources[tt.key] != tt.source {
+			t.Fatalf("Expected %s from %s, got %s", tt.key, tt.source, sources[tt.key])
+		}
+	}
+	if *stop != 30*time.Second || sources["container.stop_timeout"] != SourceFile {
+		t.Fatalf("Expected stop timeout 30s from file, got %s from %s", *stop, sources["container.stop_timeout"])
+	}
+}
+
+func TestApply_InvalidValue(t *testing.T) {
+	fs, _, _ := newFlagSet()
+	_ = fs.Parse(nil)
+
+	_, err := Apply(fs, testSettings, File{"container.stop_timeout": "soon"}, func(string) (string, bool) { return "", false })
+	if err == nil || !strings.Contains(err.Error(), "container.stop_timeout from file") {
+		t.Fatalf("Expected an error naming the key and source, got %v", err)
+	}
+}
+
+func TestParse_UnknownKey(t *testing.T) {
+	_, err := parse([]byte("mongodb:\n  topolgy: sharded\n"), "config.yaml", testSettings)
+	if err == nil || !strings.Contains(err.Error(), "mongodb.topolgy") {
+		t.Fatalf("Expected an unknown key error, got %v", err)
+	}
+}
+
+func TestWriteDefault_IsValidConfig(t *testing.T) {
+	fs, _, _ := newFlagSet()
+
+	var buf bytes.Buffer
+	if err := WriteDefault(&buf, fs, testSettings); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "  # topology: single") || !strings.Contains(buf.String(), "TXVIEWER_CONTAINER_STOP_TIMEOUT") {
+		t.Fatalf("Expected commented defaults with env names, got:\n%s", buf.String())
+	}
+
+	// Everything is commented out, so the file changes nothing until edited
+	file, err := parse(buf.Bytes(), "config.yaml", testSettings)
+	if err != nil || len(file) != 0 {
+		t.Fatalf("Expected an empty valid config, got %v, %v", file, err)
+	}
+}
@@ -0,0 +1,234 @@
+// Package containerruntime locates a Docker-compatible container runtime socket
+// (Docker, Podman, Colima) and configures testcontainers to use it.
+package containerruntime
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Kind identifies a container runtime
+type Kind string
+
+const (
+	KindNone           Kind = "none"
+	KindDockerHost     Kind = "DOCKER_HOST"
+	KindDockerDesktop  Kind = "Docker Desktop"
+	KindDockerEngine   Kind = "Docker Engine"
+	KindDockerRootless Kind = "Docker (rootless)"
+	KindPodman         Kind = "Podman"
+	KindPodmanMachine  Kind = "Podman machine"
+	KindColima         Kind = "Colima"
+	KindCustom         Kind = "Custom socket"
+)
+
+// Runtime is a detected container runtime and the socket used to reach it
+type Runtime struct {
+	Kind   Kind
+	Socket string // Absolute socket path, or the raw DOCKER_HOST value for KindDockerHost
+}
+
+// Found returns whether a runtime was located
+func (r Runtime) Found() bool {
+	return r.Kind != KindNone && r.Kind != ""
+}
+
+// Host returns the DOCKER_HOST value for the runtime
+func (r Runtime) Host() string {
+	if r.Kind == KindDockerHost {
+		return r.Socket
+	}
+	return "unix://" + r.Socket
+}
+
+// String describes the runtime for display
+func (r Runtime) String() string {
+	if !r.Found() {
+		return "no container runtime found"
+	}
+	return fmt.Sprintf("%s (%s)", r.Kind, r.Host())
+}
+
+// Env returns the environment variables testcontainers needs to use the runtime
+func (r Runtime) Env() map[string]string {
+	switch r.Kind {
+	case KindNone, KindDockerHost, KindDockerEngine:
+		// Already discovered by testcontainers without help
+		return nil
+	case KindPodman, KindPodmanMachine:
+		return map[string]string{
+			"DOCKER_HOST": r.Host(),
+			// Ryuk needs privileges to reach the Podman socket
+			"TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED": "true",
+		}
+	case KindColima:
+		return map[string]string{
+			"DOCKER_HOST": r.Host(),
+			// Inside the Colima VM the socket lives at the standard path
+			"TESTCONTAINERS_DOCKER_SOCKET_OVERRIDE": "/var/run/docker.sock",
+		}
+	}
+	return map[string]string{"DOCKER_HOST": r.Host()}
+}
+
+// Apply exports the runtime's environment for the rest of the session.
+// It must run before testcontainers is first used, as testcontainers caches its configuration.
+func (r Runtime) Apply() error {
+	for k, v := range r.Env() {
+		if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("failed to set %s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// candidate is a socket location probed during detection
+type candidate struct {
+	kind Kind
+	path string
+}
+
+// Detector probes well-known socket locations
+type Detector struct {
+	FS     fs.StatFS               // Filesystem rooted at "/"
+	Getenv func(key string) string // Environment lookup
+	Home   string                  // User home directory
+	Dial   func(path string) error // Checks that a socket accepts connections; nil skips the check
+}
+
+// NewDetector creates a detector for the real filesystem and environment
+func NewDetector() *Detector {
+	home, _ := os.UserHomeDir()
+	return &Detector{
+		FS:     os.DirFS("/").(fs.StatFS),
+		Getenv: os.Getenv,
+		Home:   home,
+		Dial:   dialSocket,
+	}
+}
+
+// candidates lists socket locations in probe order
+func (d *Detector) candidates() []candidate {
+	var cs []candidate
+
+	if d.Home != "" {
+		cs = append(cs,
+			candidate{KindDockerDesktop, filepath.Join(d.Home, ".docker", "run", "docker.sock")},
+			candidate{KindDockerDesktop, filepath.Join(d.Home, ".docker", "desktop", "docker.sock")},
+		)
+	}
+
+	cs = append(cs, candidate{KindDockerEngine, "/var/run/docker.sock"})
+
+	if xdg := d.Getenv("XDG_RUNTIME_DIR"); xdg != "" {
+		cs = append(cs,
+			candidate{KindDockerRootless, filepath.Join(xdg, "docker.sock")},
+			candidate{KindPodman, filepath.Join(xdg, "podman", "podman.sock")},
+		)
+	}
+
+	cs = append(cs, candidate{KindPodman, "/run/podman/podman.sock"})
+
+	if d.Home != "" {
+		cs = append(cs,
+			candidate{KindPodmanMachine, filepath.Join(d.Home, ".local", "share", "containers", "podman", "machine", "podman.sock")},
+			candidate{KindPodmanMachine, filepath.Join(d.Home, ".local", "share", "containers", "podman", "machine", "qemu", "podman.sock")},
+			candidate{KindColima, filepath.Join(d.Home, ".colima", "default", "docker.sock")},
+			candidate{KindColima, filepath.Join(d.Home, ".colima", "docker.sock")},
+		)
+	}
+
+	return cs
+}
+
+// ProbedPaths returns every socket path detection looks at, for guidance text
+func (d *Detector) ProbedPaths() []string {
+	cs := d.candidates()
+	paths := make([]string, len(cs))
+	for i, c := range cs {
+		paths[i] = c.path
+	}
+	return paths
+}
+
+// Detect returns the first runtime found, honoring an explicit DOCKER_HOST
+func (d *Detector) Detect() Runtime {
+	if host := d.Getenv("DOCKER_HOST"); host != "" {
+		return Runtime{Kind: KindDockerHost, Socket: host}
+	}
+
+	for _, c := range d.candidates() {
+		if d.isSocket(c.path) {
+			return Runtime{Kind: c.kind, Socket: c.path}
+		}
+	}
+	return Runtime{Kind: KindNone}
+}
+
+// Probe returns the runtime Detect finds once it answers, or an error saying why there is no usable
+// one: no socket at any of the probed paths, or a socket nothing listens on, as Docker Desktop
+// leaves behind when it isn't running. DOCKER_HOST is trusted as set.
+func (d *Detector) Probe() (Runtime, error) {
+	r := d.Detect()
+	if !r.Found() {
+		return r, fmt.Errorf("no container runtime found: DOCKER_HOST is unset and none of these sockets exist: %s",
+			strings.Join(d.ProbedPaths(), ", "))
+	}
+	if r.Kind != KindDockerHost && d.Dial != nil {
+		if err := d.Dial(r.Socket); err != nil {
+			return r, fmt.Errorf("%s is not running: failed to connect to %s: %w", r.Kind, r.Socket, err)
+		}
+	}
+	return r, nil
+}
+
+// dialSocket connects to the Unix socket at path and hangs up
+func dialSocket(path string) error {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Custom validates a user-entered socket path
+func (d *Detector) Custom(path string) (Runtime, error) {
+	path = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(path), "unix://"))
+	if path == "" {
+		return Runtime{}, errors.New("socket path is empty")
+	}
+	if strings.HasPrefix(path, "~/") && d.Home != "" {
+		path = filepath.Join(d.Home, path[2:])
+	}
+	if !filepath.IsAbs(path) {
+		return Runtime{}, fmt.Errorf("socket path %q must be absolute", path)
+	}
+
+	info, err := d.FS.Stat(strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return Runtime{}, fmt.Errorf("cannot access %s: %w", path, err)
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return Runtime{}, fmt.Errorf("%s is not a socket", path)
+	}
+
+	// Keep the runtime-specific environment tweaks when the path is a known one
+	for _, c := range d.candidates() {
+		if c.path == path {
+			return Runtime{Kind: c.kind, Socket: path}, nil
+		}
+	}
+	return Runtime{Kind: KindCustom, Socket: path}, nil
+}
+
+// isSocket returns whether path exists and is a Unix socket
+func (d *Detector) isSocket(path string) bool {
+	info, err := d.FS.Stat(strings.TrimPrefix(path, "/"))
+	return err == nil && info.Mode()&fs.ModeSocket != 0
+}
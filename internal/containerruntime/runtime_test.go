@@ -0,0 +1,143 @@
+package containerruntime
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// newTestDetector builds a detector over a fake filesystem containing the given sockets
+func newTestDetector(env map[string]string, sockets ...string) *Detector {
+	fsys := fstest.MapFS{}
+	for _, s := range sockets {
+		fsys[s] = &fstest.MapFile{Mode: fs.ModeSocket}
+	}
+	return &Detector{
+		FS:     fsys,
+		Getenv: func(key string) string { return env[key] },
+		Home:   "/home/dev",
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		sockets []string
+		want    Runtime
+	}{
+		{
+			name: "nothing found",
+			want: Runtime{Kind: KindNone},
+		},
+		{
+			name:    "DOCKER_HOST wins",
+			env:     map[string]string{"DOCKER_HOST": "tcp://10.0.0.5:2375"},
+			sockets: []string{"var/run/docker.sock"},
+			want:    Runtime{Kind: KindDockerHost, Socket: "tcp://10.0.0.5:2375"},
+		},
+		{
+			name:    "docker engine",
+			sockets: []string{"var/run/docker.sock"},
+			want:    Runtime{Kind: KindDockerEngine, Socket: "/var/run/docker.sock"},
+		},
+		{
+			name:    "docker desktop preferred over engine",
+			sockets: []string{"var/run/docker.sock", "home/dev/.docker/run/docker.sock"},
+			want:    Runtime{Kind: KindDockerDesktop, Socket: "/home/dev/.docker/run/docker.sock"},
+		},
+		{
+			name:    "rootless podman",
+			env:     map[string]string{"XDG_RUNTIME_DIR": "/run/user/1000"},
+			sockets: []string{"run/user/1000/podman/podman.sock"},
+			want:    Runtime{Kind: KindPodman, Socket: "/run/user/1000/podman/podman.sock"},
+		},
+		{
+			name:    "rootless docker",
+			env:     map[string]string{"XDG_RUNTIME_DIR": "/run/user/1000"},
+			sockets: []string{"run/user/1000/docker.sock"},
+			want:    Runtime{Kind: KindDockerRootless, Socket: "/run/user/1000/docker.sock"},
+		},
+		{
+			name:    "podman machine",
+			sockets: []string{"home/dev/.local/share/containers/podman/machine/qemu/podman.sock"},
+			want:    Runtime{Kind: KindPodmanMachine, Socket: "/home/dev/.local/share/containers/podman/machine/qemu/podman.sock"},
+		},
+		{
+			name:    "colima",
+			sockets: []string{"home/dev/.colima/default/docker.sock"},
+			want:    Runtime{Kind: KindColima, Socket: "/home/dev/.colima/default/docker.sock"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTestDetector(tt.env, tt.sockets...).Detect()
+			if got != tt.want {
+				t.Fatalf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDetect_IgnoresRegularFiles(t *testing.T) {
+	d := newTestDetector(nil)
+	d.FS.(fstest.MapFS)["var/run/docker.sock"] = &fstest.MapFile{Data: []byte("not a socket")}
+
+	if got := d.Detect(); got.Found() {
+		t.Fatalf("Expected no runtime for a regular file, got %+v", got)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	if _, err := newTestDetector(nil).Probe(); err == nil || !strings.Contains(err.Error(), "/var/run/docker.sock") {
+		t.Fatalf("Expected the probed paths in the error, got %v", err)
+	}
+
+	d := newTestDetector(nil, "var/run/docker.sock")
+	d.Dial = func(path string) error { return errors.New("connection refused") }
+	if _, err := d.Probe(); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("Expected a socket nothing listens on to fail, got %v", err)
+	}
+
+	d.Dial = func(path string) error { return nil }
+	if r, err := d.Probe(); err != nil || r.Kind != KindDockerEngine {
+		t.Fatalf("Expected Docker Engine, got %+v (%v)", r, err)
+	}
+}
+
+func TestCustom(t *testing.T) {
+	d := newTestDetector(nil, "tmp/custom.sock", "home/dev/.colima/default/docker.sock")
+	d.FS.(fstest.MapFS)["tmp/file"] = &fstest.MapFile{}
+
+	r, err := d.Custom("unix:///tmp/custom.sock")
+	if err != nil || r.Kind != KindCustom || r.Host() != "unix:///tmp/custom.sock" {
+		t.Fatalf("Expected custom runtime, got %+v (%v)", r, err)
+	}
+
+	// Known paths keep their runtime kind, and ~ expands to the home directory
+	r, err = d.Custom("~/.colima/default/docker.sock")
+	if err != nil || r.Kind != KindColima {
+		t.Fatalf("Expected Colima runtime, got %+v (%v)", r, err)
+	}
+
+	for _, bad := range []string{"", "relative.sock", "/tmp/missing.sock", "/tmp/file"} {
+		if _, err := d.Custom(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestRuntimeEnv(t *testing.T) {
+	podman := Runtime{Kind: KindPodman, Socket: "/run/podman/podman.sock"}
+	env := podman.Env()
+	if env["DOCKER_HOST"] != "unix:///run/podman/podman.sock" || env["TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED"] != "true" {
+		t.Fatalf("Unexpected Podman env: %v", env)
+	}
+
+	if env := (Runtime{Kind: KindDockerEngine, Socket: "/var/run/docker.sock"}).Env(); len(env) != 0 {
+		t.Fatalf("Expected no env for the default Docker socket, got %v", env)
+	}
+}
@@ -0,0 +1,118 @@
+package headless
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Event types of the jsonl format
+const (
+	EventRunStarted  = "run-started"
+	EventStep        = "step"
+	EventRunFinished = "run-finished"
+)
+
+// Event is one line of the jsonl format. Type tells which fields are set: run-started events carry
+// the seed, step events the step's fields, run-finished events the verdict, duration, error, assertions
+// and any cleanup error.
+type Event struct {
+	Type           string    `json:"type"`
+	Time           time.Time `json:"time"`
+	Scenario       string    `json:"scenario"`
+	IsolationLevel string    `json:"isolation_level,omitempty"`
+	Seed           *int64    `json:"seed,omitempty"` // Set on run-started events, even to 0, to reproduce the run
+
+	*scenario.StepResult
+
+	Verdict      report.Verdict       `json:"verdict,omitempty"`
+	DurationMS   int64                `json:"duration_ms,omitempty"`
+	Error        string               `json:"error,omitempty"`
+	CleanupError string               `json:"cleanup_error,omitempty"`
+	Assertions   []scenario.Assertion `json:"assertions,omitempty"`
+}
+
+// RunObserver is implemented by StepWriters that also report when each run starts and finishes
+type RunObserver interface {
+	RunStarted(run *report.Run) error
+	RunFinished(run *report.Run) error
+}
+
+// flusher is implemented by buffered writers
+type flusher interface {
+	Flush() error
+}
+
+// jsonlWriter emits one Event per line and flushes after each, so consumers see steps as they happen
+type jsonlWriter struct {
+	w        io.Writer
+	enc      *json.Encoder
+	scenario *string // Name of the current run, shared by copies of the writer
+	now      func() time.Time
+}
+
+// newJSONLWriter returns a jsonl writer on w
+func newJSONLWriter(w io.Writer) jsonlWriter {
+	return jsonlWriter{w: w, enc: json.NewEncoder(w), scenario: new(string), now: time.Now}
+}
+
+// RunStarted implements RunObserver
+func (j jsonlWriter) RunStarted(run *report.Run) error {
+	*j.scenario = run.Scenario
+	return j.emit(StartedEvent(run))
+}
+
+// WriteStep implements StepWriter
+func (j jsonlWriter) WriteStep(step scenario.StepResult) error {
+	return j.emit(StepEvent(*j.scenario, step))
+}
+
+// RunFinished implements RunObserver
+func (j jsonlWriter) RunFinished(run *report.Run) error {
+	return j.emit(FinishedEvent(run))
+}
+
+// StartedEvent returns the run-started event of run
+func StartedEvent(run *report.Run) Event {
+	seed := run.Seed
+	return Event{Type: EventRunStarted, Scenario: run.Scenario, IsolationLevel: run.IsolationLevel, Seed: &seed}
+}
+
+// StepEvent returns the step event of a step of the named scenario
+func StepEvent(scenarioName string, step scenario.StepResult) Event {
+	return Event{Type: EventStep, Scenario: scenarioName, StepResult: &step}
+}
+
+// FinishedEvent returns the run-finished event of run
+func FinishedEvent(run *report.Run) Event {
+	e := Event{
+		Type:           EventRunFinished,
+		Scenario:       run.Scenario,
+		IsolationLevel: run.IsolationLevel,
+		Verdict:        run.Verdict(),
+		DurationMS:     run.Duration.Milliseconds(),
+		Assertions:     run.Assertions,
+	}
+	if run.Err != nil {
+		e.Error = run.Err.Error()
+	}
+	if run.CleanupErr != nil {
+		e.CleanupError = run.CleanupErr.Error()
+	}
+	return e
+}
+
+// emit writes one event line and flushes it
+func (j jsonlWriter) emit(e Event) error {
+	e.Time = j.now().UTC()
+	if err := j.enc.Encode(e); err != nil {
+		return err
+	}
+	if f, ok := j.w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
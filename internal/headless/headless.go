@@ -0,0 +1,166 @@
+// Package headless runs scenarios without the TUI, streaming each step to a writer
+// so runs can be driven from CI or over SSH.
+package headless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Format selects how steps are written
+type Format string
+
+const (
+	FormatText  Format = "text"  // Human-readable lines prefixed with the session
+	FormatJSON  Format = "json"  // One JSON object per step
+	FormatJSONL Format = "jsonl" // One Event per line, including run-started and run-finished
+)
+
+// Formats lists every supported output format
+var Formats = []Format{FormatText, FormatJSON, FormatJSONL}
+
+// ParseFormat converts a --format value into a Format
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown format %q (valid: %s)", s, strings.Join(names, ", "))
+}
+
+// StepWriter renders steps as they arrive
+type StepWriter interface {
+	WriteStep(step scenario.StepResult) error
+}
+
+// NewStepWriter returns a writer for the given format
+func NewStepWriter(f Format, w io.Writer) StepWriter {
+	switch f {
+	case FormatJSON:
+		return jsonWriter{enc: json.NewEncoder(w)}
+	case FormatJSONL:
+		return newJSONLWriter(w)
+	}
+	return textWriter{w: w}
+}
+
+// abandonGrace is how long a cancelled scenario may take to return before it is abandoned
+const abandonGrace = 5 * time.Second
+
+// Run executes s, writing each step to out as soon as the scenario reports it.
+// The returned error is nil only when setup and run both succeeded. When ctx is done, e.g. on a
+// timeout, a scenario that doesn't return promptly is abandoned so callers never hang.
+func Run(ctx context.Context, s scenario.Scenario, params scenario.Params, out StepWriter) error {
+	return execute(ctx, s, params, out).Err
+}
+
+// execute is Run, also reporting whether the scenario failed to clean up
+func execute(ctx context.Context, s scenario.Scenario, params scenario.Params, out StepWriter) scenario.Outcome {
+	ctx = scenario.WithParams(ctx, params)
+	output := make(chan scenario.StepResult, 100)
+
+	outcome := make(chan scenario.Outcome, 1)
+	go func() {
+		outcome <- scenario.Execute(ctx, s, output)
+	}()
+
+	// Keep draining after a write error so the scenario never blocks on output
+	var writeErr error
+	for done := false; !done; {
+		select {
+		case step, ok := <-output:
+			if !ok {
+				done = true
+				break
+			}
+			if writeErr == nil {
+				writeErr = out.WriteStep(step)
+			}
+		case <-ctx.Done():
+			select {
+			case o := <-outcome:
+				if o.Err == nil {
+					o.Err = ctx.Err()
+				}
+				return o
+			case <-time.After(abandonGrace):
+				return scenario.Outcome{Err: fmt.Errorf("scenario did not stop after %w", ctx.Err())}
+			}
+		}
+	}
+
+	o := <-outcome
+	if o.Err == nil && writeErr != nil {
+		o.Err = fmt.Errorf("failed to write step: %w", writeErr)
+	}
+	return o
+}
+
+// textWriter prints steps the way the runner view lays them out
+type textWriter struct {
+	w io.Writer
+}
+
+// RunStarted implements RunObserver, printing a header with the seed needed to reproduce the run
+func (t textWriter) RunStarted(run *report.Run) error {
+	_, err := fmt.Fprint(t.w, i18n.Render(fmt.Sprintf("# %s (%s, seed %d)\n", run.Scenario, run.IsolationLevel, run.Seed)))
+	return err
+}
+
+// RunFinished implements RunObserver; the verdict is reported by the caller
+func (t textWriter) RunFinished(run *report.Run) error {
+	return nil
+}
+
+// WriteStep implements StepWriter
+func (t textWriter) WriteStep(step scenario.StepResult) error {
+	var b strings.Builder
+	if step.IsHeader {
+		fmt.Fprintf(&b, "\n== %s ==\n", step.Description)
+	} else {
+		fmt.Fprintf(&b, "[%d] %s %s\n", step.Step, i18n.Pad(step.Session, 10), step.Description)
+		if step.Query != "" {
+			fmt.Fprintf(&b, "    → %s\n", step.Query)
+		}
+		switch {
+		case step.Pending:
+			fmt.Fprintf(&b, "    %s\n", i18n.T("⏳ waiting..."))
+		case step.Waited > 0:
+			fmt.Fprintf(&b, "    %s\n", i18n.T("⏱️ waited %s", step.Waited.Round(100*time.Millisecond)))
+		}
+		mark := "✓"
+		if !step.Success {
+			mark = "✗"
+		}
+		for _, line := range strings.Split(step.Result, "\n") {
+			if line != "" {
+				fmt.Fprintf(&b, "    %s %s\n", mark, line)
+			}
+		}
+	}
+	_, err := io.WriteString(t.w, i18n.Render(b.String()))
+	return err
+}
+
+// jsonWriter encodes one JSON object per line
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+// WriteStep implements StepWriter
+func (j jsonWriter) WriteStep(step scenario.StepResult) error {
+	return j.enc.Encode(step)
+}
@@ -0,0 +1,223 @@
+package headless
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/scenariotest"
+)
+
+// scriptedScenario emits fixed steps, records its assertions, optionally waits for ctx and then returns err
+type scriptedScenario struct {
+	steps      []scenario.StepResult
+	assertions []scenario.Assertion
+	block      bool
+	err        error
+}
+
+func (s *scriptedScenario) Name() string                      { return "Scripted" }
+func (s *scriptedScenario) Description() string               { return "" }
+func (s *scriptedScenario) IsolationLevel() string            { return "snapshot" }
+func (s *scriptedScenario) Setup(ctx context.Context) error   { return nil }
+func (s *scriptedScenario) Cleanup(ctx context.Context) error { return nil }
+
+func (s *scriptedScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	for _, step := range s.steps {
+		output <- step
+	}
+	for _, a := range s.assertions {
+		scenario.Assert(ctx, a.Name, a.Held, a.Detail)
+	}
+	if s.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return s.err
+}
+
+func TestRun_StreamsJSONLines(t *testing.T) {
+	s := &scriptedScenario{steps: []scenario.StepResult{
+		{IsHeader: true, Description: "Part 1"},
+		{Session: "Session A", Step: 1, Description: "Insert", Query: "insertOne", Result: "ok", Success: true},
+	}}
+
+	var buf bytes.Buffer
+	if err := Run(context.Background(), s, scenario.DefaultParams(), NewStepWriter(FormatJSON, &buf)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	var step scenario.StepResult
+	if err := json.Unmarshal([]byte(lines[1]), &step); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if step.Session != "Session A" || step.Query != "insertOne" || !step.Success {
+		t.Fatalf("Unexpected step %+v", step)
+	}
+}
+
+func TestRun_ReturnsScenarioError(t *testing.T) {
+	failure := errors.New("write conflict not detected")
+	s := &scriptedScenario{
+		steps: []scenario.StepResult{{Session: "Session B", Step: 1, Description: "Update", Result: "boom"}},
+		err:   failure,
+	}
+
+	var buf bytes.Buffer
+	err := Run(context.Background(), s, scenario.DefaultParams(), NewStepWriter(FormatText, &buf))
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected scenario error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "[1] Session B") || !strings.Contains(buf.String(), "✗ boom") {
+		t.Fatalf("Unexpected text output %q", buf.String())
+	}
+}
+
+func TestRecord_FailedAssertion(t *testing.T) {
+	s := &scriptedScenario{assertions: []scenario.Assertion{
+		{Name: "uncommitted write is invisible", Held: true},
+		{Name: "final balance", Held: false, Detail: "got 800"},
+	}}
+
+	run := Record(context.Background(), s, scenario.DefaultParams(), nil)
+	if run.Verdict() != report.VerdictFail {
+		t.Fatalf("Expected FAIL, got %s", run.Verdict())
+	}
+	if failed := run.FailedAssertions(); len(failed) != 1 || failed[0].Name != "final balance" {
+		t.Fatalf("Expected the final balance assertion to fail, got %+v", failed)
+	}
+}
+
+func TestStartedEvent_RecordsSeedZero(t *testing.T) {
+	started, err := json.Marshal(StartedEvent(&report.Run{Scenario: "Scripted", Seed: 0}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(started), `"seed":0`) {
+		t.Fatalf("Expected the run-started event to record seed 0, got %s", started)
+	}
+	step, err := json.Marshal(StepEvent("Scripted", scenario.StepResult{Step: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(step), `"seed"`) {
+		t.Fatalf("Expected step events without a seed, got %s", step)
+	}
+}
+
+func TestRecord_Timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	run := Record(ctx, &scriptedScenario{block: true}, scenario.DefaultParams(), nil)
+	if !errors.Is(run.Err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", run.Err)
+	}
+	if run.Verdict() != report.VerdictError {
+		t.Fatalf("Expected ERROR, got %s", run.Verdict())
+	}
+}
+
+func TestRecord_JSONLStream(t *testing.T) {
+	s := &scriptedScenario{
+		steps: []scenario.StepResult{
+			{IsHeader: true, Description: "Part 1"},
+			{Session: "Session A", Step: 1, Description: "Insert", Query: "insertOne", Result: "ok", Success: true},
+		},
+		assertions: []scenario.Assertion{{Name: "insert is visible", Held: true}},
+	}
+
+	// Read through a pipe so each event must be flushed as a whole line
+	r, w := io.Pipe()
+	go func() {
+		Record(context.Background(), s, scenario.DefaultParams(), NewStepWriter(FormatJSONL, w))
+		w.Close()
+	}()
+
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Expected each line to be a JSON object, got %q: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+
+	types := make([]string, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	want := []string{EventRunStarted, EventStep, EventStep, EventRunFinished}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected events %v, got %v", want, types)
+	}
+
+	if events[0].Seed == nil || *events[0].Seed != scenario.DefaultSeed {
+		t.Fatalf("Expected the run-started event to record seed %d, got %v", scenario.DefaultSeed, events[0].Seed)
+	}
+	step := events[2]
+	if step.Scenario != "Scripted" || step.StepResult == nil || step.Session != "Session A" || step.Query != "insertOne" {
+		t.Fatalf("Unexpected step event %+v", step)
+	}
+	finished := events[3]
+	if finished.Verdict != report.VerdictPass || len(finished.Assertions) != 1 || finished.StepResult != nil {
+		t.Fatalf("Unexpected run-finished event %+v", finished)
+	}
+}
+
+func TestRecord_ManySteps(t *testing.T) {
+	steps := make([]scenario.StepResult, 1000)
+	for i := range steps {
+		steps[i] = scenario.StepResult{Session: "Session A", Step: i + 1, Success: true}
+	}
+
+	run := Record(context.Background(), &scriptedScenario{steps: steps}, scenario.DefaultParams(), nil)
+	if run.Err != nil {
+		t.Fatalf("Expected no error, got %v", run.Err)
+	}
+	if len(run.Steps) != len(steps) {
+		t.Fatalf("Expected %d steps, got %d", len(steps), len(run.Steps))
+	}
+}
+
+// FuzzJSONWriter checks that any step text is written as one JSON line that reads back as the
+// step, invalid UTF-8 replaced as encoding/json does
+func FuzzJSONWriter(f *testing.F) {
+	for _, text := range scenariotest.Texts {
+		f.Add("Session A", text, text, text)
+	}
+	f.Fuzz(func(t *testing.T, session, description, query, result string) {
+		step := scenario.StepResult{Session: session, Step: 1, Description: description, Query: query, Result: result}
+		var b bytes.Buffer
+		if err := NewStepWriter(FormatJSON, &b).WriteStep(step); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if n := bytes.Count(b.Bytes(), []byte("\n")); n != 1 {
+			t.Fatalf("Expected one line, got %d", n)
+		}
+
+		var got scenario.StepResult
+		if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+			t.Fatalf("Expected valid JSON, got %v: %q", err, b.String())
+		}
+		// Converting to runes replaces each invalid byte, as the encoder does
+		valid := func(s string) string { return string([]rune(s)) }
+		want := scenario.StepResult{Session: valid(session), Step: 1, Description: valid(description), Query: valid(query), Result: valid(result)}
+		if got != want {
+			t.Fatalf("Expected %+v to read back, got %+v", want, got)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package headless
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/logging"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// recorder is a StepWriter that keeps every step of a run
+type recorder struct {
+	run *report.Run
+	tee StepWriter
+}
+
+// WriteStep implements StepWriter
+func (r recorder) WriteStep(step scenario.StepResult) error {
+	r.run.AddStep(step)
+	if r.tee != nil {
+		return r.tee.WriteStep(step)
+	}
+	return nil
+}
+
+// Record executes s and captures its steps, assertions, outcome and duration.
+// Steps are also passed to tee when it is not nil, so a run can be streamed and recorded at once;
+// a tee that is a RunObserver also hears when the run starts and finishes.
+func Record(ctx context.Context, s scenario.Scenario, params scenario.Params, tee StepWriter) *report.Run {
+	run := report.NewRun(s)
+	run.Seed = params.Seed
+
+	observer, _ := tee.(RunObserver)
+	if observer != nil {
+		if err := observer.RunStarted(run); err != nil {
+			run.Err = fmt.Errorf("failed to write run start: %w", err)
+			return run
+		}
+	}
+
+	var assertions scenario.Assertions
+	ctx = scenario.WithAssertions(ctx, &assertions)
+
+	outcome := execute(ctx, s, params, recorder{run: run, tee: tee})
+	run.Err = outcome.Err
+	run.CleanupErr = outcome.Cleanup
+	run.Duration = time.Since(run.Started)
+	run.Assertions = assertions.All()
+
+	if observer != nil {
+		if err := observer.RunFinished(run); err != nil && run.Err == nil {
+			run.Err = fmt.Errorf("failed to write run finish: %w", err)
+		}
+	}
+	return run
+}
+
+// RunSuite records every scenario of a started provider in order. Failed scenarios don't stop
+// the suite, and scenarios the provider lacks capabilities for are skipped; done is called after each.
+func RunSuite(ctx context.Context, p provider.Provider, params scenario.Params, done func(*report.Run)) *report.Suite {
+	suite := &report.Suite{
+		Provider:       p.Name(),
+		ConnectionInfo: logging.Redact(p.ConnectionInfo()),
+		Generated:      time.Now(),
+	}
+
+	for _, s := range p.GetScenarios().GetAll() {
+		var run *report.Run
+		if missing := p.Capabilities().Missing(scenario.Requirements(s)); len(missing) > 0 {
+			run = report.NewRun(s)
+			run.SkipReason = fmt.Sprintf("%s does not support %s", p.Name(), joinCapabilities(missing))
+		} else {
+			run = Record(ctx, s, params, nil)
+		}
+
+		suite.Runs = append(suite.Runs, run)
+		if done != nil {
+			done(run)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return suite
+}
+
+// joinCapabilities lists capabilities for messages
+func joinCapabilities(caps []scenario.Capability) string {
+	names := make([]string, len(caps))
+	for i, c := range caps {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
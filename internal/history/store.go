@@ -0,0 +1,201 @@
+// Package history persists data about past sessions, such as provider startup timings and
+// quiz scores, in a JSON file under the user's state directory.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+)
+
+const (
+	// MaxStarts is how many startups are kept per provider
+	MaxStarts = 20
+
+	// MaxQuizScores is how many quiz results are kept per scenario
+	MaxQuizScores = 20
+)
+
+// QuizScore is the result of answering a scenario's quiz once
+type QuizScore struct {
+	Scenario string    `json:"scenario"`
+	Correct  int       `json:"correct"`
+	Total    int       `json:"total"`
+	At       time.Time `json:"at"`
+}
+
+// data is the on-disk format
+type data struct {
+	Starts  []provider.StartupMetrics `json:"starts"`
+	Quizzes []QuizScore               `json:"quizzes,omitempty"`
+}
+
+// Store reads and writes the history file
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store backed by the file at path; the file is created on first write
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns $XDG_STATE_HOME/txviewer/history.json, falling back to ~/.local/state
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "txviewer", "history.json"), nil
+}
+
+// AddStart records a provider startup, keeping only the latest MaxStarts per provider
+func (s *Store) AddStart(m provider.StartupMetrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	d.Starts = append(d.Starts, m)
+
+	// Drop the oldest entries of this provider beyond the limit
+	count := 0
+	for i := len(d.Starts) - 1; i >= 0; i-- {
+		if d.Starts[i].Provider != m.Provider {
+			continue
+		}
+		count++
+		if count > MaxStarts {
+			d.Starts = append(d.Starts[:i], d.Starts[i+1:]...)
+		}
+	}
+
+	return s.save(d)
+}
+
+// Starts returns the recorded startups of a provider, oldest first
+func (s *Store) Starts(providerName string) ([]provider.StartupMetrics, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []provider.StartupMetrics
+	for _, m := range d.Starts {
+		if m.Provider == providerName {
+			starts = append(starts, m)
+		}
+	}
+	return starts, nil
+}
+
+// LastStarted returns the name of the provider started most recently, or "" before any start
+func (s *Store) LastStarted() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.load()
+	if err != nil || len(d.Starts) == 0 {
+		return "", err
+	}
+	return d.Starts[len(d.Starts)-1].Provider, nil
+}
+
+// AddQuizScore records a quiz result, keeping only the latest MaxQuizScores per scenario
+func (s *Store) AddQuizScore(score QuizScore) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	d.Quizzes = append(d.Quizzes, score)
+
+	count := 0
+	for i := len(d.Quizzes) - 1; i >= 0; i-- {
+		if d.Quizzes[i].Scenario != score.Scenario {
+			continue
+		}
+		count++
+		if count > MaxQuizScores {
+			d.Quizzes = append(d.Quizzes[:i], d.Quizzes[i+1:]...)
+		}
+	}
+
+	return s.save(d)
+}
+
+// QuizScores returns the recorded quiz results of a scenario, oldest first
+func (s *Store) QuizScores(scenarioName string) ([]QuizScore, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var scores []QuizScore
+	for _, q := range d.Quizzes {
+		if q.Scenario == scenarioName {
+			scores = append(scores, q)
+		}
+	}
+	return scores, nil
+}
+
+// load reads the history file; a missing file is empty history. The caller must hold s.mu.
+func (s *Store) load() (data, error) {
+	var d data
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return d, fmt.Errorf("failed to read history: %w", err)
+	}
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return d, fmt.Errorf("failed to parse history %s: %w", s.path, err)
+	}
+	return d, nil
+}
+
+// save writes the history file atomically; the caller must hold s.mu
+func (s *Store) save(d data) error {
+	raw, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+)
+
+func TestStore_Starts(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "nested", "history.json"))
+
+	starts, err := s.Starts("MongoDB")
+	if err != nil || len(starts) != 0 {
+		t.Fatalf("Expected empty history, got %v (%v)", starts, err)
+	}
+
+	for i := 0; i < MaxStarts+5; i++ {
+		if err := s.AddStart(provider.StartupMetrics{Provider: "MongoDB", Total: time.Duration(i) * time.Second}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := s.AddStart(provider.StartupMetrics{Provider: "PostgreSQL"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	starts, err = s.Starts("MongoDB")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(starts) != MaxStarts {
+		t.Fatalf("Expected %d starts, got %d", MaxStarts, len(starts))
+	}
+	if starts[0].Total != 5*time.Second || starts[MaxStarts-1].Total != time.Duration(MaxStarts+4)*time.Second {
+		t.Fatalf("Expected the oldest starts to be dropped, got first %s last %s", starts[0].Total, starts[MaxStarts-1].Total)
+	}
+
+	if others, _ := s.Starts("PostgreSQL"); len(others) != 1 {
+		t.Fatalf("Expected other providers to be kept, got %d", len(others))
+	}
+	if last, err := s.LastStarted(); err != nil || last != "PostgreSQL" {
+		t.Fatalf("Expected PostgreSQL started last, got %q (%v)", last, err)
+	}
+}
+
+func TestStore_QuizScores(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "history.json"))
+
+	for i := 0; i < MaxQuizScores+3; i++ {
+		if err := s.AddQuizScore(QuizScore{Scenario: "Dirty Read Prevention", Correct: i % 3, Total: 2}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := s.AddQuizScore(QuizScore{Scenario: "Snapshot Isolation", Correct: 2, Total: 2}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Quiz scores are kept next to the startups
+	if err := s.AddStart(provider.StartupMetrics{Provider: "MongoDB"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	scores, err := s.QuizScores("Dirty Read Prevention")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(scores) != MaxQuizScores {
+		t.Fatalf("Expected %d scores, got %d", MaxQuizScores, len(scores))
+	}
+	if others, _ := s.QuizScores("Snapshot Isolation"); len(others) != 1 {
+		t.Fatalf("Expected other scenarios to be kept, got %d", len(others))
+	}
+}
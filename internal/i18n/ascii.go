@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// ascii is set when text must be written in plain ASCII, e.g. over SSH to a terminal without a
+// UTF-8 locale or into CI logs
+var ascii atomic.Bool
+
+// asciiSubstitutes spells out the symbols the TUI and the scenarios use in ASCII
+var asciiSubstitutes = map[rune]string{
+	'·': ".",
+	'—': "-",
+	'•': "*",
+	'…': "...",
+	'←': "<-",
+	'→': "->",
+	'↑': "^",
+	'↓': "v",
+	'≠': "!=",
+	'▸': ">",
+	'✓': "OK",
+	'✗': "X",
+	'✅': "[OK]",
+	'❌': "[X]",
+	'❓': "[?]",
+	'⚠': "[!]",
+	'⏳': "[..]",
+	'⏱': "[t]",
+	'⏭': ">>",
+	'🎉': "**",
+
+	// Sparkline levels, lowest first
+	'▁': "_", '▂': ".", '▃': ",", '▄': "-", '▅': "=", '▆': "+", '▇': "*", '█': "#",
+
+	// Spinner frames
+	'⠋': "|", '⠙': "/", '⠹': "-", '⠸': "\\", '⠼': "|", '⠴': "/", '⠦': "-", '⠧': "\\", '⠇': "|", '⠏': "/",
+}
+
+// SetASCII turns ASCII mode on or off. It is meant to be set once at startup, like the language.
+func SetASCII(on bool) {
+	ascii.Store(on)
+}
+
+// ASCIIMode returns whether text is written in plain ASCII
+func ASCIIMode() bool {
+	return ascii.Load()
+}
+
+// Render prepares text for the terminal: in ASCII mode it is converted by ToASCII, otherwise it
+// is returned as is
+func Render(s string) string {
+	if !ascii.Load() {
+		return s
+	}
+	return ToASCII(s)
+}
+
+// ToASCII replaces emoji, arrows, box drawing and the other symbols of the UI with ASCII.
+// Symbols missing from the substitution table become *, while letters of other alphabets are
+// kept so translated text stays readable.
+func ToASCII(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch sub, ok := asciiSubstitutes[r]; {
+		case r <= unicode.MaxASCII:
+			b.WriteRune(r)
+		case ok:
+			b.WriteString(sub)
+		case r == '\u200d' || unicode.Is(unicode.Variation_Selector, r):
+			// Joiners and emoji presentation selectors only modify the symbol before them
+		case r >= '─' && r <= '╿':
+			b.WriteString(boxDrawing(r))
+		case unicode.IsSpace(r):
+			b.WriteByte(' ')
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('*')
+		}
+	}
+	return b.String()
+}
+
+// boxDrawing returns the ASCII for a box drawing character: lines become - and |, corners and
+// junctions become +
+func boxDrawing(r rune) string {
+	switch r {
+	case '─', '━', '═', '┄', '┅', '┈', '┉', '╌', '╍':
+		return "-"
+	case '│', '┃', '║', '┆', '┇', '┊', '┋', '╎', '╏':
+		return "|"
+	}
+	return "+"
+}
+
+// UTF8Locale reports whether the locale in the environment, looked up with lookup, can show
+// UTF-8. Like the C library, the first of LC_ALL, LC_CTYPE and LANG that is set decides; with none
+// set the locale is C, which is ASCII. Windows terminals don't use these variables.
+func UTF8Locale(lookup func(string) (string, bool)) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v, ok := lookup(key); ok && v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
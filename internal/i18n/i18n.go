@@ -0,0 +1,104 @@
+// Package i18n translates the narration of the scenarios and the labels of the TUI. Each language
+// is an embedded JSON catalog that maps the English text to its translation; English needs none.
+// Numbers in translated text follow the conventions of the selected language.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// DefaultLanguage is the language the text is written in
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var catalogs = mustLoad(locales)
+
+// printer formats text in the selected language
+var printer atomic.Pointer[message.Printer]
+
+func init() {
+	printer.Store(message.NewPrinter(language.English, message.Catalog(catalogs)))
+}
+
+// mustLoad builds a catalog from every locales/<lang>.json file
+func mustLoad(fsys fs.FS) *catalog.Builder {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	files, err := fs.Glob(fsys, "locales/*.json")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("invalid catalog %s: %v", file, err))
+		}
+		tag := language.MustParse(strings.TrimSuffix(path.Base(file), ".json"))
+		for key, msg := range messages {
+			if err := b.SetString(tag, key, msg); err != nil {
+				panic(fmt.Sprintf("invalid message %q in %s: %v", key, file, err))
+			}
+		}
+	}
+	return b
+}
+
+// Languages returns the codes of every available language, e.g. en and ru
+func Languages() []string {
+	langs := []string{DefaultLanguage}
+	for _, tag := range catalogs.Languages() {
+		langs = append(langs, tag.String())
+	}
+	slices.Sort(langs)
+	return slices.Compact(langs)
+}
+
+// SetLanguage selects the language of all text from now on, e.g. "ru" or "ru-RU"
+func SetLanguage(lang string) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return fmt.Errorf("unknown language %q (valid: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	base, _ := tag.Base()
+	if !slices.Contains(Languages(), base.String()) {
+		return fmt.Errorf("unsupported language %q (valid: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	printer.Store(message.NewPrinter(language.Make(base.String()), message.Catalog(catalogs)))
+	return nil
+}
+
+// T translates the English text key into the selected language and formats it with args like
+// fmt.Sprintf. Text without a translation is used as is.
+func T(key string, args ...any) string {
+	return printer.Load().Sprintf(key, args...)
+}
+
+// Money formats an amount of cents in dollars the way the selected language writes it, e.g.
+// $1,000.00 or -$5.00 in English. The dollars are grouped as integers and the cents appended,
+// so every amount formats exactly.
+func Money(cents int64) string {
+	p := printer.Load()
+	sign, abs := "", uint64(cents)
+	if cents < 0 {
+		sign, abs = "-", -abs
+	}
+	// Formatting the cents alone as a fraction, e.g. 0.05, gives the language's decimal separator
+	fraction := p.Sprintf("%.2f", float64(abs%100)/100)
+	return sign + T("$%s", p.Sprintf("%d", abs/100)+strings.TrimPrefix(fraction, "0"))
+}
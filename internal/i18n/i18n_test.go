@@ -0,0 +1,261 @@
+package i18n
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// verbs matches the formatting verbs of a message, ignoring escaped percent signs
+var verbs = regexp.MustCompile(`%(?:\[\d+\])?[-+# 0]*\d*(?:\.\d+)?[a-zA-Z]`)
+
+// sourceKeys returns every literal key passed to i18n.T in the module's non-test Go files
+func sourceKeys(t *testing.T) []string {
+	t.Helper()
+	var keys []string
+	err := filepath.WalkDir("../..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "T" {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+				return true
+			}
+			if key, ok := literal(call.Args[0]); ok {
+				keys = append(keys, key)
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan sources: %v", err)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// literal returns the value of a string literal or a concatenation of them
+func literal(e ast.Expr) (string, bool) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.BinaryExpr:
+		left, ok := literal(e.X)
+		if !ok || e.Op != token.ADD {
+			return "", false
+		}
+		right, ok := literal(e.Y)
+		return left + right, ok
+	}
+	return "", false
+}
+
+func TestCatalogs_TranslateEveryMessage(t *testing.T) {
+	keys := sourceKeys(t)
+	if len(keys) == 0 {
+		t.Fatal("Expected to find messages in the sources")
+	}
+
+	files, err := fs.Glob(locales, "locales/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Expected embedded catalogs, got %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+
+		for _, key := range keys {
+			msg, ok := messages[key]
+			if !ok {
+				t.Errorf("%s: missing translation of %q", file, key)
+				continue
+			}
+			if want, got := len(verbs.FindAllString(key, -1)), len(verbs.FindAllString(msg, -1)); want != got {
+				t.Errorf("%s: expected %d formatting verbs in the translation of %q, got %d", file, want, key, got)
+			}
+		}
+	}
+}
+
+func TestMoney_FormatsCents(t *testing.T) {
+	tests := map[int64]string{
+		0:             "$0.00",
+		1:             "$0.01",
+		29999:         "$299.99",
+		100000:        "$1,000.00",
+		123456789:     "$1,234,567.89",
+		-50000:        "-$500.00",
+		-5:            "-$0.05",
+		1<<53 - 1:     "$90,071,992,547,409.91",
+		1<<53 + 1:     "$90,071,992,547,409.93",
+		math.MaxInt64: "$92,233,720,368,547,758.07",
+		math.MinInt64: "-$92,233,720,368,547,758.08",
+	}
+	for cents, want := range tests {
+		if got := Money(cents); got != want {
+			t.Errorf("Expected %d cents to format as %s, got %s", cents, want, got)
+		}
+	}
+}
+
+func TestT_FormatsNumbersPerLanguage(t *testing.T) {
+	t.Cleanup(func() { _ = SetLanguage(DefaultLanguage) })
+
+	if got := Money(100000); got != "$1,000.00" {
+		t.Fatalf("Expected $1,000.00 in English, got %q", got)
+	}
+
+	if err := SetLanguage("ru-RU"); err != nil {
+		t.Fatalf("Expected ru-RU to select Russian, got %v", err)
+	}
+	// Russian groups digits with a no-break space
+	if got := Money(100000); got != "1\u00a0000,00 $" {
+		t.Fatalf("Expected 1 000,00 $ in Russian, got %q", got)
+	}
+	if got := Money(-5); got != "-0,05 $" {
+		t.Fatalf("Expected -0,05 $ in Russian, got %q", got)
+	}
+	if got := T("Transaction started"); got == "Transaction started" {
+		t.Fatal("Expected a Russian translation")
+	}
+
+	if err := SetLanguage("xx"); err == nil {
+		t.Fatal("Expected an error for an unsupported language")
+	}
+}
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"✅ Session B sees only committed data", "[OK] Session B sees only committed data"},
+		{"⚔️ Write conflict", "* Write conflict"},
+		{"↑/↓ navigate • enter run", "^/v navigate * enter run"},
+		{"╭──╮\n│ok│\n╰──╯", "+--+\n|ok|\n+--+"},
+		{"▁▄█ ⠋", "_-# |"},
+		{"1\u00a0000,00 $", "1 000,00 $"},
+		{"Сессия A", "Сессия A"},
+	}
+	for _, tt := range tests {
+		if got := ToASCII(tt.in); got != tt.want {
+			t.Errorf("Expected %q to become %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
+
+// FuzzToASCII checks that whatever a step holds, ASCII mode leaves only ASCII and letters, marks
+// and digits of other alphabets
+func FuzzToASCII(f *testing.F) {
+	for _, s := range []string{
+		"⚔️ Write conflict", "🛡️ Session A's withdrawal prevented", "👨‍👩‍👧‍👦 family", "╭──╮\n│ok│",
+		"Сессия A", "مرحبا ‏שלום", "\x1b[31mred\x1b[0m", "\xff\xfe\xc3(",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		got := ToASCII(s)
+		if !utf8.ValidString(got) {
+			t.Fatalf("Expected valid UTF-8, got %q", got)
+		}
+		for _, r := range got {
+			if r > unicode.MaxASCII && !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) {
+				t.Fatalf("Expected no symbols outside ASCII, got %q in %q", r, got)
+			}
+		}
+		if len(got) > 2*len(s) {
+			t.Fatalf("Expected at most twice the length, got %d bytes from %d", len(got), len(s))
+		}
+		if again := ToASCII(got); again != got {
+			t.Fatalf("Expected converting twice to change nothing, got %q then %q", got, again)
+		}
+	})
+}
+
+func TestTruncate_KeepsGraphemesWhole(t *testing.T) {
+	tests := []struct {
+		in    string
+		ascii bool
+		want  string
+	}{
+		{"👨‍👩‍👧 family", false, "👨‍👩‍👧 fami…"},
+		{"⚔️ Session", false, "⚔️ Sess…"},
+		{"short", false, "short"},
+		{"⚔️ Session", true, "* Ses..."},
+	}
+	for _, tt := range tests {
+		SetASCII(tt.ascii)
+		got := Truncate(tt.in, 8)
+		SetASCII(false)
+		if got != tt.want {
+			t.Errorf("Expected %q to become %q, got %q", tt.in, tt.want, got)
+		}
+	}
+
+	SetASCII(true)
+	defer SetASCII(false)
+	if w := Width(Pad("✅ ok", 10)); w != 10 {
+		t.Fatalf("Expected padding to 10 columns in ASCII mode, got %d", w)
+	}
+}
+
+func TestUTF8Locale(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{"LANG": "en_US.UTF-8"}, true},
+		{map[string]string{"LANG": "ru_RU.utf8"}, true},
+		{map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "C"}, false},
+		{map[string]string{"LANG": "C", "LC_CTYPE": "C.UTF-8"}, true},
+		{map[string]string{"LANG": "POSIX"}, false},
+		{map[string]string{}, false},
+	}
+	for _, tt := range tests {
+		lookup := func(key string) (string, bool) {
+			v, ok := tt.env[key]
+			return v, ok
+		}
+		if got := UTF8Locale(lookup); got != tt.want {
+			t.Errorf("Expected UTF-8 %v for %v, got %v", tt.want, tt.env, got)
+		}
+	}
+}
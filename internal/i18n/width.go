@@ -0,0 +1,26 @@
+package i18n
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Width returns the columns s takes on the terminal once rendered: graphemes rather than runes
+// are counted, emoji and other wide characters take two columns, escape codes none, and in ASCII
+// mode the substitutes are measured instead of the symbols they replace
+func Width(s string) int {
+	return ansi.StringWidth(Render(s))
+}
+
+// Pad renders s and pads it with spaces to width columns; wider text is left as is
+func Pad(s string, width int) string {
+	s = Render(s)
+	return s + strings.Repeat(" ", max(width-ansi.StringWidth(s), 0))
+}
+
+// Truncate renders s and cuts it to at most width columns, ending in an ellipsis when cut. Whole
+// graphemes are dropped, so an emoji sequence is never split.
+func Truncate(s string, width int) string {
+	return ansi.Truncate(Render(s), width, Render("…"))
+}
@@ -0,0 +1,72 @@
+package imagepull
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// layerTracker folds the daemon's per-layer pull messages into one overall fraction.
+// Downloading counts for the first half of a layer and extracting for the second.
+type layerTracker struct {
+	order  []string
+	layers map[string]float64
+	status string
+}
+
+// newLayerTracker creates an empty tracker
+func newLayerTracker() *layerTracker {
+	return &layerTracker{layers: make(map[string]float64)}
+}
+
+// Update records one message from the pull stream
+func (t *layerTracker) Update(msg jsonmessage.JSONMessage) {
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from") {
+		// Image-level status such as "Digest: ..." or "Pulling from library/mongo"
+		if msg.Status != "" {
+			t.status = msg.Status
+		}
+		return
+	}
+
+	if _, ok := t.layers[msg.ID]; !ok {
+		t.order = append(t.order, msg.ID)
+		t.layers[msg.ID] = 0
+	}
+
+	var fraction float64
+	if msg.Progress != nil && msg.Progress.Total > 0 {
+		fraction = float64(msg.Progress.Current) / float64(msg.Progress.Total)
+	}
+
+	switch msg.Status {
+	case "Downloading":
+		t.layers[msg.ID] = fraction / 2
+	case "Verifying Checksum", "Download complete":
+		t.layers[msg.ID] = 0.5
+	case "Extracting":
+		t.layers[msg.ID] = 0.5 + fraction/2
+	case "Pull complete", "Already exists":
+		t.layers[msg.ID] = 1
+	}
+
+	t.status = fmt.Sprintf("%s %s", msg.Status, msg.ID)
+}
+
+// Fraction returns overall progress across every layer seen so far
+func (t *layerTracker) Fraction() float64 {
+	if len(t.layers) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, f := range t.layers {
+		sum += f
+	}
+	return sum / float64(len(t.layers))
+}
+
+// Status returns the latest status line
+func (t *layerTracker) Status() string {
+	return t.status
+}
@@ -0,0 +1,32 @@
+package imagepull
+
+import (
+	"testing"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+func TestLayerTracker(t *testing.T) {
+	tr := newLayerTracker()
+	if tr.Fraction() != 0 {
+		t.Fatalf("Expected 0 for no layers, got %v", tr.Fraction())
+	}
+
+	tr.Update(jsonmessage.JSONMessage{ID: "7.0", Status: "Pulling from library/mongo"})
+	tr.Update(jsonmessage.JSONMessage{ID: "a", Status: "Already exists"})
+	tr.Update(jsonmessage.JSONMessage{ID: "b", Status: "Downloading", Progress: &jsonmessage.JSONProgress{Current: 50, Total: 100}})
+
+	// a is complete, b is halfway through downloading (a quarter of its work)
+	if got := tr.Fraction(); got != 0.625 {
+		t.Fatalf("Expected 0.625, got %v", got)
+	}
+
+	tr.Update(jsonmessage.JSONMessage{ID: "b", Status: "Extracting", Progress: &jsonmessage.JSONProgress{Current: 100, Total: 100}})
+	tr.Update(jsonmessage.JSONMessage{ID: "b", Status: "Pull complete"})
+	if got := tr.Fraction(); got != 1 {
+		t.Fatalf("Expected 1, got %v", got)
+	}
+	if tr.Status() != "Pull complete b" {
+		t.Fatalf("Expected last layer status, got %q", tr.Status())
+	}
+}
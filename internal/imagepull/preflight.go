@@ -0,0 +1,95 @@
+package imagepull
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// registryProbeTimeout bounds the reachability check, far below the daemon's pull timeout
+const registryProbeTimeout = 3 * time.Second
+
+// OfflineError reports an image that is missing locally while its registry can't be reached
+type OfflineError struct {
+	Image    string
+	Registry string
+	Err      error
+}
+
+// Error names the exact image so users can pull it once they are back online
+func (e *OfflineError) Error() string {
+	return fmt.Sprintf("image %s is not present locally and the registry %s is unreachable — connect to the network or pre-pull the image with: docker pull %s",
+		e.Image, e.Registry, e.Image)
+}
+
+// Unwrap returns the registry probe failure
+func (e *OfflineError) Unwrap() error {
+	return e.Err
+}
+
+// probeFunc checks whether a registry host answers at all
+type probeFunc func(ctx context.Context, host string) error
+
+// Preflight fails fast with an *OfflineError when ref would have to be pulled from a registry
+// that can't be reached, instead of waiting for the pull to time out
+func Preflight(ctx context.Context, ref string) error {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer cli.Close()
+
+	return preflight(ctx, cli, ref, probeRegistry)
+}
+
+// preflight returns nil when the image is cached or its registry answers
+func preflight(ctx context.Context, cli dockerClient, ref string, probe probeFunc) error {
+	if _, err := cli.ImageInspect(ctx, ref); err == nil {
+		return nil
+	}
+
+	host, err := registryHost(ref)
+	if err != nil {
+		// Leave invalid references for the pull to report
+		return nil
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, registryProbeTimeout)
+	defer cancel()
+	if err := probe(probeCtx, host); err != nil {
+		return &OfflineError{Image: ref, Registry: host, Err: err}
+	}
+	return nil
+}
+
+// registryHost returns the registry API host serving ref
+func registryHost(ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", err
+	}
+	host := reference.Domain(named)
+	if host == "docker.io" {
+		// Docker Hub's API lives on a different host than its name
+		return "registry-1.docker.io", nil
+	}
+	return host, nil
+}
+
+// probeRegistry sends a HEAD to the registry API root; any HTTP response, including 401, means it is reachable
+func probeRegistry(ctx context.Context, host string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://"+host+"/v2/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,51 @@
+package imagepull
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// fakeClient has a fixed set of local images and never pulls
+type fakeClient struct {
+	local map[string]bool
+}
+
+func (f fakeClient) ImageInspect(ctx context.Context, ref string, opts ...client.ImageInspectOption) (image.InspectResponse, error) {
+	if f.local[ref] {
+		return image.InspectResponse{}, nil
+	}
+	return image.InspectResponse{}, errors.New("No such image: " + ref)
+}
+
+func (f fakeClient) ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestPreflight(t *testing.T) {
+	offline := func(ctx context.Context, host string) error { return errors.New("dial tcp: no route to host") }
+	cli := fakeClient{local: map[string]bool{"mongo:7.0": true}}
+
+	// Cached images never touch the network
+	if err := preflight(context.Background(), cli, "mongo:7.0", offline); err != nil {
+		t.Fatalf("Expected cached image to pass, got %v", err)
+	}
+
+	err := preflight(context.Background(), cli, "mongo:8.0", offline)
+	var offlineErr *OfflineError
+	if !errors.As(err, &offlineErr) {
+		t.Fatalf("Expected OfflineError, got %v", err)
+	}
+	if offlineErr.Image != "mongo:8.0" || offlineErr.Registry != "registry-1.docker.io" {
+		t.Fatalf("Unexpected error details: %+v", offlineErr)
+	}
+
+	online := func(ctx context.Context, host string) error { return nil }
+	if err := preflight(context.Background(), cli, "ghcr.io/acme/mongo:7", online); err != nil {
+		t.Fatalf("Expected reachable registry to pass, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Package imagepull pre-pulls container images through the Docker API so that
+// per-layer download progress can be shown while providers are not yet started.
+package imagepull
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Progress is a snapshot of one image's pull
+type Progress struct {
+	Image    string
+	Fraction float64 // 0..1 across all layers
+	Status   string  // Latest status line reported by the daemon
+	Done     bool
+	Err      error
+}
+
+// dockerClient is the part of the Docker API used for pulling
+type dockerClient interface {
+	ImageInspect(ctx context.Context, imageID string, opts ...client.ImageInspectOption) (image.InspectResponse, error)
+	ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error)
+}
+
+// PullAll pulls images in parallel, sending progress to updates and closing it when every pull has finished.
+// Images already present locally are reported complete immediately. Cancelling ctx aborts the pulls;
+// layers that finished downloading stay in the daemon, so calling PullAll again resumes from there.
+func PullAll(ctx context.Context, images []string, updates chan<- Progress) {
+	defer close(updates)
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to connect to Docker: %w", err)
+		for _, ref := range images {
+			updates <- Progress{Image: ref, Done: true, Err: err}
+		}
+		return
+	}
+	defer cli.Close()
+
+	pullAll(ctx, cli, images, updates)
+}
+
+// Ensure pulls ref unless it is already present, discarding progress
+func Ensure(ctx context.Context, ref string) error {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer cli.Close()
+
+	updates := make(chan Progress)
+	go func() {
+		for range updates {
+		}
+	}()
+	defer close(updates)
+
+	return pull(ctx, cli, ref, updates)
+}
+
+// pullAll runs one pull per image and waits for all of them
+func pullAll(ctx context.Context, cli dockerClient, images []string, updates chan<- Progress) {
+	var wg sync.WaitGroup
+	for _, ref := range images {
+		wg.Add(1)
+		go func(ref string) {
+			defer wg.Done()
+			err := pull(ctx, cli, ref, updates)
+			updates <- Progress{Image: ref, Fraction: fractionFor(err), Status: statusFor(err), Done: true, Err: err}
+		}(ref)
+	}
+	wg.Wait()
+}
+
+// pull fetches a single image unless it is already present
+func pull(ctx context.Context, cli dockerClient, ref string, updates chan<- Progress) error {
+	if _, err := cli.ImageInspect(ctx, ref); err == nil {
+		return nil
+	}
+
+	reader, err := cli.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %w", ref, err)
+	}
+	defer reader.Close()
+
+	tracker := newLayerTracker()
+	decoder := json.NewDecoder(reader)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read pull progress for %s: %w", ref, err)
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("failed to pull %s: %w", ref, msg.Error)
+		}
+
+		tracker.Update(msg)
+		updates <- Progress{Image: ref, Fraction: tracker.Fraction(), Status: tracker.Status()}
+	}
+}
+
+// fractionFor returns the final fraction for a finished pull
+func fractionFor(err error) float64 {
+	if err != nil {
+		return 0
+	}
+	return 1
+}
+
+// statusFor returns the final status line for a finished pull
+func statusFor(err error) string {
+	switch {
+	case err == nil:
+		return "Ready"
+	case errors.Is(err, context.Canceled):
+		return "Cancelled"
+	}
+	return "Failed"
+}
@@ -0,0 +1,93 @@
+// Package logging writes structured diagnostics to a file, since the TUI's alt screen hides stderr
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultPath returns where the log is written by default: $XDG_STATE_HOME/txviewer/txviewer.log,
+// falling back to ~/.local/state when XDG_STATE_HOME is unset
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "txviewer", "txviewer.log"), nil
+}
+
+// ParseLevel parses a level name: debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", name)
+	}
+	return level, nil
+}
+
+// Open appends to the log file at path, creating its directory, and returns a logger that writes
+// records at level and above. Connection string credentials are redacted from every record.
+func Open(path string, level slog.Level) (*slog.Logger, io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return New(f, level), f, nil
+}
+
+// New returns a logger that writes redacted text records at level and above to w
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	}))
+}
+
+// Discard returns a logger that drops every record, for when the log file can't be opened
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+var (
+	// credentials matches the user:password part of a URI, e.g. mongodb://, postgres:// or mysql://
+	credentials = regexp.MustCompile(`://([^:@/\s]+):([^@/\s]+)@`)
+
+	// dsnCredentials matches the user:password part of a Go MySQL DSN, e.g. root:secret@tcp(db:3306)/
+	dsnCredentials = regexp.MustCompile(`(^|\s)([^:@/\s]+):([^@/\s]+)@(tcp|unix)\(`)
+
+	// passwordKey matches a password in key=value form, e.g. SQL Server's Password=secret; or
+	// Postgres' password=secret and a URI's ?password=secret
+	passwordKey = regexp.MustCompile(`(?i)\b(password|pwd)=([^;&\s]+)`)
+)
+
+// Redact hides the passwords of connection strings in s
+func Redact(s string) string {
+	if strings.Contains(s, "@") {
+		s = credentials.ReplaceAllString(s, "://$1:xxxxx@")
+		s = dsnCredentials.ReplaceAllString(s, "$1$2:xxxxx@$4(")
+	}
+	return passwordKey.ReplaceAllString(s, "$1=xxxxx")
+}
+
+// redactAttr redacts string and error values before they are written
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	switch v := a.Value.Any().(type) {
+	case string:
+		a.Value = slog.StringValue(Redact(v))
+	case error:
+		a.Value = slog.StringValue(Redact(v.Error()))
+	}
+	return a
+}
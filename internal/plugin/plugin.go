@@ -0,0 +1,241 @@
+// Package plugin runs scenarios implemented by external executables.
+//
+// A plugin is invoked once per phase with a JSON Request on stdin. For "describe" it prints a
+// Descriptor; for "setup" and "cleanup" only its exit status matters; for "run" it prints one
+// scenario.StepResult per line until it exits. A line of the form {"assertion": {...}} records
+// an assertion instead of a step.
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Phases a plugin is invoked for
+const (
+	PhaseDescribe = "describe"
+	PhaseSetup    = "setup"
+	PhaseRun      = "run"
+	PhaseCleanup  = "cleanup"
+)
+
+const (
+	// describeTimeout bounds the describe call made for every plugin at startup
+	describeTimeout = 5 * time.Second
+
+	// phaseTimeout bounds setup and cleanup
+	phaseTimeout = 30 * time.Second
+
+	// DefaultRunTimeout bounds a run when the descriptor doesn't set timeout_seconds
+	DefaultRunTimeout = 5 * time.Minute
+
+	// waitDelay is how long a plugin may keep its output open after being killed
+	waitDelay = 2 * time.Second
+
+	// maxLine is the longest output line accepted from a plugin
+	maxLine = 1 << 20
+
+	// stderrTail is how much of a plugin's stderr is kept for error messages
+	stderrTail = 2048
+)
+
+// Request is written to a plugin's stdin
+type Request struct {
+	Phase string `json:"phase"`
+	provider.Endpoint
+	Seed     int64 `json:"seed"`
+	PacingMS int64 `json:"pacing_ms"` // Suggested pause between steps
+}
+
+// Descriptor is what a plugin prints for the describe phase
+type Descriptor struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	IsolationLevel string   `json:"isolation_level"`
+	Provider       string   `json:"provider"` // Provider the scenario runs against, e.g. "MongoDB"
+	Tags           []string `json:"tags,omitempty"`
+	Requires       []string `json:"requires,omitempty"` // Capability names, e.g. "Multi-document transactions"
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+}
+
+// line is one line of run output: a step, or an assertion when Assertion is set
+type line struct {
+	scenario.StepResult
+	Assertion *scenario.Assertion `json:"assertion,omitempty"`
+}
+
+// Plugin is an executable and the scenario it describes
+type Plugin struct {
+	Path string
+	Descriptor
+}
+
+// Discover describes every executable in dir, in name order. A missing directory has no plugins.
+// Plugins that fail to describe themselves are skipped and reported in the returned error.
+func Discover(ctx context.Context, dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	var (
+		plugins []Plugin
+		errs    []error
+	)
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+
+		d, err := Describe(ctx, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plugins = append(plugins, Plugin{Path: path, Descriptor: d})
+	}
+	return plugins, errors.Join(errs...)
+}
+
+// Describe asks the plugin at path for its descriptor
+func Describe(ctx context.Context, path string) (Descriptor, error) {
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	if err := invoke(ctx, path, Request{Phase: PhaseDescribe}, &stdout, nil); err != nil {
+		return Descriptor{}, err
+	}
+
+	var d Descriptor
+	if err := json.Unmarshal(stdout.Bytes(), &d); err != nil {
+		return Descriptor{}, fmt.Errorf("plugin %s: malformed descriptor: %w", filepath.Base(path), err)
+	}
+	if d.Name == "" || d.Provider == "" {
+		return Descriptor{}, fmt.Errorf("plugin %s: descriptor needs a name and a provider", filepath.Base(path))
+	}
+	return d, nil
+}
+
+// invoke runs the plugin for one phase, writing req to its stdin and its stdout to stdout.
+// When lines is set, it is called for every line of output instead and may stop the plugin by
+// returning an error.
+func invoke(ctx context.Context, path string, req Request, stdout io.Writer, lines func(n int, text []byte) error) error {
+	name := filepath.Base(path)
+	input, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("plugin %s: failed to encode request: %w", name, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	stderr := &tailBuffer{max: stderrTail}
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+
+	var pipe io.ReadCloser
+	if lines != nil {
+		if pipe, err = cmd.StdoutPipe(); err != nil {
+			return fmt.Errorf("plugin %s: %w", name, err)
+		}
+	} else {
+		cmd.Stdout = stdout
+	}
+
+	log := slog.Default().With("plugin", name, "phase", req.Phase)
+	log.DebugContext(ctx, "plugin started")
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start plugin %s: %w", name, err)
+	}
+
+	var lineErr error
+	if lines != nil {
+		scanner := bufio.NewScanner(pipe)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+		n := 0
+		for scanner.Scan() {
+			n++
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			if lineErr = lines(n, scanner.Bytes()); lineErr != nil {
+				break
+			}
+		}
+		if lineErr == nil {
+			if err := scanner.Err(); err != nil {
+				lineErr = fmt.Errorf("plugin %s: line %d: %w", name, n+1, err)
+			}
+		}
+		if lineErr != nil {
+			// Stop the plugin rather than wait for output nobody reads
+			cancel()
+			_, _ = io.Copy(io.Discard, pipe)
+		}
+	}
+
+	err = cmd.Wait()
+	switch {
+	case lineErr != nil:
+		log.WarnContext(ctx, "plugin output rejected", "error", lineErr)
+		return lineErr
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		log.WarnContext(ctx, "plugin timed out")
+		return fmt.Errorf("plugin %s timed out%s", name, stderr.suffix())
+	case ctx.Err() != nil:
+		return fmt.Errorf("plugin %s: %w", name, ctx.Err())
+	case err != nil:
+		log.WarnContext(ctx, "plugin failed", "error", err, "stderr", stderr.String())
+		return fmt.Errorf("plugin %s failed: %w%s", name, err, stderr.suffix())
+	}
+	log.DebugContext(ctx, "plugin finished")
+	return nil
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return strings.TrimSpace(string(b.buf))
+}
+
+// suffix formats the captured stderr for the end of an error message
+func (b *tailBuffer) suffix() string {
+	if s := b.String(); s != "" {
+		return ": " + s
+	}
+	return ""
+}
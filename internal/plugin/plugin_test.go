@@ -0,0 +1,124 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// writePlugin writes a shell script plugin into dir
+func writePlugin(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins in tests are shell scripts")
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	return path
+}
+
+// endpoint is a provider endpoint for tests
+func endpoint() (provider.Endpoint, error) {
+	return provider.Endpoint{Provider: "MongoDB", URI: "mongodb://localhost:27017"}, nil
+}
+
+// run runs a plugin scenario and returns its steps and error
+func run(t *testing.T, s *PluginScenario) ([]scenario.StepResult, *scenario.Assertions, error) {
+	t.Helper()
+	assertions := &scenario.Assertions{}
+	ctx := scenario.WithAssertions(context.Background(), assertions)
+	output := make(chan scenario.StepResult)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(output)
+		errc <- s.Run(ctx, output)
+	}()
+
+	var steps []scenario.StepResult
+	for step := range output {
+		steps = append(steps, step)
+	}
+	return steps, assertions, <-errc
+}
+
+func TestDiscoverAndRun(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "orders", `read req
+case "$req" in
+*'"phase":"describe"'*)
+  echo '{"name":"Order Invariant","provider":"MongoDB","isolation_level":"Snapshot"}' ;;
+*'"phase":"run"'*)
+  case "$req" in *'"uri":"mongodb://localhost:27017"'*) ;; *) exit 1 ;; esac
+  echo '{"session":"Session A","step":1,"description":"Placing order","success":true}'
+  echo
+  echo '{"assertion":{"name":"stock never negative","held":true}}' ;;
+esac
+`)
+	// Not executable, so not a plugin
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := Discover(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(plugins) != 1 || plugins[0].Name != "Order Invariant" {
+		t.Fatalf("Expected the orders plugin, got %+v", plugins)
+	}
+
+	s := NewPluginScenario(plugins[0], endpoint)
+	steps, assertions, err := run(t, s)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(steps) != 1 || steps[0].Description != "Placing order" {
+		t.Fatalf("Expected one step, got %+v", steps)
+	}
+	if all := assertions.All(); len(all) != 1 || !all[0].Held {
+		t.Fatalf("Expected one held assertion, got %+v", all)
+	}
+}
+
+func TestRun_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "malformed output",
+			body: "echo '{\"step\":1,\"description\":\"ok\"}'\necho 'not json'\nexec sleep 10\n",
+			want: "line 2: malformed output",
+		},
+		{
+			name: "crash",
+			body: "echo 'connection refused' >&2\nexit 3\n",
+			want: "exit status 3: connection refused",
+		},
+		{
+			name: "timeout",
+			body: "exec sleep 10\n",
+			want: "timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writePlugin(t, t.TempDir(), "plugin", tt.body)
+			s := NewPluginScenario(Plugin{Path: path, Descriptor: Descriptor{Name: tt.name, TimeoutSeconds: 1}}, endpoint)
+			_, _, err := run(t, s)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
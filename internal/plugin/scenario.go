@@ -0,0 +1,138 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Compile-time interface checks
+var (
+	_ scenario.Scenario  = (*PluginScenario)(nil)
+	_ scenario.Requirer  = (*PluginScenario)(nil)
+	_ scenario.Describer = (*PluginScenario)(nil)
+)
+
+// PluginScenario runs a plugin against the endpoint of its provider
+type PluginScenario struct {
+	plugin   Plugin
+	endpoint func() (provider.Endpoint, error)
+}
+
+// NewPluginScenario creates a scenario that runs plugin against the endpoint returned by endpoint
+func NewPluginScenario(plugin Plugin, endpoint func() (provider.Endpoint, error)) *PluginScenario {
+	return &PluginScenario{plugin: plugin, endpoint: endpoint}
+}
+
+func (s *PluginScenario) Name() string {
+	return s.plugin.Name
+}
+
+func (s *PluginScenario) Description() string {
+	if s.plugin.Description == "" {
+		return fmt.Sprintf("Plugin scenario from %s", s.plugin.Path)
+	}
+	return s.plugin.Description
+}
+
+func (s *PluginScenario) IsolationLevel() string {
+	if s.plugin.IsolationLevel == "" {
+		return "Custom"
+	}
+	return s.plugin.IsolationLevel
+}
+
+func (s *PluginScenario) Requires() []scenario.Capability {
+	caps := make([]scenario.Capability, len(s.plugin.Requires))
+	for i, c := range s.plugin.Requires {
+		caps[i] = scenario.Capability(c)
+	}
+	return caps
+}
+
+// Metadata tags the scenario as coming from a plugin. Its time limit leaves room for setup and
+// the run, so the plugin's own run timeout is what stops a slow plugin.
+func (s *PluginScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{
+		Tags:    append([]string{scenario.TagPlugin}, s.plugin.Tags...),
+		Timeout: phaseTimeout + s.runTimeout() + phaseTimeout,
+	}
+}
+
+// runTimeout returns how long the run phase may take
+func (s *PluginScenario) runTimeout() time.Duration {
+	if s.plugin.TimeoutSeconds > 0 {
+		return time.Duration(s.plugin.TimeoutSeconds) * time.Second
+	}
+	return DefaultRunTimeout
+}
+
+func (s *PluginScenario) Setup(ctx context.Context) error {
+	return s.phase(ctx, PhaseSetup)
+}
+
+func (s *PluginScenario) Cleanup(ctx context.Context) error {
+	return s.phase(ctx, PhaseCleanup)
+}
+
+// phase runs a phase whose output is ignored
+func (s *PluginScenario) phase(ctx context.Context, phase string) error {
+	req, err := s.request(ctx, phase)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, phaseTimeout)
+	defer cancel()
+	return invoke(ctx, s.plugin.Path, req, io.Discard, nil)
+}
+
+func (s *PluginScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	req, err := s.request(ctx, PhaseRun)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.runTimeout())
+	defer cancel()
+
+	name := filepath.Base(s.plugin.Path)
+	return invoke(ctx, s.plugin.Path, req, nil, func(n int, text []byte) error {
+		var l line
+		if err := json.Unmarshal(text, &l); err != nil {
+			return fmt.Errorf("plugin %s: line %d: malformed output: %w", name, n, err)
+		}
+		if l.Assertion != nil {
+			scenario.Assert(ctx, l.Assertion.Name, l.Assertion.Held, l.Assertion.Detail)
+			return nil
+		}
+
+		select {
+		case output <- l.StepResult:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("plugin %s: %w", name, ctx.Err())
+		}
+	})
+}
+
+// request builds the stdin of a phase from the provider's endpoint and the run's params
+func (s *PluginScenario) request(ctx context.Context, phase string) (Request, error) {
+	endpoint, err := s.endpoint()
+	if err != nil {
+		return Request{}, err
+	}
+	params := scenario.ParamsFromContext(ctx)
+	return Request{
+		Phase:    phase,
+		Endpoint: endpoint,
+		Seed:     params.Seed,
+		PacingMS: params.Pacing.Milliseconds(),
+	}, nil
+}
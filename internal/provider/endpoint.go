@@ -0,0 +1,14 @@
+package provider
+
+// Endpoint tells programs outside txviewer, such as scenario plugins, how to reach a running provider
+type Endpoint struct {
+	Provider string `json:"provider"`
+	URI      string `json:"uri"`
+	Database string `json:"database,omitempty"`
+}
+
+// EndpointSource is implemented by providers that can hand their connection to other programs
+type EndpointSource interface {
+	// Endpoint returns the connection of the running provider, failing until it has started
+	Endpoint() (Endpoint, error)
+}
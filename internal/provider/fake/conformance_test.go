@@ -0,0 +1,39 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/providertest"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/scenariotest"
+)
+
+func TestProvider_Conforms(t *testing.T) {
+	providertest.Conformance(t, func() provider.Provider { return NewProvider() })
+}
+
+func TestScenarios_Conform(t *testing.T) {
+	startDelay = 0
+	p := NewProvider()
+	ctx := context.Background()
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("Expected Start to succeed, got %v", err)
+	}
+	defer p.Stop(ctx)
+
+	opts := []scenariotest.Option{
+		scenariotest.WithCapabilities(p.Capabilities()),
+		scenariotest.WithParams(p.ScenarioParams()),
+		scenariotest.WithLeftovers(func(ctx context.Context, s scenario.Scenario) ([]string, error) {
+			store, err := p.handle()
+			if err != nil {
+				return nil, err
+			}
+			return store.Keys(), nil
+		}),
+	}
+	scenariotest.Conformance(t, func() scenario.Scenario { return NewDirtyReadScenario(p.handle) }, opts...)
+	scenariotest.Conformance(t, func() scenario.Scenario { return NewLostUpdateScenario(p.handle) }, opts...)
+}
@@ -0,0 +1,190 @@
+// Package fake provides a database that lives in memory, for demos and UI work on machines
+// without Docker. It implements every optional provider interface that doesn't need a real
+// server, so it doubles as the reference for writing a provider.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Compile-time interface checks
+var (
+	_ provider.Provider     = (*Provider)(nil)
+	_ provider.Configurable = (*Provider)(nil)
+	_ provider.ParamSource  = (*Provider)(nil)
+	_ provider.RuntimeUser  = (*Provider)(nil)
+)
+
+// Pacings are the choices of the pacing setting, by name
+var Pacings = map[string]time.Duration{
+	"none":   0,
+	"normal": scenario.DefaultPacing,
+	"slow":   2 * scenario.DefaultPacing,
+}
+
+// pacingNames lists the pacings in display order
+var pacingNames = []string{"none", "normal", "slow"}
+
+// Overridable for tests
+var startDelay = 300 * time.Millisecond
+
+// Provider is a provider.Provider whose database is an in-process Store
+type Provider struct {
+	scenarios *scenario.Registry
+
+	mu     sync.Mutex
+	store  *Store // nil until started
+	params scenario.Params
+	pacing string
+}
+
+// NewProvider creates a fake provider with its scenarios registered
+func NewProvider() *Provider {
+	p := &Provider{
+		scenarios: scenario.NewRegistry(),
+		params:    scenario.DefaultParams(),
+		pacing:    "normal",
+	}
+	// Label a default pacing changed with scenario.SetDefaultPacing by the choice it matches
+	for _, name := range pacingNames {
+		if Pacings[name] == p.params.Pacing {
+			p.pacing = name
+		}
+	}
+	p.scenarios.Register(NewDirtyReadScenario(p.handle))
+	p.scenarios.Register(NewLostUpdateScenario(p.handle))
+	return p
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Fake"
+}
+
+// Description returns the provider description
+func (p *Provider) Description() string {
+	return i18n.T("In-memory store with snapshot transactions, for demos without Docker")
+}
+
+// Start creates an empty store, reporting its stages like a container start would. A running
+// provider keeps its store.
+func (p *Provider) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.store != nil {
+		return nil
+	}
+
+	provider.EnterPhase(ctx, provider.PhaseCreate)
+	provider.ReportProgress(ctx, i18n.T("Creating the in-memory store..."))
+	select {
+	case <-time.After(startDelay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	// Without a delay both cases are ready at once; a cancelled start still creates nothing
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	provider.EnterPhase(ctx, provider.PhaseReady)
+	p.store = NewStore()
+	return nil
+}
+
+// Stop discards the store and everything in it
+func (p *Provider) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.store = nil
+	return nil
+}
+
+// IsRunning returns whether the store exists
+func (p *Provider) IsRunning() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.store != nil
+}
+
+// GetScenarios returns the scenario registry
+func (p *Provider) GetScenarios() *scenario.Registry {
+	return p.scenarios
+}
+
+// Capabilities returns what the store supports once running
+func (p *Provider) Capabilities() scenario.CapabilitySet {
+	if !p.IsRunning() {
+		return scenario.NewCapabilitySet()
+	}
+	return scenario.NewCapabilitySet(scenario.CapMultiDocumentTransactions, scenario.CapSnapshotReads)
+}
+
+// ConnectionInfo describes the store, or returns "" when stopped
+func (p *Provider) ConnectionInfo() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.store == nil {
+		return ""
+	}
+	return i18n.T("in-memory store (%d keys)", p.store.Len())
+}
+
+// NeedsRuntime returns false: nothing runs in a container
+func (p *Provider) NeedsRuntime() bool {
+	return false
+}
+
+// Settings returns the options shown before the provider is started
+func (p *Provider) Settings() []provider.Setting {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return []provider.Setting{
+		{
+			Key:         "pacing",
+			Name:        i18n.T("Pacing"),
+			Description: i18n.T("pause between steps: none / 0.5s / 1s"),
+			Choices:     pacingNames,
+			Value:       p.pacing,
+		},
+	}
+}
+
+// ApplySetting changes a setting for the next run
+func (p *Provider) ApplySetting(key, value string) error {
+	if key != "pacing" {
+		return fmt.Errorf("unknown Fake setting %q", key)
+	}
+	pacing, ok := Pacings[value]
+	if !ok {
+		return fmt.Errorf("unknown pacing %q (valid: none, normal, slow)", value)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pacing = value
+	p.params.Pacing = pacing
+	return nil
+}
+
+// ScenarioParams returns the params chosen on the options screen
+func (p *Provider) ScenarioParams() scenario.Params {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.params
+}
+
+// handle resolves the store, failing until Start has succeeded
+func (p *Provider) handle() (*Store, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.store == nil {
+		return nil, scenario.ErrProviderNotStarted
+	}
+	return p.store, nil
+}
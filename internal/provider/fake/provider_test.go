@@ -0,0 +1,58 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+func TestProvider_RunsEveryScenario(t *testing.T) {
+	startDelay = 0
+	p := NewProvider()
+	ctx := context.Background()
+
+	for _, s := range p.GetScenarios().GetAll() {
+		if err := s.Setup(ctx); !errors.Is(err, scenario.ErrProviderNotStarted) {
+			t.Fatalf("Expected ErrProviderNotStarted from %s before Start, got %v", s.Name(), err)
+		}
+	}
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("Expected Start to succeed, got %v", err)
+	}
+	defer p.Stop(ctx)
+	if missing := p.Capabilities().Missing([]scenario.Capability{scenario.CapMultiDocumentTransactions}); len(missing) > 0 {
+		t.Fatalf("Expected transactions once started, missing %v", missing)
+	}
+	if err := p.ApplySetting("pacing", "none"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range p.GetScenarios().GetAll() {
+		var assertions scenario.Assertions
+		ctx := scenario.WithParams(ctx, p.ScenarioParams())
+		ctx = scenario.WithAssertions(ctx, &assertions)
+
+		output := make(chan scenario.StepResult, 100)
+		outcome := scenario.Execute(ctx, s, output)
+		if outcome.Err != nil || outcome.Cleanup != nil {
+			t.Fatalf("Expected %s to run cleanly, got %v, cleanup %v", s.Name(), outcome.Err, outcome.Cleanup)
+		}
+		for step := range output {
+			if !step.IsHeader && !step.Success {
+				t.Fatalf("Expected every step of %s to succeed, got %+v", s.Name(), step)
+			}
+		}
+		if failed := assertions.Failed(); len(failed) > 0 {
+			t.Fatalf("Expected the assertions of %s to hold, got %v", s.Name(), failed)
+		}
+		if scenario.AnomalyOf(s) == "" {
+			t.Fatalf("Expected %s to declare its anomaly for comparisons", s.Name())
+		}
+	}
+	if n := p.store.Len(); n != 0 {
+		t.Fatalf("Expected the scenarios to clean up, %d keys left", n)
+	}
+}
@@ -0,0 +1,347 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Handle resolves the store of the running provider, failing with scenario.ErrProviderNotStarted
+// until it has started
+type Handle func() (*Store, error)
+
+// DirtyReadScenario shows that a transaction's writes stay invisible to others until it commits
+type DirtyReadScenario struct {
+	handle Handle
+}
+
+// NewDirtyReadScenario creates the dirty read scenario on the store handle resolves
+func NewDirtyReadScenario(handle Handle) *DirtyReadScenario {
+	return &DirtyReadScenario{handle: handle}
+}
+
+func (s *DirtyReadScenario) Name() string {
+	return "Dirty Read Prevention"
+}
+
+func (s *DirtyReadScenario) Description() string {
+	return i18n.T(`Shows on the in-memory store that uncommitted writes stay invisible.
+
+1. Session A starts a transaction and writes a key
+2. Session B reads the key - it is NOT there yet
+3. Session A commits
+4. Session B reads again - now it is`)
+}
+
+func (s *DirtyReadScenario) IsolationLevel() string {
+	return "Snapshot"
+}
+
+func (s *DirtyReadScenario) Requires() []scenario.Capability {
+	return []scenario.Capability{scenario.CapMultiDocumentTransactions}
+}
+
+// Metadata returns listing tags and the typical run time
+func (s *DirtyReadScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{Tags: []string{"dirty-read", "anomaly"}, EstimatedDuration: 2 * time.Second}
+}
+
+// AnomalyKey identifies the scenario for comparisons across providers
+func (s *DirtyReadScenario) AnomalyKey() string {
+	return scenario.AnomalyDirtyRead
+}
+
+func (s *DirtyReadScenario) Setup(ctx context.Context) error {
+	store, err := s.handle()
+	if err != nil {
+		return err
+	}
+	store.Delete("widget")
+	return nil
+}
+
+func (s *DirtyReadScenario) Cleanup(ctx context.Context) error {
+	return s.Setup(ctx)
+}
+
+func (s *DirtyReadScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	store, err := s.handle()
+	if err != nil {
+		return err
+	}
+
+	output <- scenario.StepResult{IsHeader: true, Description: i18n.T("🔒 Dirty Read Prevention on the in-memory store")}
+
+	output <- scenario.StepResult{
+		Session:     "Setup",
+		Step:        1,
+		Description: i18n.T("Checking initial state - the key should be missing"),
+		Query:       "GET widget",
+		Result:      found(store.Get("widget")),
+		Success:     true,
+	}
+
+	txA := store.Begin()
+	defer txA.Abort()
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        2,
+		Description: i18n.T("Starting a transaction"),
+		Role:        scenario.RoleBegin,
+		Query:       "BEGIN",
+		Result:      i18n.T("Transaction started"),
+		Success:     true,
+	}
+
+	if err := txA.Put("widget", 100); err != nil {
+		return fmt.Errorf("session A: %w", err)
+	}
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        3,
+		Description: i18n.T("Writing the key within the transaction (NOT YET COMMITTED)"),
+		Role:        scenario.RoleWrite,
+		Query:       "PUT widget 100",
+		Result:      i18n.T("Written (within transaction)"),
+		Success:     true,
+	}
+	scenario.Pause(ctx)
+
+	_, visible := store.Get("widget")
+	scenario.Assert(ctx, "uncommitted writes are invisible to other sessions", !visible, found(store.Get("widget")))
+	output <- scenario.StepResult{
+		Session:     "Session B",
+		Step:        4,
+		Description: i18n.T("Reading the key outside the transaction"),
+		Role:        scenario.RoleRead,
+		Query:       "GET widget",
+		Result:      found(store.Get("widget")),
+		Success:     !visible,
+	}
+	scenario.Pause(ctx)
+
+	if err := txA.Commit(); err != nil {
+		return fmt.Errorf("session A: %w", err)
+	}
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        5,
+		Description: i18n.T("Committing the transaction"),
+		Role:        scenario.RoleCommit,
+		Query:       "COMMIT",
+		Result:      i18n.T("Committed"),
+		Success:     true,
+	}
+	scenario.Pause(ctx)
+
+	v, visible := store.Get("widget")
+	scenario.Assert(ctx, "committed writes are visible", visible && v == 100, found(v, visible))
+	output <- scenario.StepResult{
+		Session:     "Session B",
+		Step:        6,
+		Description: i18n.T("Reading the key again after the commit"),
+		Role:        scenario.RoleReread,
+		Query:       "GET widget",
+		Result:      found(v, visible),
+		Success:     visible,
+	}
+	return nil
+}
+
+// LostUpdateScenario shows two transactions updating the same key, where the second to commit
+// is aborted instead of overwriting the first
+type LostUpdateScenario struct {
+	handle Handle
+}
+
+// NewLostUpdateScenario creates the lost update scenario on the store handle resolves
+func NewLostUpdateScenario(handle Handle) *LostUpdateScenario {
+	return &LostUpdateScenario{handle: handle}
+}
+
+func (s *LostUpdateScenario) Name() string {
+	return "Lost Update Prevention"
+}
+
+func (s *LostUpdateScenario) Description() string {
+	return i18n.T(`Shows on the in-memory store that concurrent updates can't overwrite each other.
+
+1. Sessions A and B both read a balance of 100
+2. A adds 50 and B subtracts 30, each in its own transaction
+3. A commits first
+4. B's commit fails with a write conflict instead of losing A's update`)
+}
+
+func (s *LostUpdateScenario) IsolationLevel() string {
+	return "Snapshot"
+}
+
+func (s *LostUpdateScenario) Requires() []scenario.Capability {
+	return []scenario.Capability{scenario.CapMultiDocumentTransactions}
+}
+
+// Metadata returns listing tags and the typical run time
+func (s *LostUpdateScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{Tags: []string{"lost-update", "write-conflict", "anomaly"}, EstimatedDuration: 3 * time.Second}
+}
+
+// AnomalyKey identifies the scenario for comparisons across providers
+func (s *LostUpdateScenario) AnomalyKey() string {
+	return scenario.AnomalyLostUpdate
+}
+
+func (s *LostUpdateScenario) Setup(ctx context.Context) error {
+	store, err := s.handle()
+	if err != nil {
+		return err
+	}
+	store.Put("balance", 100)
+	return nil
+}
+
+func (s *LostUpdateScenario) Cleanup(ctx context.Context) error {
+	store, err := s.handle()
+	if err != nil {
+		return err
+	}
+	store.Delete("balance")
+	return nil
+}
+
+func (s *LostUpdateScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	store, err := s.handle()
+	if err != nil {
+		return err
+	}
+
+	output <- scenario.StepResult{IsHeader: true, Description: i18n.T("💥 Lost Update Prevention on the in-memory store")}
+
+	output <- scenario.StepResult{
+		Session:     "Setup",
+		Step:        1,
+		Description: i18n.T("Seeded the balance"),
+		Query:       "PUT balance 100",
+		Result:      found(store.Get("balance")),
+		Success:     true,
+	}
+
+	step := 2
+	txs := map[string]*Tx{"Session A": store.Begin(), "Session B": store.Begin()}
+	for _, session := range []string{"Session A", "Session B"} {
+		defer txs[session].Abort()
+		output <- scenario.StepResult{
+			Session:     session,
+			Step:        step,
+			Description: i18n.T("Starting a transaction"),
+			Role:        scenario.RoleBegin,
+			Query:       "BEGIN",
+			Result:      i18n.T("Transaction started"),
+			Success:     true,
+		}
+		step++
+	}
+
+	read := map[string]int64{}
+	for _, session := range []string{"Session A", "Session B"} {
+		v, ok := txs[session].Get("balance")
+		read[session] = v
+		output <- scenario.StepResult{
+			Session:     session,
+			Step:        step,
+			Description: i18n.T("Reading the balance"),
+			Role:        scenario.RoleRead,
+			Query:       "GET balance",
+			Result:      found(v, ok),
+			Success:     ok,
+		}
+		step++
+		scenario.Pause(ctx)
+	}
+
+	updates := []struct {
+		session string
+		delta   int64
+		role    string
+	}{
+		{"Session A", 50, scenario.RoleWrite},
+		{"Session B", -30, scenario.RoleConcurrentWrite},
+	}
+	for _, u := range updates {
+		value := read[u.session] + u.delta
+		if err := txs[u.session].Put("balance", value); err != nil {
+			return fmt.Errorf("%s: %w", u.session, err)
+		}
+		output <- scenario.StepResult{
+			Session:     u.session,
+			Step:        step,
+			Description: i18n.T("Writing the balance it read %+d", u.delta),
+			Role:        u.role,
+			Query:       fmt.Sprintf("PUT balance %d", value),
+			Result:      i18n.T("Written (within transaction)"),
+			Success:     true,
+		}
+		step++
+		scenario.Pause(ctx)
+	}
+
+	if err := txs["Session A"].Commit(); err != nil {
+		return fmt.Errorf("session A: %w", err)
+	}
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        step,
+		Description: i18n.T("Committing the transaction"),
+		Role:        scenario.RoleCommit,
+		Query:       "COMMIT",
+		Result:      i18n.T("Committed"),
+		Success:     true,
+	}
+	step++
+	scenario.Pause(ctx)
+
+	err = txs["Session B"].Commit()
+	if err != nil && !errors.Is(err, ErrWriteConflict) {
+		return fmt.Errorf("session B: %w", err)
+	}
+	conflicted := errors.Is(err, ErrWriteConflict)
+	scenario.Assert(ctx, "the second commit of a concurrent update is rejected", conflicted, fmt.Sprint(err))
+	result := i18n.T("Committed, overwriting Session A's update")
+	if conflicted {
+		result = i18n.T("Aborted: write conflict (Session A committed the balance first)")
+	}
+	output <- scenario.StepResult{
+		Session:     "Session B",
+		Step:        step,
+		Description: i18n.T("Committing the transaction"),
+		Role:        scenario.RoleAbort,
+		Query:       "COMMIT",
+		Result:      result,
+		Success:     conflicted,
+	}
+	step++
+
+	v, ok := store.Get("balance")
+	scenario.Assert(ctx, "the first committed update is kept", v == 150, found(v, ok))
+	output <- scenario.StepResult{
+		Session:     "Verify",
+		Step:        step,
+		Description: i18n.T("Reading the committed balance"),
+		Role:        scenario.RoleVerify,
+		Query:       "GET balance",
+		Result:      found(v, ok),
+		Success:     v == 150,
+	}
+	return nil
+}
+
+// found describes the result of a read
+func found(v int64, ok bool) string {
+	if !ok {
+		return i18n.T("Not found")
+	}
+	return i18n.T("Value: %d", v)
+}
@@ -0,0 +1,140 @@
+package fake
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ErrWriteConflict is returned by Commit when another transaction committed a write to a key this
+// one wrote since it began, as a snapshot-isolated database would abort it
+var ErrWriteConflict = errors.New("write conflict")
+
+// errTxDone is returned by operations on a committed or aborted transaction
+var errTxDone = errors.New("transaction already ended")
+
+// Store is the fake database: integer values by key with snapshot-isolated transactions. Reads
+// outside a transaction see the latest committed values. Safe for concurrent use.
+type Store struct {
+	mu       sync.Mutex
+	values   map[string]int64
+	versions map[string]uint64 // Commit that last wrote each key
+	commits  uint64
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{values: make(map[string]int64), versions: make(map[string]uint64)}
+}
+
+// Get returns the committed value of key and whether it exists
+func (s *Store) Get(key string) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Put commits value under key on its own
+func (s *Store) Put(key string, value int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commits++
+	s.values[key] = value
+	s.versions[key] = s.commits
+}
+
+// Delete removes the keys, committing on their own
+func (s *Store) Delete(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commits++
+	for _, key := range keys {
+		delete(s.values, key)
+		s.versions[key] = s.commits
+	}
+}
+
+// Len returns the number of committed keys
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.values)
+}
+
+// Keys returns the committed keys in order
+func (s *Store) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Sorted(maps.Keys(s.values))
+}
+
+// Begin starts a transaction reading from a snapshot of the values committed so far
+func (s *Store) Begin() *Tx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Tx{
+		store:    s,
+		snapshot: maps.Clone(s.values),
+		began:    s.commits,
+		writes:   make(map[string]int64),
+	}
+}
+
+// Tx is a transaction on a Store. Its writes stay invisible to everyone else until Commit.
+type Tx struct {
+	store    *Store
+	snapshot map[string]int64
+	began    uint64 // Commits the snapshot includes
+	writes   map[string]int64
+	done     bool
+}
+
+// Get returns the value of key as the transaction sees it: its own writes over its snapshot
+func (tx *Tx) Get(key string) (int64, bool) {
+	if v, ok := tx.writes[key]; ok {
+		return v, true
+	}
+	v, ok := tx.snapshot[key]
+	return v, ok
+}
+
+// Put writes value under key inside the transaction
+func (tx *Tx) Put(key string, value int64) error {
+	if tx.done {
+		return errTxDone
+	}
+	tx.writes[key] = value
+	return nil
+}
+
+// Commit makes the writes visible, failing with ErrWriteConflict when a key written here was
+// committed by someone else since Begin. The transaction is over either way.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return errTxDone
+	}
+	tx.done = true
+
+	s := tx.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range tx.writes {
+		if s.versions[key] > tx.began {
+			return ErrWriteConflict
+		}
+	}
+	s.commits++
+	for key, v := range tx.writes {
+		s.values[key] = v
+		s.versions[key] = s.commits
+	}
+	return nil
+}
+
+// Abort discards the writes
+func (tx *Tx) Abort() {
+	tx.done = true
+	tx.writes = nil
+}
@@ -0,0 +1,54 @@
+package fake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTx_WritesStayInvisibleUntilCommit(t *testing.T) {
+	s := NewStore()
+	tx := s.Begin()
+	if err := tx.Put("k", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("k"); ok {
+		t.Fatal("Expected an uncommitted write to be invisible")
+	}
+	if v, ok := tx.Get("k"); !ok || v != 1 {
+		t.Fatalf("Expected the transaction to see its own write, got %d, %v", v, ok)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Expected the commit to succeed, got %v", err)
+	}
+	if v, ok := s.Get("k"); !ok || v != 1 {
+		t.Fatalf("Expected the committed write to be visible, got %d, %v", v, ok)
+	}
+}
+
+func TestTx_SecondCommitterConflicts(t *testing.T) {
+	s := NewStore()
+	s.Put("k", 100)
+	a, b := s.Begin(), s.Begin()
+	_ = a.Put("k", 150)
+	_ = b.Put("k", 70)
+
+	if err := a.Commit(); err != nil {
+		t.Fatalf("Expected the first commit to succeed, got %v", err)
+	}
+	if err := b.Commit(); !errors.Is(err, ErrWriteConflict) {
+		t.Fatalf("Expected ErrWriteConflict, got %v", err)
+	}
+	if v, _ := s.Get("k"); v != 150 {
+		t.Fatalf("Expected the first update to be kept, got %d", v)
+	}
+
+	// A transaction that began after the commit reads it and doesn't conflict
+	c := s.Begin()
+	if v, _ := c.Get("k"); v != 150 {
+		t.Fatalf("Expected a later snapshot to include the commit, got %d", v)
+	}
+	_ = c.Put("k", 160)
+	if err := c.Commit(); err != nil {
+		t.Fatalf("Expected no conflict, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package provider
+
+// ImageProvider is implemented by providers that run container images, so they can be pulled ahead of Start
+type ImageProvider interface {
+	// Images returns the image references Start would launch with the current settings
+	Images() []string
+}
+
+// Attachable is implemented by providers that can use a database whose lifecycle they don't manage
+type Attachable interface {
+	// AttachedTo names the external database in use, or returns "" when the provider manages its own
+	AttachedTo() string
+}
+
+// RuntimeUser is implemented by providers whose need for a container runtime depends on their
+// settings, e.g. one connecting to a database by URI needs none
+type RuntimeUser interface {
+	// NeedsRuntime returns whether Start would use the container runtime with the current settings
+	NeedsRuntime() bool
+}
+
+// NeedsRuntime returns whether starting p requires a container runtime. Providers that don't
+// implement RuntimeUser are assumed to need one.
+func NeedsRuntime(p Provider) bool {
+	if u, ok := p.(RuntimeUser); ok {
+		return u.NeedsRuntime()
+	}
+	return true
+}
+
+// Shell is implemented by providers that can point users at an interactive database shell
+type Shell interface {
+	// ContainerID returns the ID of the container backing the database, or "" when there is none
+	ContainerID() string
+
+	// ShellCommand returns a ready-to-paste command that opens a shell on the database
+	ShellCommand() string
+}
+
+// ShortID abbreviates a container ID the way the docker CLI does
+func ShortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
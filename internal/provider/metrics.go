@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+)
+
+// Phase is a coarse startup stage that is timed separately
+type Phase string
+
+const (
+	PhasePull    Phase = "pull"
+	PhaseCreate  Phase = "create"
+	PhaseInit    Phase = "init"
+	PhaseConnect Phase = "connect"
+	PhaseReady   Phase = "ready" // Waiting for the deployment to take writes
+)
+
+// Phases lists the startup phases in the order they normally run
+var Phases = []Phase{PhasePull, PhaseCreate, PhaseInit, PhaseConnect, PhaseReady}
+
+// StartupMetrics is the timing of one provider start
+type StartupMetrics struct {
+	Provider  string                  `json:"provider"`
+	StartedAt time.Time               `json:"started_at"`
+	Total     time.Duration           `json:"total"`
+	Phases    map[Phase]time.Duration `json:"phases"`
+}
+
+// Summary formats the metrics as "started in 8.2s — pull 0s, init 5.1s, connect 0.4s"
+func (m StartupMetrics) Summary() string {
+	var parts []string
+	for _, p := range Phases {
+		if d, ok := m.Phases[p]; ok {
+			parts = append(parts, fmt.Sprintf("%s %s", p, formatSeconds(d)))
+		}
+	}
+
+	summary := i18n.T("started in %s", formatSeconds(m.Total))
+	if len(parts) > 0 {
+		summary += " — " + strings.Join(parts, ", ")
+	}
+	return summary
+}
+
+// formatSeconds renders a duration with one decimal, and sub-50ms durations as 0s
+func formatSeconds(d time.Duration) string {
+	if d < 50*time.Millisecond {
+		return "0s"
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// StartupTimer attributes elapsed time to the phase that is currently running
+type StartupTimer struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	start      time.Time
+	phaseStart time.Time
+	current    Phase
+	phases     map[Phase]time.Duration
+}
+
+// NewStartupTimer creates a timer that starts counting immediately
+func NewStartupTimer() *StartupTimer {
+	return newStartupTimer(time.Now)
+}
+
+func newStartupTimer(now func() time.Time) *StartupTimer {
+	start := now()
+	return &StartupTimer{
+		now:        now,
+		start:      start,
+		phaseStart: start,
+		phases:     make(map[Phase]time.Duration),
+	}
+}
+
+// Enter ends the current phase and starts p; re-entering a phase adds to its total
+func (t *StartupTimer) Enter(p Phase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.closePhase(now)
+	t.current = p
+	t.phaseStart = now
+}
+
+// Finish ends the current phase and returns the collected metrics
+func (t *StartupTimer) Finish(providerName string) StartupMetrics {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.closePhase(now)
+	t.current = ""
+
+	phases := make(map[Phase]time.Duration, len(t.phases))
+	for p, d := range t.phases {
+		phases[p] = d
+	}
+	return StartupMetrics{
+		Provider:  providerName,
+		StartedAt: t.start,
+		Total:     now.Sub(t.start),
+		Phases:    phases,
+	}
+}
+
+// closePhase adds the time since phaseStart to the current phase; the caller must hold t.mu
+func (t *StartupTimer) closePhase(now time.Time) {
+	if t.current != "" {
+		t.phases[t.current] += now.Sub(t.phaseStart)
+	}
+}
+
+type timerKey struct{}
+
+// WithTimer returns a context whose startup phases are recorded by t
+func WithTimer(ctx context.Context, t *StartupTimer) context.Context {
+	return context.WithValue(ctx, timerKey{}, t)
+}
+
+// EnterPhase marks the beginning of a startup phase on the context's timer, if any
+func EnterPhase(ctx context.Context, p Phase) {
+	slog.DebugContext(ctx, "startup phase", "phase", p)
+	if t, ok := ctx.Value(timerKey{}).(*StartupTimer); ok && t != nil {
+		t.Enter(p)
+	}
+}
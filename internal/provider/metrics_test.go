@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStartupTimer(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timer := newStartupTimer(clock)
+	ctx := WithTimer(context.Background(), timer)
+
+	EnterPhase(ctx, PhasePull)
+	now = now.Add(10 * time.Millisecond)
+	EnterPhase(ctx, PhaseInit)
+	now = now.Add(5 * time.Second)
+	EnterPhase(ctx, PhaseConnect)
+	now = now.Add(300 * time.Millisecond)
+	EnterPhase(ctx, PhaseInit)
+	now = now.Add(100 * time.Millisecond)
+
+	m := timer.Finish("MongoDB")
+	if m.Phases[PhaseInit] != 5100*time.Millisecond {
+		t.Fatalf("Expected re-entered init phase to accumulate 5.1s, got %s", m.Phases[PhaseInit])
+	}
+
+	want := "started in 5.4s — pull 0s, init 5.1s, connect 0.3s"
+	if got := m.Summary(); got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
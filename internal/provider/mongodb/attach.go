@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Labels docker compose puts on the containers it manages
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// ComposeService identifies a docker compose service to attach to instead of launching containers
+type ComposeService struct {
+	Project string
+	Service string
+}
+
+// IsSet returns whether attach mode is configured
+func (s ComposeService) IsSet() bool {
+	return s.Project != "" && s.Service != ""
+}
+
+// String returns "project/service"
+func (s ComposeService) String() string {
+	return s.Project + "/" + s.Service
+}
+
+// labels returns the label filters that identify the service's containers
+func (s ComposeService) labels() []string {
+	return []string{
+		composeProjectLabel + "=" + s.Project,
+		composeServiceLabel + "=" + s.Service,
+	}
+}
+
+// attachedService is a container owned by docker compose; stopping only disconnects
+type attachedService struct {
+	id   string
+	name string
+}
+
+// Terminate implements terminator; compose owns the container, so nothing is removed
+func (attachedService) Terminate(ctx context.Context) error {
+	return nil
+}
+
+// ForceRemove implements terminator; compose owns the container, so nothing is removed
+func (attachedService) ForceRemove(ctx context.Context) error {
+	return nil
+}
+
+// locateComposeService finds the service's running container and the connection string for its published port
+func locateComposeService(ctx context.Context, svc ComposeService) (attachedService, string, error) {
+	provider, err := testcontainers.NewDockerProvider()
+	if err != nil {
+		return attachedService{}, "", fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer provider.Close()
+
+	args := filters.NewArgs(filters.Arg("status", "running"))
+	for _, l := range svc.labels() {
+		args.Add("label", l)
+	}
+	containers, err := provider.Client().ContainerList(ctx, container.ListOptions{Filters: args})
+	if err != nil {
+		return attachedService{}, "", fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	host, err := provider.DaemonHost(ctx)
+	if err != nil {
+		return attachedService{}, "", fmt.Errorf("failed to get Docker host: %w", err)
+	}
+	return composeConnection(containers, svc, host)
+}
+
+// composeConnection picks the service container and builds a direct connection string to its published mongod port
+func composeConnection(containers []container.Summary, svc ComposeService, host string) (attachedService, string, error) {
+	if len(containers) == 0 {
+		return attachedService{}, "", fmt.Errorf("no running container found for compose service %s (searched labels %s)",
+			svc, strings.Join(svc.labels(), ", "))
+	}
+
+	// With scaled services any replica will do; take the first
+	c := containers[0]
+	attached := attachedService{id: c.ID, name: strings.TrimPrefix(firstOr(c.Names, c.ID), "/")}
+
+	for _, p := range c.Ports {
+		if p.PrivatePort != 27017 || p.PublicPort == 0 || strings.Contains(p.IP, ":") {
+			continue
+		}
+		addr := net.JoinHostPort(host, strconv.Itoa(int(p.PublicPort)))
+		// directConnection avoids following replica set member names that only resolve inside the compose network
+		return attached, fmt.Sprintf("mongodb://%s/?directConnection=true", addr), nil
+	}
+
+	return attachedService{}, "", fmt.Errorf("compose service %s (container %s) does not publish port 27017 to the host; add a ports entry such as \"27017:27017\"",
+		svc, attached.name)
+}
+
+// firstOr returns the first element of values, or fallback when it is empty
+func firstOr(values []string, fallback string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return fallback
+}
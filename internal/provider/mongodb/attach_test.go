@@ -0,0 +1,39 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestComposeConnection(t *testing.T) {
+	svc := ComposeService{Project: "shop", Service: "mongo"}
+
+	_, _, err := composeConnection(nil, svc, "localhost")
+	if err == nil || !strings.Contains(err.Error(), "com.docker.compose.project=shop") ||
+		!strings.Contains(err.Error(), "com.docker.compose.service=mongo") {
+		t.Fatalf("Expected error naming the searched labels, got %v", err)
+	}
+
+	published := container.Summary{
+		ID:    "abc123",
+		Names: []string{"/shop-mongo-1"},
+		Ports: []container.Port{
+			{IP: "::", PrivatePort: 27017, PublicPort: 27018},
+			{IP: "0.0.0.0", PrivatePort: 27017, PublicPort: 27018},
+		},
+	}
+	attached, connStr, err := composeConnection([]container.Summary{published}, svc, "localhost")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if connStr != "mongodb://localhost:27018/?directConnection=true" || attached.name != "shop-mongo-1" {
+		t.Fatalf("Unexpected attachment %+v %q", attached, connStr)
+	}
+
+	unpublished := container.Summary{ID: "def456", Names: []string{"/shop-mongo-1"}, Ports: []container.Port{{PrivatePort: 27017}}}
+	if _, _, err := composeConnection([]container.Summary{unpublished}, svc, "localhost"); err == nil {
+		t.Fatal("Expected error for a service that doesn't publish 27017")
+	}
+}
@@ -0,0 +1,97 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// serverInfo holds the parts of hello/buildInfo used to derive capabilities
+type serverInfo struct {
+	Version      []int32
+	SetName      string
+	Hosts        []string
+	IsMongos     bool
+	TestCommands bool
+}
+
+// atLeast returns whether the server version is at least major.minor
+func (i serverInfo) atLeast(major, minor int32) bool {
+	if len(i.Version) < 2 {
+		return false
+	}
+	if i.Version[0] != major {
+		return i.Version[0] > major
+	}
+	return i.Version[1] >= minor
+}
+
+// capabilities derives the capability set from the server info
+func (i serverInfo) capabilities() scenario.CapabilitySet {
+	caps := scenario.NewCapabilitySet()
+
+	// Transactions need a replica set (4.0+) or a sharded cluster (4.2+)
+	if (i.SetName != "" && i.atLeast(4, 0)) || (i.IsMongos && i.atLeast(4, 2)) {
+		caps[scenario.CapMultiDocumentTransactions] = true
+		caps[scenario.CapSnapshotReads] = true
+	}
+
+	// Secondary reads need at least one member besides the primary
+	if i.SetName != "" && len(i.Hosts) > 1 {
+		caps[scenario.CapSecondaryReads] = true
+	}
+
+	if i.IsMongos {
+		caps[scenario.CapShardedCluster] = true
+	}
+
+	if i.TestCommands {
+		caps[scenario.CapFailPoints] = true
+	}
+
+	return caps
+}
+
+// detectCapabilities inspects the server with hello/buildInfo to determine what it supports
+func detectCapabilities(ctx context.Context, client *mongo.Client) (scenario.CapabilitySet, error) {
+	admin := client.Database("admin")
+
+	var hello struct {
+		SetName string   `bson:"setName"`
+		Hosts   []string `bson:"hosts"`
+		Msg     string   `bson:"msg"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return nil, fmt.Errorf("failed to run hello: %w", err)
+	}
+
+	var build struct {
+		VersionArray []int32 `bson:"versionArray"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&build); err != nil {
+		return nil, fmt.Errorf("failed to run buildInfo: %w", err)
+	}
+
+	info := serverInfo{
+		Version:  build.VersionArray,
+		SetName:  hello.SetName,
+		Hosts:    hello.Hosts,
+		IsMongos: hello.Msg == "isdbgrid",
+	}
+
+	// enableTestCommands can only be read, not inferred; a failure means it's off
+	var param struct {
+		EnableTestCommands bool `bson:"enableTestCommands"`
+	}
+	err := admin.RunCommand(ctx, bson.D{
+		{Key: "getParameter", Value: 1},
+		{Key: "enableTestCommands", Value: 1},
+	}).Decode(&param)
+	info.TestCommands = err == nil && param.EnableTestCommands
+
+	return info.capabilities(), nil
+}
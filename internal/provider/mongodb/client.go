@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"time"
+
+	mongoScenarios "github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/mongodb"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Client settings for a demo tool: a handful of sessions against one deployment, which must fail
+// fast rather than hang when its container is gone
+const (
+	// serverSelectionTimeout bounds waiting for a server to send an operation to, which is what a
+	// stopped container makes every operation do
+	serverSelectionTimeout = 5 * time.Second
+
+	// connectTimeout bounds opening a connection to a server that was selected
+	connectTimeout = 5 * time.Second
+
+	// operationTimeout bounds each operation without a deadline of its own, socket reads
+	// included, so a container that stops answering mid-operation fails it too
+	operationTimeout = 30 * time.Second
+
+	// maxPoolSize caps the connections per server. Scenarios run a few sessions at a time, plus
+	// seeding in parallel.
+	maxPoolSize = 16
+
+	// maxConnIdleTime closes connections left idle between runs
+	maxConnIdleTime = time.Minute
+)
+
+// clientOptions returns the options every client of the provider starts from, applying uri on
+// top: settings the connection string spells out, e.g. serverSelectionTimeoutMS, win. The app name
+// doesn't, as scenarios scope their fail points to it. The Container builds its one client from
+// them and scenarios share it.
+func clientOptions(uri string) *options.ClientOptions {
+	return options.Client().
+		SetServerSelectionTimeout(serverSelectionTimeout).
+		SetConnectTimeout(connectTimeout).
+		SetTimeout(operationTimeout).
+		SetMaxPoolSize(maxPoolSize).
+		SetMaxConnIdleTime(maxConnIdleTime).
+		ApplyURI(uri).
+		SetAppName(mongoScenarios.AppName)
+}
@@ -0,0 +1,29 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	mongoScenarios "github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/mongodb"
+)
+
+func TestClientOptions(t *testing.T) {
+	opts := clientOptions("mongodb://localhost:27017/?directConnection=true")
+	if opts.ServerSelectionTimeout == nil || *opts.ServerSelectionTimeout != serverSelectionTimeout {
+		t.Fatalf("Expected server selection to time out after %v, got %v", serverSelectionTimeout, opts.ServerSelectionTimeout)
+	}
+	if opts.Timeout == nil || *opts.Timeout != operationTimeout {
+		t.Fatalf("Expected operations to time out after %v, got %v", operationTimeout, opts.Timeout)
+	}
+	if opts.MaxPoolSize == nil || *opts.MaxPoolSize != maxPoolSize {
+		t.Fatalf("Expected a pool of %d connections, got %v", maxPoolSize, opts.MaxPoolSize)
+	}
+
+	opts = clientOptions("mongodb://staging:27017/?serverSelectionTimeoutMS=1000&maxPoolSize=50&appName=other")
+	if *opts.ServerSelectionTimeout != time.Second || *opts.MaxPoolSize != 50 {
+		t.Fatalf("Expected the connection string to win, got %v and %d", *opts.ServerSelectionTimeout, *opts.MaxPoolSize)
+	}
+	if opts.AppName == nil || *opts.AppName != mongoScenarios.AppName {
+		t.Fatalf("Expected the app name fail points are scoped to, got %v", opts.AppName)
+	}
+}
@@ -0,0 +1,166 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/network"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	tcnetwork "github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	mongodPort        = "27017/tcp"
+	readyPollInterval = 500 * time.Millisecond
+)
+
+// containerGroup is a set of mongod/mongos containers sharing one Docker network
+type containerGroup struct {
+	config  ContainerConfig
+	network string // Shared network name, empty once released
+	members []testcontainers.Container
+	// hostAddrs maps a member's in-network address to its address published on the host
+	hostAddrs map[string]string
+}
+
+// newContainerGroup takes a reference on the shared network for a multi-container topology
+func newContainerGroup(ctx context.Context, config ContainerConfig) (*containerGroup, error) {
+	if _, err := network.Default.Acquire(ctx, network.DefaultName); err != nil {
+		return nil, err
+	}
+	return &containerGroup{
+		config:    config,
+		network:   network.DefaultName,
+		hostAddrs: make(map[string]string),
+	}, nil
+}
+
+// run starts a container reachable as alias:27017 on the group network and returns it
+func (g *containerGroup) run(ctx context.Context, alias string, cmd ...string) (testcontainers.Container, error) {
+	opts := []testcontainers.ContainerCustomizer{
+		testcontainers.WithExposedPorts(mongodPort),
+		testcontainers.WithCmd(append(cmd, testCommandArgs...)...),
+		tcnetwork.WithNetworkName([]string{alias}, g.network),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("Waiting for connections"),
+			wait.ForListeningPort(mongodPort),
+		),
+	}
+	opts = append(opts, g.config.customizers()...)
+
+	member, err := testcontainers.Run(ctx, g.config.Image, opts...)
+	if member != nil {
+		g.members = append(g.members, member)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", alias, err)
+	}
+
+	host, err := member.Host(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host for %s: %w", alias, err)
+	}
+	port, err := member.MappedPort(ctx, mongodPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mapped port for %s: %w", alias, err)
+	}
+
+	g.hostAddrs[alias+":27017"] = net.JoinHostPort(host, port.Port())
+	return member, nil
+}
+
+// Dialer returns a dialer that reaches members through their published host ports,
+// since the in-network aliases in the topology config don't resolve from the host
+func (g *containerGroup) Dialer() *memberDialer {
+	return &memberDialer{addrs: g.hostAddrs}
+}
+
+// Terminate removes every member container and then releases the network
+func (g *containerGroup) Terminate(ctx context.Context) error {
+	var errs []error
+	for _, m := range g.members {
+		if err := m.Terminate(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	g.members = nil
+
+	if err := g.releaseNetwork(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to terminate containers: %w", err)
+	}
+	return nil
+}
+
+// releaseNetwork drops the group's reference on the shared network
+func (g *containerGroup) releaseNetwork(ctx context.Context) error {
+	if g.network == "" {
+		return nil
+	}
+	name := g.network
+	g.network = ""
+	return network.Default.Release(ctx, name)
+}
+
+// execScript evaluates a mongosh script inside a container and returns its output
+func execScript(ctx context.Context, c testcontainers.Container, script string) (string, error) {
+	code, out, err := c.Exec(ctx, []string{"mongosh", "--quiet", "--eval", script}, tcexec.Multiplexed())
+	if err != nil {
+		return "", err
+	}
+
+	output, _ := io.ReadAll(out)
+	result := strings.TrimSpace(string(output))
+	if code != 0 {
+		return result, fmt.Errorf("mongosh exited with code %d: %s", code, result)
+	}
+	return result, nil
+}
+
+// waitWritable polls a replica set member until it reports itself as a writable primary
+// within timeout
+func waitWritable(ctx context.Context, c testcontainers.Container, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		out, err := execScript(ctx, c, "db.hello().isWritablePrimary")
+		if err == nil && out == "true" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s did not become primary within %s", name, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// memberDialer rewrites in-network member addresses to host-published addresses
+type memberDialer struct {
+	addrs  map[string]string
+	dialer net.Dialer
+}
+
+// DialContext implements options.ContextDialer
+func (d *memberDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if mapped, ok := d.addrs[address]; ok {
+		address = mapped
+	}
+	return d.dialer.DialContext(ctx, network, address)
+}
@@ -0,0 +1,63 @@
+//go:build integration
+
+package mongodb
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/network"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/providertest"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	mongoScenarios "github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/mongodb"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/scenariotest"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The shared provider's containers exist throughout, so only the ones a check adds are counted
+func TestProvider_Conforms(t *testing.T) {
+	providertest.Conformance(t, func() provider.Provider { return NewProvider() },
+		providertest.WithContainers(providertest.DockerContainers(network.Label)))
+}
+
+func TestScenarios_Conform(t *testing.T) {
+	p := shared
+	opts := []scenariotest.Option{
+		scenariotest.WithCapabilities(p.Capabilities()),
+		scenariotest.WithParams(p.ScenarioParams()),
+		scenariotest.WithLeftovers(func(ctx context.Context, s scenario.Scenario) ([]string, error) {
+			owner, ok := s.(scenario.CollectionOwner)
+			if !ok {
+				return nil, nil
+			}
+			_, db, err := p.handle()
+			if err != nil {
+				return nil, err
+			}
+			names, err := db.ListCollectionNames(ctx, bson.M{})
+			if err != nil {
+				return nil, err
+			}
+			return slices.DeleteFunc(owner.Collections(), func(c string) bool {
+				return !slices.Contains(names, c)
+			}), nil
+		}),
+	}
+	scenariotest.Conformance(t, func() scenario.Scenario { return mongoScenarios.NewDirtyReadScenario(p.handle) }, opts...)
+	scenariotest.Conformance(t, func() scenario.Scenario { return mongoScenarios.NewReadCommittedScenario(p.handle) }, opts...)
+	scenariotest.Conformance(t, func() scenario.Scenario { return mongoScenarios.NewSnapshotIsolationScenario(p.handle) }, opts...)
+	scenariotest.Conformance(t, func() scenario.Scenario { return mongoScenarios.NewWriteConflictScenario(p.handle) }, opts...)
+	scenariotest.Conformance(t, func() scenario.Scenario { return mongoScenarios.NewDistributedTransactionScenario(p.handle) }, opts...)
+	scenariotest.Conformance(t, func() scenario.Scenario { return mongoScenarios.NewPhantomReadScenario(p.handle) }, opts...)
+
+	examples, err := mongoScenarios.ExampleScripts()
+	if err != nil {
+		t.Fatalf("Failed to load the example scripts: %v", err)
+	}
+	for _, script := range examples {
+		scenariotest.Conformance(t, func() scenario.Scenario { return mongoScenarios.NewScriptScenario(p.handle, script) }, opts...)
+	}
+}
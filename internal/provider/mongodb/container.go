@@ -2,25 +2,193 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/imagepull"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/network"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/retry"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// DefaultImage is the MongoDB image used when none is configured
+const DefaultImage = "mongo:7.0"
+
+// DefaultDatabase is the database scenarios use when none is configured
+const DefaultDatabase = "txdemo"
+
+// ValidateDatabaseName checks a name against MongoDB's database naming rules
+func ValidateDatabaseName(name string) error {
+	if name == "" {
+		return errors.New("database name must not be empty")
+	}
+	if len(name) >= 64 {
+		return fmt.Errorf("database name %q must be shorter than 64 bytes", name)
+	}
+	if i := strings.IndexAny(name, `/\. "$`+"\x00"); i >= 0 {
+		return fmt.Errorf("database name %q must not contain %q", name, name[i])
+	}
+	return nil
+}
+
+// Topology selects how many mongod processes back the provider
+type Topology string
+
+const (
+	// TopologySingle is a single-node replica set (fast, supports transactions)
+	TopologySingle Topology = "single"
+
+	// TopologyReplicaSet is a three-member PSS replica set on a shared Docker network
+	TopologyReplicaSet Topology = "replicaset"
+
+	// TopologySharded is a config server, two shards and a mongos for distributed transactions
+	TopologySharded Topology = "sharded"
+)
+
+// Topologies lists every supported topology in display order
+var Topologies = []Topology{TopologySingle, TopologyReplicaSet, TopologySharded}
+
+// testCommandArgs lets scenarios use fail points on the servers txviewer starts. They are
+// throwaway, so test commands put nothing at risk.
+var testCommandArgs = []string{"--setParameter", "enableTestCommands=1"}
+
+// ParseTopology converts a user-supplied name into a Topology
+func ParseTopology(name string) (Topology, error) {
+	for _, t := range Topologies {
+		if Topology(name) == t {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown MongoDB topology %q (valid: %s, %s, %s)",
+		name, TopologySingle, TopologyReplicaSet, TopologySharded)
+}
+
+// ContainerConfig configures how the MongoDB containers are launched
+type ContainerConfig struct {
+	Image    string
+	Topology Topology
+
+	// StopTimeout bounds a graceful Stop before falling back to force-removal
+	StopTimeout time.Duration
+
+	// ReadyTimeout bounds each wait on Start for a node or replica set to accept writes
+	ReadyTimeout time.Duration
+
+	// MemoryLimit caps each container's memory in bytes (0 = unlimited)
+	MemoryLimit int64
+
+	// CPULimit caps each container's CPU usage in cores (0 = unlimited)
+	CPULimit float64
+
+	// Database is where scenarios create their collections; it is never dropped as a whole
+	Database string
+
+	// Compose attaches to a running docker compose service instead of launching containers
+	Compose ComposeService
+
+	// URI connects to an existing deployment instead of launching containers
+	URI string
+
+	// DataVolume keeps the single node's dbpath in a named volume per image, so later starts
+	// skip initializing it. Other topologies always start empty.
+	DataVolume bool
+}
+
+// HasLimits returns whether any resource limit is configured
+func (c ContainerConfig) HasLimits() bool {
+	return c.MemoryLimit > 0 || c.CPULimit > 0
+}
+
+// LimitsSummary describes the configured resource limits for display
+func (c ContainerConfig) LimitsSummary() string {
+	if !c.HasLimits() {
+		return "no resource limits"
+	}
+
+	var parts []string
+	if c.MemoryLimit > 0 {
+		parts = append(parts, "memory "+units.BytesSize(float64(c.MemoryLimit)))
+	}
+	if c.CPULimit > 0 {
+		parts = append(parts, fmt.Sprintf("cpus %g", c.CPULimit))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// customizers returns the testcontainers options every container gets: the txviewer label, so
+// they can be listed, and the resource limits
+func (c ContainerConfig) customizers() []testcontainers.ContainerCustomizer {
+	opts := []testcontainers.ContainerCustomizer{testcontainers.WithLabels(map[string]string{network.Label: "true"})}
+	if !c.HasLimits() {
+		return opts
+	}
+	return append(opts,
+		testcontainers.WithHostConfigModifier(func(hc *container.HostConfig) {
+			if c.MemoryLimit > 0 {
+				hc.Memory = c.MemoryLimit
+				// Disallow swap so the limit is a real ceiling
+				hc.MemorySwap = c.MemoryLimit
+			}
+			if c.CPULimit > 0 {
+				hc.NanoCPUs = int64(c.CPULimit * 1e9)
+			}
+		}))
+}
+
+// explainStartError points at the resource limits as a likely cause of a failed start
+func (c ContainerConfig) explainStartError(err error) error {
+	if c.MemoryLimit <= 0 {
+		return err
+	}
+	return fmt.Errorf("%w\n(containers are limited to %s of memory, which may be too low for MongoDB; try raising --container-memory)",
+		err, units.BytesSize(float64(c.MemoryLimit)))
+}
+
 // Container manages a MongoDB testcontainer with replica set support
 type Container struct {
+	config    ContainerConfig
 	container *mongodb.MongoDBContainer
+	replSet   *replicaSet
+	sharded   *shardedCluster
+	term      terminator // Tears down whichever topology was started
+	shell     shellTarget
 	client    *mongo.Client
 	connStr   string
+	unlock    func() error // Releases the data volume; nil without one
+	preinit   bool         // The image's node was initiated when it was built
 	mu        sync.Mutex
 }
 
 // NewContainer creates a new MongoDB container manager
-func NewContainer() *Container {
-	return &Container{}
+func NewContainer(config ContainerConfig) *Container {
+	if config.Image == "" {
+		config.Image = DefaultImage
+	}
+	if config.Topology == "" {
+		config.Topology = TopologySingle
+	}
+	if config.StopTimeout <= 0 {
+		config.StopTimeout = DefaultStopTimeout
+	}
+	if config.ReadyTimeout <= 0 {
+		config.ReadyTimeout = DefaultReadyTimeout
+	}
+	if config.Database == "" {
+		config.Database = DefaultDatabase
+	}
+	return &Container{config: config}
 }
 
 // Start launches the MongoDB container with replica set support
@@ -28,79 +196,340 @@ func (c *Container) Start(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.container != nil {
+	if c.running() {
 		return nil // Already running
 	}
 
-	// Start MongoDB with replica set for transaction support
-	container, err := mongodb.Run(ctx,
-		"mongo:7.0",
-		mongodb.WithReplicaSet("rs0"),
-	)
+	started := time.Now()
+	slog.InfoContext(ctx, "starting MongoDB", "image", c.config.Image, "topology", c.config.Topology)
+
+	// Docker hiccups like pull timeouts or port races are retried; a bad image tag is not
+	err := retry.DefaultPolicy().Do(ctx, c.start, func(attempt, attempts int, reason string, delay time.Duration) {
+		slog.WarnContext(ctx, "retrying MongoDB start", "attempt", attempt, "attempts", attempts,
+			"reason", reason, "delay", delay)
+		provider.ReportProgress(ctx, i18n.T("attempt %d/%d: retrying after %s…", attempt, attempts, reason))
+	})
+	if err != nil && ctx.Err() != nil {
+		slog.InfoContext(ctx, "MongoDB start cancelled", "duration", time.Since(started))
+		return fmt.Errorf("MongoDB start cancelled: %w", ctx.Err())
+	}
 	if err != nil {
-		return fmt.Errorf("failed to start MongoDB container: %w", err)
+		slog.ErrorContext(ctx, "failed to start MongoDB", "error", err, "duration", time.Since(started))
+		return c.config.explainStartError(err)
 	}
+	slog.InfoContext(ctx, "MongoDB started", "uri", c.connStr, "container", c.shell.id,
+		"duration", time.Since(started))
+	return nil
+}
 
-	c.container = container
-
-	// Get connection string
-	connStr, err := container.ConnectionString(ctx)
+// start launches the configured topology, or attaches to a compose service, and connects to it;
+// the caller must hold c.mu
+func (c *Container) start(ctx context.Context) error {
+	var clientOpts *options.ClientOptions
+	var err error
+	switch {
+	case c.config.URI != "":
+		clientOpts = c.connectExternal(ctx)
+	case c.config.Compose.IsSet():
+		clientOpts, err = c.attach(ctx)
+	default:
+		clientOpts, err = c.launch(ctx)
+	}
 	if err != nil {
-		c.Stop(ctx)
-		return fmt.Errorf("failed to get connection string: %w", err)
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		c.discard(ctx)
+		return err
 	}
-	c.connStr = connStr
 
 	// Create MongoDB client
-	clientOpts := options.Client().ApplyURI(connStr)
+	provider.EnterPhase(ctx, provider.PhaseConnect)
+	provider.ReportProgress(ctx, i18n.T("Connecting to database..."))
 	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
-		c.Stop(ctx)
+		c.discard(ctx)
 		return fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
+	c.client = client
 
 	// Verify connection
 	if err := client.Ping(ctx, nil); err != nil {
-		c.Stop(ctx)
+		c.discard(ctx)
 		return fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
-	c.client = client
+	if c.container != nil {
+		// The single node comes up as an uninitiated replica set member, unless its image was
+		// initiated at build time. It is initiated over this connection rather than with mongosh in
+		// the container, and the start goes on as soon as it takes writes.
+		if !c.preinit {
+			provider.EnterPhase(ctx, provider.PhaseInit)
+			provider.ReportProgress(ctx, i18n.T("Initiating replica set..."))
+			if err := initiateSingleNode(ctx, client); err != nil {
+				c.discard(ctx)
+				return err
+			}
+		}
+		provider.EnterPhase(ctx, provider.PhaseReady)
+		provider.ReportProgress(ctx, i18n.T("Waiting for MongoDB to accept writes..."))
+		if err := waitWritablePrimary(ctx, client.Database(c.config.Database), c.config.ReadyTimeout); err != nil {
+			c.discard(ctx)
+			return err
+		}
+	}
+
+	if c.replSet != nil {
+		provider.EnterPhase(ctx, provider.PhaseInit)
+		provider.ReportProgress(ctx, i18n.T("Waiting for replica set members to sync..."))
+		if err := c.replSet.WaitReady(ctx, client); err != nil {
+			c.discard(ctx)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// launch starts the containers of the configured topology and returns how to connect to them
+func (c *Container) launch(ctx context.Context) (*options.ClientOptions, error) {
+	// Pull explicitly so the download is timed apart from container creation
+	provider.EnterPhase(ctx, provider.PhasePull)
+	provider.ReportProgress(ctx, i18n.T("Checking image %s...", c.config.Image))
+	if err := imagepull.Preflight(ctx, c.config.Image); err != nil {
+		return nil, err
+	}
+	if err := imagepull.Ensure(ctx, c.config.Image); err != nil {
+		return nil, err
+	}
+	setName, err := preinitialized(ctx, c.config.Image)
+	if err != nil {
+		return nil, err
+	}
+	if setName != "" && c.config.Topology != TopologySingle {
+		return nil, fmt.Errorf("image %s holds a pre-initialized single node and only runs the %s topology",
+			c.config.Image, TopologySingle)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if c.config.DataVolume && c.config.Topology != TopologySingle {
+		// The topology was changed on the options screen after the volume was asked for
+		slog.WarnContext(ctx, "data volume ignored: only the single topology keeps one",
+			"topology", c.config.Topology)
+	}
+
+	provider.EnterPhase(ctx, provider.PhaseCreate)
+	switch c.config.Topology {
+	case TopologyReplicaSet:
+		rs, err := startReplicaSet(ctx, c.config)
+		if rs != nil {
+			c.replSet = rs
+			c.term = rs.containerGroup
+		}
+		if err != nil {
+			c.discard(ctx)
+			return nil, err
+		}
+		c.connStr = rs.ConnectionString()
+		// The member aliases in the connection string resolve inside the network too
+		c.shell = newShellTarget(ctx, rs.members[0], c.connStr)
+		return clientOptions(c.connStr).SetDialer(rs.Dialer()), nil
+
+	case TopologySharded:
+		sc, err := startShardedCluster(ctx, c.config)
+		if sc != nil {
+			c.sharded = sc
+			c.term = sc.containerGroup
+		}
+		if err != nil {
+			c.discard(ctx)
+			return nil, err
+		}
+		c.connStr = sc.ConnectionString()
+		c.shell = newShellTarget(ctx, sc.members[len(sc.members)-1], inContainerURI)
+		return clientOptions(c.connStr), nil
+
+	default:
+		// Start MongoDB as a replica set member for transaction support; start initiates it
+		provider.ReportProgress(ctx, i18n.T("Starting %s container...", c.config.Image))
+		// A pre-initialized image names its replica set itself
+		var opts []testcontainers.ContainerCustomizer
+		if setName == "" {
+			opts = append(opts, testcontainers.WithCmdArgs("--replSet", replicaSetName))
+		}
+		opts = append(opts, testcontainers.WithCmdArgs(testCommandArgs...))
+		opts = append(opts, c.config.customizers()...)
+		c.preinit = setName != ""
+		if c.config.DataVolume && c.preinit {
+			slog.InfoContext(ctx, "not mounting a data volume over a pre-initialized image", "image", c.config.Image)
+		} else if c.config.DataVolume {
+			mount, unlock, err := c.dataVolume(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if mount != nil {
+				opts = append(opts, mount)
+				c.unlock = unlock
+			}
+		}
+		container, err := mongodb.Run(ctx, c.config.Image, opts...)
+		if container != nil {
+			c.container = container
+			c.term = singleContainer{container}
+		}
+		if err != nil {
+			c.discard(ctx)
+			return nil, fmt.Errorf("failed to start MongoDB container: %w", err)
+		}
+
+		// Get connection string
+		connStr, err := container.ConnectionString(ctx)
+		if err != nil {
+			c.discard(ctx)
+			return nil, fmt.Errorf("failed to get connection string: %w", err)
+		}
+		// Until it is initiated the member can only be reached directly, and as a one-member set
+		// there is nothing to discover anyway
+		c.connStr = connStr + "?directConnection=true"
+		c.shell = newShellTarget(ctx, container, inContainerURI)
+		return clientOptions(c.connStr), nil
+	}
+}
+
+// attach locates the configured compose service; its lifecycle stays with docker compose
+func (c *Container) attach(ctx context.Context) (*options.ClientOptions, error) {
+	provider.EnterPhase(ctx, provider.PhaseConnect)
+	provider.ReportProgress(ctx, i18n.T("Locating compose service %s...", c.config.Compose))
+
+	attached, connStr, err := locateComposeService(ctx, c.config.Compose)
+	if err != nil {
+		return nil, err
+	}
+	c.term = attached
+	c.connStr = connStr
+	c.shell = shellTarget{id: attached.id, name: attached.name, uri: inContainerURI}
+	return clientOptions(connStr), nil
+}
+
+// connectExternal targets the configured deployment; txviewer only ever disconnects from it
+func (c *Container) connectExternal(ctx context.Context) *options.ClientOptions {
+	provider.EnterPhase(ctx, provider.PhaseConnect)
+	provider.ReportProgress(ctx, i18n.T("Connecting to external MongoDB..."))
+
+	c.term = externalDeployment{}
+	c.connStr = c.config.URI
+	c.shell = shellTarget{} // No container to exec into, so the shell hint is a plain mongosh
+	return clientOptions(c.config.URI)
+}
+
+// externalDeployment is a deployment txviewer was pointed at; stopping only disconnects
+type externalDeployment struct{}
+
+// Terminate implements terminator; the deployment isn't txviewer's, so nothing is removed
+func (externalDeployment) Terminate(ctx context.Context) error {
+	return nil
+}
+
+// ForceRemove implements terminator; the deployment isn't txviewer's, so nothing is removed
+func (externalDeployment) ForceRemove(ctx context.Context) error {
 	return nil
 }
 
-// Stop terminates the MongoDB container
+// Stop terminates the MongoDB container. If the graceful stop exceeds the configured
+// timeout the containers are force-removed and an error wrapping ErrForcedStop is returned.
 func (c *Container) Stop(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.term == nil && c.client == nil {
+		return nil
+	}
+	started := time.Now()
+	slog.InfoContext(ctx, "stopping MongoDB", "container", c.shell.id)
+	if err := c.stop(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to stop MongoDB", "error", err, "duration", time.Since(started))
+		return err
+	}
+	slog.InfoContext(ctx, "MongoDB stopped", "duration", time.Since(started))
+	return nil
+}
+
+// discard tears down whatever a failed or cancelled start created. The start's context may be
+// done already, so the teardown runs without its cancellation; the caller must hold c.mu
+func (c *Container) discard(ctx context.Context) {
+	if err := c.stop(context.WithoutCancel(ctx)); err != nil {
+		slog.WarnContext(ctx, "failed to remove partially started MongoDB", "error", err)
+	}
+}
+
+// stop releases the client and every container; the caller must hold c.mu
+func (c *Container) stop(ctx context.Context) error {
 	if c.client != nil {
 		if err := c.client.Disconnect(ctx); err != nil {
 			// Log but don't fail
-			fmt.Printf("Warning: failed to disconnect client: %v\n", err)
+			slog.WarnContext(ctx, "failed to disconnect client", "error", err)
 		}
 		c.client = nil
 	}
 
-	if c.container != nil {
-		if err := c.container.Terminate(ctx); err != nil {
-			return fmt.Errorf("failed to terminate container: %w", err)
+	var stopErr error
+	if c.term != nil {
+		stopErr = stopWithTimeout(ctx, c.term, c.config.StopTimeout)
+		if stopErr != nil && !errors.Is(stopErr, ErrForcedStop) {
+			// Keep the state so Stop can be retried
+			return fmt.Errorf("failed to terminate container: %w", stopErr)
+		}
+	}
+
+	if c.unlock != nil {
+		// Only now is mongod done with the volume's files
+		if err := c.unlock(); err != nil {
+			slog.WarnContext(ctx, "failed to release the data volume", "error", err)
 		}
-		c.container = nil
+		c.unlock = nil
 	}
 
+	c.container = nil
+	c.preinit = false
+	c.replSet = nil
+	c.sharded = nil
+	c.term = nil
+	c.shell = shellTarget{}
 	c.connStr = ""
-	return nil
+	return stopErr
+}
+
+// Config returns the launch configuration
+func (c *Container) Config() ContainerConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.config
+}
+
+// SetTopology changes the topology used by the next Start
+func (c *Container) SetTopology(t Topology) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config.Topology = t
 }
 
 // IsRunning returns whether the container is running
 func (c *Container) IsRunning() bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return c.container != nil && c.client != nil
+	return c.running() && c.client != nil
+}
+
+// running returns whether any containers exist, or a deployment was attached to; the caller must
+// hold c.mu
+func (c *Container) running() bool {
+	return c.term != nil
 }
 
-// Client returns the MongoDB client
+// Client returns the MongoDB client, or nil until started. Scenarios and capability checks all share
+// it, so nothing opens a pool of its own.
 func (c *Container) Client() *mongo.Client {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -0,0 +1,80 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestContainerConfig_LimitsSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ContainerConfig
+		want   string
+	}{
+		{"unlimited", ContainerConfig{}, "no resource limits"},
+		{"memory only", ContainerConfig{MemoryLimit: 512 * 1024 * 1024}, "memory 512MiB"},
+		{"both", ContainerConfig{MemoryLimit: 1024 * 1024 * 1024, CPULimit: 1.5}, "memory 1GiB, cpus 1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.LimitsSummary(); got != tt.want {
+				t.Fatalf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestContainerConfig_ExplainStartError(t *testing.T) {
+	boom := errors.New("container exited (137)")
+
+	if err := (ContainerConfig{}).explainStartError(boom); err != boom {
+		t.Fatalf("Expected error unchanged without a memory limit, got %v", err)
+	}
+
+	err := (ContainerConfig{MemoryLimit: 128 * 1024 * 1024}).explainStartError(boom)
+	if !errors.Is(err, boom) {
+		t.Fatalf("Expected wrapped error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "128MiB") {
+		t.Fatalf("Expected the configured limit in the message, got %q", err.Error())
+	}
+}
+
+func TestValidateDatabaseName(t *testing.T) {
+	for _, name := range []string{"txdemo", "txdemo_alice", "team-42"} {
+		if err := ValidateDatabaseName(name); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"", "tx.demo", "tx demo", "a/b", "$db", strings.Repeat("x", 64)} {
+		if err := ValidateDatabaseName(name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestContainer_ExternalURIIsRunningOnceStarted(t *testing.T) {
+	c := NewContainer(ContainerConfig{URI: "mongodb://" + fakeServer(t) + "/?directConnection=true"})
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Expected Start to succeed, got %v", err)
+	}
+	if !c.IsRunning() {
+		t.Fatal("Expected the external deployment to be running once started")
+	}
+	client := c.Client()
+	if err := c.Start(ctx); err != nil || c.Client() != client {
+		t.Fatalf("Expected a second Start to keep the client, got %v", err)
+	}
+
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("Expected Stop to succeed, got %v", err)
+	}
+	if c.IsRunning() || c.Client() != nil {
+		t.Fatal("Expected Stop to disconnect")
+	}
+}
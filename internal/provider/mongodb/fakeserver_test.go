@@ -0,0 +1,85 @@
+package mongodb
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/wiremessage"
+)
+
+// fakeServer is a standalone mongod as far as connecting and pinging go: it answers every
+// command, hello and ping included, with ok. It returns the server's address.
+func fakeServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	reply, err := bson.Marshal(bson.D{
+		{Key: "ok", Value: 1.0},
+		{Key: "isWritablePrimary", Value: true},
+		{Key: "ismaster", Value: true},
+		{Key: "maxWireVersion", Value: int32(21)},
+		{Key: "minWireVersion", Value: int32(0)},
+		{Key: "maxBsonObjectSize", Value: int32(16 * 1024 * 1024)},
+		{Key: "maxMessageSizeBytes", Value: int32(48 * 1000 * 1000)},
+		{Key: "maxWriteBatchSize", Value: int32(100000)},
+		{Key: "logicalSessionTimeoutMinutes", Value: int32(30)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode the reply: %v", err)
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveFake(conn, reply)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// serveFake answers each message on conn with reply, in the format the message came in
+func serveFake(conn net.Conn, reply []byte) {
+	defer conn.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		msg := make([]byte, binary.LittleEndian.Uint32(size[:]))
+		copy(msg, size[:])
+		if _, err := io.ReadFull(conn, msg[4:]); err != nil {
+			return
+		}
+		_, requestID, _, opcode, _, ok := wiremessage.ReadHeader(msg)
+		if !ok {
+			return
+		}
+
+		idx, out := wiremessage.AppendHeaderStart(nil, requestID+1, requestID, wiremessage.OpMsg)
+		if opcode == wiremessage.OpQuery {
+			// The legacy handshake
+			idx, out = wiremessage.AppendHeaderStart(nil, requestID+1, requestID, wiremessage.OpReply)
+			out = wiremessage.AppendReplyFlags(out, 0)
+			out = wiremessage.AppendReplyCursorID(out, 0)
+			out = wiremessage.AppendReplyStartingFrom(out, 0)
+			out = wiremessage.AppendReplyNumberReturned(out, 1)
+		} else {
+			out = wiremessage.AppendMsgFlags(out, 0)
+			out = wiremessage.AppendMsgSectionType(out, wiremessage.SingleDocument)
+		}
+		out = append(out, reply...)
+		binary.LittleEndian.PutUint32(out[idx:], uint32(len(out)))
+		if _, err := conn.Write(out); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/network"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// preinitLabel marks an image whose data directory already holds an initiated one-node replica
+// set, named by the label's value. Such an image starts mongod with its own --replSet and dbpath,
+// so the single node is neither initiated nor given a data volume.
+const preinitLabel = network.Label + ".mongodb.replset"
+
+// ImagePreset is an image that can be chosen by name instead of by reference
+type ImagePreset struct {
+	Name string
+	Ref  string
+
+	// Validated lists the capabilities whose scenarios were run against the image. Scenarios
+	// needing any other capability may still run when the server has it, but nobody checked.
+	Validated []scenario.Capability
+}
+
+// ImagePresets are the images --mongodb-image accepts by name
+var ImagePresets = []ImagePreset{
+	{
+		Name: "default",
+		Ref:  DefaultImage,
+		Validated: []scenario.Capability{
+			scenario.CapMultiDocumentTransactions, scenario.CapSnapshotReads, scenario.CapSecondaryReads,
+			scenario.CapFailPoints, scenario.CapShardedCluster,
+		},
+	},
+	{
+		// Built from docker/mongodb-slim: mongod and its libraries only, without mongosh and the
+		// database tools, and with the replica set initiated at build time. Single node only.
+		Name: "slim",
+		Ref:  "txviewer/mongodb-slim:7.0",
+		Validated: []scenario.Capability{
+			scenario.CapMultiDocumentTransactions, scenario.CapSnapshotReads, scenario.CapFailPoints,
+		},
+	},
+}
+
+// ResolveImage returns the reference of the preset called name, or name itself for any other image
+func ResolveImage(name string) string {
+	if p, ok := presetByName(name); ok {
+		return p.Ref
+	}
+	return name
+}
+
+// PresetNames returns the names of the image presets, for flag help
+func PresetNames() string {
+	names := make([]string, len(ImagePresets))
+	for i, p := range ImagePresets {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// presetByName returns the preset called name
+func presetByName(name string) (ImagePreset, bool) {
+	for _, p := range ImagePresets {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return ImagePreset{}, false
+}
+
+// presetByRef returns the preset launching ref
+func presetByRef(ref string) (ImagePreset, bool) {
+	for _, p := range ImagePresets {
+		if p.Ref == ref {
+			return p, true
+		}
+	}
+	return ImagePreset{}, false
+}
+
+// unvalidated returns the capabilities in caps no scenario was validated with on the preset
+// launching ref, or nil for an image that isn't a preset
+func unvalidated(ref string, caps scenario.CapabilitySet) []scenario.Capability {
+	p, ok := presetByRef(ref)
+	if !ok {
+		return nil
+	}
+	validated := scenario.NewCapabilitySet(p.Validated...)
+	var missing []scenario.Capability
+	for c, ok := range caps {
+		if ok && !validated.Has(c) {
+			missing = append(missing, c)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// preinitialized returns the replica set name the image img declares with preinitLabel, or ""
+// when its node has to be initiated at startup
+func preinitialized(ctx context.Context, img string) (string, error) {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer cli.Close()
+
+	inspected, err := cli.ImageInspect(ctx, img)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", img, err)
+	}
+	if inspected.Config == nil {
+		return "", nil
+	}
+	setName := inspected.Config.Labels[preinitLabel]
+	if setName != "" {
+		slog.InfoContext(ctx, "image has a pre-initialized replica set", "image", img, "set", setName)
+	}
+	return setName, nil
+}
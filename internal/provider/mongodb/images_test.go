@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+func TestResolveImage(t *testing.T) {
+	if got := ResolveImage("slim"); got != "txviewer/mongodb-slim:7.0" {
+		t.Fatalf("Expected the slim preset to resolve to its reference, got %q", got)
+	}
+	if got := ResolveImage("mongo:8.0"); got != "mongo:8.0" {
+		t.Fatalf("Expected other images to be kept, got %q", got)
+	}
+}
+
+func TestUnvalidated(t *testing.T) {
+	caps := scenario.NewCapabilitySet(scenario.CapMultiDocumentTransactions, scenario.CapSecondaryReads)
+
+	got := unvalidated(ResolveImage("slim"), caps)
+	if !slices.Equal(got, []scenario.Capability{scenario.CapSecondaryReads}) {
+		t.Fatalf("Expected secondary reads to be unvalidated on the slim image, got %v", got)
+	}
+	if got := unvalidated(DefaultImage, caps); got != nil {
+		t.Fatalf("Expected every capability to be validated on the default image, got %v", got)
+	}
+	if got := unvalidated("mongo:8.0", caps); got != nil {
+		t.Fatalf("Expected nothing to be reported for an image that isn't a preset, got %v", got)
+	}
+}
+
+func TestSlimDockerfile_DeclaresThePreinitLabel(t *testing.T) {
+	data, err := os.ReadFile("../../../docker/mongodb-slim/Dockerfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "LABEL " + preinitLabel + "=" + replicaSetName; !strings.Contains(string(data), want) {
+		t.Fatalf("Expected the Dockerfile to contain %q", want)
+	}
+}
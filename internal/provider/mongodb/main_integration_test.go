@@ -0,0 +1,47 @@
+//go:build integration
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// shared is the provider integration tests share, started once by TestMain. Tests needing another
+// image, topology or database start their own.
+var shared *Provider
+
+// Run with: make test-integration, or go test -tags integration ./internal/provider/mongodb/ (needs Docker)
+func TestMain(m *testing.M) {
+	os.Exit(runIntegration(m))
+}
+
+// runIntegration starts the shared provider around the tests. A panicking test ends the process
+// before the deferred Stop runs, so the containers are then left to the testcontainers reaper,
+// which removes them once the process is gone.
+func runIntegration(m *testing.M) int {
+	if testcontainers.ReadConfig().Config.RyukDisabled {
+		fmt.Fprintln(os.Stderr, "Integration tests need the testcontainers reaper to remove the containers of a "+
+			"test that panics; unset TESTCONTAINERS_RYUK_DISABLED")
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	shared = NewProvider()
+	if err := shared.Start(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to start MongoDB: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := shared.Stop(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to stop MongoDB: %v\n", err)
+		}
+	}()
+	return m.Run()
+}
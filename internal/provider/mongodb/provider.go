@@ -3,28 +3,130 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/logging"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
 
 	mongoScenarios "github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/mongodb"
+
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// Compile-time interface check
-var _ provider.Provider = (*Provider)(nil)
+// Compile-time interface checks
+var (
+	_ provider.Provider       = (*Provider)(nil)
+	_ provider.Configurable   = (*Provider)(nil)
+	_ provider.ImageProvider  = (*Provider)(nil)
+	_ provider.ParamSource    = (*Provider)(nil)
+	_ provider.Attachable     = (*Provider)(nil)
+	_ provider.EndpointSource = (*Provider)(nil)
+	_ provider.RuntimeUser    = (*Provider)(nil)
+)
 
 // Provider implements the provider.Provider interface for MongoDB
 type Provider struct {
 	container *Container
 	scenarios *scenario.Registry
+	params    scenario.Params
+
+	startMu sync.Mutex // Serializes Start and Stop
+	started bool       // Start succeeded and Stop hasn't been called since
+
+	mu           sync.RWMutex
+	capabilities scenario.CapabilitySet
+
+	// Overridable for tests
+	launch func(ctx context.Context) (scenario.CapabilitySet, error)
+}
+
+// Option configures a MongoDB provider
+type Option func(*ContainerConfig)
+
+// WithImage selects the MongoDB image launched on Start, by reference or preset name
+func WithImage(image string) Option {
+	return func(c *ContainerConfig) {
+		c.Image = ResolveImage(image)
+	}
+}
+
+// WithTopology selects the replica set topology launched on Start
+func WithTopology(t Topology) Option {
+	return func(c *ContainerConfig) {
+		c.Topology = t
+	}
+}
+
+// WithStopTimeout bounds how long Stop waits before force-removing containers
+func WithStopTimeout(d time.Duration) Option {
+	return func(c *ContainerConfig) {
+		c.StopTimeout = d
+	}
+}
+
+// WithReadyTimeout bounds how long Start waits for the deployment to accept writes
+func WithReadyTimeout(d time.Duration) Option {
+	return func(c *ContainerConfig) {
+		c.ReadyTimeout = d
+	}
+}
+
+// WithResourceLimits caps memory (bytes) and CPU (cores) for every launched container; zero means unlimited
+func WithResourceLimits(memory int64, cpus float64) Option {
+	return func(c *ContainerConfig) {
+		c.MemoryLimit = memory
+		c.CPULimit = cpus
+	}
+}
+
+// WithDatabase selects the database scenarios create their collections in
+func WithDatabase(name string) Option {
+	return func(c *ContainerConfig) {
+		c.Database = name
+	}
+}
+
+// WithComposeService attaches to a running docker compose service instead of launching containers
+func WithComposeService(project, service string) Option {
+	return func(c *ContainerConfig) {
+		c.Compose = ComposeService{Project: project, Service: service}
+	}
+}
+
+// WithURI connects to an existing deployment instead of launching containers
+func WithURI(uri string) Option {
+	return func(c *ContainerConfig) {
+		c.URI = uri
+	}
+}
+
+// WithDataVolume keeps the single node's data in a named Docker volume between starts
+func WithDataVolume(on bool) Option {
+	return func(c *ContainerConfig) {
+		c.DataVolume = on
+	}
 }
 
 // NewProvider creates a new MongoDB provider
-func NewProvider() *Provider {
+func NewProvider(opts ...Option) *Provider {
+	var config ContainerConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
 	p := &Provider{
-		container: NewContainer(),
+		container: NewContainer(config),
 		scenarios: scenario.NewRegistry(),
+		params:    scenario.DefaultParams(),
 	}
+	p.launch = p.launchContainer
+
+	// Scenarios resolve the client lazily, so their metadata is available before Start
+	p.registerScenarios()
 	return p
 }
 
@@ -35,24 +137,70 @@ func (p *Provider) Name() string {
 
 // Description returns the provider description
 func (p *Provider) Description() string {
-	return "MongoDB 7.0 with replica set for multi-document transaction support"
+	config := p.container.Config()
+	if config.Compose.IsSet() {
+		return i18n.T("Existing MongoDB from docker compose service %s (not started or stopped by txviewer)", config.Compose.String())
+	}
+
+	switch config.Topology {
+	case TopologyReplicaSet:
+		return i18n.T("MongoDB 7.0 three-member replica set for secondary reads and rollbacks")
+	case TopologySharded:
+		return i18n.T("MongoDB 7.0 sharded cluster (2 shards + mongos) for distributed transactions")
+	}
+	return i18n.T("MongoDB 7.0 with replica set for multi-document transaction support")
 }
 
-// Start initializes the MongoDB container and registers scenarios
+// Start launches the MongoDB container and detects what the server supports. It is idempotent:
+// concurrent calls wait for the start in flight, and calls after it succeeded return at once.
 func (p *Provider) Start(ctx context.Context) error {
-	if err := p.container.Start(ctx); err != nil {
+	p.startMu.Lock()
+	defer p.startMu.Unlock()
+
+	if p.started {
+		return nil
+	}
+	caps, err := p.launch(ctx)
+	if err != nil {
 		return err
 	}
 
-	// Register MongoDB-specific scenarios
-	p.scenarios.Clear()
-	p.registerScenarios()
-
+	p.mu.Lock()
+	p.capabilities = caps
+	p.mu.Unlock()
+	p.started = true
 	return nil
 }
 
+// launchContainer starts the container and inspects the server rather than assuming what the image supports
+func (p *Provider) launchContainer(ctx context.Context) (scenario.CapabilitySet, error) {
+	if err := p.container.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	provider.ReportProgress(ctx, i18n.T("Detecting server capabilities..."))
+	caps, err := detectCapabilities(ctx, p.container.Client())
+	if err != nil {
+		// The context may have been cancelled, which must not leave the container behind
+		p.container.Stop(context.WithoutCancel(ctx))
+		return nil, err
+	}
+	if missing := unvalidated(p.container.Config().Image, caps); len(missing) > 0 {
+		slog.WarnContext(ctx, "scenarios needing these capabilities were not validated against the image",
+			"image", p.container.Config().Image, "capabilities", missing)
+	}
+	return caps, nil
+}
+
 // Stop terminates the MongoDB container
 func (p *Provider) Stop(ctx context.Context) error {
+	p.startMu.Lock()
+	defer p.startMu.Unlock()
+
+	p.mu.Lock()
+	p.capabilities = nil
+	p.mu.Unlock()
+	p.started = false
 	return p.container.Stop(ctx)
 }
 
@@ -66,13 +214,148 @@ func (p *Provider) GetScenarios() *scenario.Registry {
 	return p.scenarios
 }
 
+// Capabilities returns the features detected on the running server
+func (p *Provider) Capabilities() scenario.CapabilitySet {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.capabilities
+}
+
 // ConnectionInfo returns connection details
 func (p *Provider) ConnectionInfo() string {
 	connStr := p.container.ConnectionString()
 	if connStr == "" {
-		return "Not connected"
+		return ""
+	}
+	config := p.container.Config()
+
+	var info string
+	switch {
+	case config.URI != "":
+		info = fmt.Sprintf("Connected to external MongoDB\n%s", connStr)
+	case config.Compose.IsSet():
+		info = fmt.Sprintf("Attached to compose service %s\n%s", config.Compose, connStr)
+	default:
+		switch config.Topology {
+		case TopologyReplicaSet:
+			info = fmt.Sprintf("Connected to 3-member MongoDB replica set\n%s", connStr)
+		case TopologySharded:
+			info = fmt.Sprintf("Connected to MongoDB sharded cluster via mongos\n%s", connStr)
+		default:
+			info = fmt.Sprintf("Connected to MongoDB replica set\n%s", connStr)
+		}
+	}
+
+	info += fmt.Sprintf("\nDatabase: %s", config.Database)
+	if id := p.container.ContainerID(); id != "" {
+		info += fmt.Sprintf("\nContainer: %s (%s)", id, p.container.ContainerName())
+	}
+	if config.HasLimits() {
+		info += fmt.Sprintf("\nLimits: %s", config.LimitsSummary())
+	}
+	return info
+}
+
+// Endpoint returns the connection string and scenario database of the running deployment
+func (p *Provider) Endpoint() (provider.Endpoint, error) {
+	connStr := p.container.ConnectionString()
+	if connStr == "" {
+		return provider.Endpoint{}, scenario.ErrProviderNotStarted
+	}
+	return provider.Endpoint{Provider: p.Name(), URI: connStr, Database: p.container.Config().Database}, nil
+}
+
+// ContainerID returns the ID of the container a shell would exec into
+func (p *Provider) ContainerID() string {
+	return p.container.ContainerID()
+}
+
+// ShellCommand returns a docker exec command that opens mongosh, or a plain mongosh command when no container is known
+func (p *Provider) ShellCommand() string {
+	return p.container.ShellCommand()
+}
+
+// Images returns the container image used by every topology, or none when attached
+func (p *Provider) Images() []string {
+	config := p.container.Config()
+	if config.Compose.IsSet() || config.URI != "" {
+		return nil
+	}
+	return []string{config.Image}
+}
+
+// AttachedTo returns the compose service or external deployment in use, or "" when txviewer manages the containers
+func (p *Provider) AttachedTo() string {
+	config := p.container.Config()
+	switch {
+	case config.URI != "":
+		return "uri:" + logging.Redact(config.URI)
+	case config.Compose.IsSet():
+		return "compose:" + config.Compose.String()
 	}
-	return fmt.Sprintf("Connected to MongoDB replica set\n%s", connStr)
+	return ""
+}
+
+// NeedsRuntime returns whether Start uses Docker, which it does unless connecting by URI; a
+// compose service is located through Docker too
+func (p *Provider) NeedsRuntime() bool {
+	return p.container.Config().URI == ""
+}
+
+// Settings returns the options shown before the provider is started
+func (p *Provider) Settings() []provider.Setting {
+	topologies := make([]string, len(Topologies))
+	for i, t := range Topologies {
+		topologies[i] = string(t)
+	}
+
+	sizes := make([]string, len(scenario.DatasetSizes))
+	for i, d := range scenario.DatasetSizes {
+		sizes[i] = string(d)
+	}
+
+	return []provider.Setting{
+		{
+			Key:         "topology",
+			Name:        i18n.T("Topology"),
+			Description: i18n.T("sharded starts 5 containers and takes noticeably longer"),
+			Choices:     topologies,
+			Value:       string(p.container.Config().Topology),
+		},
+		{
+			Key:         "dataset",
+			Name:        i18n.T("Dataset size"),
+			Description: i18n.T("documents seeded by range scenarios: 10 / 1,000 / 100,000"),
+			Choices:     sizes,
+			Value:       string(p.params.DatasetSize),
+		},
+	}
+}
+
+// ApplySetting changes a setting for the next Start
+func (p *Provider) ApplySetting(key, value string) error {
+	switch key {
+	case "topology":
+		t, err := ParseTopology(value)
+		if err != nil {
+			return err
+		}
+		p.container.SetTopology(t)
+		return nil
+	case "dataset":
+		d, err := scenario.ParseDatasetSize(value)
+		if err != nil {
+			return err
+		}
+		p.params.DatasetSize = d
+		return nil
+	}
+	return fmt.Errorf("unknown MongoDB setting %q", key)
+}
+
+// ScenarioParams returns the params chosen on the options screen
+func (p *Provider) ScenarioParams() scenario.Params {
+	return p.params
 }
 
 // GetContainer returns the underlying container for scenario access
@@ -80,14 +363,41 @@ func (p *Provider) GetContainer() *Container {
 	return p.container
 }
 
+// handle resolves the live client and scenario database, failing until Start has succeeded
+func (p *Provider) handle() (*mongo.Client, *mongo.Database, error) {
+	client := p.container.Client()
+	if client == nil {
+		return nil, nil, scenario.ErrProviderNotStarted
+	}
+	return client, client.Database(p.container.Config().Database), nil
+}
+
 // registerScenarios registers all MongoDB-specific scenarios
 func (p *Provider) registerScenarios() {
-	db := p.container.Database("txdemo")
-	client := p.container.Client()
+	p.scenarios.Register(mongoScenarios.NewDirtyReadScenario(p.handle))
+	p.scenarios.Register(mongoScenarios.NewReadCommittedScenario(p.handle))
+	p.scenarios.Register(mongoScenarios.NewSnapshotIsolationScenario(p.handle))
+	p.scenarios.Register(mongoScenarios.NewWriteConflictScenario(p.handle))
+	p.scenarios.Register(mongoScenarios.NewDistributedTransactionScenario(p.handle))
+	p.scenarios.Register(mongoScenarios.NewPhantomReadScenario(p.handle))
 
-	// Register scenarios
-	p.scenarios.Register(mongoScenarios.NewDirtyReadScenario(client, db))
-	p.scenarios.Register(mongoScenarios.NewReadCommittedScenario(client, db))
-	p.scenarios.Register(mongoScenarios.NewSnapshotIsolationScenario(client, db))
-	p.scenarios.Register(mongoScenarios.NewWriteConflictScenario(client, db))
+	// The examples are parsed by tests, so an error here is a bug in a shipped file
+	examples, err := mongoScenarios.ExampleScripts()
+	if err != nil {
+		panic(err)
+	}
+	if err := p.AddScripts(examples); err != nil {
+		panic(err)
+	}
+}
+
+// AddScripts registers scenarios declared in script files. Names must not clash with registered scenarios.
+func (p *Provider) AddScripts(scripts []*mongoScenarios.Script) error {
+	for _, s := range scripts {
+		if p.scenarios.GetByName(s.Name) != nil {
+			return fmt.Errorf("%s: a scenario named %q already exists", s.Path, s.Name)
+		}
+		p.scenarios.Register(mongoScenarios.NewScriptScenario(p.handle, s))
+	}
+	return nil
 }
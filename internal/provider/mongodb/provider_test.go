@@ -0,0 +1,75 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+func TestProvider_ScenariosBeforeStart(t *testing.T) {
+	p := NewProvider()
+	ctx := context.Background()
+
+	scenarios := p.GetScenarios().GetAll()
+	if len(scenarios) == 0 {
+		t.Fatal("Expected scenarios to be registered before Start")
+	}
+
+	for _, s := range scenarios {
+		if err := s.Setup(ctx); !errors.Is(err, scenario.ErrProviderNotStarted) {
+			t.Fatalf("Expected ErrProviderNotStarted from %s Setup, got %v", s.Name(), err)
+		}
+
+		output := make(chan scenario.StepResult, 1)
+		if err := s.Run(ctx, output); !errors.Is(err, scenario.ErrProviderNotStarted) {
+			t.Fatalf("Expected ErrProviderNotStarted from %s Run, got %v", s.Name(), err)
+		}
+		if len(output) > 0 {
+			t.Fatalf("Expected %s Run to send no steps", s.Name())
+		}
+	}
+}
+
+func TestProvider_ConcurrentStartLaunchesOnce(t *testing.T) {
+	p := NewProvider()
+	registered := len(p.GetScenarios().GetAll())
+
+	var launches atomic.Int32
+	p.launch = func(ctx context.Context) (scenario.CapabilitySet, error) {
+		launches.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return scenario.CapabilitySet{scenario.CapMultiDocumentTransactions: true}, nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- p.Start(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if n := launches.Load(); n != 1 {
+		t.Fatalf("Expected one launch, got %d", n)
+	}
+	if got := len(p.GetScenarios().GetAll()); got != registered {
+		t.Fatalf("Expected %d scenarios, got %d", registered, got)
+	}
+	if !p.Capabilities()[scenario.CapMultiDocumentTransactions] {
+		t.Fatal("Expected the detected capabilities to be kept")
+	}
+}
@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// DefaultReadyTimeout bounds how long a start waits for a node or replica set to accept writes
+	DefaultReadyTimeout = 90 * time.Second
+
+	// probeInterval is how often a starting node is asked whether it takes writes yet
+	probeInterval = 50 * time.Millisecond
+	// probeTimeout bounds one round of the readiness probe
+	probeTimeout = time.Second
+	// probeCollection takes the readiness probe's test write and is dropped right after it
+	probeCollection = "txviewer_ready"
+)
+
+// errNotPrimary reports a node that is up but doesn't accept writes yet
+var errNotPrimary = errors.New("node is not a writable primary yet")
+
+// alreadyInitializedCode is the server error code replSetInitiate fails with on a member that
+// has a replica set config
+const alreadyInitializedCode = 23
+
+// initiateSingleNode makes the node client is directly connected to a one-member replica set,
+// which is what transactions need. The member addresses itself as localhost, so the in-container
+// shell reaches it as well. A node whose dbpath, e.g. on a reused data volume, holds a config
+// already is left as it is.
+func initiateSingleNode(ctx context.Context, client *mongo.Client) error {
+	admin := client.Database("admin")
+	var hello struct {
+		SetName string `bson:"setName"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return fmt.Errorf("failed to run hello: %w", err)
+	}
+	if hello.SetName != "" {
+		return nil
+	}
+
+	config := bson.D{
+		{Key: "_id", Value: replicaSetName},
+		{Key: "members", Value: bson.A{bson.D{{Key: "_id", Value: 0}, {Key: "host", Value: "localhost:27017"}}}},
+	}
+	err := admin.RunCommand(ctx, bson.D{{Key: "replSetInitiate", Value: config}}).Err()
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.HasErrorCode(alreadyInitializedCode) {
+		// The config was still loading when hello answered
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to initiate replica set: %w", err)
+	}
+	return nil
+}
+
+// waitWritablePrimary polls hello until the node reports itself as a writable primary and then
+// makes a test write in db, returning as soon as one goes through rather than after a fixed wait.
+// It gives up after timeout.
+func waitWritablePrimary(ctx context.Context, db *mongo.Database, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+
+	for {
+		err := probeWritable(ctx, db)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("MongoDB did not accept writes within %s: %w", timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeWritable asks the node whether it is a writable primary and, if so, writes to db
+func probeWritable(ctx context.Context, db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	var hello struct {
+		IsWritablePrimary bool `bson:"isWritablePrimary"`
+	}
+	if err := db.Client().Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return fmt.Errorf("failed to run hello: %w", err)
+	}
+	if !hello.IsWritablePrimary {
+		return errNotPrimary
+	}
+
+	coll := db.Collection(probeCollection)
+	if _, err := coll.InsertOne(ctx, bson.M{"at": time.Now()}); err != nil {
+		return fmt.Errorf("failed to make a test write: %w", err)
+	}
+	return coll.Drop(ctx)
+}
@@ -0,0 +1,130 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	replicaSetName    = "rs0"
+	replicaSetMembers = 3
+)
+
+// replicaSet is a three-member replica set running in separate containers on one Docker network
+type replicaSet struct {
+	*containerGroup
+	aliases []string
+}
+
+// startReplicaSet launches the members and initiates the replica set.
+// On failure the partially created replica set is returned so the caller can tear it down.
+func startReplicaSet(ctx context.Context, config ContainerConfig) (*replicaSet, error) {
+	provider.ReportProgress(ctx, i18n.T("Creating Docker network..."))
+	group, err := newContainerGroup(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	rs := &replicaSet{containerGroup: group}
+
+	for i := 0; i < replicaSetMembers; i++ {
+		alias := fmt.Sprintf("mongo%d", i)
+		provider.ReportProgress(ctx, i18n.T("Starting replica set member %s...", alias))
+
+		if _, err := group.run(ctx, alias, "mongod", "--replSet", replicaSetName, "--bind_ip_all"); err != nil {
+			return rs, err
+		}
+		rs.aliases = append(rs.aliases, alias)
+	}
+
+	provider.EnterPhase(ctx, provider.PhaseInit)
+	provider.ReportProgress(ctx, i18n.T("Initiating replica set..."))
+	if err := rs.initiate(ctx); err != nil {
+		return rs, err
+	}
+
+	return rs, nil
+}
+
+// initiate runs replSetInitiate on the first member with every member's network alias
+func (rs *replicaSet) initiate(ctx context.Context) error {
+	members := make([]string, len(rs.aliases))
+	for i, alias := range rs.aliases {
+		// Favor the first member so the primary is predictable
+		priority := 1
+		if i == 0 {
+			priority = 2
+		}
+		members[i] = fmt.Sprintf("{_id: %d, host: '%s:27017', priority: %d}", i, alias, priority)
+	}
+	script := fmt.Sprintf("rs.initiate({_id: '%s', members: [%s]})", replicaSetName, strings.Join(members, ", "))
+
+	if _, err := execScript(ctx, rs.members[0], script); err != nil {
+		return fmt.Errorf("failed to initiate replica set: %w", err)
+	}
+	return nil
+}
+
+// WaitReady polls replSetGetStatus until there is a primary and every other member is a secondary
+func (rs *replicaSet) WaitReady(ctx context.Context, client *mongo.Client) error {
+	ctx, cancel := context.WithTimeout(ctx, rs.config.ReadyTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var status struct {
+			Members []memberStatus `bson:"members"`
+		}
+		err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetGetStatus", Value: 1}}).Decode(&status)
+		if err == nil && replicaSetHealthy(status.Members) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = errors.New("members did not reach PRIMARY/SECONDARY state")
+			}
+			return fmt.Errorf("replica set not ready after %s: %w", rs.config.ReadyTimeout, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// memberStatus is the part of a replSetGetStatus member entry used for readiness
+type memberStatus struct {
+	StateStr string `bson:"stateStr"`
+}
+
+// replicaSetHealthy returns whether exactly one member is primary and the rest are secondaries
+func replicaSetHealthy(members []memberStatus) bool {
+	primaries, secondaries := 0, 0
+	for _, m := range members {
+		switch m.StateStr {
+		case "PRIMARY":
+			primaries++
+		case "SECONDARY":
+			secondaries++
+		}
+	}
+	return len(members) == replicaSetMembers && primaries == 1 && secondaries == replicaSetMembers-1
+}
+
+// ConnectionString lists every member by its in-network address
+func (rs *replicaSet) ConnectionString() string {
+	hosts := make([]string, len(rs.aliases))
+	for i, alias := range rs.aliases {
+		hosts[i] = alias + ":27017"
+	}
+	return fmt.Sprintf("mongodb://%s/?replicaSet=%s", strings.Join(hosts, ","), replicaSetName)
+}
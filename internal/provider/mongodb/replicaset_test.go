@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"testing"
+)
+
+func TestReplicaSetHealthy(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []string
+		healthy bool
+	}{
+		{"all up", []string{"PRIMARY", "SECONDARY", "SECONDARY"}, true},
+		{"election in progress", []string{"SECONDARY", "SECONDARY", "SECONDARY"}, false},
+		{"member still syncing", []string{"PRIMARY", "SECONDARY", "STARTUP2"}, false},
+		{"missing member", []string{"PRIMARY", "SECONDARY"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := make([]memberStatus, len(tt.states))
+			for i, s := range tt.states {
+				members[i] = memberStatus{StateStr: s}
+			}
+			if got := replicaSetHealthy(members); got != tt.healthy {
+				t.Fatalf("Expected healthy=%v, got %v", tt.healthy, got)
+			}
+		})
+	}
+}
+
+func TestReplicaSetConnectionString(t *testing.T) {
+	rs := &replicaSet{aliases: []string{"mongo0", "mongo1", "mongo2"}}
+
+	want := "mongodb://mongo0:27017,mongo1:27017,mongo2:27017/?replicaSet=rs0"
+	if got := rs.ConnectionString(); got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestParseTopology(t *testing.T) {
+	if topo, err := ParseTopology("replicaset"); err != nil || topo != TopologyReplicaSet {
+		t.Fatalf("Expected replicaset topology, got %q (%v)", topo, err)
+	}
+	if _, err := ParseTopology("sharded-ish"); err == nil {
+		t.Fatal("Expected error for unknown topology")
+	}
+}
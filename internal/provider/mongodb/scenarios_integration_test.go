@@ -0,0 +1,212 @@
+//go:build integration
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+func TestScenarios_DeclaredAssertionsHold(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	params := shared.ScenarioParams()
+	params.Pacing = 0
+	for _, s := range shared.GetScenarios().GetAll() {
+		t.Run(s.Name(), func(t *testing.T) {
+			if missing := shared.Capabilities().Missing(scenario.Requirements(s)); len(missing) > 0 {
+				t.Skipf("Requires %v", missing)
+			}
+			run := headless.Record(ctx, s, params, nil)
+			if run.Err != nil {
+				t.Fatalf("Expected the run to succeed, got %v", run.Err)
+			}
+			if len(run.Assertions) == 0 {
+				t.Fatal("Expected the run to check assertions")
+			}
+			for _, a := range run.FailedAssertions() {
+				t.Errorf("Expected %q to hold, observed %s", a.Name, a.Detail)
+			}
+		})
+	}
+}
+
+func TestScenarios_RunTwiceOnFreshDatabase(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// A database no scenario has used, so the first Setup drops collections that don't exist
+	p := NewProvider(WithDatabase(fmt.Sprintf("txviewer_fresh_%d", time.Now().UnixNano())))
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("Expected the provider to start, got %v", err)
+	}
+	defer p.Stop(context.Background())
+
+	params := p.ScenarioParams()
+	params.Pacing = 0
+	for _, s := range p.GetScenarios().GetAll() {
+		if missing := p.Capabilities().Missing(scenario.Requirements(s)); len(missing) > 0 {
+			continue
+		}
+		for attempt := 1; attempt <= 2; attempt++ {
+			run := headless.Record(ctx, s, params, nil)
+			if v := run.Verdict(); v != report.VerdictPass {
+				t.Fatalf("Expected %s to pass on run %d, got %s: %v", s.Name(), attempt, v, run.Err)
+			}
+		}
+	}
+}
+
+func TestSnapshotScenario_CountsAcrossVersions(t *testing.T) {
+	for _, image := range []string{"mongo:6.0", "mongo:7.0"} {
+		t.Run(image, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			p := NewProvider(WithImage(image))
+			if err := p.Start(ctx); err != nil {
+				t.Fatalf("Expected the provider to start, got %v", err)
+			}
+			defer p.Stop(context.Background())
+
+			params := p.ScenarioParams()
+			params.Pacing = 0
+			run := headless.Record(ctx, p.GetScenarios().GetByAnomaly(scenario.AnomalyReadSkew), params, nil)
+			if run.Err != nil {
+				t.Fatalf("Expected no error, got %v", run.Err)
+			}
+
+			// Counts by role: the snapshot read and reread inside the transaction, then the read after it
+			want := map[string]string{
+				scenario.RoleRead:   "Product count: 3",
+				scenario.RoleReread: "Product count: 3",
+				scenario.RoleVerify: "Product count: 4",
+			}
+			for _, step := range run.Steps {
+				prefix, ok := want[step.Role]
+				if !ok || step.Session != "Session A" {
+					continue
+				}
+				if !strings.HasPrefix(step.Result, prefix) {
+					t.Fatalf("Expected the %s step to report %q, got %q", step.Role, prefix, step.Result)
+				}
+				delete(want, step.Role)
+			}
+			if len(want) > 0 {
+				t.Fatalf("Expected steps for roles %v", want)
+			}
+		})
+	}
+}
+
+func TestContainer_StartsWritableAndTimesTheWait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	timer := provider.NewStartupTimer()
+	c := NewContainer(ContainerConfig{})
+	if err := c.Start(provider.WithTimer(ctx, timer)); err != nil {
+		t.Fatalf("Expected the container to start, got %v", err)
+	}
+	defer c.Stop(context.Background())
+	m := timer.Finish("MongoDB")
+	t.Log(m.Summary())
+
+	if _, ok := m.Phases[provider.PhaseReady]; !ok {
+		t.Fatalf("Expected the wait for writes to be timed, got %v", m.Phases)
+	}
+	if _, err := c.client.Database(DefaultDatabase).Collection("probe").InsertOne(ctx, map[string]int{"n": 1}); err != nil {
+		t.Fatalf("Expected the started node to take writes, got %v", err)
+	}
+}
+
+func TestContainer_StoppedContainerFailsOperationsQuickly(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	c := NewContainer(ContainerConfig{})
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Expected the container to start, got %v", err)
+	}
+	defer c.Stop(context.Background())
+
+	// Stopped behind the client's back, as when Docker or the container dies
+	client := c.Client()
+	timeout := 10 * time.Second
+	if err := c.container.Stop(ctx, &timeout); err != nil {
+		t.Fatalf("Expected the container to stop, got %v", err)
+	}
+
+	started := time.Now()
+	_, err := client.Database(DefaultDatabase).Collection("probe").InsertOne(context.Background(), map[string]int{"n": 1})
+	if err == nil {
+		t.Fatal("Expected the write to fail once the container stopped")
+	}
+	if elapsed := time.Since(started); elapsed > serverSelectionTimeout+2*time.Second {
+		t.Fatalf("Expected the write to fail within about %v, took %v", serverSelectionTimeout, elapsed)
+	}
+}
+
+func TestContainer_StartStopLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	c := NewContainer(ContainerConfig{})
+	defer c.Stop(context.Background())
+	if c.IsRunning() {
+		t.Fatal("Expected a new container not to be running")
+	}
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Expected the container to start, got %v", err)
+	}
+	if !c.IsRunning() {
+		t.Fatal("Expected the started container to be running")
+	}
+
+	uri := c.ConnectionString()
+	if _, err := connstring.ParseAndValidate(uri); err != nil {
+		t.Fatalf("Expected a valid connection string, got %q: %v", uri, err)
+	}
+	// A client of its own shows the string reaches the node, not only that it parses
+	client, err := mongo.Connect(ctx, clientOptions(uri))
+	if err != nil {
+		t.Fatalf("Expected to connect with %q, got %v", uri, err)
+	}
+	defer client.Disconnect(context.Background())
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatalf("Expected %q to reach the node, got %v", uri, err)
+	}
+
+	// Starting again keeps the node that is running
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Expected a second Start to succeed, got %v", err)
+	}
+	if got := c.ConnectionString(); got != uri {
+		t.Fatalf("Expected a second Start to keep %q, got %q", uri, got)
+	}
+
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("Expected the container to stop, got %v", err)
+	}
+	if c.IsRunning() {
+		t.Fatal("Expected the stopped container not to be running")
+	}
+	if got := c.ConnectionString(); got != "" {
+		t.Fatalf("Expected no connection string once stopped, got %q", got)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("Expected a second Stop to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	configReplicaSet = "cfg"
+	shardCount       = 2
+)
+
+// shardedCluster is a config server, two single-member shard replica sets and a mongos
+type shardedCluster struct {
+	*containerGroup
+	mongosAddr string
+}
+
+// startShardedCluster launches and wires up every component of the cluster.
+// On failure the partially created cluster is returned so the caller can tear it down.
+func startShardedCluster(ctx context.Context, config ContainerConfig) (*shardedCluster, error) {
+	provider.ReportProgress(ctx, i18n.T("Creating Docker network..."))
+	group, err := newContainerGroup(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	sc := &shardedCluster{containerGroup: group}
+
+	// Config server replica set
+	provider.ReportProgress(ctx, i18n.T("Starting config server..."))
+	cfg, err := group.run(ctx, "cfg0",
+		"mongod", "--configsvr", "--replSet", configReplicaSet, "--port", "27017", "--bind_ip_all")
+	if err != nil {
+		return sc, err
+	}
+	if err := initiateSingleMember(ctx, cfg, configReplicaSet, "cfg0", true, config.ReadyTimeout); err != nil {
+		return sc, err
+	}
+
+	// Shard replica sets
+	shards := make([]string, shardCount)
+	for i := 0; i < shardCount; i++ {
+		name := fmt.Sprintf("shard%d", i)
+		provider.ReportProgress(ctx, i18n.T("Starting %s...", name))
+
+		shard, err := group.run(ctx, name,
+			"mongod", "--shardsvr", "--replSet", name, "--port", "27017", "--bind_ip_all")
+		if err != nil {
+			return sc, err
+		}
+		if err := initiateSingleMember(ctx, shard, name, name, false, config.ReadyTimeout); err != nil {
+			return sc, err
+		}
+		shards[i] = name
+	}
+
+	// Router
+	provider.ReportProgress(ctx, i18n.T("Starting mongos router..."))
+	mongos, err := group.run(ctx, "mongos",
+		"mongos", "--configdb", configReplicaSet+"/cfg0:27017", "--port", "27017", "--bind_ip_all")
+	if err != nil {
+		return sc, err
+	}
+	sc.mongosAddr = group.hostAddrs["mongos:27017"]
+
+	provider.EnterPhase(ctx, provider.PhaseInit)
+	provider.ReportProgress(ctx, i18n.T("Adding shards to the cluster..."))
+	for _, name := range shards {
+		script := fmt.Sprintf("sh.addShard('%s/%s:27017')", name, name)
+		if _, err := execScript(ctx, mongos, script); err != nil {
+			return sc, fmt.Errorf("failed to add %s: %w", name, err)
+		}
+	}
+
+	return sc, nil
+}
+
+// initiateSingleMember initiates a one-member replica set and waits until it accepts writes
+func initiateSingleMember(ctx context.Context, c testcontainers.Container, setName, alias string, configsvr bool,
+	timeout time.Duration) error {
+	script := fmt.Sprintf("rs.initiate({_id: '%s', configsvr: %t, members: [{_id: 0, host: '%s:27017'}]})",
+		setName, configsvr, alias)
+	if _, err := execScript(ctx, c, script); err != nil {
+		return fmt.Errorf("failed to initiate %s: %w", setName, err)
+	}
+	return waitWritable(ctx, c, setName, timeout)
+}
+
+// ConnectionString points at the mongos router through its published host port
+func (sc *shardedCluster) ConnectionString() string {
+	return fmt.Sprintf("mongodb://%s/", sc.mongosAddr)
+}
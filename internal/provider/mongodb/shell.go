@@ -0,0 +1,60 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// inContainerURI reaches mongod or mongos from inside its own container
+const inContainerURI = "mongodb://localhost:27017/?directConnection=true"
+
+// shellTarget is the container users exec into for a mongosh prompt
+type shellTarget struct {
+	id   string
+	name string
+	uri  string // Connection string that works from inside the container
+}
+
+// newShellTarget names a testcontainer, falling back to its ID when it can't be inspected
+func newShellTarget(ctx context.Context, c testcontainers.Container, uri string) shellTarget {
+	target := shellTarget{id: c.GetContainerID(), name: c.GetContainerID(), uri: uri}
+	if info, err := c.Inspect(ctx); err == nil && info.Name != "" {
+		target.name = strings.TrimPrefix(info.Name, "/")
+	}
+	return target
+}
+
+// command returns a docker exec into the target, or a plain mongosh against connStr when there is no container
+func (t shellTarget) command(connStr string) string {
+	if t.id == "" {
+		return fmt.Sprintf("mongosh %q", connStr)
+	}
+	return fmt.Sprintf("docker exec -it %s mongosh %q", t.name, t.uri)
+}
+
+// ContainerID returns the ID of the container a shell would exec into, or "" when not running
+func (c *Container) ContainerID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.shell.id
+}
+
+// ContainerName returns the name of the container a shell would exec into, or "" when not running
+func (c *Container) ContainerName() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.shell.name
+}
+
+// ShellCommand returns a command that opens mongosh on the running database
+func (c *Container) ShellCommand() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.connStr == "" {
+		return ""
+	}
+	return c.shell.command(c.connStr)
+}
@@ -0,0 +1,17 @@
+package mongodb
+
+import "testing"
+
+func TestShellTarget_Command(t *testing.T) {
+	target := shellTarget{id: "0123456789abcdef", name: "mongo-1", uri: inContainerURI}
+	expected := `docker exec -it mongo-1 mongosh "mongodb://localhost:27017/?directConnection=true"`
+	if got := target.command("mongodb://localhost:32768/"); got != expected {
+		t.Fatalf("Expected %s, got %s", expected, got)
+	}
+
+	// Without a managed container the hint degrades to connecting from the host
+	expected = `mongosh "mongodb://db.example.com:27017/"`
+	if got := (shellTarget{}).command("mongodb://db.example.com:27017/"); got != expected {
+		t.Fatalf("Expected %s, got %s", expected, got)
+	}
+}
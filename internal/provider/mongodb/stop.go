@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	// DefaultStopTimeout bounds how long a graceful stop may take before containers are force-removed
+	DefaultStopTimeout = 20 * time.Second
+
+	// forceRemoveTimeout bounds the force-remove fallback itself
+	forceRemoveTimeout = 10 * time.Second
+)
+
+// ErrForcedStop indicates the graceful stop timed out and the containers were force-removed
+var ErrForcedStop = errors.New("graceful stop timed out, containers were force-removed")
+
+// terminator tears down the containers backing a topology
+type terminator interface {
+	// Terminate stops and removes the containers gracefully
+	Terminate(ctx context.Context) error
+
+	// ForceRemove kills and removes the containers through the Docker API
+	ForceRemove(ctx context.Context) error
+}
+
+// stopWithTimeout terminates gracefully, falling back to a force-remove once timeout elapses
+func stopWithTimeout(ctx context.Context, t terminator, timeout time.Duration) error {
+	graceCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- t.Terminate(graceCtx)
+	}()
+
+	select {
+	case err := <-done:
+		// A prompt failure is a real error, not a wedged daemon
+		if err == nil || graceCtx.Err() == nil {
+			return err
+		}
+	case <-graceCtx.Done():
+	}
+
+	// The caller's context may already be done; the fallback gets its own deadline
+	forceCtx, cancelForce := context.WithTimeout(context.WithoutCancel(ctx), forceRemoveTimeout)
+	defer cancelForce()
+
+	if err := t.ForceRemove(forceCtx); err != nil {
+		return fmt.Errorf("%w after %s, but force-remove failed: %v", ErrForcedStop, timeout, err)
+	}
+	return fmt.Errorf("%w after %s", ErrForcedStop, timeout)
+}
+
+// ForceRemove implements terminator for a group of containers
+func (g *containerGroup) ForceRemove(ctx context.Context) error {
+	var ids []string
+	for _, m := range g.members {
+		ids = append(ids, m.GetContainerID())
+	}
+
+	if err := forceRemove(ctx, ids); err != nil {
+		return err
+	}
+	g.members = nil
+	return g.releaseNetwork(ctx)
+}
+
+// singleContainer adapts the single-node testcontainer to terminator
+type singleContainer struct {
+	testcontainers.Container
+}
+
+// Terminate stops and removes the container
+func (s singleContainer) Terminate(ctx context.Context) error {
+	return s.Container.Terminate(ctx)
+}
+
+// ForceRemove kills and removes the container through the Docker API
+func (s singleContainer) ForceRemove(ctx context.Context) error {
+	return forceRemove(ctx, []string{s.GetContainerID()})
+}
+
+// forceRemove kills and removes containers
+func forceRemove(ctx context.Context, containerIDs []string) error {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer cli.Close()
+
+	var errs []error
+	for _, id := range containerIDs {
+		if err := cli.ContainerRemove(ctx, id, container.RemoveOptions{Force: true, RemoveVolumes: true}); err != nil {
+			errs = append(errs, fmt.Errorf("container %.12s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
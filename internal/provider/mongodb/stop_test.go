@@ -0,0 +1,95 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeTerminator records how it was stopped
+type fakeTerminator struct {
+	terminateDelay time.Duration
+	terminateErr   error
+	forceErr       error
+	terminated     bool
+	forced         bool
+}
+
+func (f *fakeTerminator) Terminate(ctx context.Context) error {
+	select {
+	case <-time.After(f.terminateDelay):
+		f.terminated = true
+		return f.terminateErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *fakeTerminator) ForceRemove(ctx context.Context) error {
+	f.forced = true
+	return f.forceErr
+}
+
+func TestStopWithTimeout_Graceful(t *testing.T) {
+	f := &fakeTerminator{}
+
+	if err := stopWithTimeout(context.Background(), f, time.Second); err != nil {
+		t.Fatalf("Expected graceful stop to succeed, got %v", err)
+	}
+	if !f.terminated || f.forced {
+		t.Fatalf("Expected terminate only, got terminated=%v forced=%v", f.terminated, f.forced)
+	}
+}
+
+func TestStopWithTimeout_Forced(t *testing.T) {
+	f := &fakeTerminator{terminateDelay: time.Hour}
+
+	start := time.Now()
+	err := stopWithTimeout(context.Background(), f, 50*time.Millisecond)
+	if !errors.Is(err, ErrForcedStop) {
+		t.Fatalf("Expected ErrForcedStop, got %v", err)
+	}
+	if !f.forced {
+		t.Fatal("Expected container to be force-removed")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected stop to return shortly after the timeout, took %s", elapsed)
+	}
+}
+
+func TestStopWithTimeout_ForceFails(t *testing.T) {
+	f := &fakeTerminator{terminateDelay: time.Hour, forceErr: errors.New("daemon gone")}
+
+	err := stopWithTimeout(context.Background(), f, 10*time.Millisecond)
+	if !errors.Is(err, ErrForcedStop) {
+		t.Fatalf("Expected ErrForcedStop, got %v", err)
+	}
+}
+
+func TestStopWithTimeout_TerminateError(t *testing.T) {
+	boom := errors.New("permission denied")
+	f := &fakeTerminator{terminateErr: boom}
+
+	err := stopWithTimeout(context.Background(), f, time.Second)
+	if !errors.Is(err, boom) || errors.Is(err, ErrForcedStop) {
+		t.Fatalf("Expected the terminate error as-is, got %v", err)
+	}
+	if f.forced {
+		t.Fatal("Expected no force-remove for a prompt failure")
+	}
+}
+
+func TestContainerStop_ForcedClearsState(t *testing.T) {
+	c := NewContainer(ContainerConfig{StopTimeout: 10 * time.Millisecond})
+	c.term = &fakeTerminator{terminateDelay: time.Hour}
+	c.connStr = "mongodb://localhost:27017"
+
+	err := c.Stop(context.Background())
+	if !errors.Is(err, ErrForcedStop) {
+		t.Fatalf("Expected ErrForcedStop, got %v", err)
+	}
+	if c.IsRunning() || c.ConnectionString() != "" {
+		t.Fatal("Expected container state to be cleared after a forced stop")
+	}
+}
@@ -0,0 +1,216 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/network"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	// volumeImageLabel records the image a data volume was initialized by
+	volumeImageLabel = network.Label + ".image"
+
+	// volumeImageIDLabel records the ID of that image, which changes when a tag moves to
+	// another MongoDB version
+	volumeImageIDLabel = network.Label + ".image-id"
+
+	// dataPath is where the mongo image keeps its dbpath
+	dataPath = "/data/db"
+)
+
+// errVolumeLocked reports a data volume another txviewer process is running MongoDB on
+var errVolumeLocked = errors.New("data volume is in use by another txviewer")
+
+// volumeClient is the part of the Docker API data volumes need; *client.Client implements it
+type volumeClient interface {
+	ImageInspect(ctx context.Context, imageID string, opts ...client.ImageInspectOption) (image.InspectResponse, error)
+	VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
+	VolumeCreate(ctx context.Context, options volume.CreateOptions) (volume.Volume, error)
+	VolumeRemove(ctx context.Context, volumeID string, force bool) error
+}
+
+// Overridable for tests
+var lockDir = os.TempDir
+
+var unsafeVolumeChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// volumeName returns the data volume of image, e.g. txviewer-mongodb-mongo-7.0
+func volumeName(image string) string {
+	return "txviewer-mongodb-" + strings.Trim(unsafeVolumeChars.ReplaceAllString(image, "-"), "-")
+}
+
+// dataVolume returns the option mounting the persistent data volume of the configured image,
+// creating the volume first when needed, and the function releasing its lock. When another
+// txviewer holds the volume, nil options are returned and the node starts with throwaway data.
+func (c *Container) dataVolume(ctx context.Context) (testcontainers.ContainerCustomizer, func() error, error) {
+	name := volumeName(c.config.Image)
+	unlock, err := lockVolume(name)
+	if errors.Is(err, errVolumeLocked) {
+		slog.WarnContext(ctx, "starting without the data volume", "volume", name, "error", err)
+		provider.ReportProgress(ctx, i18n.T("Data volume %s is in use by another txviewer; starting without it...", name))
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, nil, errors.Join(fmt.Errorf("failed to connect to Docker: %w", err), unlock())
+	}
+	defer cli.Close()
+
+	if err := prepareVolume(ctx, cli, name, c.config.Image); err != nil {
+		return nil, nil, errors.Join(err, unlock())
+	}
+	return testcontainers.WithMounts(testcontainers.VolumeMount(name, dataPath)), unlock, nil
+}
+
+// prepareVolume makes sure the data volume name exists and was initialized by the current build
+// of img. A volume left by another MongoDB version is discarded, since mongod may refuse to open
+// its files, and the node is initialized again from an empty one.
+func prepareVolume(ctx context.Context, cli volumeClient, name, img string) error {
+	inspected, err := cli.ImageInspect(ctx, img)
+	if err != nil {
+		return fmt.Errorf("failed to inspect image %s: %w", img, err)
+	}
+
+	existing, err := cli.VolumeList(ctx, volume.ListOptions{Filters: filters.NewArgs(filters.Arg("name", name))})
+	if err != nil {
+		return fmt.Errorf("failed to list data volumes: %w", err)
+	}
+	for _, v := range existing.Volumes {
+		// The name filter matches substrings
+		if v.Name != name {
+			continue
+		}
+		if v.Labels[volumeImageIDLabel] == inspected.ID {
+			provider.ReportProgress(ctx, i18n.T("Reusing data volume %s...", name))
+			return nil
+		}
+		slog.InfoContext(ctx, "discarding data volume of another image version", "volume", name,
+			"image_id", v.Labels[volumeImageIDLabel], "want", inspected.ID)
+		if err := cli.VolumeRemove(ctx, name, false); err != nil {
+			return fmt.Errorf("failed to remove data volume %s of another MongoDB version: %w", name, err)
+		}
+	}
+
+	provider.ReportProgress(ctx, i18n.T("Creating data volume %s...", name))
+	_, err = cli.VolumeCreate(ctx, volume.CreateOptions{
+		Name:   name,
+		Labels: map[string]string{network.Label: "true", volumeImageLabel: img, volumeImageIDLabel: inspected.ID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create data volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// lockVolume takes the data volume name for this process, so two txviewers never run mongod on
+// the same files, and returns the function releasing it. A lock left by a process that is gone
+// is taken over.
+func lockVolume(name string) (func() error, error) {
+	path := filepath.Join(lockDir(), name+".lock")
+	for range 2 {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = fmt.Fprint(f, os.Getpid())
+			if err = errors.Join(err, f.Close()); err != nil {
+				return nil, errors.Join(fmt.Errorf("failed to lock data volume %s: %w", name, err), os.Remove(path))
+			}
+			return func() error { return os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock data volume %s: %w", name, err)
+		}
+
+		if pid := lockHolder(path); pid > 0 && processAlive(pid) {
+			return nil, fmt.Errorf("%w (process %d)", errVolumeLocked, pid)
+		}
+		slog.Info("taking over a stale data volume lock", "path", path)
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale lock %s: %w", path, err)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s keeps reappearing", errVolumeLocked, path)
+}
+
+// volumeLocked returns whether a live process holds the lock of data volume name
+func volumeLocked(name string) bool {
+	pid := lockHolder(filepath.Join(lockDir(), name+".lock"))
+	return pid > 0 && processAlive(pid)
+}
+
+// lockHolder returns the process id written to the lock file at path, or 0 when there is none
+func lockHolder(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
+
+// processAlive returns whether process pid exists. Where signals aren't supported, e.g. on
+// Windows, every holder counts as gone; mongod's own lock on its dbpath still keeps a second
+// server out.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
+
+// RemoveDataVolumes deletes the data volumes kept by --mongodb-volume and returns their names.
+// Volumes a running txviewer holds are left alone and reported in the error.
+func RemoveDataVolumes(ctx context.Context) ([]string, error) {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer cli.Close()
+	return removeDataVolumes(ctx, cli)
+}
+
+// removeDataVolumes is RemoveDataVolumes on cli
+func removeDataVolumes(ctx context.Context, cli volumeClient) ([]string, error) {
+	list, err := cli.VolumeList(ctx, volume.ListOptions{Filters: filters.NewArgs(filters.Arg("label", volumeImageIDLabel))})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data volumes: %w", err)
+	}
+
+	var removed []string
+	var errs []error
+	for _, v := range list.Volumes {
+		if volumeLocked(v.Name) {
+			errs = append(errs, fmt.Errorf("%s: %w", v.Name, errVolumeLocked))
+			continue
+		}
+		if err := cli.VolumeRemove(ctx, v.Name, false); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", v.Name, err))
+			continue
+		}
+		removed = append(removed, v.Name)
+	}
+	return removed, errors.Join(errs...)
+}
@@ -0,0 +1,144 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// fakeVolumes keeps volumes in memory and reports a fixed image ID
+type fakeVolumes struct {
+	imageID string
+	volumes map[string]map[string]string // Labels by volume name
+	removed []string
+	created []string
+}
+
+func (f *fakeVolumes) ImageInspect(ctx context.Context, imageID string, opts ...client.ImageInspectOption) (image.InspectResponse, error) {
+	return image.InspectResponse{ID: f.imageID}, nil
+}
+
+func (f *fakeVolumes) VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+	var list volume.ListResponse
+	for name, labels := range f.volumes {
+		if options.Filters.Contains("label") {
+			if _, ok := labels[volumeImageIDLabel]; !ok {
+				continue
+			}
+		}
+		list.Volumes = append(list.Volumes, &volume.Volume{Name: name, Labels: labels})
+	}
+	return list, nil
+}
+
+func (f *fakeVolumes) VolumeCreate(ctx context.Context, options volume.CreateOptions) (volume.Volume, error) {
+	f.volumes[options.Name] = options.Labels
+	f.created = append(f.created, options.Name)
+	return volume.Volume{Name: options.Name, Labels: options.Labels}, nil
+}
+
+func (f *fakeVolumes) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	delete(f.volumes, volumeID)
+	f.removed = append(f.removed, volumeID)
+	return nil
+}
+
+func TestPrepareVolume(t *testing.T) {
+	name := volumeName("mongo:7.0")
+	tests := []struct {
+		name        string
+		existing    map[string]string
+		wantRemoved bool
+		wantCreated bool
+	}{
+		{name: "missing", wantCreated: true},
+		{name: "same image", existing: map[string]string{volumeImageIDLabel: "sha256:new"}},
+		{name: "other version", existing: map[string]string{volumeImageIDLabel: "sha256:old"}, wantRemoved: true, wantCreated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeVolumes{imageID: "sha256:new", volumes: map[string]map[string]string{}}
+			if tt.existing != nil {
+				f.volumes[name] = tt.existing
+			}
+
+			if err := prepareVolume(context.Background(), f, name, "mongo:7.0"); err != nil {
+				t.Fatalf("Expected the volume to be prepared, got %v", err)
+			}
+			if got := len(f.removed) > 0; got != tt.wantRemoved {
+				t.Fatalf("Expected removed %v, got %v", tt.wantRemoved, f.removed)
+			}
+			if got := len(f.created) > 0; got != tt.wantCreated {
+				t.Fatalf("Expected created %v, got %v", tt.wantCreated, f.created)
+			}
+			if id := f.volumes[name][volumeImageIDLabel]; id != "sha256:new" {
+				t.Fatalf("Expected the volume to be labeled with the current image, got %q", id)
+			}
+		})
+	}
+}
+
+func TestLockVolume(t *testing.T) {
+	dir := t.TempDir()
+	lockDir = func() string { return dir }
+	t.Cleanup(func() { lockDir = os.TempDir })
+
+	unlock, err := lockVolume("data")
+	if err != nil {
+		t.Fatalf("Expected to lock the volume, got %v", err)
+	}
+	if _, err := lockVolume("data"); !errors.Is(err, errVolumeLocked) {
+		t.Fatalf("Expected a second lock to fail with errVolumeLocked, got %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("Expected to unlock the volume, got %v", err)
+	}
+
+	// A lock whose process is gone is taken over
+	stale := filepath.Join(dir, "data.lock")
+	if err := os.WriteFile(stale, []byte(strconv.Itoa(1<<22+1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = lockVolume("data")
+	if err != nil {
+		t.Fatalf("Expected a stale lock to be taken over, got %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("Expected to unlock the volume, got %v", err)
+	}
+}
+
+func TestRemoveDataVolumes_SkipsLockedVolumes(t *testing.T) {
+	dir := t.TempDir()
+	lockDir = func() string { return dir }
+	t.Cleanup(func() { lockDir = os.TempDir })
+
+	f := &fakeVolumes{volumes: map[string]map[string]string{
+		"txviewer-mongodb-mongo-7.0": {volumeImageIDLabel: "a"},
+		"txviewer-mongodb-mongo-8.0": {volumeImageIDLabel: "b"},
+		"unrelated":                  {},
+	}}
+	unlock, err := lockVolume("txviewer-mongodb-mongo-8.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	removed, err := removeDataVolumes(context.Background(), f)
+	if !errors.Is(err, errVolumeLocked) {
+		t.Fatalf("Expected the locked volume to be reported, got %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "txviewer-mongodb-mongo-7.0" {
+		t.Fatalf("Expected only the unlocked volume to be removed, got %v", removed)
+	}
+	if _, ok := f.volumes["unrelated"]; !ok {
+		t.Fatal("Expected volumes without the label to be kept")
+	}
+}
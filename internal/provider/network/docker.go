@@ -0,0 +1,80 @@
+package network
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// DockerManager manages networks through the Docker API testcontainers is configured for
+type DockerManager struct{}
+
+// Ensure implements Manager
+func (DockerManager) Ensure(ctx context.Context, name string, labels map[string]string) (string, error) {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer cli.Close()
+
+	// Reuse a network left from an earlier start
+	existing, err := cli.NetworkList(ctx, network.ListOptions{Filters: filters.NewArgs(filters.Arg("name", name))})
+	if err != nil {
+		return "", err
+	}
+	for _, n := range existing {
+		// The name filter matches substrings
+		if n.Name == name {
+			return n.ID, nil
+		}
+	}
+
+	created, err := cli.NetworkCreate(ctx, name, network.CreateOptions{
+		Driver:     "bridge",
+		Attachable: true,
+		Labels:     labels,
+	})
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// Remove implements Manager
+func (DockerManager) Remove(ctx context.Context, id string) error {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer cli.Close()
+
+	return cli.NetworkRemove(ctx, id)
+}
+
+// List implements Manager
+func (DockerManager) List(ctx context.Context, label string) ([]Info, error) {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer cli.Close()
+
+	summaries, err := cli.NetworkList(ctx, network.ListOptions{Filters: filters.NewArgs(filters.Arg("label", label))})
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(summaries))
+	for _, s := range summaries {
+		// List doesn't populate attached containers, so inspect each network
+		detail, err := cli.NetworkInspect(ctx, s.ID, network.InspectOptions{})
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, Info{ID: s.ID, Name: s.Name, Containers: len(detail.Containers)})
+	}
+	return infos, nil
+}
@@ -0,0 +1,140 @@
+// Package network shares one labeled Docker network between provider containers.
+// The network is created on first use, reused while any container group holds it,
+// and removed when the last one lets go. Networks left behind by crashed runs are
+// swept on startup.
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// DefaultName is the network multi-container topologies attach to
+	DefaultName = "txviewer"
+
+	// Label marks networks created by txviewer so orphans can be found
+	Label = "io.github.ravilushqa.txviewer"
+)
+
+// Info describes an existing network
+type Info struct {
+	ID         string
+	Name       string
+	Containers int // Number of attached containers
+}
+
+// Manager creates and removes networks; the Docker implementation is DockerManager
+type Manager interface {
+	// Ensure returns the ID of the named network, creating it with labels if it doesn't exist
+	Ensure(ctx context.Context, name string, labels map[string]string) (string, error)
+
+	// Remove deletes a network
+	Remove(ctx context.Context, id string) error
+
+	// List returns every network carrying label
+	List(ctx context.Context, label string) ([]Info, error)
+}
+
+// lease counts the holders of one network
+type lease struct {
+	id   string
+	refs int
+}
+
+// Pool reference-counts networks across container groups
+type Pool struct {
+	manager Manager
+	mu      sync.Mutex
+	leases  map[string]*lease
+}
+
+// NewPool creates a pool backed by manager
+func NewPool(manager Manager) *Pool {
+	return &Pool{
+		manager: manager,
+		leases:  make(map[string]*lease),
+	}
+}
+
+// Default is the pool shared by every provider in the process
+var Default = NewPool(DockerManager{})
+
+// Acquire returns the ID of the named network, creating or reusing it, and takes a reference
+func (p *Pool) Acquire(ctx context.Context, name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if l, ok := p.leases[name]; ok {
+		l.refs++
+		return l.id, nil
+	}
+
+	id, err := p.manager.Ensure(ctx, name, map[string]string{Label: "true"})
+	if err != nil {
+		return "", fmt.Errorf("failed to create Docker network %s: %w", name, err)
+	}
+	p.leases[name] = &lease{id: id, refs: 1}
+	return id, nil
+}
+
+// Release drops a reference and removes the network once nobody holds it
+func (p *Pool) Release(ctx context.Context, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l, ok := p.leases[name]
+	if !ok {
+		return fmt.Errorf("network %s is not held", name)
+	}
+
+	l.refs--
+	if l.refs > 0 {
+		return nil
+	}
+	delete(p.leases, name)
+
+	if err := p.manager.Remove(ctx, l.id); err != nil {
+		return fmt.Errorf("failed to remove Docker network %s: %w", name, err)
+	}
+	return nil
+}
+
+// Refs returns how many holders the named network has
+func (p *Pool) Refs(name string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if l, ok := p.leases[name]; ok {
+		return l.refs
+	}
+	return 0
+}
+
+// Sweep removes labeled networks that have no containers and aren't held by this process.
+// It returns the names of the removed networks.
+func (p *Pool) Sweep(ctx context.Context) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	networks, err := p.manager.List(ctx, Label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Docker networks: %w", err)
+	}
+
+	var removed []string
+	var errs []error
+	for _, n := range networks {
+		if _, held := p.leases[n.Name]; held || n.Containers > 0 {
+			continue
+		}
+		if err := p.manager.Remove(ctx, n.ID); err != nil {
+			errs = append(errs, fmt.Errorf("network %s: %w", n.Name, err))
+			continue
+		}
+		removed = append(removed, n.Name)
+	}
+	return removed, errors.Join(errs...)
+}
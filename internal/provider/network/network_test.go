@@ -0,0 +1,132 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// fakeManager records network operations in memory
+type fakeManager struct {
+	networks map[string]Info // By ID
+	created  int
+	removed  []string
+	fail     error
+}
+
+func newFakeManager(existing ...Info) *fakeManager {
+	m := &fakeManager{networks: make(map[string]Info)}
+	for _, n := range existing {
+		m.networks[n.ID] = n
+	}
+	return m
+}
+
+func (m *fakeManager) Ensure(ctx context.Context, name string, labels map[string]string) (string, error) {
+	if m.fail != nil {
+		return "", m.fail
+	}
+	for _, n := range m.networks {
+		if n.Name == name {
+			return n.ID, nil
+		}
+	}
+	m.created++
+	id := "id-" + name
+	m.networks[id] = Info{ID: id, Name: name}
+	return id, nil
+}
+
+func (m *fakeManager) Remove(ctx context.Context, id string) error {
+	if m.fail != nil {
+		return m.fail
+	}
+	delete(m.networks, id)
+	m.removed = append(m.removed, id)
+	return nil
+}
+
+func (m *fakeManager) List(ctx context.Context, label string) ([]Info, error) {
+	var infos []Info
+	for _, n := range m.networks {
+		infos = append(infos, n)
+	}
+	return infos, nil
+}
+
+func TestPool_RefCounting(t *testing.T) {
+	ctx := context.Background()
+	m := newFakeManager()
+	p := NewPool(m)
+
+	id1, err := p.Acquire(ctx, DefaultName)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	id2, _ := p.Acquire(ctx, DefaultName)
+	if id1 != id2 || m.created != 1 {
+		t.Fatalf("Expected one shared network, got %q/%q after %d creates", id1, id2, m.created)
+	}
+
+	if err := p.Release(ctx, DefaultName); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(m.removed) != 0 || p.Refs(DefaultName) != 1 {
+		t.Fatalf("Expected network to stay while still held, removed %v", m.removed)
+	}
+
+	if err := p.Release(ctx, DefaultName); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(m.removed) != 1 || p.Refs(DefaultName) != 0 {
+		t.Fatalf("Expected network removed after last release, removed %v", m.removed)
+	}
+
+	if err := p.Release(ctx, DefaultName); err == nil {
+		t.Fatal("Expected error releasing a network that isn't held")
+	}
+}
+
+func TestPool_ReusesExistingNetwork(t *testing.T) {
+	m := newFakeManager(Info{ID: "old", Name: DefaultName})
+	p := NewPool(m)
+
+	id, err := p.Acquire(context.Background(), DefaultName)
+	if err != nil || id != "old" || m.created != 0 {
+		t.Fatalf("Expected existing network reused, got %q (%v) after %d creates", id, err, m.created)
+	}
+}
+
+func TestPool_AcquireError(t *testing.T) {
+	m := newFakeManager()
+	m.fail = errors.New("daemon down")
+	p := NewPool(m)
+
+	if _, err := p.Acquire(context.Background(), DefaultName); err == nil {
+		t.Fatal("Expected error when the network can't be created")
+	}
+	if p.Refs(DefaultName) != 0 {
+		t.Fatal("Expected no reference after a failed acquire")
+	}
+}
+
+func TestPool_Sweep(t *testing.T) {
+	ctx := context.Background()
+	m := newFakeManager(
+		Info{ID: "orphan", Name: "txviewer-old"},
+		Info{ID: "busy", Name: "txviewer-busy", Containers: 2},
+	)
+	p := NewPool(m)
+	if _, err := p.Acquire(ctx, DefaultName); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	removed, err := p.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(removed, []string{"txviewer-old"}) {
+		t.Fatalf("Expected only the empty unheld network removed, got %v", removed)
+	}
+}
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ProgressFunc receives a human-readable description of each startup stage as it begins
+type ProgressFunc func(stage string)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose startup stages are reported to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress logs a startup stage and announces it to the context's progress callback, if any
+func ReportProgress(ctx context.Context, stage string) {
+	slog.InfoContext(ctx, "startup stage", "stage", stage)
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(stage)
+	}
+}
@@ -2,8 +2,13 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/slug"
 )
 
 // Provider defines the interface for database providers
@@ -26,13 +31,20 @@ type Provider interface {
 	// GetScenarios returns the registry of scenarios for this provider
 	GetScenarios() *scenario.Registry
 
-	// ConnectionInfo returns connection details for display purposes
+	// Capabilities returns the features supported by the running database
+	Capabilities() scenario.CapabilitySet
+
+	// ConnectionInfo returns connection details for display purposes, credentials included, or ""
+	// while the database isn't running; pass it through logging.Redact before showing or saving it
 	ConnectionInfo() string
 }
 
-// Registry holds all registered providers
+// Registry holds all registered providers. It is safe for concurrent use, and providers are
+// always listed in the order they were registered.
 type Registry struct {
+	mu        sync.RWMutex
 	providers []Provider
+	slugs     []string // Slug of each provider's name, in the same order
 }
 
 // NewRegistry creates a new provider registry
@@ -42,22 +54,60 @@ func NewRegistry() *Registry {
 	}
 }
 
-// Register adds a provider to the registry
+// Register adds a provider to the registry, after those already registered
 func (r *Registry) Register(p Provider) {
+	name := slug.Make(p.Name())
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.providers = append(r.providers, p)
+	r.slugs = append(r.slugs, name)
 }
 
-// GetAll returns all registered providers
+// GetAll returns a copy of the registered providers in registration order
 func (r *Registry) GetAll() []Provider {
-	return r.providers
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.providers)
 }
 
-// GetByName returns a provider by name
+// GetByName returns a provider by its name in any case or by its slug
 func (r *Registry) GetByName(name string) Provider {
-	for _, p := range r.providers {
-		if p.Name() == name {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i, p := range r.providers {
+		if slug.Matches(name, p.Name(), r.slugs[i]) {
 			return p
 		}
 	}
 	return nil
 }
+
+// Suggest returns the slugs of the providers whose names are close to name, for when GetByName
+// finds none
+func (r *Registry) Suggest(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slug.Suggest(name, r.slugs)
+}
+
+// Names returns the names of all registered providers
+func (r *Registry) Names() []string {
+	providers := r.GetAll()
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// StopAll stops every registered provider, e.g. while recovering from a crash. The registry isn't
+// locked while they stop, so a slow provider doesn't hold up lookups.
+func (r *Registry) StopAll(ctx context.Context) error {
+	var errs []error
+	for _, p := range r.GetAll() {
+		if err := p.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", p.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,236 @@
+// Package providertest checks the lifecycle contract every provider.Provider shares, as
+// scenariotest does for scenarios. A provider's tests call Conformance once; for providers that
+// start containers, that call belongs under the integration build tag.
+package providertest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+)
+
+// StartTimeout bounds each Start. Overridable for slow machines or large topologies.
+var StartTimeout = 10 * time.Minute
+
+// CancelBound is how long Start may keep going once its context is cancelled
+var CancelBound = 30 * time.Second
+
+// cancelAfter is how far into a start it is cancelled: late enough for a provider to be busy
+// starting, early enough for a container provider not to be done yet
+const cancelAfter = 200 * time.Millisecond
+
+// Factory creates the provider under test. Each check gets its own, so a provider left running
+// by a failed check doesn't carry over to the next.
+type Factory func() provider.Provider
+
+// Containers lists the IDs of the containers that exist, e.g. DockerContainers
+type Containers func(ctx context.Context) ([]string, error)
+
+// Option configures Conformance
+type Option func(*config)
+
+type config struct {
+	containers Containers
+}
+
+// WithContainers checks that the provider starts containers that containers lists and that Stop,
+// or a cancelled Start, leaves none of them behind. Without it these checks are skipped.
+func WithContainers(containers Containers) Option {
+	return func(c *config) {
+		c.containers = containers
+	}
+}
+
+// Conformance runs the checks every provider must pass, as subtests named after it:
+//   - a provider not started isn't running, has no connection info, and can be stopped
+//   - GetScenarios returns the same registry every time, in the same order, whether running or not
+//   - Start is idempotent, and while running IsRunning is true and the connection info is set
+//   - Stop after Start leaves nothing running, nor any container when WithContainers tells how to look
+//   - Start returns within CancelBound of its context being cancelled, leaving nothing running
+func Conformance(t *testing.T, factory Factory, opts ...Option) {
+	t.Helper()
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	t.Run(factory().Name(), func(t *testing.T) {
+		t.Run("NotStarted", func(t *testing.T) { checkNotStarted(t, factory()) })
+		t.Run("Scenarios", func(t *testing.T) { checkScenarios(t, factory()) })
+		t.Run("Lifecycle", func(t *testing.T) { checkLifecycle(t, factory(), cfg) })
+		t.Run("StartCancelled", func(t *testing.T) { checkStartCancelled(t, factory(), cfg) })
+	})
+}
+
+func checkNotStarted(t *testing.T, p provider.Provider) {
+	if p.Name() == "" || p.Description() == "" {
+		t.Fatalf("Expected a name and a description, got %q and %q", p.Name(), p.Description())
+	}
+	checkStopped(t, p)
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected Stop before Start to succeed, got %v", err)
+	}
+	checkStopped(t, p)
+}
+
+func checkScenarios(t *testing.T, p provider.Provider) {
+	registry := p.GetScenarios()
+	if registry == nil {
+		t.Fatal("Expected a scenario registry")
+	}
+	names := scenarioNames(p)
+	if len(names) == 0 {
+		t.Fatal("Expected the provider to have scenarios")
+	}
+	for i, name := range names {
+		if name == "" {
+			t.Fatalf("Expected scenario %d to have a name", i)
+		}
+		if slices.Contains(names[:i], name) {
+			t.Fatalf("Expected scenario names to be unique, got %q twice", name)
+		}
+	}
+	if p.GetScenarios() != registry {
+		t.Fatal("Expected GetScenarios to return the same registry every time")
+	}
+	if again := scenarioNames(p); !slices.Equal(again, names) {
+		t.Fatalf("Expected the scenarios in the same order, got %v then %v", names, again)
+	}
+}
+
+func checkLifecycle(t *testing.T, p provider.Provider, cfg config) {
+	ctx, cancel := context.WithTimeout(context.Background(), StartTimeout)
+	defer cancel()
+	before := listContainers(t, cfg)
+	registry, names := p.GetScenarios(), scenarioNames(p)
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("Expected Start to succeed, got %v", err)
+	}
+	defer p.Stop(context.Background())
+	checkRunning(t, p)
+	if cfg.containers != nil && len(started(before, listContainers(t, cfg))) == 0 {
+		t.Fatal("Expected Start to create containers that can be listed")
+	}
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("Expected a second Start to succeed, got %v", err)
+	}
+	checkRunning(t, p)
+	if p.GetScenarios() != registry || !slices.Equal(scenarioNames(p), names) {
+		t.Fatal("Expected starting to keep the scenarios")
+	}
+
+	if err := p.Stop(ctx); err != nil {
+		t.Fatalf("Expected Stop to succeed, got %v", err)
+	}
+	checkStopped(t, p)
+	checkNoneLeft(t, cfg, before, "Stop")
+	if err := p.Stop(ctx); err != nil {
+		t.Fatalf("Expected a second Stop to succeed, got %v", err)
+	}
+}
+
+func checkStartCancelled(t *testing.T, p provider.Provider, cfg config) {
+	before := listContainers(t, cfg)
+
+	// Cancelled before it starts, Start must not start anything
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Start(cancelled); err == nil {
+		p.Stop(context.Background())
+		t.Fatal("Expected Start with a cancelled context to fail")
+	}
+	checkStopped(t, p)
+	checkNoneLeft(t, cfg, before, "a cancelled Start")
+
+	// Cancelled while it starts, Start must return promptly
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Start(ctx)
+	}()
+	time.Sleep(cancelAfter)
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			// It started before the cancellation, which is fine as long as it can be stopped
+			if stopErr := p.Stop(context.Background()); stopErr != nil {
+				t.Fatalf("Expected Stop to succeed, got %v", stopErr)
+			}
+		} else if !errors.Is(err, context.Canceled) {
+			t.Logf("Start failed with %v rather than a cancellation", err)
+		}
+	case <-time.After(CancelBound):
+		t.Fatalf("Expected Start to return within %s of cancellation", CancelBound)
+	}
+	checkStopped(t, p)
+	checkNoneLeft(t, cfg, before, "a cancelled Start")
+}
+
+// checkRunning fails the test unless p reports it is running and how to reach it
+func checkRunning(t *testing.T, p provider.Provider) {
+	t.Helper()
+	if !p.IsRunning() {
+		t.Fatal("Expected the provider to be running")
+	}
+	if p.ConnectionInfo() == "" {
+		t.Fatal("Expected connection info while running")
+	}
+}
+
+// checkStopped fails the test unless p reports it isn't running and has no connection info
+func checkStopped(t *testing.T, p provider.Provider) {
+	t.Helper()
+	if p.IsRunning() {
+		t.Fatal("Expected the provider not to be running")
+	}
+	if info := p.ConnectionInfo(); info != "" {
+		t.Fatalf("Expected no connection info while not running, got %q", info)
+	}
+}
+
+// checkNoneLeft fails the test when containers exist that didn't before
+func checkNoneLeft(t *testing.T, cfg config, before []string, after string) {
+	t.Helper()
+	if left := started(before, listContainers(t, cfg)); len(left) > 0 {
+		t.Fatalf("Expected %s to leave no containers, got %v", after, left)
+	}
+}
+
+// listContainers returns the containers that exist, or nil without WithContainers
+func listContainers(t *testing.T, cfg config) []string {
+	t.Helper()
+	if cfg.containers == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ids, err := cfg.containers(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list containers: %v", err)
+	}
+	return ids
+}
+
+// started returns the containers of after missing from before
+func started(before, after []string) []string {
+	return slices.DeleteFunc(slices.Clone(after), func(id string) bool {
+		return slices.Contains(before, id)
+	})
+}
+
+// scenarioNames returns the names of p's scenarios in registry order
+func scenarioNames(p provider.Provider) []string {
+	var names []string
+	for _, s := range p.GetScenarios().GetAll() {
+		names = append(names, s.Name())
+	}
+	return names
+}
@@ -0,0 +1,32 @@
+package providertest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// DockerContainers lists the containers carrying label, running or not, through the Docker API
+// testcontainers is configured for
+func DockerContainers(label string) Containers {
+	return func(ctx context.Context) ([]string, error) {
+		cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+		}
+		defer cli.Close()
+
+		list, err := cli.ContainerList(ctx, container.ListOptions{All: true, Filters: filters.NewArgs(filters.Arg("label", label))})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list containers: %w", err)
+		}
+		ids := make([]string, len(list))
+		for i, c := range list {
+			ids[i] = c.ID
+		}
+		return ids, nil
+	}
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// MockProvider is a mock implementation of the Provider interface
+type MockProvider struct {
+	name string
+}
+
+func (m *MockProvider) Name() string                         { return m.name }
+func (m *MockProvider) Description() string                  { return "Mock Description" }
+func (m *MockProvider) Start(ctx context.Context) error      { return nil }
+func (m *MockProvider) Stop(ctx context.Context) error       { return nil }
+func (m *MockProvider) IsRunning() bool                      { return false }
+func (m *MockProvider) GetScenarios() *scenario.Registry     { return scenario.NewRegistry() }
+func (m *MockProvider) Capabilities() scenario.CapabilitySet { return nil }
+func (m *MockProvider) ConnectionInfo() string               { return "" }
+
+func TestRegistry_GetByName(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&MockProvider{name: "MongoDB"})
+
+	for _, name := range []string{"MongoDB", "mongodb", "MONGODB"} {
+		if p := r.GetByName(name); p == nil {
+			t.Fatalf("Expected provider for %q, got nil", name)
+		}
+	}
+
+	if p := r.GetByName("postgres"); p != nil {
+		t.Fatalf("Expected nil for unknown provider, got %s", p.Name())
+	}
+}
+
+func TestRegistry_ConcurrentRegisterAndGetAll(t *testing.T) {
+	r := NewRegistry()
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			r.Register(&MockProvider{name: fmt.Sprintf("Provider %d", i)})
+		}()
+		go func() {
+			defer wg.Done()
+			r.GetAll()
+			r.GetByName("Provider 0")
+			r.Names()
+		}()
+	}
+	wg.Wait()
+
+	if n := len(r.GetAll()); n != 100 {
+		t.Fatalf("Expected 100 providers, got %d", n)
+	}
+	r.GetAll()[0] = nil
+	if r.GetAll()[0] == nil {
+		t.Fatal("Expected GetAll to return a copy, got the registry's own slice")
+	}
+}
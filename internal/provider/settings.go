@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Setting is a provider option the user can change on the options screen before Start
+type Setting struct {
+	Key         string   // Stable identifier passed back to ApplySetting
+	Name        string   // Display label
+	Description string   // One-line explanation shown under the setting
+	Choices     []string // Allowed values, in display order
+	Value       string   // Currently selected value
+}
+
+// Configurable is implemented by providers that expose settings before they are started
+type Configurable interface {
+	// Settings returns the current settings in display order
+	Settings() []Setting
+
+	// ApplySetting changes a setting; it only takes effect on the next Start
+	ApplySetting(key, value string) error
+}
+
+// ParamSource is implemented by providers whose settings include scenario params
+type ParamSource interface {
+	// ScenarioParams returns the params passed to every scenario run
+	ScenarioParams() scenario.Params
+}
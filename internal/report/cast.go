@@ -0,0 +1,53 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CastFrame is one full screen of a recording
+type CastFrame struct {
+	At     time.Duration // Offset from the start of the recording
+	Screen string
+}
+
+// castHeader is the first line of an asciinema v2 file
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// clearScreen moves the cursor home and clears the terminal before each frame
+const clearScreen = "\x1b[H\x1b[2J"
+
+// WriteCast writes frames as an asciinema v2 recording, playable with `asciinema play`
+func WriteCast(w io.Writer, width, height int, title string, started time.Time, frames []CastFrame) error {
+	enc := json.NewEncoder(w)
+	header := castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: started.Unix(),
+		Title:     title,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	}
+	if err := enc.Encode(header); err != nil {
+		return fmt.Errorf("failed to write cast header: %w", err)
+	}
+
+	for _, f := range frames {
+		// Raw terminals need carriage returns to start each line at column 0
+		data := clearScreen + strings.ReplaceAll(f.Screen, "\n", "\r\n")
+		if err := enc.Encode([]any{f.At.Seconds(), "o", data}); err != nil {
+			return fmt.Errorf("failed to write cast event: %w", err)
+		}
+	}
+	return nil
+}
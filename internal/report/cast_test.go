@@ -0,0 +1,43 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWriteCast(t *testing.T) {
+	frames := []CastFrame{
+		{At: 0, Screen: "Preparing"},
+		{At: 1500 * time.Millisecond, Screen: "[1] Session A\n  ok"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCast(&buf, 100, 30, "Demo", time.Unix(1700000000, 0), frames); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	scanner.Scan()
+	var header castHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Version != 2 || header.Width != 100 {
+		t.Fatalf("Expected v2 header, got %s (%v)", scanner.Text(), err)
+	}
+
+	var events [][]any
+	for scanner.Scan() {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Expected JSON event line, got %s", scanner.Text())
+		}
+		events = append(events, event)
+	}
+	if len(events) != 2 || events[1][0] != 1.5 || events[1][1] != "o" {
+		t.Fatalf("Unexpected events %v", events)
+	}
+	if events[1][2] != clearScreen+"[1] Session A\r\n  ok" {
+		t.Fatalf("Expected CRLF line endings after a clear, got %q", events[1][2])
+	}
+}
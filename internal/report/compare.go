@@ -0,0 +1,215 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Comparison lines up two runs of the same anomaly, usually on different providers
+type Comparison struct {
+	Anomaly   string
+	Generated time.Time
+	Left      Side
+	Right     Side
+	Rows      []Row
+}
+
+// Side is one provider's run in a comparison
+type Side struct {
+	Provider string
+	Run      *Run
+}
+
+// Row pairs the steps of both runs that play the same role in the same session. Either step is nil
+// when only one run has it.
+type Row struct {
+	Left  *scenario.StepResult
+	Right *scenario.StepResult
+}
+
+// Role returns the role of the row's steps, or "" for narration only one run has
+func (r Row) Role() string {
+	if r.Left != nil {
+		return r.Left.Role
+	}
+	return r.Right.Role
+}
+
+// Diverges returns whether the runs had different outcomes at this row: one step failed while
+// the other succeeded, or only one run got to a step with a role, e.g. because the other errored
+func (r Row) Diverges() bool {
+	if r.Left == nil || r.Right == nil {
+		return r.Role() != ""
+	}
+	return r.Left.Success != r.Right.Success
+}
+
+// NewComparison aligns the steps of two runs of the anomaly. The runs are aligned by their Steps,
+// so runs that spilled steps to disk should be passed Whole.
+func NewComparison(anomaly string, left, right Side) *Comparison {
+	return &Comparison{
+		Anomaly:   anomaly,
+		Generated: time.Now(),
+		Left:      left,
+		Right:     right,
+		Rows:      Align(left.Run.Steps, right.Run.Steps),
+	}
+}
+
+// Diverged returns whether the runs ended differently or diverged at any step
+func (c *Comparison) Diverged() bool {
+	if c.Left.Run.Verdict() != c.Right.Run.Verdict() {
+		return true
+	}
+	for _, r := range c.Rows {
+		if r.Diverges() {
+			return true
+		}
+	}
+	return false
+}
+
+// Align pairs the steps of two runs by session and role, keeping both runs' order. Steps without
+// a role and steps only one run has get a row of their own; headers are left out.
+func Align(left, right []scenario.StepResult) []Row {
+	a, b := withoutHeaders(left), withoutHeaders(right)
+
+	// Longest common subsequence of the role keys; lcs[i][j] covers a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case matches(a[i], b[j]):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var rows []Row
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && matches(a[i], b[j]):
+			rows = append(rows, Row{Left: &a[i], Right: &b[j]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			rows = append(rows, Row{Left: &a[i]})
+			i++
+		default:
+			rows = append(rows, Row{Right: &b[j]})
+			j++
+		}
+	}
+	return rows
+}
+
+// matches returns whether two steps play the same role in the same session
+func matches(a, b scenario.StepResult) bool {
+	return a.Role != "" && a.Role == b.Role && a.Session == b.Session
+}
+
+// withoutHeaders returns a copy of steps without section headers
+func withoutHeaders(steps []scenario.StepResult) []scenario.StepResult {
+	var out []scenario.StepResult
+	for _, s := range steps {
+		if !s.IsHeader {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// WriteComparison renders the comparison in the given format
+func WriteComparison(w io.Writer, c *Comparison, f Format) error {
+	if f == FormatHTML {
+		return WriteComparisonHTML(w, c)
+	}
+	return WriteComparisonMarkdown(w, c)
+}
+
+// WriteComparisonMarkdown renders the comparison as a Markdown document with a two-column step table
+func WriteComparisonMarkdown(w io.Writer, c *Comparison) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Comparison: %s\n\n", c.Anomaly)
+	fmt.Fprintf(&b, "Generated %s.\n\n", c.Generated.UTC().Format("2006-01-02 15:04 MST"))
+
+	fmt.Fprintf(&b, "| | %s | %s |\n", cell(c.Left.Provider), cell(c.Right.Provider))
+	b.WriteString("|---|---|---|\n")
+	fmt.Fprintf(&b, "| Scenario | %s | %s |\n", cell(c.Left.Run.Scenario), cell(c.Right.Run.Scenario))
+	fmt.Fprintf(&b, "| Isolation level | %s | %s |\n", cell(c.Left.Run.IsolationLevel), cell(c.Right.Run.IsolationLevel))
+	fmt.Fprintf(&b, "| Verdict | %s | %s |\n", verdictCell(c.Left.Run), verdictCell(c.Right.Run))
+	fmt.Fprintf(&b, "| Duration | %s | %s |\n\n", formatDuration(c.Left.Run), formatDuration(c.Right.Run))
+
+	if c.Diverged() {
+		b.WriteString("> ⚠️ The runs diverge at the marked steps.\n\n")
+	} else {
+		b.WriteString("> The runs had the same outcome at every step.\n\n")
+	}
+
+	b.WriteString("## Step log\n\n")
+	fmt.Fprintf(&b, "| | Role | %s | %s |\n", cell(c.Left.Provider), cell(c.Right.Provider))
+	b.WriteString("|---|---|---|---|\n")
+	for _, r := range c.Rows {
+		mark := ""
+		if r.Diverges() {
+			mark = "⚠️"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", mark, r.Role(), stepCell(r.Left), stepCell(r.Right))
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// verdictCell renders a run's verdict for a table cell, with the error if it had one
+func verdictCell(r *Run) string {
+	v := fmt.Sprintf("%s %s", verdictIcons[r.Verdict()], r.Verdict())
+	if r.Err != nil {
+		v += ": " + r.Err.Error()
+	}
+	return cell(v)
+}
+
+// stepCell renders a step for a Markdown table cell
+func stepCell(step *scenario.StepResult) string {
+	if step == nil {
+		return "—"
+	}
+	mark := "✓"
+	if !step.Success {
+		mark = "✗"
+	}
+	s := fmt.Sprintf("`[%d]` **%s** %s", step.Step, step.Session, step.Description)
+	if result := strings.TrimSpace(step.Result); result != "" {
+		s += "<br>" + mark + " " + strings.ReplaceAll(result, "\n", "<br>")
+	}
+	return cell(s)
+}
+
+var compareTemplate = template.Must(template.New("compare.html.tmpl").Funcs(template.FuncMap{
+	"sessionColor": sessionColor,
+	"duration":     formatDuration,
+	"icon":         func(v Verdict) string { return verdictIcons[v] },
+	"lower":        strings.ToLower,
+	"pair":         func(left, right any) []any { return []any{left, right} },
+}).ParseFS(templates, "templates/compare.html.tmpl"))
+
+// WriteComparisonHTML renders the comparison as a self-contained HTML page
+func WriteComparisonHTML(w io.Writer, c *Comparison) error {
+	return compareTemplate.Execute(w, c)
+}
@@ -0,0 +1,89 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+func TestAlign_PairsStepsByRole(t *testing.T) {
+	left := []scenario.StepResult{
+		{IsHeader: true, Description: "Phantom"},
+		{Session: "Session A", Step: 1, Role: scenario.RoleRead, Success: true},
+		{Session: "Session B", Step: 2, Role: scenario.RoleConcurrentWrite, Success: true},
+		{Session: "Session A", Step: 3, Description: "Narration", Success: true},
+		{Session: "Session A", Step: 4, Role: scenario.RoleReread, Success: true},
+	}
+	right := []scenario.StepResult{
+		{Session: "Setup", Step: 1, Success: true},
+		{Session: "Session A", Step: 2, Role: scenario.RoleRead, Success: true},
+		{Session: "Session B", Step: 3, Role: scenario.RoleConcurrentWrite, Success: false},
+	}
+
+	rows := Align(left, right)
+
+	var got []string
+	for _, r := range rows {
+		var l, rt string
+		if r.Left != nil {
+			l = strconv.Itoa(r.Left.Step)
+		}
+		if r.Right != nil {
+			rt = strconv.Itoa(r.Right.Step)
+		}
+		got = append(got, l+":"+rt)
+	}
+	want := ":1 1:2 2:3 3: 4:"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("Expected rows %s, got %s", want, strings.Join(got, " "))
+	}
+
+	// The blocked write and the reread only the left run got to diverge; narration doesn't
+	var diverging []int
+	for i, r := range rows {
+		if r.Diverges() {
+			diverging = append(diverging, i)
+		}
+	}
+	if len(diverging) != 2 || diverging[0] != 2 || diverging[1] != 4 {
+		t.Fatalf("Expected rows 2 and 4 to diverge, got %v", diverging)
+	}
+}
+
+func TestWriteComparison(t *testing.T) {
+	left := &Run{Scenario: "Phantom Read", Steps: []scenario.StepResult{
+		{Session: "Session A", Step: 1, Description: "Count", Result: "Count: 3", Role: scenario.RoleReread, Success: true},
+	}}
+	right := &Run{Scenario: "Phantom Read", Err: errors.New("lock timeout")}
+	c := NewComparison(scenario.AnomalyPhantomRead, Side{Provider: "MongoDB", Run: left}, Side{Provider: "Other", Run: right})
+
+	if !c.Diverged() {
+		t.Fatal("Expected runs with different verdicts to diverge")
+	}
+
+	var md bytes.Buffer
+	if err := WriteComparison(&md, c, FormatMarkdown); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"# Comparison: phantom-read",
+		"| Verdict | ✅ PASS | 💥 ERROR: lock timeout |",
+		"| ⚠️ | reread | `[1]` **Session A** Count<br>✓ Count: 3 | — |",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Fatalf("Expected Markdown to contain %q, got:\n%s", want, md.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := WriteComparison(&html, c, FormatHTML); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(html.String(), `<tr class="diverges">`) {
+		t.Fatalf("Expected the diverging row to be highlighted, got:\n%s", html.String())
+	}
+}
@@ -0,0 +1,41 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// Format selects the document type a suite is rendered as
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat converts a --format value into a Format
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatMarkdown, "md":
+		return FormatMarkdown, nil
+	case FormatHTML:
+		return FormatHTML, nil
+	}
+	return "", fmt.Errorf("unknown report format %q (valid: markdown, html)", s)
+}
+
+// Ext returns the file extension for documents in this format
+func (f Format) Ext() string {
+	if f == FormatHTML {
+		return ".html"
+	}
+	return ".md"
+}
+
+// Write renders the suite in the given format
+func Write(w io.Writer, suite *Suite, f Format) error {
+	if f == FormatHTML {
+		return WriteHTML(w, suite)
+	}
+	return WriteMarkdown(w, suite)
+}
@@ -0,0 +1,148 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// SessionColors mirrors the runner's session palette in internal/ui/styles.go
+var SessionColors = map[string]string{
+	"Session A": "#3B82F6",
+	"Session B": "#EC4899",
+	"Setup":     "#8B5CF6",
+	"Result":    "#10B981",
+}
+
+// defaultSessionColor is used for sessions outside the palette
+const defaultSessionColor = "#6B7280"
+
+//go:embed templates/*.html.tmpl
+var templates embed.FS
+
+var htmlTemplate = template.Must(template.New("report.html.tmpl").Funcs(template.FuncMap{
+	"sessionColor": sessionColor,
+	"anchor":       anchor,
+	"duration":     formatDuration,
+	"icon":         func(v Verdict) string { return verdictIcons[v] },
+	"lower":        strings.ToLower,
+}).ParseFS(templates, "templates/report.html.tmpl"))
+
+// htmlRun is a run prepared for the template
+type htmlRun struct {
+	*Run
+	Sections []section
+	Timeline template.HTML
+}
+
+// section groups the steps that follow one header
+type section struct {
+	Title string
+	Steps []scenario.StepResult
+}
+
+// WriteHTML renders the suite as a single self-contained HTML file with inline CSS and SVG
+func WriteHTML(w io.Writer, suite *Suite) error {
+	runs := make([]htmlRun, len(suite.Runs))
+	for i, r := range suite.Runs {
+		whole, err := r.Whole()
+		if err != nil {
+			return err
+		}
+		runs[i] = htmlRun{Run: whole, Sections: sections(whole.Steps), Timeline: timeline(whole.Steps)}
+	}
+
+	return htmlTemplate.Execute(w, struct {
+		*Suite
+		Runs []htmlRun
+	}{suite, runs})
+}
+
+// sections splits steps at each header; steps before the first header get an untitled section
+func sections(steps []scenario.StepResult) []section {
+	var out []section
+	for _, step := range steps {
+		if step.IsHeader {
+			out = append(out, section{Title: step.Description})
+			continue
+		}
+		if len(out) == 0 {
+			out = append(out, section{})
+		}
+		out[len(out)-1].Steps = append(out[len(out)-1].Steps, step)
+	}
+	return out
+}
+
+// sessionColor returns the palette color of a session
+func sessionColor(session string) string {
+	if c, ok := SessionColors[session]; ok {
+		return c
+	}
+	return defaultSessionColor
+}
+
+// Timeline geometry in SVG user units
+const (
+	timelineLabelWidth = 90
+	timelineStepWidth  = 26
+	timelineLaneHeight = 26
+)
+
+// timeline draws one lane per session and one dot per step, in order, so interleavings are visible
+// at a glance; headers become dashed separators
+func timeline(steps []scenario.StepResult) template.HTML {
+	var lanes []string
+	laneOf := make(map[string]int)
+	count := 0
+	for _, step := range steps {
+		if step.IsHeader {
+			continue
+		}
+		count++
+		if _, ok := laneOf[step.Session]; !ok {
+			laneOf[step.Session] = len(lanes)
+			lanes = append(lanes, step.Session)
+		}
+	}
+	if count == 0 {
+		return ""
+	}
+
+	width := timelineLabelWidth + (count+1)*timelineStepWidth
+	height := (len(lanes) + 1) * timelineLaneHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="timeline" xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		width, height, width, height)
+	for i, lane := range lanes {
+		y := (i + 1) * timelineLaneHeight
+		fmt.Fprintf(&b, `<text x="4" y="%d" fill="%s">%s</text>`, y+4, sessionColor(lane), html.EscapeString(lane))
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#374151"/>`, timelineLabelWidth, y, width, y)
+	}
+
+	x := timelineLabelWidth
+	for _, step := range steps {
+		if step.IsHeader {
+			sep := x + timelineStepWidth/2
+			fmt.Fprintf(&b, `<line x1="%d" y1="4" x2="%d" y2="%d" stroke="#6B7280" stroke-dasharray="3 3"><title>%s</title></line>`,
+				sep, sep, height, html.EscapeString(step.Description))
+			continue
+		}
+		x += timelineStepWidth
+		y := (laneOf[step.Session] + 1) * timelineLaneHeight
+		stroke := ""
+		if !step.Success {
+			stroke = ` stroke="#EF4444" stroke-width="2"`
+		}
+		fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="7" fill="%s"%s><title>[%d] %s</title></circle>`,
+			x, y, sessionColor(step.Session), stroke, step.Step, html.EscapeString(step.Description))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
@@ -0,0 +1,95 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/scenariotest"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestWriteHTML_Golden(t *testing.T) {
+	suite := &Suite{
+		Provider:       "MongoDB",
+		ConnectionInfo: "Connected to MongoDB replica set\nmongodb://localhost:27017/",
+		Generated:      time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+		Runs: []*Run{
+			{
+				Scenario:       "Write Conflict Detection",
+				Description:    "Two sessions update the same <document>.",
+				IsolationLevel: "Serializable (Write Conflicts)",
+				Duration:       2500 * time.Millisecond,
+				Seed:           42,
+				Steps: []scenario.StepResult{
+					{IsHeader: true, Description: "Part 1: Concurrent updates"},
+					{Session: "Session A", Step: 1, Description: "Start transaction", Query: "session.startTransaction()", Success: true},
+					{Session: "Session B", Step: 2, Description: "Update balance", Query: "updateOne({_id: 1})", Result: "WriteConflict\naborted", Success: false},
+					{Session: "Result", Step: 3, Description: "Conflict detected", Result: "ok", Success: true},
+				},
+				Assertions: []scenario.Assertion{
+					{Name: "second writer conflicts", Held: true},
+					{Name: "final balance", Held: false, Detail: "want 300, got 800"},
+				},
+			},
+			{Scenario: "Snapshot Isolation", IsolationLevel: "Snapshot", Duration: time.Second, Err: errors.New("connection lost")},
+			{Scenario: "Distributed Transaction", IsolationLevel: "Snapshot", SkipReason: "requires Sharded cluster"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, suite); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	golden := filepath.Join("testdata", "report.html.golden")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatalf("HTML differs from %s; run go test ./internal/report -update and review the diff", golden)
+	}
+}
+
+// FuzzWriteHTML checks that step text can't add markup to the report: with every field replaced
+// by a placeholder, the document has as many tags
+func FuzzWriteHTML(f *testing.F) {
+	for _, text := range scenariotest.Texts {
+		f.Add("Session A", text, text, text)
+	}
+	// Keeps the fields that are set, so the template takes the same branches
+	placeholder := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "x"
+	}
+	f.Fuzz(func(t *testing.T, session, description, query, result string) {
+		var got, want bytes.Buffer
+		if err := WriteHTML(&got, fuzzSuite(session, description, query, result)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := WriteHTML(&want, fuzzSuite(placeholder(session), placeholder(description), placeholder(query), placeholder(result))); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if n, m := bytes.Count(got.Bytes(), []byte("<")), bytes.Count(want.Bytes(), []byte("<")); n != m {
+			t.Fatalf("Expected %d tags as with placeholders, got %d", m, n)
+		}
+		in := len(session) + len(description) + len(query) + len(result)
+		if grown := got.Len() - want.Len(); grown > 32*in {
+			t.Fatalf("Expected the document to grow with the text, got %d bytes more for %d", grown, in)
+		}
+	})
+}
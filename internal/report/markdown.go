@@ -0,0 +1,135 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// verdictIcons decorate verdicts in the summary table
+var verdictIcons = map[Verdict]string{
+	VerdictPass:  "✅",
+	VerdictFail:  "❌",
+	VerdictError: "💥",
+	VerdictSkip:  "⏭️",
+}
+
+// WriteMarkdown renders the suite as one Markdown document with a table of contents,
+// a summary table and a section per scenario
+func WriteMarkdown(w io.Writer, suite *Suite) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Transaction isolation report: %s\n\n", suite.Provider)
+	fmt.Fprintf(&b, "Generated %s.\n\n", suite.Generated.UTC().Format("2006-01-02 15:04 MST"))
+	if suite.ConnectionInfo != "" {
+		b.WriteString("```text\n" + suite.ConnectionInfo + "\n```\n\n")
+	}
+
+	b.WriteString("## Contents\n\n")
+	b.WriteString("- [Summary](#summary)\n")
+	for _, r := range suite.Runs {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", r.Scenario, anchor(r.Scenario))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Scenario | Isolation level | Verdict | Duration |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, r := range suite.Runs {
+		fmt.Fprintf(&b, "| [%s](#%s) | %s | %s %s | %s |\n",
+			cell(r.Scenario), anchor(r.Scenario), cell(r.IsolationLevel),
+			verdictIcons[r.Verdict()], r.Verdict(), formatDuration(r))
+	}
+	b.WriteString("\n")
+
+	for _, r := range suite.Runs {
+		whole, err := r.Whole()
+		if err != nil {
+			return err
+		}
+		writeRunMarkdown(&b, whole)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeRunMarkdown renders one scenario section
+func writeRunMarkdown(b *strings.Builder, r *Run) {
+	fmt.Fprintf(b, "## %s\n\n", r.Scenario)
+	fmt.Fprintf(b, "**Isolation level:** %s · **Verdict:** %s %s · **Duration:** %s · **Seed:** %d\n\n",
+		r.IsolationLevel, verdictIcons[r.Verdict()], r.Verdict(), formatDuration(r), r.Seed)
+
+	if r.Description != "" {
+		b.WriteString(strings.TrimSpace(r.Description) + "\n\n")
+	}
+
+	switch r.Verdict() {
+	case VerdictSkip:
+		fmt.Fprintf(b, "> Skipped: %s\n\n", r.SkipReason)
+		return
+	case VerdictError:
+		fmt.Fprintf(b, "> **Error:** %s\n\n", r.Err)
+	}
+
+	if len(r.Assertions) > 0 {
+		b.WriteString("### Assertions\n\n")
+		for _, a := range r.Assertions {
+			mark := "✓"
+			if !a.Held {
+				mark = "✗"
+			}
+			fmt.Fprintf(b, "- %s %s", mark, a.Name)
+			if a.Detail != "" {
+				fmt.Fprintf(b, " — %s", a.Detail)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Steps) == 0 {
+		return
+	}
+	b.WriteString("### Step log\n\n")
+	for _, step := range r.Steps {
+		if step.IsHeader {
+			fmt.Fprintf(b, "\n**%s**\n\n", step.Description)
+			continue
+		}
+		fmt.Fprintf(b, "- `[%d]` **%s** — %s\n", step.Step, step.Session, step.Description)
+		if step.Query != "" {
+			fmt.Fprintf(b, "  - `%s`\n", strings.ReplaceAll(step.Query, "`", "'"))
+		}
+		switch {
+		case step.Pending:
+			b.WriteString("  - ⏳ still waiting when the run ended\n")
+		case step.Waited > 0:
+			fmt.Fprintf(b, "  - ⏱️ waited %s\n", step.Waited.Round(100*time.Millisecond))
+		}
+		mark := "✓"
+		if !step.Success {
+			mark = "✗"
+		}
+		for _, line := range strings.Split(step.Result, "\n") {
+			if line != "" {
+				fmt.Fprintf(b, "  - %s %s\n", mark, line)
+			}
+		}
+	}
+	b.WriteString("\n")
+}
+
+// cell escapes text for a Markdown table cell
+func cell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// formatDuration rounds a run's duration for display
+func formatDuration(r *Run) string {
+	if r.Verdict() == VerdictSkip {
+		return "-"
+	}
+	return r.Duration.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/scenariotest"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	suite := &Suite{
+		Provider:  "MongoDB",
+		Generated: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+		Runs: []*Run{
+			{
+				Scenario:       "Write Conflict Detection",
+				IsolationLevel: "Serializable (Write Conflicts)",
+				Duration:       2500 * time.Millisecond,
+				Steps: []scenario.StepResult{
+					{IsHeader: true, Description: "Part 1"},
+					{Session: "Session A", Step: 1, Description: "Update", Query: "updateOne", Result: "ok", Success: true},
+				},
+			},
+			{Scenario: "Snapshot Isolation", IsolationLevel: "Snapshot", Err: errors.New("connection lost")},
+			{Scenario: "Dirty Read Prevention", IsolationLevel: "Read Committed", Assertions: []scenario.Assertion{
+				{Name: "conflict is detected", Held: false, Detail: "commit succeeded"},
+			}},
+			{Scenario: "Distributed Transaction", IsolationLevel: "Snapshot", SkipReason: "requires Sharded cluster"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, suite); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"- [Write Conflict Detection](#write-conflict-detection)",
+		"| [Write Conflict Detection](#write-conflict-detection) | Serializable (Write Conflicts) | ✅ PASS | 2.5s |",
+		"| 💥 ERROR |",
+		"| ❌ FAIL |",
+		"- ✗ conflict is detected — commit succeeded",
+		"> **Error:** connection lost",
+		"> Skipped: requires Sharded cluster",
+		"- `[1]` **Session A** — Update",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+	if !suite.Failed() {
+		t.Fatal("Expected suite with a failed run to report failure")
+	}
+}
+
+func TestWriteMarkdown_RedactsConnectionInfo(t *testing.T) {
+	suite := SuiteOf("MongoDB", "Connected to external MongoDB\nmongodb://admin:s3cret@db:27017/", &Run{Scenario: "Dirty Read Prevention"})
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, suite); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out := buf.String(); strings.Contains(out, "s3cret") || !strings.Contains(out, "mongodb://admin:xxxxx@db:27017/") {
+		t.Fatalf("Expected the password to be redacted, got:\n%s", out)
+	}
+}
+
+// fuzzSuite is a suite of one run whose header and step carry the fuzzed text
+func fuzzSuite(session, description, query, result string) *Suite {
+	return &Suite{
+		Provider:  "MongoDB",
+		Generated: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+		Runs: []*Run{{
+			Scenario:       "Fuzzed",
+			IsolationLevel: "Snapshot",
+			Steps: []scenario.StepResult{
+				{IsHeader: true, Description: description},
+				{Session: session, Step: 1, Description: description, Query: query, Result: result},
+			},
+		}},
+	}
+}
+
+// FuzzWriteMarkdown checks that any step text is exported, in a document growing no faster than
+// the text
+func FuzzWriteMarkdown(f *testing.F) {
+	for _, text := range scenariotest.Texts {
+		f.Add("Session A", text, text, text)
+	}
+	var empty bytes.Buffer
+	if err := WriteMarkdown(&empty, fuzzSuite("", "", "", "")); err != nil {
+		f.Fatalf("Expected no error, got %v", err)
+	}
+	f.Fuzz(func(t *testing.T, session, description, query, result string) {
+		var b bytes.Buffer
+		if err := WriteMarkdown(&b, fuzzSuite(session, description, query, result)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		in := len(session) + len(description) + len(query) + len(result)
+		if grown := b.Len() - empty.Len(); grown > 16*in {
+			t.Fatalf("Expected the document to grow with the text, got %d bytes more for %d", grown, in)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+// Package report captures finished scenario runs and renders them as documents.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/logging"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// Verdict summarizes how a run ended
+type Verdict string
+
+const (
+	VerdictPass  Verdict = "PASS"
+	VerdictFail  Verdict = "FAIL"  // An assertion did not hold
+	VerdictError Verdict = "ERROR" // The scenario could not run to completion
+	VerdictSkip  Verdict = "SKIP"  // Not run, e.g. because the provider lacks a capability
+)
+
+// Run is one scenario execution with everything needed to render it later
+type Run struct {
+	Scenario       string
+	Description    string
+	IsolationLevel string
+	Steps          []scenario.StepResult // In memory: every step, or the latest StepLimit once AddStep spilled older ones
+	Offsets        []time.Duration       // When each step arrived, relative to Started
+	Assertions     []scenario.Assertion
+	Started        time.Time
+	Seed           int64 // Params.Seed of the run, to reproduce it
+	Duration       time.Duration
+	Err            error
+	CleanupErr     error // Cleanup failed after the run; doesn't change the verdict
+	SkipReason     string
+
+	spill    *spill // Steps AddStep moved out of memory, oldest first; nil until the first
+	spillErr error  // Why spilling failed; every step then stays in memory
+	pending  int    // Pending steps AddStep put in Steps that are still there
+
+	// Pending steps spilled to disk, by index in the spill, until their outcome replaces them there
+	spilledPending map[int]scenario.StepResult
+}
+
+// NewRun starts a record for s; steps and the outcome are filled in as it runs
+func NewRun(s scenario.Scenario) *Run {
+	return &Run{
+		Scenario:       s.Name(),
+		Description:    s.Description(),
+		IsolationLevel: s.IsolationLevel(),
+		Started:        time.Now(),
+	}
+}
+
+// AddStep records a step as arriving now
+func (r *Run) AddStep(step scenario.StepResult) {
+	r.AddStepAt(step, time.Since(r.Started))
+}
+
+// AddStepAt records a step that arrived offset after Started. Beyond StepLimit steps in memory
+// the oldest is spilled to a temporary file, which Whole reads back, so a long session keeps a
+// bounded number of steps in memory. The outcome of a pending step replaces it, in memory or on
+// disk, keeping when it started.
+func (r *Run) AddStepAt(step scenario.StepResult, offset time.Duration) {
+	if i := r.PendingStep(step); i >= 0 {
+		r.Steps[i] = step
+		r.pending--
+		return
+	}
+	if r.replaceSpilled(step) {
+		return
+	}
+	if step.Pending {
+		r.pending++
+	}
+	r.Steps = append(r.Steps, step)
+	r.Offsets = append(r.Offsets, offset)
+	limit := StepLimit()
+	if limit <= 0 || len(r.Steps) <= limit || len(r.Offsets) != len(r.Steps) || r.spillErr != nil {
+		return
+	}
+
+	if r.spill == nil {
+		r.spill, r.spillErr = newSpill()
+	}
+	if r.spillErr == nil {
+		r.spillErr = r.spill.write(r.Steps[0], r.Offsets[0])
+	}
+	if r.spillErr != nil {
+		slog.Warn("keeping every step of the run in memory", "scenario", r.Scenario, "error", r.spillErr)
+		return
+	}
+	if r.Steps[0].Pending {
+		r.pending--
+		if r.spilledPending == nil {
+			r.spilledPending = make(map[int]scenario.StepResult)
+		}
+		r.spilledPending[r.spill.steps-1] = r.Steps[0]
+	}
+	r.Steps, r.Offsets = r.Steps[1:], r.Offsets[1:]
+}
+
+// replaceSpilled puts step in place of the pending step spilled to disk it is the outcome of,
+// reporting whether there was one. A step that can't be replaced there is added like any other.
+func (r *Run) replaceSpilled(step scenario.StepResult) bool {
+	for i, pending := range r.spilledPending {
+		if !step.Completes(pending) {
+			continue
+		}
+		delete(r.spilledPending, i)
+		if err := r.spill.replace(i, step); err != nil {
+			slog.Warn("adding the outcome of a spilled pending step as a step of its own", "scenario", r.Scenario, "error", err)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// PendingStep returns the index in Steps of the pending step AddStep recorded that step is the
+// outcome of, or -1 when there is none in memory
+func (r *Run) PendingStep(step scenario.StepResult) int {
+	if r.pending == 0 {
+		return -1
+	}
+	for i := len(r.Steps) - 1; i >= 0; i-- {
+		if step.Completes(r.Steps[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Spilled returns how many of the oldest steps were moved out of memory; Steps holds the rest
+func (r *Run) Spilled() int {
+	if r.spill == nil {
+		return 0
+	}
+	return r.spill.steps
+}
+
+// Whole returns the run with every step in Steps, reading back the steps spilled to disk. A run
+// that never spilled is returned as is.
+func (r *Run) Whole() (*Run, error) {
+	if r.spill == nil {
+		return r, nil
+	}
+	steps, offsets, err := r.spill.read()
+	if err != nil {
+		return nil, err
+	}
+	whole := *r
+	whole.Steps = append(steps, r.Steps...)
+	whole.Offsets = append(offsets, r.Offsets...)
+	whole.spill = nil
+	return &whole, nil
+}
+
+// Close removes the steps spilled to disk; the steps in memory stay
+func (r *Run) Close() error {
+	if r.spill == nil {
+		return nil
+	}
+	return r.spill.remove()
+}
+
+// StepOffset returns when step i arrived, assuming the scenarios' usual pacing if it wasn't recorded
+func (r *Run) StepOffset(i int) time.Duration {
+	if i < len(r.Offsets) {
+		return r.Offsets[i]
+	}
+	return time.Duration(i+1) * 500 * time.Millisecond
+}
+
+// Verdict returns whether the run passed, failed an assertion, errored or was skipped
+func (r *Run) Verdict() Verdict {
+	switch {
+	case r.SkipReason != "":
+		return VerdictSkip
+	case r.Err != nil:
+		return VerdictError
+	case len(r.FailedAssertions()) > 0:
+		return VerdictFail
+	}
+	return VerdictPass
+}
+
+// FailedAssertions returns the assertions that did not hold
+func (r *Run) FailedAssertions() []scenario.Assertion {
+	var failed []scenario.Assertion
+	for _, a := range r.Assertions {
+		if !a.Held {
+			failed = append(failed, a)
+		}
+	}
+	return failed
+}
+
+// Suite is every run made against one provider
+type Suite struct {
+	Provider       string
+	ConnectionInfo string // Credentials redacted
+	Generated      time.Time
+	Runs           []*Run
+}
+
+// Failed returns whether any run failed or errored; skipped runs don't count
+func (s *Suite) Failed() bool {
+	for _, r := range s.Runs {
+		if v := r.Verdict(); v == VerdictFail || v == VerdictError {
+			return true
+		}
+	}
+	return false
+}
+
+// Close removes the steps every run spilled to disk
+func (s *Suite) Close() error {
+	var errs []error
+	for _, r := range s.Runs {
+		errs = append(errs, r.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// anchor returns the heading slug GitHub generates for title
+func anchor(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// SuiteOf wraps a single run so it can be rendered like a full suite. Credentials in
+// connectionInfo are redacted, since exports get shared.
+func SuiteOf(providerName, connectionInfo string, run *Run) *Suite {
+	return &Suite{
+		Provider:       providerName,
+		ConnectionInfo: logging.Redact(connectionInfo),
+		Generated:      time.Now(),
+		Runs:           []*Run{run},
+	}
+}
+
+// FileName suggests a file name for the suite with the given extension, e.g. ".md"
+func (s *Suite) FileName(ext string) string {
+	subject := "suite"
+	if len(s.Runs) == 1 {
+		subject = anchor(s.Runs[0].Scenario)
+	}
+	return fmt.Sprintf("txviewer-%s-%s-%s%s", anchor(s.Provider), subject, s.Generated.Format("20060102-150405"), ext)
+}
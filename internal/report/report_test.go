@@ -0,0 +1,27 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+func TestRun_OutcomeReplacesPendingStep(t *testing.T) {
+	r := &Run{}
+	r.AddStepAt(scenario.StepResult{Session: "Session B", Step: 2, Description: "Lock the row", Pending: true}, time.Second)
+	r.AddStepAt(scenario.StepResult{Session: "Session A", Step: 3, Description: "Commit", Success: true}, 2*time.Second)
+	// Another session's step with the same number isn't its outcome
+	r.AddStepAt(scenario.StepResult{Session: "Session A", Step: 2, Description: "Read", Success: true}, 3*time.Second)
+	r.AddStepAt(scenario.StepResult{Session: "Session B", Step: 2, Description: "Lock the row", Success: true, Waited: 3 * time.Second}, 4*time.Second)
+
+	if len(r.Steps) != 3 {
+		t.Fatalf("Expected 3 steps, got %d", len(r.Steps))
+	}
+	if got := r.Steps[0]; got.Pending || got.Waited != 3*time.Second || r.Offsets[0] != time.Second {
+		t.Fatalf("Expected the outcome in place of the pending step, got %+v at %s", got, r.Offsets[0])
+	}
+	if i := r.PendingStep(scenario.StepResult{Session: "Session B", Step: 2}); i != -1 {
+		t.Fatalf("Expected no pending step left, got %d", i)
+	}
+}
@@ -0,0 +1,153 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// DefaultStepLimit is how many steps of a run are kept in memory unless SetStepLimit changed it
+const DefaultStepLimit = 5000
+
+var stepLimit atomic.Int64
+
+func init() {
+	stepLimit.Store(DefaultStepLimit)
+}
+
+// SetStepLimit sets how many steps of a run AddStep keeps in memory before spilling the oldest
+// to disk; 0 or less keeps every step. It is meant to be set once at startup.
+func SetStepLimit(n int) {
+	stepLimit.Store(int64(n))
+}
+
+// StepLimit returns how many steps of a run are kept in memory, or 0 for every step
+func StepLimit() int {
+	return int(max(stepLimit.Load(), 0))
+}
+
+// spilledStep is a line of a spill file. A line replacing a step holds the outcome of the pending
+// step spilled at that index, which takes its place and keeps its offset.
+type spilledStep struct {
+	Step     scenario.StepResult `json:"step"`
+	Offset   time.Duration       `json:"offset"`
+	Replaces *int                `json:"replaces,omitempty"`
+}
+
+// spill is a temporary JSONL file holding the oldest steps of a run, in order. Safe for
+// concurrent use, so an export can read a run while the app moves on to the next.
+type spill struct {
+	mu       sync.Mutex
+	file     *os.File // nil once removed
+	w        *bufio.Writer
+	steps    int  // Steps spilled, not counting the lines replacing one
+	lines    int  // Lines written
+	unlinked bool // Whether the file was deleted right away and lives on only while open
+}
+
+// newSpill creates an empty spill file in the temporary directory. Where the OS allows it, the
+// file is deleted at once, so it never outlives the process even when nothing removes it.
+func newSpill() (*spill, error) {
+	file, err := os.CreateTemp("", "txviewer-steps-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create step spill file: %w", err)
+	}
+	return &spill{file: file, w: bufio.NewWriter(file), unlinked: os.Remove(file.Name()) == nil}, nil
+}
+
+// write appends a step
+func (s *spill) write(step scenario.StepResult, offset time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.append(spilledStep{Step: step, Offset: offset}); err != nil {
+		return err
+	}
+	s.steps++
+	return nil
+}
+
+// replace puts step in place of the spilled step at index i
+func (s *spill) replace(i int, step scenario.StepResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i < 0 || i >= s.steps {
+		return fmt.Errorf("failed to replace spilled step %d of %d", i, s.steps)
+	}
+	return s.append(spilledStep{Step: step, Replaces: &i})
+}
+
+// append writes a line. Called with s.mu held.
+func (s *spill) append(line spilledStep) error {
+	if s.file == nil {
+		return errSpillRemoved
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("failed to encode spilled step: %w", err)
+	}
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to spill step: %w", err)
+	}
+	s.lines++
+	return nil
+}
+
+// read returns the spilled steps and when they arrived, oldest first
+func (s *spill) read() ([]scenario.StepResult, []time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil, nil, errSpillRemoved
+	}
+	if err := s.w.Flush(); err != nil {
+		return nil, nil, fmt.Errorf("failed to spill steps: %w", err)
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, fmt.Errorf("failed to read spilled steps: %w", err)
+	}
+	// Later writes append at the end again
+	defer s.file.Seek(0, io.SeekEnd)
+
+	steps := make([]scenario.StepResult, 0, s.steps)
+	offsets := make([]time.Duration, 0, s.steps)
+	dec := json.NewDecoder(bufio.NewReader(s.file))
+	for range s.lines {
+		var line spilledStep
+		if err := dec.Decode(&line); err != nil {
+			return nil, nil, fmt.Errorf("failed to read spilled steps: %w", err)
+		}
+		if line.Replaces != nil {
+			steps[*line.Replaces] = line.Step
+			continue
+		}
+		steps = append(steps, line.Step)
+		offsets = append(offsets, line.Offset)
+	}
+	return steps, offsets, nil
+}
+
+// errSpillRemoved reports a spill used after the run was closed
+var errSpillRemoved = errors.New("spilled steps were removed when the run was closed")
+
+// remove deletes the spill file
+func (s *spill) remove() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	if !s.unlinked {
+		err = errors.Join(err, os.Remove(s.file.Name()))
+	}
+	s.file = nil
+	return err
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+func TestRun_SpillsStepsBeyondTheLimit(t *testing.T) {
+	defer SetStepLimit(StepLimit())
+	SetStepLimit(3)
+
+	run := &Run{Scenario: "Long"}
+	for i := range 10 {
+		run.AddStepAt(scenario.StepResult{Step: i + 1}, time.Duration(i)*time.Second)
+	}
+	if len(run.Steps) != 3 || run.Spilled() != 7 || run.Steps[0].Step != 8 {
+		t.Fatalf("Expected steps 8-10 in memory and 7 spilled, got %d from %+v and %d spilled", len(run.Steps), run.Steps[0], run.Spilled())
+	}
+
+	whole, err := run.Whole()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(whole.Steps) != 10 || len(whole.Offsets) != 10 {
+		t.Fatalf("Expected every step and offset back, got %d and %d", len(whole.Steps), len(whole.Offsets))
+	}
+	for i, step := range whole.Steps {
+		if step.Step != i+1 || whole.StepOffset(i) != time.Duration(i)*time.Second {
+			t.Fatalf("Expected step %d at %ds, got %d at %v", i+1, i, step.Step, whole.StepOffset(i))
+		}
+	}
+
+	// Reading back leaves the run recording where it was
+	run.AddStepAt(scenario.StepResult{Step: 11}, 10*time.Second)
+	if whole, err = run.Whole(); err != nil || len(whole.Steps) != 11 || whole.Steps[7].Step != 8 {
+		t.Fatalf("Expected 11 steps in order after another step, got %d (%v)", len(whole.Steps), err)
+	}
+
+	if err := run.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := run.Whole(); err == nil {
+		t.Fatal("Expected an error reading steps spilled by a closed run")
+	}
+}
+
+func TestRun_OutcomeReplacesSpilledPendingStep(t *testing.T) {
+	defer SetStepLimit(StepLimit())
+	SetStepLimit(2)
+
+	run := &Run{Scenario: "Locks"}
+	defer run.Close()
+	run.AddStepAt(scenario.StepResult{Session: "Session A", Step: 1, Success: true}, 0)
+	run.AddStepAt(scenario.StepResult{Session: "Session B", Step: 2, Pending: true}, time.Second)
+	for i := 3; i <= 5; i++ {
+		run.AddStepAt(scenario.StepResult{Session: "Session A", Step: i, Success: true}, time.Duration(i)*time.Second)
+	}
+	if run.Spilled() != 3 {
+		t.Fatalf("Expected the pending step spilled, got %d spilled", run.Spilled())
+	}
+
+	run.AddStepAt(scenario.StepResult{Session: "Session B", Step: 2, Success: true, Waited: 4 * time.Second}, 5*time.Second)
+	if run.Spilled() != 3 || len(run.Steps) != 2 {
+		t.Fatalf("Expected the outcome not to add a step, got %d spilled and %d in memory", run.Spilled(), len(run.Steps))
+	}
+	whole, err := run.Whole()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(whole.Steps) != 5 || len(whole.Offsets) != 5 {
+		t.Fatalf("Expected 5 steps and offsets, got %d and %d", len(whole.Steps), len(whole.Offsets))
+	}
+	if got := whole.Steps[1]; got.Pending || got.Waited != 4*time.Second || whole.Offsets[1] != time.Second {
+		t.Fatalf("Expected the outcome in place of the spilled pending step, got %+v at %s", got, whole.Offsets[1])
+	}
+	for i, step := range whole.Steps {
+		if step.Step != i+1 {
+			t.Fatalf("Expected step %d at %d, got %d", i+1, i, step.Step)
+		}
+	}
+}
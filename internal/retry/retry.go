@@ -0,0 +1,135 @@
+// Package retry re-runs operations that fail with transient Docker errors,
+// backing off exponentially with jitter between attempts.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// transientErrors maps error substrings known to clear up on their own to a short reason
+var transientErrors = []struct {
+	substr string
+	reason string
+}{
+	{"port is already allocated", "port conflict"},
+	{"address already in use", "port conflict"},
+	{"TLS handshake timeout", "pull timeout"},
+	{"i/o timeout", "pull timeout"},
+	{"Client.Timeout exceeded", "pull timeout"},
+	{"request canceled while waiting for connection", "pull timeout"},
+	{"connection reset by peer", "network error"},
+	{"unexpected EOF", "network error"},
+	{"toomanyrequests", "registry rate limit"},
+	{"502 Bad Gateway", "registry unavailable"},
+	{"503 Service Unavailable", "registry unavailable"},
+}
+
+// permanentErrors are checked first, as some wrap a transient-looking message
+var permanentErrors = []string{
+	"manifest unknown",
+	"invalid reference format",
+	"pull access denied",
+	"repository does not exist",
+	"No such image",
+	"not found",
+}
+
+// Classify returns whether err is worth retrying, with a short reason for progress messages.
+// Unknown errors are treated as permanent.
+func Classify(err error) (transient bool, reason string) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false, ""
+	}
+
+	msg := err.Error()
+	for _, p := range permanentErrors {
+		if strings.Contains(msg, p) {
+			return false, ""
+		}
+	}
+	for _, t := range transientErrors {
+		if strings.Contains(msg, t.substr) {
+			return true, t.reason
+		}
+	}
+	return false, ""
+}
+
+// Policy bounds how often and how long an operation is retried
+type Policy struct {
+	Attempts  int           // Total attempts including the first
+	BaseDelay time.Duration // Delay before the second attempt; doubled for each later one
+	MaxDelay  time.Duration // Upper bound for a single delay
+
+	// Overridable for tests
+	sleep func(ctx context.Context, d time.Duration) error
+	rand  func() float64
+}
+
+// DefaultPolicy makes 3 attempts, waiting about 1s and then 2s
+func DefaultPolicy() Policy {
+	return Policy{Attempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+}
+
+// RetryFunc is told about each retry before its delay starts
+type RetryFunc func(attempt, attempts int, reason string, delay time.Duration)
+
+// Do runs fn until it succeeds, fails permanently, or the attempts are used up
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error, onRetry RetryFunc) error {
+	attempts := max(p.Attempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		transient, reason := Classify(err)
+		if !transient || attempt >= attempts {
+			return err
+		}
+
+		delay := p.delay(attempt)
+		if onRetry != nil {
+			onRetry(attempt+1, attempts, reason, delay)
+		}
+		if sleepErr := p.doSleep(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// delay returns the backoff before attempt+1: half fixed, half random to spread out retries
+func (p Policy) delay(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	random := rand.Float64
+	if p.rand != nil {
+		random = p.rand
+	}
+	return d/2 + time.Duration(random()*float64(d/2))
+}
+
+// doSleep waits for d or until ctx is done
+func (p Policy) doSleep(ctx context.Context, d time.Duration) error {
+	if p.sleep != nil {
+		return p.sleep(ctx, d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
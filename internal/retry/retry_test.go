@@ -0,0 +1,99 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err       string
+		transient bool
+		reason    string
+	}{
+		{"Bind for 0.0.0.0:27017 failed: port is already allocated", true, "port conflict"},
+		{"Get \"https://registry-1.docker.io/v2/\": net/http: TLS handshake timeout", true, "pull timeout"},
+		{"read tcp 10.0.0.2:5000->1.2.3.4:443: read: connection reset by peer", true, "network error"},
+		{"toomanyrequests: You have reached your pull rate limit", true, "registry rate limit"},
+		{"received unexpected HTTP status: 503 Service Unavailable", true, "registry unavailable"},
+		{"manifest for mongo:99 not found: manifest unknown: manifest unknown", false, ""},
+		{"invalid reference format: repository name must be lowercase", false, ""},
+		{"pull access denied for private/mongo, repository does not exist", false, ""},
+		{"No such image: mongo:7.0", false, ""},
+		{"something unexpected happened", false, ""},
+	}
+
+	for _, tt := range tests {
+		transient, reason := Classify(fmt.Errorf("failed to start container: %w", errors.New(tt.err)))
+		if transient != tt.transient || reason != tt.reason {
+			t.Errorf("Classify(%q) = %t %q, expected %t %q", tt.err, transient, reason, tt.transient, tt.reason)
+		}
+	}
+
+	if transient, _ := Classify(context.Canceled); transient {
+		t.Error("Expected context.Canceled to be permanent")
+	}
+}
+
+// testPolicy returns a policy that records delays instead of sleeping
+func testPolicy(delays *[]time.Duration) Policy {
+	p := DefaultPolicy()
+	p.rand = func() float64 { return 1 }
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return p
+}
+
+func TestPolicy_RetriesTransientErrors(t *testing.T) {
+	var delays []time.Duration
+	var notices []string
+	calls := 0
+
+	err := testPolicy(&delays).Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("port is already allocated")
+		}
+		return nil
+	}, func(attempt, attempts int, reason string, delay time.Duration) {
+		notices = append(notices, fmt.Sprintf("attempt %d/%d: %s", attempt, attempts, reason))
+	})
+
+	if err != nil || calls != 3 {
+		t.Fatalf("Expected success on the third attempt, got %v after %d calls", err, calls)
+	}
+	if len(delays) != 2 || delays[0] != time.Second || delays[1] != 2*time.Second {
+		t.Fatalf("Expected exponential delays of 1s and 2s, got %v", delays)
+	}
+	if notices[1] != "attempt 3/3: port conflict" {
+		t.Fatalf("Unexpected retry notice %q", notices[1])
+	}
+}
+
+func TestPolicy_StopsOnPermanentErrorAndExhaustion(t *testing.T) {
+	var delays []time.Duration
+	calls := 0
+	permanent := errors.New("manifest unknown")
+
+	err := testPolicy(&delays).Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return permanent
+	}, nil)
+	if !errors.Is(err, permanent) || calls != 1 {
+		t.Fatalf("Expected immediate permanent failure, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = testPolicy(&delays).Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return errors.New("i/o timeout")
+	}, nil)
+	if err == nil || calls != 3 {
+		t.Fatalf("Expected failure after 3 attempts, got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,48 @@
+package scenario
+
+// Anomalies that scenarios demonstrate. Scenarios of different providers with the same key show the
+// same phenomenon, so their runs can be compared.
+const (
+	AnomalyDirtyRead         = "dirty-read"
+	AnomalyNonRepeatableRead = "non-repeatable-read"
+	AnomalyPhantomRead       = "phantom-read"
+	AnomalyReadSkew          = "read-skew"
+	AnomalyLostUpdate        = "lost-update"
+)
+
+// Roles a step plays in a demonstration. Comparisons line up steps of the same session and role,
+// whatever their numbers are.
+const (
+	RoleBegin           = "begin"            // A transaction starts
+	RoleRead            = "read"             // A read of the data under test
+	RoleWrite           = "write"            // A write inside the session's own transaction
+	RoleConcurrentWrite = "concurrent-write" // A write that races the other session
+	RoleReread          = "reread"           // The same read again, which shows whether the anomaly happened
+	RoleCommit          = "commit"
+	RoleAbort           = "abort"
+	RoleVerify          = "verify" // A read of the outcome once the sessions are done
+)
+
+// AnomalyKeyer is implemented by scenarios that demonstrate a well-known anomaly
+type AnomalyKeyer interface {
+	// AnomalyKey returns the anomaly the scenario demonstrates, e.g. AnomalyPhantomRead
+	AnomalyKey() string
+}
+
+// AnomalyOf returns the anomaly a scenario demonstrates, or "" if it declares none
+func AnomalyOf(s Scenario) string {
+	if k, ok := s.(AnomalyKeyer); ok {
+		return k.AnomalyKey()
+	}
+	return ""
+}
+
+// GetByAnomaly returns the first scenario demonstrating the anomaly, or nil if there is none
+func (r *Registry) GetByAnomaly(key string) Scenario {
+	for _, s := range r.scenarios {
+		if key != "" && AnomalyOf(s) == key {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package scenario
+
+import (
+	"context"
+	"sync"
+)
+
+// Assertion is an expectation about the database's isolation behavior, checked during a run
+type Assertion struct {
+	Name   string `json:"name"`
+	Held   bool   `json:"held"`
+	Detail string `json:"detail,omitempty"` // What was actually observed
+}
+
+// Assertions collects the assertions checked during a run; safe for concurrent use
+type Assertions struct {
+	mu   sync.Mutex
+	list []Assertion
+}
+
+// All returns every recorded assertion in order
+func (a *Assertions) All() []Assertion {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Assertion(nil), a.list...)
+}
+
+// Failed returns the assertions that did not hold
+func (a *Assertions) Failed() []Assertion {
+	var failed []Assertion
+	for _, as := range a.All() {
+		if !as.Held {
+			failed = append(failed, as)
+		}
+	}
+	return failed
+}
+
+func (a *Assertions) add(as Assertion) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.list = append(a.list, as)
+}
+
+type assertionsKey struct{}
+
+// WithAssertions returns a context whose scenario assertions are recorded in a
+func WithAssertions(ctx context.Context, a *Assertions) context.Context {
+	return context.WithValue(ctx, assertionsKey{}, a)
+}
+
+// Assert records whether an expectation held, with what was observed.
+// Nothing is recorded when the context has no collector, e.g. in the TUI.
+func Assert(ctx context.Context, name string, held bool, detail string) {
+	if a, ok := ctx.Value(assertionsKey{}).(*Assertions); ok {
+		a.add(Assertion{Name: name, Held: held, Detail: detail})
+	}
+}
@@ -0,0 +1,24 @@
+package scenario
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAssert(t *testing.T) {
+	// Without a collector assertions are dropped
+	Assert(context.Background(), "ignored", false, "")
+
+	var a Assertions
+	ctx := WithAssertions(context.Background(), &a)
+	Assert(ctx, "uncommitted writes are invisible", true, "0 documents")
+	Assert(ctx, "conflict is detected", false, "commit succeeded")
+
+	if len(a.All()) != 2 {
+		t.Fatalf("Expected 2 assertions, got %d", len(a.All()))
+	}
+	failed := a.Failed()
+	if len(failed) != 1 || failed[0].Name != "conflict is detected" {
+		t.Fatalf("Expected the conflict assertion to fail, got %+v", failed)
+	}
+}
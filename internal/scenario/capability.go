@@ -0,0 +1,63 @@
+package scenario
+
+// Capability identifies a database feature that a scenario may depend on
+type Capability string
+
+const (
+	// CapMultiDocumentTransactions means the database supports transactions spanning several documents/rows
+	CapMultiDocumentTransactions Capability = "Multi-document transactions"
+
+	// CapSnapshotReads means reads can be pinned to a point-in-time snapshot
+	CapSnapshotReads Capability = "Snapshot reads"
+
+	// CapSecondaryReads means the deployment has secondaries that can serve reads
+	CapSecondaryReads Capability = "Secondary reads"
+
+	// CapFailPoints means the server accepts failpoint test commands
+	CapFailPoints Capability = "Fail points"
+
+	// CapShardedCluster means data can be spread across shards and transactions may span them
+	CapShardedCluster Capability = "Sharded cluster"
+)
+
+// CapabilitySet is the set of capabilities a provider supports
+type CapabilitySet map[Capability]bool
+
+// NewCapabilitySet creates a capability set containing the given capabilities
+func NewCapabilitySet(caps ...Capability) CapabilitySet {
+	set := make(CapabilitySet, len(caps))
+	for _, c := range caps {
+		set[c] = true
+	}
+	return set
+}
+
+// Has returns whether the set contains the capability
+func (s CapabilitySet) Has(c Capability) bool {
+	return s[c]
+}
+
+// Missing returns the required capabilities that are not in the set, preserving order
+func (s CapabilitySet) Missing(required []Capability) []Capability {
+	var missing []Capability
+	for _, c := range required {
+		if !s.Has(c) {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// Requirer is implemented by scenarios that only work when the provider has specific capabilities
+type Requirer interface {
+	// Requires returns the capabilities the scenario needs to run
+	Requires() []Capability
+}
+
+// Requirements returns the capabilities required by a scenario, or nil if it declares none
+func Requirements(s Scenario) []Capability {
+	if r, ok := s.(Requirer); ok {
+		return r.Requires()
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package scenario
+
+import (
+	"testing"
+)
+
+// requiringScenario is a mock scenario that declares capability requirements
+type requiringScenario struct {
+	MockScenario
+	requires []Capability
+}
+
+func (r *requiringScenario) Requires() []Capability {
+	return r.requires
+}
+
+func TestCapabilitySet_Missing(t *testing.T) {
+	caps := NewCapabilitySet(CapMultiDocumentTransactions, CapSnapshotReads)
+
+	missing := caps.Missing([]Capability{CapSecondaryReads, CapSnapshotReads, CapFailPoints})
+	if len(missing) != 2 || missing[0] != CapSecondaryReads || missing[1] != CapFailPoints {
+		t.Fatalf("Expected [%s %s], got %v", CapSecondaryReads, CapFailPoints, missing)
+	}
+
+	if missing := caps.Missing(nil); len(missing) != 0 {
+		t.Fatalf("Expected nothing missing for no requirements, got %v", missing)
+	}
+
+	// A nil set (provider not started) supports nothing
+	var none CapabilitySet
+	if len(none.Missing([]Capability{CapSnapshotReads})) != 1 {
+		t.Fatal("Expected nil set to be missing every requirement")
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	if reqs := Requirements(&MockScenario{name: "plain"}); reqs != nil {
+		t.Fatalf("Expected no requirements, got %v", reqs)
+	}
+
+	s := &requiringScenario{requires: []Capability{CapFailPoints}}
+	if reqs := Requirements(s); len(reqs) != 1 || reqs[0] != CapFailPoints {
+		t.Fatalf("Expected [%s], got %v", CapFailPoints, reqs)
+	}
+}
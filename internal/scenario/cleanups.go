@@ -0,0 +1,152 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// RunID identifies a run in Cleanups
+type RunID uint64
+
+// Cleanups tracks runs from their Setup until their Cleanup has run, so whichever way a run ends -
+// completion, failure, cancellation or the app quitting - a scenario that was set up is cleaned up
+// exactly once. Safe for concurrent use.
+type Cleanups struct {
+	mu      sync.Mutex
+	next    RunID
+	pending map[RunID]Scenario // Runs whose Cleanup hasn't started
+	kept    map[Scenario]RunID // Pending runs that left their data for the next run of the scenario
+	active  int                // Runs whose Cleanup hasn't finished
+	wg      sync.WaitGroup
+}
+
+// NewCleanups creates an empty cleanup registry
+func NewCleanups() *Cleanups {
+	return &Cleanups{pending: make(map[RunID]Scenario), kept: make(map[Scenario]RunID)}
+}
+
+// add records that s is being set up and will need cleaning up
+func (c *Cleanups) add(s Scenario) RunID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next++
+	c.pending[c.next] = s
+	c.active++
+	c.wg.Add(1)
+	return c.next
+}
+
+// drop forgets the run id without cleaning up, for a Setup that failed
+func (c *Cleanups) drop(id RunID) {
+	if c.take(id) != nil {
+		c.finish()
+	}
+}
+
+// take removes the run id, returning its scenario or nil when it is no longer pending
+func (c *Cleanups) take(id RunID) Scenario {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.pending[id]
+	delete(c.pending, id)
+	if kept, ok := c.kept[s]; ok && kept == id {
+		delete(c.kept, s)
+	}
+	return s
+}
+
+// keep leaves the data of run id in place for the next run of its scenario to take over with
+// reuse. The run stays pending, so Wait still cleans it up when no run does.
+func (c *Cleanups) keep(id RunID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.pending[id]; ok {
+		c.kept[s] = id
+	}
+}
+
+// reuse hands the run whose data an earlier run of s kept over to a new run, which then owns its
+// cleanup
+func (c *Cleanups) reuse(s Scenario) (RunID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.kept[s]
+	delete(c.kept, s)
+	return id, ok
+}
+
+// finish marks a taken run as done
+func (c *Cleanups) finish() {
+	c.mu.Lock()
+	c.active--
+	c.mu.Unlock()
+	c.wg.Done()
+}
+
+// run cleans up the run id unless that already happened. Cleanup gets CleanupTimeout even when
+// ctx was cancelled, since cancelling a run is exactly when its data would otherwise be left behind.
+func (c *Cleanups) run(ctx context.Context, id RunID) error {
+	s := c.take(id)
+	if s == nil {
+		return nil
+	}
+	defer c.finish()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), CleanupTimeout)
+	defer cancel()
+	return cleanup(ctx, s)
+}
+
+// Pending returns the number of runs being set up, run or cleaned up
+func (c *Cleanups) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.active
+}
+
+// Wait cleans up the data runs kept for reuse, then blocks until every pending run is cleaned up
+// or failed its Setup, or ctx is done
+func (c *Cleanups) Wait(ctx context.Context) error {
+	c.mu.Lock()
+	kept := make([]RunID, 0, len(c.kept))
+	for _, id := range c.kept {
+		kept = append(kept, id)
+	}
+	c.mu.Unlock()
+	var errs []error
+	for _, id := range kept {
+		if err := c.run(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return errors.Join(errs...)
+	case <-ctx.Done():
+		return fmt.Errorf("failed to wait for %d scenario cleanups: %w", c.Pending(), ctx.Err())
+	}
+}
+
+type cleanupsKey struct{}
+
+// WithCleanups returns a context whose runs are tracked in c
+func WithCleanups(ctx context.Context, c *Cleanups) context.Context {
+	return context.WithValue(ctx, cleanupsKey{}, c)
+}
+
+// cleanupsFrom returns the registry of ctx and true, or a new one and false for a run nobody
+// else tracks
+func cleanupsFrom(ctx context.Context) (*Cleanups, bool) {
+	if c, ok := ctx.Value(cleanupsKey{}).(*Cleanups); ok {
+		return c, true
+	}
+	return NewCleanups(), false
+}
@@ -0,0 +1,29 @@
+package scenario
+
+import (
+	"math"
+	"math/rand"
+)
+
+// datasetRegions are spread across generated records
+var datasetRegions = []string{"eu", "us", "apac"}
+
+// Record is one generated row/document for range-scan scenarios
+type Record struct {
+	ID     int
+	Amount float64 // Between 1 and 200, so roughly half the records have an amount above 100
+	Region string
+}
+
+// GenerateDataset returns the records for a dataset size, drawing amounts and regions from rng
+func GenerateDataset(size DatasetSize, rng *rand.Rand) []Record {
+	records := make([]Record, size.Count())
+	for i := range records {
+		records[i] = Record{
+			ID:     i + 1,
+			Amount: math.Round((1+rng.Float64()*199)*100) / 100,
+			Region: datasetRegions[rng.Intn(len(datasetRegions))],
+		}
+	}
+	return records
+}
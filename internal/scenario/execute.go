@@ -0,0 +1,303 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+)
+
+// Outcome is how an executed scenario ended
+type Outcome struct {
+	Err     error // Setup or Run failed
+	Cleanup error // Cleanup failed afterwards, as a *CleanupError; the run itself may still have passed
+}
+
+// CleanupError reports that Cleanup failed, leaving the scenario's data behind
+type CleanupError struct {
+	Collections []string // What the scenario would have removed, when it tells
+	Err         error
+}
+
+func (e *CleanupError) Error() string {
+	if len(e.Collections) == 0 {
+		return fmt.Sprintf("failed to clean up: %v", e.Err)
+	}
+	return fmt.Sprintf("failed to clean up %s: %v", strings.Join(e.Collections, ", "), e.Err)
+}
+
+func (e *CleanupError) Unwrap() error {
+	return e.Err
+}
+
+// CollectionOwner is implemented by scenarios that can name the collections or tables they
+// create, so a failed cleanup can tell what was left behind
+type CollectionOwner interface {
+	Collections() []string
+}
+
+// Reusable is implemented by scenarios whose data is worth keeping when a run fails before its
+// first step, such as large seeded datasets. Scenarios narrate each change as they make it, so a
+// run without steps changed nothing, and the next run can skip Setup when Pristine confirms,
+// cheaply, e.g. with a sentinel document, that the data seeded is what the params of ctx call for.
+type Reusable interface {
+	Pristine(ctx context.Context) (bool, error)
+}
+
+// Execute runs Setup, Run and Cleanup in turn, converting panics into errors.
+// Steps are sent to output, which is closed once Run returns or Setup fails; scenarios never close it.
+// Setup and Run share the time limit given by TimeoutOf. Past it they are cancelled, a failed step
+// says so and the run fails with a *TimeoutError.
+// The run is tracked in the Cleanups of ctx, if any. Once Setup succeeded Cleanup always runs,
+// with CleanupTimeout of its own even when ctx was cancelled. A Cleanup error is reported apart
+// from the run's, so it doesn't mask the outcome of the run. A tracked run of a Reusable scenario
+// that fails before its first step keeps its data instead, and the next run of the scenario skips
+// Setup when the data is still pristine, saying so in a header.
+func Execute(ctx context.Context, s Scenario, output chan<- StepResult) Outcome {
+	log := slog.Default().With("scenario", s.Name())
+	started := time.Now()
+	timeout := TimeoutOf(s, ParamsFromContext(ctx))
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cleanups, tracked := cleanupsFrom(ctx)
+	id, reused := cleanups.reuse(s)
+	if reused && !pristine(runCtx, log, s) {
+		if err := cleanups.run(ctx, id); err != nil {
+			log.WarnContext(ctx, "cleanup of the previous run's data failed", "error", err)
+		}
+		reused = false
+	}
+
+	if reused {
+		log.InfoContext(ctx, "scenario reusing the previous run's data")
+		forward(ctx, output, StepResult{
+			IsHeader:    true,
+			Description: i18n.T("♻️ Reusing existing test data: the previous run failed before changing it"),
+		})
+	} else {
+		id = cleanups.add(s)
+		log.InfoContext(ctx, "scenario setup", "timeout", timeout)
+		if err := setup(runCtx, s); err != nil {
+			cleanups.drop(id)
+			if timedOut(ctx, runCtx) {
+				err = &TimeoutError{Timeout: timeout}
+			}
+			log.ErrorContext(ctx, "scenario setup failed", "error", err)
+			close(output)
+			return Outcome{Err: err}
+		}
+	}
+
+	steps, stepCount := logSteps(ctx, log, output)
+	err := run(runCtx, s, steps)
+	expired := err != nil && timedOut(ctx, runCtx)
+	if expired {
+		err = &TimeoutError{Timeout: timeout}
+		steps <- StepResult{
+			Session:     "Result",
+			Description: i18n.T("⏱️ Scenario timed out after %s", timeout),
+			Result:      i18n.T("Cancelled; the scenario was still running"),
+			Success:     false,
+		}
+	}
+	close(steps)
+	if err != nil {
+		log.ErrorContext(ctx, "scenario failed", "error", err, "duration", time.Since(started))
+	} else {
+		log.InfoContext(ctx, "scenario finished", "duration", time.Since(started))
+	}
+
+	outcome := Outcome{Err: err}
+	if _, ok := s.(Reusable); ok && tracked && err != nil && !expired && ctx.Err() == nil && stepCount() == 0 {
+		log.InfoContext(ctx, "scenario failed before its first step; keeping its data for the next run")
+		cleanups.keep(id)
+		return outcome
+	}
+	if cleanupErr := cleanups.run(ctx, id); cleanupErr != nil {
+		log.WarnContext(ctx, "scenario cleanup failed", "error", cleanupErr)
+		outcome.Cleanup = &CleanupError{Collections: collectionsOf(s), Err: cleanupErr}
+	}
+	return outcome
+}
+
+// collectionsOf returns the collections s creates, or nil when it doesn't tell
+func collectionsOf(s Scenario) []string {
+	if o, ok := s.(CollectionOwner); ok {
+		return o.Collections()
+	}
+	return nil
+}
+
+// logSteps returns a channel that logs each step with the time since the previous one before
+// forwarding it to output, renumbering steps whose numbers repeat or go back. The outcome of a
+// pending step takes its number and how long it waited. output is closed
+// when the returned channel is. Once it is, the returned func waits for the steps left to be
+// forwarded and returns how many there were, headers aside.
+// Once ctx is done, steps the consumer doesn't take are dropped, so a scenario sending after a
+// consumer gave up is never left blocked. After the first dropped step the rest are dropped too,
+// so the consumer never sees a gap.
+func logSteps(ctx context.Context, log *slog.Logger, output chan<- StepResult) (chan<- StepResult, func() int) {
+	steps := make(chan StepResult, cap(output))
+	done := make(chan struct{})
+	count := 0
+	go func() {
+		defer close(done)
+		defer close(output)
+		last := time.Now()
+		var numbers stepNumbers
+		warned := false
+		delivering := true
+		for step := range steps {
+			if step.IsHeader {
+				log.InfoContext(ctx, "scenario section", "description", step.Description)
+				delivering = delivering && forward(ctx, output, step)
+				continue
+			}
+			now := time.Now()
+			if numbers.complete(&step, now) {
+				// The outcome replaces its pending step rather than adding one
+				log.InfoContext(ctx, "scenario step completed", "step", step.Step, "session", step.Session,
+					"description", step.Description, "success", step.Success, "waited", step.Waited)
+				last = now
+				delivering = delivering && forward(ctx, output, step)
+				continue
+			}
+			if numbers.renumber(&step) && !warned {
+				// Once per run is enough for the author to notice
+				log.WarnContext(ctx, "scenario step numbers repeat or go back; renumbering for display",
+					"step", step.OriginalStep, "renumbered", step.Step)
+				warned = true
+			}
+			if step.Pending {
+				numbers.wait(step, now)
+			}
+			log.InfoContext(ctx, "scenario step", "step", step.Step, "session", step.Session,
+				"description", step.Description, "success", step.Success, "duration", now.Sub(last))
+			last = now
+			count++
+			delivering = delivering && forward(ctx, output, step)
+		}
+	}()
+	return steps, func() int {
+		<-done
+		return count
+	}
+}
+
+// stepNumbers keeps the numbers of a run's steps strictly increasing, but for the outcomes of
+// pending steps, which share their number
+type stepNumbers struct {
+	last    int
+	started bool
+	pending map[string]pendingStep // The step each session is waiting on
+}
+
+// pendingStep is a pending step as it was forwarded, and when
+type pendingStep struct {
+	step  StepResult
+	since time.Time
+}
+
+// wait records step, forwarded at now, as its session's pending step
+func (n *stepNumbers) wait(step StepResult, now time.Time) {
+	if n.pending == nil {
+		n.pending = make(map[string]pendingStep)
+	}
+	n.pending[step.Session] = pendingStep{step: step, since: now}
+}
+
+// complete gives step the number of the pending step of its session it is the outcome of, and how
+// long that one waited until now, reporting whether step was one: a step that isn't pending, with
+// the number the scenario gave the pending step or none. A blocked session does nothing else.
+func (n *stepNumbers) complete(step *StepResult, now time.Time) bool {
+	p, ok := n.pending[step.Session]
+	if !ok || step.Pending || (step.Step != 0 && step.Step != p.step.SourceStep()) {
+		return false
+	}
+	delete(n.pending, step.Session)
+	step.Step, step.OriginalStep = p.step.Step, p.step.OriginalStep
+	step.Waited = now.Sub(p.since)
+	return true
+}
+
+// renumber gives step the number after the previous one when its own repeats or goes back,
+// keeping the scenario's number in OriginalStep, and reports whether it did. Gaps are kept, so
+// quizzes and explanations still find the steps they refer to. A step without a number simply
+// gets the next one.
+func (n *stepNumbers) renumber(step *StepResult) bool {
+	if step.Step == 0 {
+		step.Step = n.last + 1
+	}
+	renumbered := n.started && step.Step <= n.last
+	if renumbered {
+		step.OriginalStep = step.Step
+		step.Step = n.last + 1
+	}
+	n.last = step.Step
+	n.started = true
+	return renumbered
+}
+
+// forward sends step to output and reports whether it did. Once ctx is done it drops the step,
+// unless the consumer is ready to take it.
+func forward(ctx context.Context, output chan<- StepResult, step StepResult) bool {
+	select {
+	case output <- step:
+		return true
+	default:
+	}
+	select {
+	case output <- step:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// pristine returns whether the data kept for s is still what its next run needs. A failed check
+// counts as no, so the data is seeded again.
+func pristine(ctx context.Context, log *slog.Logger, s Scenario) (ok bool) {
+	r, reusable := s.(Reusable)
+	if !reusable {
+		return false
+	}
+	var err error
+	func() {
+		defer Recover(&err)
+		ok, err = r.Pristine(ctx)
+	}()
+	if err != nil {
+		log.WarnContext(ctx, "failed to check the previous run's data; seeding again", "error", err)
+		return false
+	}
+	return ok
+}
+
+// setup calls Setup, converting a panic into an error
+func setup(ctx context.Context, s Scenario) (err error) {
+	defer Recover(&err)
+	return s.Setup(ctx)
+}
+
+// run calls Run, converting a panic into an error
+func run(ctx context.Context, s Scenario, output chan<- StepResult) (err error) {
+	defer Recover(&err)
+	return s.Run(ctx, output)
+}
+
+// timedOut returns whether runCtx, derived from ctx, ended because its own time limit passed
+// rather than because ctx was cancelled
+func timedOut(ctx, runCtx context.Context) bool {
+	return errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+}
+
+// cleanup calls Cleanup, converting a panic into an error
+func cleanup(ctx context.Context, s Scenario) (err error) {
+	defer Recover(&err)
+	return s.Cleanup(ctx)
+}
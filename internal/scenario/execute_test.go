@@ -0,0 +1,307 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// floodScenario emits n steps as fast as it can
+type floodScenario struct {
+	MockScenario
+	n int
+}
+
+func (f *floodScenario) Run(ctx context.Context, output chan<- StepResult) error {
+	for i := range f.n {
+		output <- StepResult{Session: "Session A", Step: i + 1, Success: true}
+	}
+	return nil
+}
+
+func TestExecute_DeliversEveryStep(t *testing.T) {
+	output := make(chan StepResult, 100)
+	done := make(chan error, 1)
+	go func() {
+		done <- Execute(context.Background(), &floodScenario{n: 10000}, output).Err
+	}()
+
+	count := 0
+	for range output {
+		count++
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 10000 {
+		t.Fatalf("Expected 10000 steps, got %d", count)
+	}
+}
+
+func TestExecute_CancelReleasesBlockedScenario(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	output := make(chan StepResult, 100)
+	done := make(chan error, 1)
+	go func() {
+		done <- Execute(ctx, &floodScenario{n: 1000}, output).Err
+	}()
+
+	// Nobody reads output, so the scenario blocks once the buffers are full
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected cancelling the context to release a scenario blocked on send")
+	}
+}
+
+// leakyScenario passes but fails to drop its collection
+type leakyScenario struct {
+	MockScenario
+}
+
+func (l *leakyScenario) Cleanup(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func (l *leakyScenario) Collections() []string {
+	return []string{"leaky_demo"}
+}
+
+func TestExecute_ReportsCleanupApart(t *testing.T) {
+	outcome := Execute(context.Background(), &leakyScenario{}, make(chan StepResult, 1))
+	if outcome.Err != nil {
+		t.Fatalf("Expected the run to succeed, got %v", outcome.Err)
+	}
+
+	var cleanupErr *CleanupError
+	if !errors.As(outcome.Cleanup, &cleanupErr) {
+		t.Fatalf("Expected a CleanupError, got %v", outcome.Cleanup)
+	}
+	if want := "failed to clean up leaky_demo: connection refused"; cleanupErr.Error() != want {
+		t.Fatalf("Expected %q, got %q", want, cleanupErr.Error())
+	}
+}
+
+// hangingScenario waits on its context after one step, like a write waiting for a majority that
+// never comes, and records whether Cleanup could still reach the database
+type hangingScenario struct {
+	MockScenario
+	cleanupErr error
+}
+
+func (h *hangingScenario) Metadata() Metadata {
+	return Metadata{Timeout: 50 * time.Millisecond}
+}
+
+func (h *hangingScenario) Run(ctx context.Context, output chan<- StepResult) error {
+	output <- StepResult{Session: "Session A", Step: 1, Success: true}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (h *hangingScenario) Cleanup(ctx context.Context) error {
+	h.cleanupErr = ctx.Err()
+	return nil
+}
+
+func TestExecute_TimesOutHangingScenario(t *testing.T) {
+	s := &hangingScenario{}
+	output := make(chan StepResult, 100)
+	outcome := Execute(context.Background(), s, output)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(outcome.Err, &timeoutErr) || timeoutErr.Timeout != 50*time.Millisecond {
+		t.Fatalf("Expected a timeout after 50ms, got %v", outcome.Err)
+	}
+	var steps []StepResult
+	for step := range output {
+		steps = append(steps, step)
+	}
+	if len(steps) != 2 || steps[1].Success || steps[1].Step != 2 {
+		t.Fatalf("Expected a failed timeout step after the first, got %+v", steps)
+	}
+	if s.cleanupErr != nil {
+		t.Fatalf("Expected Cleanup to get a live context, got %v", s.cleanupErr)
+	}
+}
+
+// exitingScenario ends its run the way exit says and counts its cleanups
+type exitingScenario struct {
+	MockScenario
+	exit       string
+	cleanups   atomic.Int32
+	cleanupErr error
+}
+
+func (e *exitingScenario) Setup(ctx context.Context) error {
+	if e.exit == "setup" {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (e *exitingScenario) Run(ctx context.Context, output chan<- StepResult) error {
+	switch e.exit {
+	case "failure":
+		return errors.New("write conflict")
+	case "cancel":
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (e *exitingScenario) Cleanup(ctx context.Context) error {
+	e.cleanups.Add(1)
+	e.cleanupErr = ctx.Err()
+	return nil
+}
+
+func TestExecute_CleansUpOnceWhateverTheExit(t *testing.T) {
+	for exit, want := range map[string]int32{"completion": 1, "failure": 1, "cancel": 1, "setup": 0} {
+		t.Run(exit, func(t *testing.T) {
+			s := &exitingScenario{exit: exit}
+			cleanups := NewCleanups()
+			ctx, cancel := context.WithCancel(WithCleanups(context.Background(), cleanups))
+			if exit == "cancel" {
+				time.AfterFunc(10*time.Millisecond, cancel)
+			}
+			defer cancel()
+
+			Execute(ctx, s, make(chan StepResult, 1))
+			if got := s.cleanups.Load(); got != want {
+				t.Fatalf("Expected %d cleanups, got %d", want, got)
+			}
+			if s.cleanupErr != nil {
+				t.Fatalf("Expected Cleanup to get a live context, got %v", s.cleanupErr)
+			}
+			if err := cleanups.Wait(context.Background()); err != nil || cleanups.Pending() != 0 {
+				t.Fatalf("Expected no pending cleanups, got %d (%v)", cleanups.Pending(), err)
+			}
+		})
+	}
+}
+
+// reusableScenario fails its run before any step while broken, and counts its setups and cleanups
+type reusableScenario struct {
+	MockScenario
+	broken   bool
+	pristine bool
+	setups   int
+	cleanups int
+}
+
+func (r *reusableScenario) Setup(ctx context.Context) error {
+	r.setups++
+	return nil
+}
+
+func (r *reusableScenario) Run(ctx context.Context, output chan<- StepResult) error {
+	output <- StepResult{IsHeader: true, Description: "Demo"}
+	if r.broken {
+		return errors.New("connection lost")
+	}
+	output <- StepResult{Session: "Session A", Step: 1, Description: "Update", Success: true}
+	return nil
+}
+
+func (r *reusableScenario) Cleanup(ctx context.Context) error {
+	r.cleanups++
+	return nil
+}
+
+func (r *reusableScenario) Pristine(ctx context.Context) (bool, error) {
+	return r.pristine, nil
+}
+
+func TestExecute_ReusesDataOfRunFailingBeforeItsFirstStep(t *testing.T) {
+	s := &reusableScenario{broken: true, pristine: true}
+	cleanups := NewCleanups()
+	ctx := WithCleanups(context.Background(), cleanups)
+	execute := func() []StepResult {
+		output := make(chan StepResult, 10)
+		Execute(ctx, s, output)
+		var steps []StepResult
+		for step := range output {
+			steps = append(steps, step)
+		}
+		return steps
+	}
+
+	execute()
+	if s.setups != 1 || s.cleanups != 0 || cleanups.Pending() != 1 {
+		t.Fatalf("Expected the data kept for the next run, got %d setups, %d cleanups", s.setups, s.cleanups)
+	}
+
+	// The data is still pristine, so the next run says it reuses it instead of seeding again
+	s.broken = false
+	steps := execute()
+	if s.setups != 1 || len(steps) == 0 || !strings.Contains(steps[0].Description, "Reusing existing test data") {
+		t.Fatalf("Expected Setup skipped with a note, got %d setups and %v", s.setups, steps)
+	}
+	if s.cleanups != 1 {
+		t.Fatalf("Expected a run with steps to clean up, got %d cleanups", s.cleanups)
+	}
+
+	// Data that changed since is cleaned up and seeded again
+	s.broken = true
+	execute()
+	s.pristine = false
+	execute()
+	if s.setups != 3 || s.cleanups != 2 {
+		t.Fatalf("Expected changed data reseeded, got %d setups, %d cleanups", s.setups, s.cleanups)
+	}
+
+	// Data kept when nobody runs the scenario again is cleaned up on quit
+	if err := cleanups.Wait(context.Background()); err != nil || s.cleanups != 3 || cleanups.Pending() != 0 {
+		t.Fatalf("Expected kept data cleaned up by Wait, got %d cleanups (%v)", s.cleanups, err)
+	}
+}
+
+// lockingScenario has Session B wait on a lock Session A holds until it commits
+type lockingScenario struct {
+	MockScenario
+	hold time.Duration
+}
+
+func (l *lockingScenario) Run(ctx context.Context, output chan<- StepResult) error {
+	output <- StepResult{Session: "Session A", Step: 1, Description: "Lock the row", Success: true}
+	output <- StepResult{Session: "Session B", Step: 2, Description: "Lock the row", Pending: true}
+	time.Sleep(l.hold)
+	output <- StepResult{Session: "Session A", Step: 3, Description: "Commit", Success: true}
+	output <- StepResult{Session: "Session B", Step: 2, Description: "Lock the row", Result: "locked", Success: true}
+	output <- StepResult{Session: "Session B", Step: 4, Description: "Commit", Success: true}
+	return nil
+}
+
+func TestExecute_CompletesPendingSteps(t *testing.T) {
+	output := make(chan StepResult, 10)
+	outcome := Execute(context.Background(), &lockingScenario{hold: 20 * time.Millisecond}, output)
+	if outcome.Err != nil {
+		t.Fatalf("Expected no error, got %v", outcome.Err)
+	}
+
+	var steps []StepResult
+	for step := range output {
+		steps = append(steps, step)
+	}
+	if len(steps) != 5 {
+		t.Fatalf("Expected 5 steps, got %d", len(steps))
+	}
+	pending, outcomeStep := steps[1], steps[3]
+	if !outcomeStep.Completes(pending) || outcomeStep.OriginalStep != 0 {
+		t.Fatalf("Expected step 2 completed without renumbering, got %+v after %+v", outcomeStep, pending)
+	}
+	if outcomeStep.Waited < 20*time.Millisecond {
+		t.Fatalf("Expected the outcome to have waited at least 20ms, got %s", outcomeStep.Waited)
+	}
+	if steps[4].Step != 4 {
+		t.Fatalf("Expected the next step to keep its number, got %d", steps[4].Step)
+	}
+}
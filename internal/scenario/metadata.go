@@ -0,0 +1,33 @@
+package scenario
+
+import "time"
+
+// Metadata describes a scenario in listings
+type Metadata struct {
+	Tags              []string
+	EstimatedDuration time.Duration // Typical run time with the default dataset, excluding provider startup
+
+	// Timeout overrides the time limit derived from EstimatedDuration, e.g. for demos that wait on
+	// purpose; see TimeoutOf
+	Timeout time.Duration
+}
+
+// Describer is implemented by scenarios that publish listing metadata
+type Describer interface {
+	// Metadata returns the scenario's tags and estimated duration
+	Metadata() Metadata
+}
+
+// MetadataOf returns a scenario's metadata, or the zero value if it declares none
+func MetadataOf(s Scenario) Metadata {
+	if d, ok := s.(Describer); ok {
+		return d.Metadata()
+	}
+	return Metadata{}
+}
+
+// Tags that mark where a scenario comes from
+const (
+	TagCustom = "custom" // Defined by users in a scenario file
+	TagPlugin = "plugin" // Implemented by an external plugin executable
+)
@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Handle resolves the client and database of the provider once it has started
+type Handle func() (*mongo.Client, *mongo.Database, error)
+
+// conn holds a scenario's connection, resolved from its Handle on each use so
+// scenarios can be constructed before the provider starts and survive restarts
+type conn struct {
+	handle         Handle
+	collectionName string
+
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// newConn creates a connection for the named collection
+func newConn(handle Handle, collectionName string) conn {
+	return conn{handle: handle, collectionName: collectionName}
+}
+
+// resolve refreshes the client, database and collection from the handle
+func (c *conn) resolve() error {
+	client, db, err := c.handle()
+	if err != nil {
+		return err
+	}
+	if client == nil || db == nil {
+		return scenario.ErrProviderNotStarted
+	}
+
+	c.client = client
+	c.db = db
+	c.collection = db.Collection(c.collectionName)
+	return nil
+}
+
+// Collections returns the collection the scenario creates
+func (c *conn) Collections() []string {
+	return []string{c.collectionName}
+}
+
+// dbConn is conn for scenarios written against Database rather than the driver, so they run on
+// the fake in tests
+type dbConn struct {
+	open           DatabaseHandle
+	collectionName string
+
+	db         Database
+	collection Collection
+}
+
+// newDBConn creates a connection for the named collection
+func newDBConn(open DatabaseHandle, collectionName string) dbConn {
+	return dbConn{open: open, collectionName: collectionName}
+}
+
+// resolve refreshes the database and collection from the handle
+func (c *dbConn) resolve() error {
+	db, err := c.open()
+	if err != nil {
+		return err
+	}
+	c.db = db
+	c.collection = db.Collection(c.collectionName)
+	return nil
+}
+
+// Collections returns the collection the scenario creates
+func (c *dbConn) Collections() []string {
+	return []string{c.collectionName}
+}
@@ -0,0 +1,149 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Database is what scenarios need of a MongoDB database: collections, sessions for transactions
+// and fail points. The driver implements it on a live server; tests use an in-memory fake, so a
+// scenario's branches can be exercised without a container.
+type Database interface {
+	// Name returns the name of the database, e.g. for namespaces
+	Name() string
+
+	// Collection returns the named collection, which needn't exist yet
+	Collection(name string, opts ...*options.CollectionOptions) Collection
+
+	// StartSession starts a session for transactions; EndSession must be called on it
+	StartSession() (Session, error)
+
+	// ConfigureFailPoint runs configureFailPoint on the admin database, see enableFailPoint
+	ConfigureFailPoint(ctx context.Context, cmd bson.D) error
+}
+
+// Collection is what scenarios need of a collection. Operations run inside the transaction of the
+// session ctx was bound to with Session.Context, and outside any transaction otherwise.
+type Collection interface {
+	// FindOne returns the first document matching filter, or mongo.ErrNoDocuments
+	FindOne(ctx context.Context, filter any) (bson.M, error)
+	Find(ctx context.Context, filter any, opts ...*options.FindOptions) ([]bson.M, error)
+	CountDocuments(ctx context.Context, filter any) (int64, error)
+	InsertOne(ctx context.Context, doc any) (*mongo.InsertOneResult, error)
+	InsertMany(ctx context.Context, docs []any) (*mongo.InsertManyResult, error)
+	UpdateOne(ctx context.Context, filter, update any) (*mongo.UpdateResult, error)
+	Drop(ctx context.Context) error
+}
+
+// Session is a logical session in which transactions run
+type Session interface {
+	StartTransaction(opts ...*options.TransactionOptions) error
+	CommitTransaction(ctx context.Context) error
+	AbortTransaction(ctx context.Context) error
+	EndSession(ctx context.Context)
+
+	// Context returns ctx bound to the session, for operations to run in its transaction
+	Context(ctx context.Context) context.Context
+}
+
+// DatabaseHandle resolves the database of the provider once it has started, like Handle
+type DatabaseHandle func() (Database, error)
+
+// Driver returns a handle to the database handle resolves, through the driver
+func Driver(handle Handle) DatabaseHandle {
+	return func() (Database, error) {
+		client, db, err := handle()
+		if err != nil {
+			return nil, err
+		}
+		if client == nil || db == nil {
+			return nil, scenario.ErrProviderNotStarted
+		}
+		return &driverDatabase{client: client, db: db}, nil
+	}
+}
+
+// driverDatabase is a Database on a live server
+type driverDatabase struct {
+	client *mongo.Client
+	db     *mongo.Database
+}
+
+func (d *driverDatabase) Name() string {
+	return d.db.Name()
+}
+
+func (d *driverDatabase) Collection(name string, opts ...*options.CollectionOptions) Collection {
+	return &driverCollection{coll: d.db.Collection(name, opts...)}
+}
+
+func (d *driverDatabase) StartSession() (Session, error) {
+	session, err := d.client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	return &driverSession{Session: session}, nil
+}
+
+func (d *driverDatabase) ConfigureFailPoint(ctx context.Context, cmd bson.D) error {
+	return d.client.Database("admin").RunCommand(ctx, cmd).Err()
+}
+
+// driverCollection is a Collection on a live server
+type driverCollection struct {
+	coll *mongo.Collection
+}
+
+func (c *driverCollection) FindOne(ctx context.Context, filter any) (bson.M, error) {
+	var doc bson.M
+	if err := c.coll.FindOne(ctx, filter).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (c *driverCollection) Find(ctx context.Context, filter any, opts ...*options.FindOptions) ([]bson.M, error) {
+	cursor, err := c.coll.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (c *driverCollection) CountDocuments(ctx context.Context, filter any) (int64, error) {
+	return c.coll.CountDocuments(ctx, filter)
+}
+
+func (c *driverCollection) InsertOne(ctx context.Context, doc any) (*mongo.InsertOneResult, error) {
+	return c.coll.InsertOne(ctx, doc)
+}
+
+func (c *driverCollection) InsertMany(ctx context.Context, docs []any) (*mongo.InsertManyResult, error) {
+	return c.coll.InsertMany(ctx, docs)
+}
+
+func (c *driverCollection) UpdateOne(ctx context.Context, filter, update any) (*mongo.UpdateResult, error) {
+	return c.coll.UpdateOne(ctx, filter, update)
+}
+
+func (c *driverCollection) Drop(ctx context.Context) error {
+	return c.coll.Drop(ctx)
+}
+
+// driverSession is a Session on a live server
+type driverSession struct {
+	mongo.Session
+}
+
+func (s *driverSession) Context(ctx context.Context) context.Context {
+	return mongo.NewSessionContext(ctx, s.Session)
+}
@@ -5,27 +5,28 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 )
 
 // DirtyReadScenario demonstrates the difference between reading with and without transactions
 type DirtyReadScenario struct {
-	client     *mongo.Client
-	db         *mongo.Database
-	collection *mongo.Collection
+	dbConn
 }
 
 // NewDirtyReadScenario creates a new dirty read demonstration scenario
-func NewDirtyReadScenario(client *mongo.Client, db *mongo.Database) *DirtyReadScenario {
+func NewDirtyReadScenario(handle Handle) *DirtyReadScenario {
+	return newDirtyReadScenario(Driver(handle))
+}
+
+// newDirtyReadScenario creates the scenario on the database open resolves
+func newDirtyReadScenario(open DatabaseHandle) *DirtyReadScenario {
 	return &DirtyReadScenario{
-		client:     client,
-		db:         db,
-		collection: db.Collection("dirty_read_demo"),
+		dbConn: newDBConn(open, "dirty_read_demo"),
 	}
 }
 
@@ -34,7 +35,7 @@ func (s *DirtyReadScenario) Name() string {
 }
 
 func (s *DirtyReadScenario) Description() string {
-	return `Demonstrates how MongoDB transactions prevent dirty reads.
+	return i18n.T(`Demonstrates how MongoDB transactions prevent dirty reads.
 
 Without transactions, reads might see uncommitted data. With transactions
 and proper read concern, you only see committed data.
@@ -43,29 +44,81 @@ This scenario shows:
 1. Session A starts a transaction and inserts a document
 2. Session B tries to read - document is NOT visible (not committed yet)
 3. Session A commits the transaction
-4. Session B reads again - document IS now visible`
+4. Session B reads again - document IS now visible`)
 }
 
 func (s *DirtyReadScenario) IsolationLevel() string {
 	return "Read Committed"
 }
 
+func (s *DirtyReadScenario) Requires() []scenario.Capability {
+	return []scenario.Capability{scenario.CapMultiDocumentTransactions}
+}
+
+// Metadata returns listing tags and the typical run time
+func (s *DirtyReadScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{Tags: []string{"dirty-read", "anomaly"}, EstimatedDuration: 3 * time.Second}
+}
+
+// AnomalyKey identifies the scenario for comparisons across providers
+func (s *DirtyReadScenario) AnomalyKey() string {
+	return scenario.AnomalyDirtyRead
+}
+
+// Quiz asks about the run that just finished
+func (s *DirtyReadScenario) Quiz() []scenario.Question {
+	return []scenario.Question{
+		{
+			Prompt: i18n.T("Why did Session B find no documents at step 5?"),
+			Choices: []string{
+				i18n.T("The insert at step 3 failed"),
+				i18n.T("Session A's transaction had not committed yet"),
+				i18n.T("Session B read from a different collection"),
+			},
+			Answer:      1,
+			Explanation: i18n.T("Writes inside a transaction stay invisible to other sessions until it commits. Once Session A committed at step 6, the same read found the document at step 7."),
+			Steps:       []int{3, 5, 6, 7},
+		},
+		{
+			Prompt: i18n.T("What would Session B have seen at step 5 with readConcern \"local\"?"),
+			Choices: []string{
+				i18n.T("The uncommitted Widget document"),
+				i18n.T("No documents"),
+				i18n.T("A WriteConflict error"),
+			},
+			Answer:      1,
+			Explanation: i18n.T("MongoDB never exposes uncommitted transaction writes, whatever the read concern. \"local\" only risks reading committed data that a failover later rolls back."),
+			Steps:       []int{3, 5},
+		},
+	}
+}
+
 func (s *DirtyReadScenario) Setup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
 	// Drop collection if exists
-	return s.collection.Drop(ctx)
+	return dropCollection(ctx, s.collection)
 }
 
 func (s *DirtyReadScenario) Cleanup(ctx context.Context) error {
-	return s.collection.Drop(ctx)
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	return dropCollection(ctx, s.collection)
 }
 
 func (s *DirtyReadScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
-	defer close(output)
+	if err := s.resolve(); err != nil {
+		return err
+	}
 
 	// Header
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "🔒 Dirty Read Prevention Demonstration",
+		Description: i18n.T("🔒 Dirty Read Prevention Demonstration"),
 	}
 
 	step := 1
@@ -74,65 +127,85 @@ func (s *DirtyReadScenario) Run(ctx context.Context, output chan<- scenario.Step
 	output <- scenario.StepResult{
 		Session:     "Setup",
 		Step:        step,
-		Description: "Checking initial state - collection should be empty",
+		Description: i18n.T("Checking initial state - collection should be empty"),
 		Query:       "db.dirty_read_demo.countDocuments({})",
-		Result:      "Count: 0",
+		Result:      i18n.T("Count: 0"),
 		Success:     true,
 	}
 	step++
 
-	// Step 2: Session A starts a transaction
-	sessionA, err := s.client.StartSession()
+	// Step 2: Session A starts a transaction and keeps it open across the following steps
+	txA, err := s.startTransaction(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to start session A: %w", err)
+		return fmt.Errorf("session A: %w", err)
 	}
-	defer sessionA.EndSession(ctx)
+	defer txA.End(ctx)
 
 	output <- scenario.StepResult{
 		Session:     "Session A",
 		Step:        step,
-		Description: "Starting a transaction",
+		Description: i18n.T("Starting a transaction"),
+		Role:        scenario.RoleBegin,
 		Query:       "session.startTransaction()",
-		Result:      "Transaction started",
+		Result:      i18n.T("Transaction started"),
 		Success:     true,
 	}
 	step++
 
 	// Step 3: Session A inserts a document within transaction
-	err = mongo.WithSession(ctx, sessionA, func(sc mongo.SessionContext) error {
-		if err := sessionA.StartTransaction(); err != nil {
-			return err
-		}
-
-		_, err := s.collection.InsertOne(sc, bson.M{
-			"product": "Widget",
-			"price":   29.99,
-			"status":  "pending",
-		})
-		return err
+	inserted, err := s.collection.InsertOne(txA.ctx, bson.M{
+		"product": "Widget",
+		"price":   29.99,
+		"status":  "pending",
 	})
 	if err != nil {
 		return fmt.Errorf("failed to insert in transaction: %w", err)
 	}
+	found, err := countByFind(txA.ctx, s.collection, bson.M{"_id": inserted.InsertedID})
+	if err != nil {
+		return fmt.Errorf("failed to read back the insert: %w", err)
+	}
 
-	output <- scenario.StepResult{
+	output <- verified(scenario.StepResult{
 		Session:     "Session A",
 		Step:        step,
-		Description: "Inserted document within transaction (NOT YET COMMITTED)",
+		Description: i18n.T("Inserted document within transaction (NOT YET COMMITTED)"),
+		Role:        scenario.RoleWrite,
 		Query:       `db.dirty_read_demo.insertOne({product: "Widget", price: 29.99, status: "pending"})`,
-		Result:      "Insert successful (within transaction)",
+		Result:      i18n.T("Insert successful (within transaction)"),
+		Success:     true,
+	}, found == 1, i18n.T("Count: %d", found))
+	step++
+
+	scenario.Pause(ctx)
+
+	// Step 4: Session A reads its own uncommitted write
+	own, err := countByFind(txA.ctx, s.collection, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to read in transaction: %w", err)
+	}
+	scenario.Assert(ctx, "a transaction sees its own uncommitted insert", own == 1,
+		fmt.Sprintf("%d documents visible inside the transaction", own))
+
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        step,
+		Description: i18n.T("Reading documents inside its own transaction"),
+		Query:       "db.dirty_read_demo.find({}, {_id: 1}).itcount()",
+		Result:      i18n.T("Count: %d (a transaction sees its own writes)", own),
 		Success:     true,
 	}
 	step++
 
 	// Small delay for visual effect
-	time.Sleep(500 * time.Millisecond)
+	scenario.Pause(ctx)
 
-	// Step 4: Session B tries to read (should NOT see uncommitted data)
+	// Step 5: Session B tries to read (should NOT see uncommitted data)
 	output <- scenario.StepResult{
 		Session:     "Session B",
 		Step:        step,
-		Description: "Attempting to read documents (outside Session A's transaction)",
+		Description: i18n.T("Attempting to read documents (outside Session A's transaction)"),
+		Role:        scenario.RoleRead,
 		Query:       `db.dirty_read_demo.find({})`,
 		Result:      "",
 		Success:     true,
@@ -140,63 +213,60 @@ func (s *DirtyReadScenario) Run(ctx context.Context, output chan<- scenario.Step
 
 	// Read with majority read concern by using a collection with that concern
 	collWithReadConcern := s.db.Collection("dirty_read_demo", options.Collection().SetReadConcern(readconcern.Majority()))
-	cursor, err := collWithReadConcern.Find(ctx, bson.M{})
+	results, err := collWithReadConcern.Find(ctx, bson.M{})
 	if err != nil {
 		return fmt.Errorf("failed to read: %w", err)
 	}
-
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil {
-		return fmt.Errorf("failed to decode results: %w", err)
-	}
+	scenario.Assert(ctx, "uncommitted insert is invisible to other sessions", len(results) == 0,
+		fmt.Sprintf("%d documents visible before commit", len(results)))
 
 	output <- scenario.StepResult{
 		Session:     "Session B",
 		Step:        step,
-		Description: "Read completed with readConcern: majority",
+		Description: i18n.T("Read completed with readConcern: majority"),
 		Query:       `db.dirty_read_demo.find({}).readConcern("majority")`,
-		Result:      fmt.Sprintf("Documents found: %d (uncommitted data NOT visible!)", len(results)),
+		Result:      i18n.T("Documents found: %d (uncommitted data NOT visible!)", len(results)),
 		Success:     true,
 	}
 	step++
 
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "✅ Dirty read prevented! Session B cannot see Session A's uncommitted data",
+		Description: i18n.T("✅ Dirty read prevented! Session B cannot see Session A's uncommitted data"),
 	}
 
-	// Step 5: Session A commits
-	time.Sleep(500 * time.Millisecond)
+	// Step 6: Session A commits
+	scenario.Pause(ctx)
 
-	err = mongo.WithSession(ctx, sessionA, func(sc mongo.SessionContext) error {
-		return sessionA.CommitTransaction(sc)
-	})
-	if err != nil {
+	if err := txA.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	committed, err := countByFind(ctx, s.collection, bson.M{"_id": inserted.InsertedID})
+	if err != nil {
+		return fmt.Errorf("failed to read back the commit: %w", err)
+	}
 
-	output <- scenario.StepResult{
+	output <- verified(scenario.StepResult{
 		Session:     "Session A",
 		Step:        step,
-		Description: "Committing the transaction",
+		Description: i18n.T("Committing the transaction"),
+		Role:        scenario.RoleCommit,
 		Query:       "session.commitTransaction()",
-		Result:      "Transaction committed successfully",
+		Result:      i18n.T("Transaction committed successfully"),
 		Success:     true,
-	}
+	}, committed == 1, i18n.T("Count: %d", committed))
 	step++
 
-	time.Sleep(500 * time.Millisecond)
+	scenario.Pause(ctx)
 
-	// Step 6: Session B reads again - now sees the data
-	cursor, err = s.collection.Find(ctx, bson.M{})
+	// Step 7: Session B reads again - now sees the data
+	results, err = s.collection.Find(ctx, bson.M{})
 	if err != nil {
 		return fmt.Errorf("failed to read after commit: %w", err)
 	}
 
-	results = nil
-	if err := cursor.All(ctx, &results); err != nil {
-		return fmt.Errorf("failed to decode results: %w", err)
-	}
+	scenario.Assert(ctx, "committed insert becomes visible", len(results) == 1,
+		fmt.Sprintf("%d documents visible after commit", len(results)))
 
 	resultStr := "[]"
 	if len(results) > 0 {
@@ -207,15 +277,16 @@ func (s *DirtyReadScenario) Run(ctx context.Context, output chan<- scenario.Step
 	output <- scenario.StepResult{
 		Session:     "Session B",
 		Step:        step,
-		Description: "Reading documents again after Session A committed",
+		Description: i18n.T("Reading documents again after Session A committed"),
+		Role:        scenario.RoleReread,
 		Query:       "db.dirty_read_demo.find({})",
-		Result:      fmt.Sprintf("Documents found: %d\n%s", len(results), resultStr),
+		Result:      i18n.T("Documents found: %d\n%s", len(results), resultStr),
 		Success:     true,
 	}
 
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "🎉 After commit, Session B can now see Session A's data",
+		Description: i18n.T("🎉 After commit, Session B can now see Session A's data"),
 	}
 
 	return nil
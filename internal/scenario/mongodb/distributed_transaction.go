@@ -0,0 +1,409 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// DistributedTransactionScenario demonstrates transactions that span shards in a sharded cluster
+type DistributedTransactionScenario struct {
+	conn
+	shards map[string]string // region -> shard holding its chunk
+}
+
+// NewDistributedTransactionScenario creates a new distributed transaction demonstration scenario
+func NewDistributedTransactionScenario(handle Handle) *DistributedTransactionScenario {
+	return &DistributedTransactionScenario{
+		conn: newConn(handle, "sharded_demo"),
+	}
+}
+
+func (s *DistributedTransactionScenario) Name() string {
+	return "Distributed Transaction"
+}
+
+func (s *DistributedTransactionScenario) Description() string {
+	return i18n.T(`Demonstrates a multi-document transaction spanning two shards.
+
+When a transaction writes to more than one shard, mongos hands the commit to a
+transaction coordinator which runs a two-phase commit: every shard PREPAREs,
+then every shard COMMITs. Conflicts are still detected per document.
+
+This scenario shows:
+1. Two accounts whose documents live on different shards
+2. Session A transfers $100 between them in one transaction
+3. The commit is coordinated across both shards (two-phase commit counter increases)
+4. Session A starts another cross-shard transfer without committing
+5. Session B writes to the same document - WriteConflict across shards
+6. Session A commits; only its transfer is applied`)
+}
+
+func (s *DistributedTransactionScenario) IsolationLevel() string {
+	return "Snapshot (Distributed)"
+}
+
+func (s *DistributedTransactionScenario) Requires() []scenario.Capability {
+	return []scenario.Capability{
+		scenario.CapMultiDocumentTransactions,
+		scenario.CapShardedCluster,
+	}
+}
+
+// Metadata returns listing tags and the typical run time
+func (s *DistributedTransactionScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{Tags: []string{"sharding", "distributed"}, EstimatedDuration: 4 * time.Second}
+}
+
+func (s *DistributedTransactionScenario) Setup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	if err := dropCollection(ctx, s.collection); err != nil {
+		return err
+	}
+
+	admin := s.client.Database("admin")
+	ns := s.db.Name() + "." + s.collection.Name()
+
+	if err := admin.RunCommand(ctx, bson.D{{Key: "enableSharding", Value: s.db.Name()}}).Err(); err != nil {
+		return fmt.Errorf("failed to enable sharding: %w", err)
+	}
+	if err := admin.RunCommand(ctx, bson.D{
+		{Key: "shardCollection", Value: ns},
+		{Key: "key", Value: bson.D{{Key: "region", Value: 1}}},
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to shard collection: %w", err)
+	}
+
+	// Split into [MinKey, "m") and ["m", MaxKey) so "eu" and "us" land in different chunks
+	if err := admin.RunCommand(ctx, bson.D{
+		{Key: "split", Value: ns},
+		{Key: "middle", Value: bson.D{{Key: "region", Value: "m"}}},
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to split chunk: %w", err)
+	}
+
+	// Both chunks start on the database's primary shard; move "us" to the other one
+	var dbInfo struct {
+		Primary string `bson:"primary"`
+	}
+	if err := s.client.Database("config").Collection("databases").
+		FindOne(ctx, bson.M{"_id": s.db.Name()}).Decode(&dbInfo); err != nil {
+		return fmt.Errorf("failed to find primary shard: %w", err)
+	}
+
+	var shardList struct {
+		Shards []struct {
+			ID string `bson:"_id"`
+		} `bson:"shards"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "listShards", Value: 1}}).Decode(&shardList); err != nil {
+		return fmt.Errorf("failed to list shards: %w", err)
+	}
+
+	other := ""
+	for _, sh := range shardList.Shards {
+		if sh.ID != dbInfo.Primary {
+			other = sh.ID
+			break
+		}
+	}
+	if other == "" {
+		return errors.New("cluster needs at least two shards")
+	}
+
+	if err := admin.RunCommand(ctx, bson.D{
+		{Key: "moveChunk", Value: ns},
+		{Key: "find", Value: bson.D{{Key: "region", Value: "us"}}},
+		{Key: "to", Value: other},
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to move chunk: %w", err)
+	}
+
+	s.shards = map[string]string{"eu": dbInfo.Primary, "us": other}
+
+	_, err := s.collection.InsertMany(ctx, []interface{}{
+		bson.M{"region": "eu", "holder": "Alice", "balanceCents": int64(100000)},
+		bson.M{"region": "us", "holder": "Bob", "balanceCents": int64(100000)},
+	})
+	return err
+}
+
+func (s *DistributedTransactionScenario) Cleanup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	return dropCollection(ctx, s.collection)
+}
+
+// twoPhaseCommits returns how many two-phase commits mongos has completed successfully
+func (s *DistributedTransactionScenario) twoPhaseCommits(ctx context.Context) (int64, error) {
+	var status struct {
+		Transactions struct {
+			CommitTypes struct {
+				TwoPhaseCommit struct {
+					Successful int64 `bson:"successful"`
+				} `bson:"twoPhaseCommit"`
+			} `bson:"commitTypes"`
+		} `bson:"transactions"`
+	}
+	err := s.client.Database("admin").RunCommand(ctx, bson.D{{Key: "serverStatus", Value: 1}}).Decode(&status)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read serverStatus: %w", err)
+	}
+	return status.Transactions.CommitTypes.TwoPhaseCommit.Successful, nil
+}
+
+func (s *DistributedTransactionScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	// Header
+	output <- scenario.StepResult{
+		IsHeader:    true,
+		Description: i18n.T("🌐 Distributed Transaction Demonstration"),
+	}
+
+	step := 1
+
+	// Step 1: Show where the data lives
+	output <- scenario.StepResult{
+		Session:     "Setup",
+		Step:        step,
+		Description: i18n.T("Accounts are sharded by region onto different shards"),
+		Query:       fmt.Sprintf(`sh.shardCollection("%s.%s", {region: 1})`, s.db.Name(), s.collection.Name()),
+		Result:      i18n.T("Alice (eu) → %s\nBob (us) → %s", s.shards["eu"], s.shards["us"]),
+		Success:     true,
+	}
+	step++
+
+	before, err := s.twoPhaseCommits(ctx)
+	if err != nil {
+		return err
+	}
+
+	txnOpts := options.Transaction().
+		SetReadConcern(readconcern.Snapshot()).
+		SetWriteConcern(writeconcern.Majority())
+
+	// Step 2: Session A transfers money across shards
+	sessionA, err := s.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session A: %w", err)
+	}
+	defer sessionA.EndSession(ctx)
+
+	err = mongo.WithSession(ctx, sessionA, func(sc mongo.SessionContext) error {
+		if err := sessionA.StartTransaction(txnOpts); err != nil {
+			return err
+		}
+
+		output <- scenario.StepResult{
+			Session:     "Session A",
+			Step:        step,
+			Description: i18n.T("Starting transaction to transfer $100 from Alice to Bob"),
+			Query:       "session.startTransaction({readConcern: 'snapshot', writeConcern: 'majority'})",
+			Result:      i18n.T("Transaction started"),
+			Success:     true,
+		}
+		step++
+
+		if _, err := s.collection.UpdateOne(sc, bson.M{"region": "eu"}, bson.M{"$inc": bson.M{"balanceCents": int64(-10000)}}); err != nil {
+			return err
+		}
+		output <- scenario.StepResult{
+			Session:     "Session A",
+			Step:        step,
+			Description: i18n.T("Debiting Alice on %s", s.shards["eu"]),
+			Query:       `db.sharded_demo.updateOne({region: "eu"}, {$inc: {balanceCents: NumberLong(-10000)}})`,
+			Result:      i18n.T("Update applied (shard 1 of 2 joined the transaction)"),
+			Success:     true,
+		}
+		step++
+
+		if _, err := s.collection.UpdateOne(sc, bson.M{"region": "us"}, bson.M{"$inc": bson.M{"balanceCents": int64(10000)}}); err != nil {
+			return err
+		}
+		output <- scenario.StepResult{
+			Session:     "Session A",
+			Step:        step,
+			Description: i18n.T("Crediting Bob on %s", s.shards["us"]),
+			Query:       `db.sharded_demo.updateOne({region: "us"}, {$inc: {balanceCents: NumberLong(10000)}})`,
+			Result:      i18n.T("Update applied (shard 2 of 2 joined the transaction)"),
+			Success:     true,
+		}
+		step++
+
+		scenario.Pause(ctx)
+
+		return sessionA.CommitTransaction(sc)
+	})
+	if err != nil {
+		return fmt.Errorf("cross-shard transfer failed: %w", err)
+	}
+
+	after, err := s.twoPhaseCommits(ctx)
+	if err != nil {
+		return err
+	}
+	scenario.Assert(ctx, "a cross-shard transaction commits with two-phase commit", after > before,
+		fmt.Sprintf("two-phase commits went from %d to %d", before, after))
+
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        step,
+		Description: i18n.T("Committing - mongos hands off to the transaction coordinator"),
+		Query:       "session.commitTransaction()",
+		Result: i18n.T("Committed via two-phase commit (prepare → commit on both shards)\n"+
+			"transactions.commitTypes.twoPhaseCommit.successful: %d → %d", before, after),
+		Success: true,
+	}
+	step++
+
+	output <- scenario.StepResult{
+		IsHeader:    true,
+		Description: i18n.T("✅ One atomic commit across two shards - both updates became visible together"),
+	}
+
+	scenario.Pause(ctx)
+
+	// Step 3: Cross-shard write conflict
+	conflictSession, err := s.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session A: %w", err)
+	}
+	defer conflictSession.EndSession(ctx)
+
+	err = mongo.WithSession(ctx, conflictSession, func(sc mongo.SessionContext) error {
+		if err := conflictSession.StartTransaction(txnOpts); err != nil {
+			return err
+		}
+
+		if _, err := s.collection.UpdateOne(sc, bson.M{"region": "eu"}, bson.M{"$inc": bson.M{"balanceCents": int64(-5000)}}); err != nil {
+			return err
+		}
+		if _, err := s.collection.UpdateOne(sc, bson.M{"region": "us"}, bson.M{"$inc": bson.M{"balanceCents": int64(5000)}}); err != nil {
+			return err
+		}
+
+		output <- scenario.StepResult{
+			Session:     "Session A",
+			Step:        step,
+			Description: i18n.T("New transaction: moving another $50 from Alice to Bob (NOT committed yet)"),
+			Query:       `updateOne({region: "eu"}, {$inc: {balanceCents: NumberLong(-5000)}}); updateOne({region: "us"}, {$inc: {balanceCents: NumberLong(5000)}})`,
+			Result:      i18n.T("Both shards hold uncommitted writes for Session A"),
+			Success:     true,
+		}
+		step++
+
+		scenario.Pause(ctx)
+
+		// Session B touches Bob's document, which Session A has already written
+		sessionB, err := s.client.StartSession()
+		if err != nil {
+			return fmt.Errorf("failed to start session B: %w", err)
+		}
+		defer sessionB.EndSession(ctx)
+
+		var conflictErr error
+		_ = mongo.WithSession(ctx, sessionB, func(scB mongo.SessionContext) error {
+			if err := sessionB.StartTransaction(txnOpts); err != nil {
+				conflictErr = err
+				return err
+			}
+			_, conflictErr = s.collection.UpdateOne(scB, bson.M{"region": "us"}, bson.M{"$set": bson.M{"frozen": true}})
+			return sessionB.AbortTransaction(scB)
+		})
+		logDriverError(ctx, "updateOne", conflictErr)
+
+		var se mongo.ServerError
+		conflicted := errors.As(conflictErr, &se) && se.HasErrorLabel("TransientTransactionError")
+		scenario.Assert(ctx, "a conflicting write on another shard is rejected", conflicted,
+			fmt.Sprintf("Session B's update returned %v", conflictErr))
+		if conflicted {
+			output <- scenario.StepResult{
+				Session:     "Session B",
+				Step:        step,
+				Description: i18n.T("Updating Bob's account on %s inside its own transaction", s.shards["us"]),
+				Query:       `db.sharded_demo.updateOne({region: "us"}, {$set: {frozen: true}})`,
+				Result:      i18n.T("❌ WriteConflict (TransientTransactionError) - Session B aborted"),
+				Success:     false,
+			}
+		} else {
+			output <- scenario.StepResult{
+				Session:     "Session B",
+				Step:        step,
+				Description: i18n.T("Updating Bob's account inside its own transaction"),
+				Query:       `db.sharded_demo.updateOne({region: "us"}, {$set: {frozen: true}})`,
+				Result:      i18n.T("Unexpected outcome: %v", conflictErr),
+				Success:     conflictErr == nil,
+			}
+		}
+		step++
+
+		return conflictSession.CommitTransaction(sc)
+	})
+	if err != nil {
+		return fmt.Errorf("session A transaction failed: %w", err)
+	}
+
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        step,
+		Description: i18n.T("Committing Session A's second transfer"),
+		Query:       "session.commitTransaction()",
+		Result:      i18n.T("Committed - Session A won the conflict because it wrote first"),
+		Success:     true,
+	}
+	step++
+
+	scenario.Pause(ctx)
+
+	// Final state
+	cursor, err := s.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "region", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("failed to read final state: %w", err)
+	}
+	var accounts []bson.M
+	if err := cursor.All(ctx, &accounts); err != nil {
+		return fmt.Errorf("failed to decode final state: %w", err)
+	}
+
+	result := ""
+	for i, acct := range accounts {
+		if i > 0 {
+			result += "\n"
+		}
+		result += fmt.Sprintf("%s (%s): %s, frozen: %v", acct["holder"], acct["region"], i18n.Money(cents(acct["balanceCents"])), acct["frozen"] == true)
+	}
+
+	output <- scenario.StepResult{
+		Session:     "Result",
+		Step:        step,
+		Description: i18n.T("Final account state"),
+		Query:       "db.sharded_demo.find({}).sort({region: 1})",
+		Result:      result,
+		Success:     true,
+	}
+
+	output <- scenario.StepResult{
+		IsHeader:    true,
+		Description: i18n.T("🎉 Distributed transactions keep snapshot semantics and conflict detection across shards"),
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// logDriverError logs an error returned by the driver together with its error labels,
+// e.g. TransientTransactionError; nil errors are ignored
+func logDriverError(ctx context.Context, op string, err error) {
+	if err == nil {
+		return
+	}
+	slog.WarnContext(ctx, "driver error", "op", op, "error", err, "labels", errorLabels(err))
+}
+
+// errorLabels returns the labels the server or driver attached to err
+func errorLabels(err error) []string {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Labels
+	}
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		return writeErr.Labels
+	}
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		return bulkErr.Labels
+	}
+	return nil
+}
+
+// writeConflictCode is the server error code of a WriteConflict
+const writeConflictCode = 112
+
+// isWriteConflict returns whether the server rejected an operation because another transaction
+// changed the same document
+func isWriteConflict(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(writeConflictCode)
+}
+
+// labelsDetail describes the error labels of err for a step result
+func labelsDetail(err error) string {
+	labels := errorLabels(err)
+	if len(labels) == 0 {
+		return i18n.T("no error labels")
+	}
+	return i18n.T("labels: %s", strings.Join(labels, ", "))
+}
+
+// abortResult describes the outcome of aborting a transaction
+func abortResult(err error) string {
+	if err != nil {
+		return i18n.T("Abort failed: %v", err)
+	}
+	return i18n.T("Transaction aborted - none of its writes were applied")
+}
+
+// namespaceNotFoundCode is the server error code for a collection or database that doesn't exist
+const namespaceNotFoundCode = 26
+
+// dropper is a collection of the driver or a Collection
+type dropper interface {
+	Drop(ctx context.Context) error
+}
+
+// dropCollection drops coll. Some server versions fail to drop a collection that doesn't exist,
+// e.g. on a fresh database, which is not an error for a scenario about to create it.
+func dropCollection(ctx context.Context, coll dropper) error {
+	err := coll.Drop(ctx)
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(namespaceNotFoundCode) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,30 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestIsWriteConflict(t *testing.T) {
+	conflict := mongo.CommandError{Code: writeConflictCode, Name: "WriteConflict", Labels: []string{"TransientTransactionError"}}
+	if !isWriteConflict(fmt.Errorf("update: %w", conflict)) {
+		t.Fatal("Expected a wrapped WriteConflict to be recognized")
+	}
+
+	write := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: writeConflictCode}}}
+	if !isWriteConflict(write) {
+		t.Fatal("Expected a WriteConflict write error to be recognized")
+	}
+
+	for _, err := range []error{
+		errors.New("connection reset by peer"),
+		mongo.CommandError{Code: 11000, Name: "DuplicateKey"},
+	} {
+		if isWriteConflict(err) {
+			t.Fatalf("Expected %v not to be a WriteConflict", err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AppName is the application name the provider's clients connect with. Fail points only fail the
+// commands of clients with this name, so other clients of a shared server are left alone.
+var AppName = fmt.Sprintf("txviewer-%d", os.Getpid())
+
+// failPoint is a server fail point a scenario turned on. Fail points are test commands, so servers
+// only accept them when started with enableTestCommands.
+type failPoint struct {
+	db   Database
+	name string
+}
+
+// enableFailPoint turns on the named fail point with mode, e.g. "alwaysOn" or {times: 1}, and data
+func enableFailPoint(ctx context.Context, db Database, name string, mode any, data bson.D) (*failPoint, error) {
+	cmd := bson.D{
+		{Key: "configureFailPoint", Value: name},
+		{Key: "mode", Value: mode},
+		{Key: "data", Value: data},
+	}
+	if err := db.ConfigureFailPoint(ctx, cmd); err != nil {
+		return nil, fmt.Errorf("failed to enable fail point %s: %w", name, err)
+	}
+	return &failPoint{db: db, name: name}, nil
+}
+
+// disable turns the fail point off again; a nil fail point was never turned on
+func (f *failPoint) disable(ctx context.Context) error {
+	if f == nil {
+		return nil
+	}
+	cmd := bson.D{
+		{Key: "configureFailPoint", Value: f.name},
+		{Key: "mode", Value: "off"},
+	}
+	if err := f.db.ConfigureFailPoint(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to disable fail point %s: %w", f.name, err)
+	}
+	return nil
+}
+
+// failPointsAvailable returns whether the server accepts fail points, turning failCommand off to
+// find out so nothing fails
+func failPointsAvailable(ctx context.Context, db Database) bool {
+	return (&failPoint{db: db, name: "failCommand"}).disable(ctx) == nil
+}
+
+// failNextUpdateWithWriteConflict makes the next update of the provider's clients to the named
+// collection fail with a WriteConflict, labelled like a real one so drivers treat it the same
+func failNextUpdateWithWriteConflict(ctx context.Context, db Database, collection string) (*failPoint, error) {
+	return enableFailPoint(ctx, db, "failCommand", bson.D{{Key: "times", Value: 1}}, bson.D{
+		{Key: "failCommands", Value: bson.A{"update"}},
+		{Key: "appName", Value: AppName},
+		{Key: "namespace", Value: db.Name() + "." + collection},
+		{Key: "errorCode", Value: writeConflictCode},
+		{Key: "errorLabels", Value: bson.A{"TransientTransactionError"}},
+	})
+}
@@ -0,0 +1,599 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// fakeDatabase is an in-memory Database with snapshot transactions, enough for the scenarios'
+// operations: equality filters, $set and $inc updates, and the failCommand fail point. Errors can
+// be scripted per operation with failNext.
+type fakeDatabase struct {
+	// detectConflicts fails an update in a transaction with a WriteConflict when another
+	// transaction wrote the document since it started, or is writing it, as servers do. Turned
+	// off, it stands in for a server letting the second writer through.
+	detectConflicts bool
+
+	// failPoints accepts configureFailPoint, as servers started with enableTestCommands do
+	failPoints bool
+
+	mu          sync.Mutex
+	collections map[string]*fakeCollectionData
+	commits     uint64
+	txs         map[*fakeTx]bool // Transactions neither committed nor aborted
+	failCommand *fakeFailPoint
+	enabled     []bson.M           // The configureFailPoint commands turning failCommand on, in order
+	scripted    map[string][]error // Errors the next operations of each name return, in order
+}
+
+// newFakeDatabase creates an empty database that behaves like a server started by the provider
+func newFakeDatabase() *fakeDatabase {
+	return &fakeDatabase{
+		detectConflicts: true,
+		failPoints:      true,
+		collections:     make(map[string]*fakeCollectionData),
+		txs:             make(map[*fakeTx]bool),
+		scripted:        make(map[string][]error),
+	}
+}
+
+// handle returns a DatabaseHandle resolving to d
+func (d *fakeDatabase) handle() DatabaseHandle {
+	return func() (Database, error) {
+		return d, nil
+	}
+}
+
+// failNext makes the next op, e.g. "update" or "commitTransaction", fail with err. A nil err lets
+// it through, to script the one after.
+func (d *fakeDatabase) failNext(op string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.scripted[op] = append(d.scripted[op], err)
+}
+
+// nonEmpty returns the collections holding committed documents, in no order
+func (d *fakeDatabase) nonEmpty() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var names []string
+	for name, data := range d.collections {
+		if len(data.ids) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fail returns the error op should fail with, if scripted or set by the fail point. Called with
+// d.mu held.
+func (d *fakeDatabase) fail(op string) error {
+	if errs := d.scripted[op]; len(errs) > 0 {
+		d.scripted[op] = errs[1:]
+		return errs[0]
+	}
+	return d.failCommand.trigger(op)
+}
+
+func (d *fakeDatabase) Name() string {
+	return "txviewer"
+}
+
+func (d *fakeDatabase) Collection(name string, opts ...*options.CollectionOptions) Collection {
+	return &fakeCollection{db: d, name: name}
+}
+
+func (d *fakeDatabase) StartSession() (Session, error) {
+	return &fakeSession{db: d}, nil
+}
+
+func (d *fakeDatabase) ConfigureFailPoint(ctx context.Context, cmd bson.D) error {
+	if !d.failPoints {
+		return mongo.CommandError{Code: 59, Name: "CommandNotFound", Message: "no such command: 'configureFailPoint'"}
+	}
+	m := cmd.Map()
+	if m["configureFailPoint"] != "failCommand" {
+		return fmt.Errorf("fake: unsupported fail point %v", m["configureFailPoint"])
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if m["mode"] == "off" {
+		d.failCommand = nil
+		return nil
+	}
+	d.enabled = append(d.enabled, m)
+	fp := &fakeFailPoint{times: -1, commands: make(map[string]bool)}
+	if mode, ok := m["mode"].(bson.D); ok {
+		for _, e := range mode {
+			switch e.Key {
+			case "skip":
+				fp.skip = int(number(e.Value))
+			case "times":
+				fp.times = int(number(e.Value))
+			}
+		}
+	}
+	data, _ := m["data"].(bson.D)
+	for _, e := range data {
+		switch e.Key {
+		case "failCommands":
+			for _, c := range e.Value.(bson.A) {
+				fp.commands[c.(string)] = true
+			}
+		case "errorCode":
+			fp.code = int32(number(e.Value))
+		case "errorLabels":
+			for _, l := range e.Value.(bson.A) {
+				fp.labels = append(fp.labels, l.(string))
+			}
+		}
+	}
+	d.failCommand = fp
+	return nil
+}
+
+// fakeFailPoint is the failCommand fail point
+type fakeFailPoint struct {
+	commands map[string]bool
+	code     int32
+	labels   []string
+	skip     int // Matching commands still let through
+	times    int // Failures left; -1 fails until turned off
+}
+
+// trigger returns the error command fails with, counting it against the fail point
+func (f *fakeFailPoint) trigger(command string) error {
+	if f == nil || !f.commands[command] || f.times == 0 {
+		return nil
+	}
+	if f.skip > 0 {
+		f.skip--
+		return nil
+	}
+	if f.times > 0 {
+		f.times--
+	}
+	return mongo.CommandError{Code: f.code, Labels: f.labels, Message: "Failing command via 'failCommand' failpoint"}
+}
+
+// writeConflict is the error servers return to the second writer of a document
+func writeConflict() error {
+	return mongo.CommandError{
+		Code:    writeConflictCode,
+		Name:    "WriteConflict",
+		Labels:  []string{"TransientTransactionError"},
+		Message: "Caused by :: Write conflict during plan execution and yielding is disabled.",
+	}
+}
+
+// fakeCollectionData is the committed state of a collection
+type fakeCollectionData struct {
+	ids      []string // Keys of the documents in insertion order
+	docs     map[string]bson.M
+	versions map[string]uint64 // Commit that last wrote each document
+}
+
+func newFakeCollectionData() *fakeCollectionData {
+	return &fakeCollectionData{docs: make(map[string]bson.M), versions: make(map[string]uint64)}
+}
+
+// clone copies the collection for a snapshot; documents are replaced on write, never changed
+func (c *fakeCollectionData) clone() *fakeCollectionData {
+	out := newFakeCollectionData()
+	out.ids = append(out.ids, c.ids...)
+	for id, doc := range c.docs {
+		out.docs[id] = doc
+		out.versions[id] = c.versions[id]
+	}
+	return out
+}
+
+// put writes doc under id, keeping the insertion order
+func (c *fakeCollectionData) put(id string, doc bson.M, version uint64) {
+	if _, ok := c.docs[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.docs[id] = doc
+	c.versions[id] = version
+}
+
+// fakeSession is a Session of fakeDatabase
+type fakeSession struct {
+	db *fakeDatabase
+	tx *fakeTx // The transaction in progress; nil outside one
+}
+
+type fakeSessionKey struct{}
+
+func (s *fakeSession) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fakeSessionKey{}, s)
+}
+
+func (s *fakeSession) StartTransaction(opts ...*options.TransactionOptions) error {
+	d := s.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s.tx != nil {
+		return fmt.Errorf("fake: transaction already in progress")
+	}
+	if err := d.fail("startTransaction"); err != nil {
+		return err
+	}
+	tx := &fakeTx{
+		began:    d.commits,
+		snapshot: make(map[string]*fakeCollectionData),
+		writes:   make(map[string]*fakeCollectionData),
+	}
+	for name, data := range d.collections {
+		tx.snapshot[name] = data.clone()
+	}
+	s.tx = tx
+	d.txs[tx] = true
+	return nil
+}
+
+func (s *fakeSession) CommitTransaction(ctx context.Context) error {
+	d := s.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tx := s.tx
+	if tx == nil {
+		return fmt.Errorf("fake: no transaction started")
+	}
+	s.tx = nil
+	delete(d.txs, tx)
+	if err := d.fail("commitTransaction"); err != nil {
+		return err
+	}
+
+	d.commits++
+	for name, writes := range tx.writes {
+		data := d.collection(name)
+		for _, id := range writes.ids {
+			data.put(id, writes.docs[id], d.commits)
+		}
+	}
+	return nil
+}
+
+func (s *fakeSession) AbortTransaction(ctx context.Context) error {
+	d := s.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s.tx == nil {
+		return fmt.Errorf("fake: no transaction started")
+	}
+	delete(d.txs, s.tx)
+	s.tx = nil
+	return d.fail("abortTransaction")
+}
+
+func (s *fakeSession) EndSession(ctx context.Context) {
+	d := s.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s.tx != nil {
+		delete(d.txs, s.tx)
+		s.tx = nil
+	}
+}
+
+// fakeTx is a transaction of fakeDatabase, reading from a snapshot of the collections taken
+// when it started
+type fakeTx struct {
+	began    uint64 // Commits the snapshot includes
+	snapshot map[string]*fakeCollectionData
+	writes   map[string]*fakeCollectionData // Documents written, by collection
+}
+
+// fakeCollection is a Collection of fakeDatabase
+type fakeCollection struct {
+	db   *fakeDatabase
+	name string
+}
+
+// collection returns the committed data of name, creating it. Called with d.mu held.
+func (d *fakeDatabase) collection(name string) *fakeCollectionData {
+	data, ok := d.collections[name]
+	if !ok {
+		data = newFakeCollectionData()
+		d.collections[name] = data
+	}
+	return data
+}
+
+// txOf returns the transaction the session of ctx has in progress, or nil. Called with d.mu held.
+func txOf(ctx context.Context) *fakeTx {
+	if s, ok := ctx.Value(fakeSessionKey{}).(*fakeSession); ok {
+		return s.tx
+	}
+	return nil
+}
+
+// visible returns the documents ctx sees in the collection, in insertion order. Called with d.mu held.
+func (c *fakeCollection) visible(ctx context.Context) (ids []string, docs map[string]bson.M) {
+	tx := txOf(ctx)
+	if tx == nil {
+		data := c.db.collection(c.name)
+		return data.ids, data.docs
+	}
+
+	docs = make(map[string]bson.M)
+	if snap, ok := tx.snapshot[c.name]; ok {
+		ids = append(ids, snap.ids...)
+		for id, doc := range snap.docs {
+			docs[id] = doc
+		}
+	}
+	if writes, ok := tx.writes[c.name]; ok {
+		for _, id := range writes.ids {
+			if _, ok := docs[id]; !ok {
+				ids = append(ids, id)
+			}
+			docs[id] = writes.docs[id]
+		}
+	}
+	return ids, docs
+}
+
+// find returns the keys and documents ctx sees matching filter. Called with d.mu held.
+func (c *fakeCollection) find(ctx context.Context, filter any) ([]string, []bson.M, error) {
+	f, ok := filter.(bson.M)
+	if !ok {
+		return nil, nil, fmt.Errorf("fake: unsupported filter %T", filter)
+	}
+	ids, docs := c.visible(ctx)
+	var matchedIDs []string
+	var matched []bson.M
+	for _, id := range ids {
+		ok, err := matches(docs[id], f)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			matchedIDs = append(matchedIDs, id)
+			matched = append(matched, docs[id])
+		}
+	}
+	return matchedIDs, matched, nil
+}
+
+// matches returns whether doc has every field of filter with an equal value
+func matches(doc, filter bson.M) (bool, error) {
+	for key, want := range filter {
+		if strings.HasPrefix(key, "$") {
+			return false, fmt.Errorf("fake: unsupported filter operator %s", key)
+		}
+		if !reflect.DeepEqual(doc[key], want) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// write stores doc under id, in the transaction of ctx or committed right away. Called with d.mu held.
+func (c *fakeCollection) write(ctx context.Context, id string, doc bson.M) {
+	d := c.db
+	tx := txOf(ctx)
+	if tx == nil {
+		d.commits++
+		d.collection(c.name).put(id, doc, d.commits)
+		return
+	}
+	writes, ok := tx.writes[c.name]
+	if !ok {
+		writes = newFakeCollectionData()
+		tx.writes[c.name] = writes
+	}
+	writes.put(id, doc, 0)
+}
+
+// conflicts returns whether the transaction of ctx writing id would conflict with another
+// transaction. Called with d.mu held.
+func (c *fakeCollection) conflicts(ctx context.Context, id string) bool {
+	d := c.db
+	tx := txOf(ctx)
+	if tx == nil || !d.detectConflicts {
+		return false
+	}
+	if d.collection(c.name).versions[id] > tx.began {
+		return true
+	}
+	for other := range d.txs {
+		if other == tx {
+			continue
+		}
+		if writes, ok := other.writes[c.name]; ok {
+			if _, ok := writes.docs[id]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *fakeCollection) FindOne(ctx context.Context, filter any) (bson.M, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if err := c.db.fail("find"); err != nil {
+		return nil, err
+	}
+	_, docs, err := c.find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return copyDoc(docs[0]), nil
+}
+
+func (c *fakeCollection) Find(ctx context.Context, filter any, opts ...*options.FindOptions) ([]bson.M, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if err := c.db.fail("find"); err != nil {
+		return nil, err
+	}
+	_, docs, err := c.find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bson.M, len(docs))
+	for i, doc := range docs {
+		out[i] = copyDoc(doc)
+	}
+	return out, nil
+}
+
+func (c *fakeCollection) CountDocuments(ctx context.Context, filter any) (int64, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if err := c.db.fail("aggregate"); err != nil {
+		return 0, err
+	}
+	_, docs, err := c.find(ctx, filter)
+	return int64(len(docs)), err
+}
+
+func (c *fakeCollection) InsertOne(ctx context.Context, doc any) (*mongo.InsertOneResult, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if err := c.db.fail("insert"); err != nil {
+		return nil, err
+	}
+	id, err := c.insert(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (c *fakeCollection) InsertMany(ctx context.Context, docs []any) (*mongo.InsertManyResult, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if err := c.db.fail("insert"); err != nil {
+		return nil, err
+	}
+	result := &mongo.InsertManyResult{}
+	for _, doc := range docs {
+		id, err := c.insert(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+		result.InsertedIDs = append(result.InsertedIDs, id)
+	}
+	return result, nil
+}
+
+// insert writes a copy of doc with an _id, returning it. Called with d.mu held.
+func (c *fakeCollection) insert(ctx context.Context, doc any) (any, error) {
+	m, ok := doc.(bson.M)
+	if !ok {
+		return nil, fmt.Errorf("fake: unsupported document %T", doc)
+	}
+	m = copyDoc(m)
+	if _, ok := m["_id"]; !ok {
+		m["_id"] = primitive.NewObjectID()
+	}
+	c.write(ctx, fmt.Sprint(m["_id"]), m)
+	return m["_id"], nil
+}
+
+func (c *fakeCollection) UpdateOne(ctx context.Context, filter, update any) (*mongo.UpdateResult, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if err := c.db.fail("update"); err != nil {
+		return nil, err
+	}
+	ids, docs, err := c.find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return &mongo.UpdateResult{}, nil
+	}
+	if c.conflicts(ctx, ids[0]) {
+		return nil, writeConflict()
+	}
+	updated, err := applyUpdate(docs[0], update)
+	if err != nil {
+		return nil, err
+	}
+	c.write(ctx, ids[0], updated)
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (c *fakeCollection) Drop(ctx context.Context) error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if err := c.db.fail("drop"); err != nil {
+		return err
+	}
+	delete(c.db.collections, c.name)
+	return nil
+}
+
+// applyUpdate returns a copy of doc with the $set and $inc operators of update applied
+func applyUpdate(doc bson.M, update any) (bson.M, error) {
+	u, ok := update.(bson.M)
+	if !ok {
+		return nil, fmt.Errorf("fake: unsupported update %T", update)
+	}
+	out := copyDoc(doc)
+	for op, fields := range u {
+		f, ok := fields.(bson.M)
+		if !ok {
+			return nil, fmt.Errorf("fake: unsupported %s fields %T", op, fields)
+		}
+		for key, v := range f {
+			switch op {
+			case "$set":
+				out[key] = v
+			case "$inc":
+				out[key] = add(out[key], v)
+			default:
+				return nil, fmt.Errorf("fake: unsupported update operator %s", op)
+			}
+		}
+	}
+	return out, nil
+}
+
+// add sums two numbers, keeping integers integral as servers do
+func add(a, b any) any {
+	switch b := b.(type) {
+	case int64:
+		if a == nil {
+			return b
+		}
+		if a, ok := a.(int64); ok {
+			return a + b
+		}
+	case int:
+		if a == nil {
+			return b
+		}
+		if a, ok := a.(int); ok {
+			return a + b
+		}
+	}
+	return number(a) + number(b)
+}
+
+// copyDoc returns a shallow copy of doc, enough for documents whose fields are replaced, never changed
+func copyDoc(doc bson.M) bson.M {
+	out := make(bson.M, len(doc))
+	for k, v := range doc {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,341 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// PhantomReadScenario demonstrates phantoms appearing in a repeated range query
+type PhantomReadScenario struct {
+	conn
+	dataset  scenario.DatasetSize
+	seed     int64           // Params.Seed the orders were generated with
+	sentinel scenario.Record // Last order seeded, checked by Pristine
+}
+
+// NewPhantomReadScenario creates a new phantom read over range demonstration scenario
+func NewPhantomReadScenario(handle Handle) *PhantomReadScenario {
+	return &PhantomReadScenario{
+		conn:    newConn(handle, "phantom_read_demo"),
+		dataset: scenario.DatasetSmall,
+	}
+}
+
+func (s *PhantomReadScenario) Name() string {
+	return "Phantom Read over Range"
+}
+
+func (s *PhantomReadScenario) Description() string {
+	return i18n.T(`Demonstrates phantom reads: a repeated range query returning rows that weren't there before.
+
+A phantom is a NEW document that starts matching a range predicate
+between two reads of the same range. Snapshot reads prevent it.
+
+This scenario shows:
+1. Orders are seeded using the selected dataset size
+2. Session A counts orders with amount > 100 without a transaction
+3. Session B inserts a qualifying order and commits
+4. Session A counts again - the phantom appears
+5. Session A repeats the reads inside a snapshot transaction - the count stays stable`)
+}
+
+func (s *PhantomReadScenario) IsolationLevel() string {
+	return "Read Committed vs Snapshot"
+}
+
+func (s *PhantomReadScenario) Requires() []scenario.Capability {
+	return []scenario.Capability{
+		scenario.CapMultiDocumentTransactions,
+		scenario.CapSnapshotReads,
+	}
+}
+
+// Metadata returns listing tags and the typical run time
+func (s *PhantomReadScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{Tags: []string{"phantom", "range", "dataset"}, EstimatedDuration: 4 * time.Second}
+}
+
+// AnomalyKey identifies the scenario for comparisons across providers
+func (s *PhantomReadScenario) AnomalyKey() string {
+	return scenario.AnomalyPhantomRead
+}
+
+// Quiz asks about the run that just finished
+func (s *PhantomReadScenario) Quiz() []scenario.Question {
+	return []scenario.Question{
+		{
+			Prompt: i18n.T("What changed between the counts at steps 2 and 4?"),
+			Choices: []string{
+				i18n.T("Session B inserted a new order over $100"),
+				i18n.T("Existing orders changed their amounts"),
+				i18n.T("Nothing - the counts only differ in rounding"),
+			},
+			Answer:      0,
+			Explanation: i18n.T("The order Session B committed at step 3 matches the range, so the repeated count at step 4 includes a phantom."),
+			Steps:       []int{2, 3, 4},
+		},
+		{
+			Prompt: i18n.T("Why did the count at step 7 not change, although Session B inserted another order at step 6?"),
+			Choices: []string{
+				i18n.T("The insert at step 6 failed"),
+				i18n.T("Both reads of the transaction use the same snapshot"),
+				i18n.T("The transaction locked the range against inserts"),
+			},
+			Answer:      1,
+			Explanation: i18n.T("MongoDB doesn't lock ranges: the insert at step 6 succeeded. The transaction started at step 5 keeps reading its snapshot, which predates it."),
+			Steps:       []int{5, 6, 7},
+		},
+	}
+}
+
+func (s *PhantomReadScenario) Setup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	params := scenario.ParamsFromContext(ctx)
+	s.dataset = params.DatasetSize
+
+	// Drop and recreate with generated orders
+	if err := dropCollection(ctx, s.collection); err != nil {
+		return err
+	}
+
+	records := scenario.GenerateDataset(s.dataset, params.Rand())
+	s.seed, s.sentinel = params.Seed, records[len(records)-1]
+	docs := make([]any, 0, len(records))
+	for _, r := range records {
+		docs = append(docs, bson.M{"_id": r.ID, "amount": r.Amount, "region": r.Region})
+	}
+	if err := seed(ctx, seedSet{name: "orders", collection: s.collection, docs: docs}); err != nil {
+		return err
+	}
+
+	// Keep the range scan cheap on large datasets
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "amount", Value: 1}}})
+	return err
+}
+
+// Pristine implements scenario.Reusable: the orders were seeded for the same dataset size and
+// seed, none were added or removed, and the last one is unchanged
+func (s *PhantomReadScenario) Pristine(ctx context.Context) (bool, error) {
+	if err := s.resolve(); err != nil {
+		return false, err
+	}
+	params := scenario.ParamsFromContext(ctx)
+	if params.DatasetSize != s.dataset || params.Seed != s.seed {
+		return false, nil
+	}
+
+	count, err := s.collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to count seeded orders: %w", err)
+	}
+	if count != int64(s.dataset.Count()) {
+		return false, nil
+	}
+
+	var doc bson.M
+	err = s.collection.FindOne(ctx, bson.M{"_id": s.sentinel.ID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read the sentinel order: %w", err)
+	}
+	return doc["amount"] == s.sentinel.Amount && doc["region"] == s.sentinel.Region, nil
+}
+
+func (s *PhantomReadScenario) Cleanup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	return dropCollection(ctx, s.collection)
+}
+
+func (s *PhantomReadScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	// Header
+	output <- scenario.StepResult{
+		IsHeader:    true,
+		Description: i18n.T("👻 Phantom Read over Range Demonstration"),
+	}
+
+	step := 1
+	rangeFilter := bson.M{"amount": bson.M{"$gt": 100}}
+
+	total, err := s.collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to count seeded orders: %w", err)
+	}
+
+	output <- scenario.StepResult{
+		Session:     "Setup",
+		Step:        step,
+		Description: i18n.T("Seeded orders"),
+		Query:       "db.phantom_read_demo.countDocuments({})",
+		Result:      i18n.T("%d orders (dataset: %s)", total, s.dataset),
+		Success:     true,
+	}
+	step++
+
+	// Part 1: plain reads see the phantom
+	output <- scenario.StepResult{
+		IsHeader:    true,
+		Description: i18n.T("Part 1: Reads outside a transaction"),
+	}
+
+	before, err := s.collection.CountDocuments(ctx, rangeFilter)
+	if err != nil {
+		return fmt.Errorf("failed to count range: %w", err)
+	}
+
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        step,
+		Description: i18n.T("Counting orders over $100"),
+		Role:        scenario.RoleRead,
+		Query:       "db.phantom_read_demo.countDocuments({amount: {$gt: 100}})",
+		Result:      i18n.T("Count: %d", before),
+		Success:     true,
+	}
+	step++
+
+	scenario.Pause(ctx)
+
+	if err := s.insertOrder(ctx, output, step, 1_000_001); err != nil {
+		return err
+	}
+	step++
+
+	scenario.Pause(ctx)
+
+	after, err := s.collection.CountDocuments(ctx, rangeFilter)
+	if err != nil {
+		return fmt.Errorf("failed to recount range: %w", err)
+	}
+	scenario.Assert(ctx, "reads outside a transaction see the phantom", after == before+1,
+		fmt.Sprintf("count went from %d to %d", before, after))
+
+	output <- scenario.StepResult{
+		Session:     "Session A",
+		Step:        step,
+		Description: i18n.T("Counting orders over $100 again"),
+		Role:        scenario.RoleReread,
+		Query:       "db.phantom_read_demo.countDocuments({amount: {$gt: 100}})",
+		Result:      i18n.T("Count: %d (was %d - a PHANTOM appeared)", after, before),
+		Success:     after == before+1,
+	}
+	step++
+
+	// Part 2: a snapshot transaction keeps the range stable
+	output <- scenario.StepResult{
+		IsHeader:    true,
+		Description: i18n.T("Part 2: Reads inside a snapshot transaction"),
+	}
+
+	sessionA, err := s.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session A: %w", err)
+	}
+	defer sessionA.EndSession(ctx)
+
+	txnOpts := options.Transaction().
+		SetReadConcern(readconcern.Snapshot()).
+		SetWriteConcern(writeconcern.Majority())
+
+	err = mongo.WithSession(ctx, sessionA, func(sc mongo.SessionContext) error {
+		if err := sessionA.StartTransaction(txnOpts); err != nil {
+			return err
+		}
+
+		first, err := s.collection.CountDocuments(sc, rangeFilter)
+		if err != nil {
+			return fmt.Errorf("failed to count range in transaction: %w", err)
+		}
+
+		output <- scenario.StepResult{
+			Session:     "Session A",
+			Step:        step,
+			Description: i18n.T("Counting orders over $100 in a snapshot transaction"),
+			Role:        scenario.RoleRead,
+			Query:       "session.startTransaction({readConcern: 'snapshot'}); countDocuments({amount: {$gt: 100}})",
+			Result:      i18n.T("Count: %d", first),
+			Success:     true,
+		}
+		step++
+
+		scenario.Pause(ctx)
+
+		// Session B writes outside of Session A's transaction
+		if err := s.insertOrder(ctx, output, step, 1_000_002); err != nil {
+			return err
+		}
+		step++
+
+		scenario.Pause(ctx)
+
+		second, err := s.collection.CountDocuments(sc, rangeFilter)
+		if err != nil {
+			return fmt.Errorf("failed to recount range in transaction: %w", err)
+		}
+		scenario.Assert(ctx, "a snapshot transaction reads the same range every time", second == first,
+			fmt.Sprintf("count went from %d to %d inside the transaction", first, second))
+
+		output <- scenario.StepResult{
+			Session:     "Session A",
+			Step:        step,
+			Description: i18n.T("Counting orders over $100 again in the same transaction"),
+			Role:        scenario.RoleReread,
+			Query:       "countDocuments({amount: {$gt: 100}})",
+			Result:      i18n.T("Count: %d (unchanged - no phantom inside the snapshot)", second),
+			Success:     second == first,
+		}
+		step++
+
+		return sessionA.CommitTransaction(sc)
+	})
+	if err != nil {
+		return fmt.Errorf("failed snapshot transaction: %w", err)
+	}
+
+	output <- scenario.StepResult{
+		IsHeader:    true,
+		Description: i18n.T("✅ Plain reads see phantoms; a snapshot transaction reads the same range every time"),
+	}
+
+	return nil
+}
+
+// insertOrder commits a new order over $100 as Session B
+func (s *PhantomReadScenario) insertOrder(ctx context.Context, output chan<- scenario.StepResult, step, id int) error {
+	if _, err := s.collection.InsertOne(ctx, bson.M{"_id": id, "amount": 150.00, "region": "eu"}); err != nil {
+		return fmt.Errorf("failed to insert phantom order: %w", err)
+	}
+
+	output <- scenario.StepResult{
+		Session:     "Session B",
+		Step:        step,
+		Description: i18n.T("Inserting a new $150 order and committing"),
+		Role:        scenario.RoleConcurrentWrite,
+		Query:       fmt.Sprintf("db.phantom_read_demo.insertOne({_id: %d, amount: 150})", id),
+		Result:      i18n.T("Inserted and committed"),
+		Success:     true,
+	}
+	return nil
+}
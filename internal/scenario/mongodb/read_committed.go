@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
@@ -16,17 +16,18 @@ import (
 
 // ReadCommittedScenario demonstrates read committed isolation level
 type ReadCommittedScenario struct {
-	client     *mongo.Client
-	db         *mongo.Database
-	collection *mongo.Collection
+	dbConn
 }
 
 // NewReadCommittedScenario creates a new read committed demonstration scenario
-func NewReadCommittedScenario(client *mongo.Client, db *mongo.Database) *ReadCommittedScenario {
+func NewReadCommittedScenario(handle Handle) *ReadCommittedScenario {
+	return newReadCommittedScenario(Driver(handle))
+}
+
+// newReadCommittedScenario creates the scenario on the database open resolves
+func newReadCommittedScenario(open DatabaseHandle) *ReadCommittedScenario {
 	return &ReadCommittedScenario{
-		client:     client,
-		db:         db,
-		collection: db.Collection("read_committed_demo"),
+		dbConn: newDBConn(open, "read_committed_demo"),
 	}
 }
 
@@ -35,7 +36,7 @@ func (s *ReadCommittedScenario) Name() string {
 }
 
 func (s *ReadCommittedScenario) Description() string {
-	return `Demonstrates Read Committed isolation using MongoDB's readConcern: "majority".
+	return i18n.T(`Demonstrates Read Committed isolation using MongoDB's readConcern: "majority".
 
 With this isolation level:
 - Reads only return data that has been committed by a majority of replica set members
@@ -47,46 +48,97 @@ This scenario shows:
 2. Session A starts a transaction and modifies data
 3. Session B reads with readConcern: majority - sees ORIGINAL data
 4. Session A commits
-5. Session B reads again - now sees UPDATED data`
+5. Session B reads again - now sees UPDATED data`)
 }
 
 func (s *ReadCommittedScenario) IsolationLevel() string {
 	return "Read Committed (majority)"
 }
 
+func (s *ReadCommittedScenario) Requires() []scenario.Capability {
+	return []scenario.Capability{scenario.CapMultiDocumentTransactions}
+}
+
+// Metadata returns listing tags and the typical run time
+func (s *ReadCommittedScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{Tags: []string{"read-committed", "visibility"}, EstimatedDuration: 3 * time.Second}
+}
+
+// AnomalyKey identifies the scenario for comparisons across providers
+func (s *ReadCommittedScenario) AnomalyKey() string {
+	return scenario.AnomalyNonRepeatableRead
+}
+
+// Quiz asks about the run that just finished
+func (s *ReadCommittedScenario) Quiz() []scenario.Question {
+	return []scenario.Question{
+		{
+			Prompt: i18n.T("Which balance did Session B read at step 4, while Session A's debit was uncommitted?"),
+			Choices: []string{
+				i18n.T("The original $1000"),
+				i18n.T("The debited $500"),
+				i18n.T("None - the read waited for Session A to commit"),
+			},
+			Answer:      0,
+			Explanation: i18n.T("Reads never wait for other transactions; they return the latest committed value. The debit from step 3 only became visible after the commit at step 5."),
+			Steps:       []int{3, 4, 5},
+		},
+		{
+			Prompt: i18n.T("What does readConcern \"majority\" add over \"local\"?"),
+			Choices: []string{
+				i18n.T("It hides writes that a failover could still roll back"),
+				i18n.T("It prevents write conflicts"),
+				i18n.T("It keeps repeated reads in a transaction stable"),
+			},
+			Answer:      0,
+			Explanation: i18n.T("\"majority\" only returns data acknowledged by a majority of the replica set, so what Session B read at steps 4 and 6 survives a failover. Stable repeated reads need \"snapshot\"."),
+			Steps:       []int{4, 6},
+		},
+	}
+}
+
 func (s *ReadCommittedScenario) Setup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
 	// Drop and recreate with initial data
-	if err := s.collection.Drop(ctx); err != nil {
+	if err := dropCollection(ctx, s.collection); err != nil {
 		return err
 	}
 
 	// Insert initial document
 	_, err := s.collection.InsertOne(ctx, bson.M{
-		"account":  "checking",
-		"balance":  1000.00,
-		"currency": "USD",
+		"account":      "checking",
+		"balanceCents": int64(100000), // Money is kept in integer cents, so it adds up exactly
+		"currency":     "USD",
 	})
 	return err
 }
 
 func (s *ReadCommittedScenario) Cleanup(ctx context.Context) error {
-	return s.collection.Drop(ctx)
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	return dropCollection(ctx, s.collection)
 }
 
 func (s *ReadCommittedScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
-	defer close(output)
+	if err := s.resolve(); err != nil {
+		return err
+	}
 
 	// Header
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "💰 Read Committed Isolation Demonstration",
+		Description: i18n.T("💰 Read Committed Isolation Demonstration"),
 	}
 
 	step := 1
 
 	// Step 1: Show initial state
-	var initial bson.M
-	err := s.collection.FindOne(ctx, bson.M{"account": "checking"}).Decode(&initial)
+	initial, err := s.collection.FindOne(ctx, bson.M{"account": "checking"})
 	if err != nil {
 		return fmt.Errorf("failed to read initial state: %w", err)
 	}
@@ -94,68 +146,65 @@ func (s *ReadCommittedScenario) Run(ctx context.Context, output chan<- scenario.
 	output <- scenario.StepResult{
 		Session:     "Setup",
 		Step:        step,
-		Description: "Initial state - checking account",
+		Description: i18n.T("Initial state - checking account"),
 		Query:       `db.read_committed_demo.findOne({account: "checking"})`,
-		Result:      fmt.Sprintf("Balance: $%.2f", initial["balance"]),
+		Result:      i18n.T("Balance: %s", i18n.Money(cents(initial["balanceCents"]))),
 		Success:     true,
 	}
 	step++
 
-	// Step 2: Session A starts a transaction and modifies balance
-	sessionA, err := s.client.StartSession()
+	// Step 2: Session A starts a transaction and modifies balance, keeping it open until the commit
+	txA, err := s.startTransaction(ctx, options.Transaction().
+		SetReadConcern(readconcern.Majority()).
+		SetWriteConcern(writeconcern.Majority()))
 	if err != nil {
-		return fmt.Errorf("failed to start session A: %w", err)
+		return fmt.Errorf("session A: %w", err)
 	}
-	defer sessionA.EndSession(ctx)
-
-	txnOpts := options.Transaction().
-		SetReadConcern(readconcern.Majority()).
-		SetWriteConcern(writeconcern.Majority())
+	defer txA.End(ctx)
 
 	output <- scenario.StepResult{
 		Session:     "Session A",
 		Step:        step,
-		Description: "Starting transaction with majority read/write concern",
+		Description: i18n.T("Starting transaction with majority read/write concern"),
+		Role:        scenario.RoleBegin,
 		Query:       "session.startTransaction({readConcern: 'majority', writeConcern: 'majority'})",
-		Result:      "Transaction started",
+		Result:      i18n.T("Transaction started"),
 		Success:     true,
 	}
 	step++
 
-	// Update within transaction
-	err = mongo.WithSession(ctx, sessionA, func(sc mongo.SessionContext) error {
-		if err := sessionA.StartTransaction(txnOpts); err != nil {
-			return err
-		}
-
-		// Debit the account
-		_, err := s.collection.UpdateOne(sc,
-			bson.M{"account": "checking"},
-			bson.M{"$inc": bson.M{"balance": -500.00}},
-		)
-		return err
-	})
+	// Debit the account within the transaction
+	_, err = s.collection.UpdateOne(txA.ctx,
+		bson.M{"account": "checking"},
+		bson.M{"$inc": bson.M{"balanceCents": int64(-50000)}},
+	)
 	if err != nil {
 		return fmt.Errorf("failed to update in transaction: %w", err)
 	}
+	debited, err := balanceOf(txA.ctx, s.collection, bson.M{"account": "checking"})
+	if err != nil {
+		return err
+	}
 
-	output <- scenario.StepResult{
+	output <- verified(scenario.StepResult{
 		Session:     "Session A",
 		Step:        step,
-		Description: "Debiting $500 from checking account (within transaction)",
-		Query:       `db.read_committed_demo.updateOne({account: "checking"}, {$inc: {balance: -500}})`,
-		Result:      "Update applied (NOT YET COMMITTED)",
+		Description: i18n.T("Debiting $500 from checking account (within transaction)"),
+		Role:        scenario.RoleWrite,
+		Query:       `db.read_committed_demo.updateOne({account: "checking"}, {$inc: {balanceCents: NumberLong(-50000)}})`,
+		Result:      i18n.T("Update applied (NOT YET COMMITTED)"),
 		Success:     true,
-	}
+	}, debited == cents(initial["balanceCents"])-50000, i18n.T("Balance: %s", i18n.Money(debited)))
 	step++
 
-	time.Sleep(500 * time.Millisecond)
+	scenario.Pause(ctx)
 
 	// Step 3: Session B reads with majority read concern
 	output <- scenario.StepResult{
 		Session:     "Session B",
 		Step:        step,
-		Description: "Reading account with readConcern: majority",
+		Description: i18n.T("Reading account with readConcern: majority"),
+		Role:        scenario.RoleRead,
 		Query:       `db.read_committed_demo.findOne({account: "checking"}).readConcern("majority")`,
 		Result:      "",
 		Success:     true,
@@ -163,67 +212,75 @@ func (s *ReadCommittedScenario) Run(ctx context.Context, output chan<- scenario.
 
 	// Use a collection with majority read concern
 	collWithReadConcern := s.db.Collection("read_committed_demo", options.Collection().SetReadConcern(readconcern.Majority()))
-	var resultB bson.M
-	err = collWithReadConcern.FindOne(ctx, bson.M{"account": "checking"}).Decode(&resultB)
+	resultB, err := collWithReadConcern.FindOne(ctx, bson.M{"account": "checking"})
 	if err != nil {
 		return fmt.Errorf("failed to read with majority: %w", err)
 	}
+	scenario.Assert(ctx, "uncommitted update is invisible to other sessions",
+		cents(resultB["balanceCents"]) == cents(initial["balanceCents"]),
+		fmt.Sprintf("balance %s before commit, %s originally", i18n.Money(cents(resultB["balanceCents"])), i18n.Money(cents(initial["balanceCents"]))))
 
 	output <- scenario.StepResult{
 		Session:     "Session B",
 		Step:        step,
-		Description: "Read result with majority concern",
+		Description: i18n.T("Read result with majority concern"),
 		Query:       "Result from readConcern: majority",
-		Result:      fmt.Sprintf("Balance: $%.2f (ORIGINAL value - uncommitted changes not visible)", resultB["balance"]),
+		Result:      i18n.T("Balance: %s (ORIGINAL value - uncommitted changes not visible)", i18n.Money(cents(resultB["balanceCents"]))),
 		Success:     true,
 	}
 	step++
 
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "✅ Session B sees only committed data (original $1000), not Session A's uncommitted -$500",
+		Description: i18n.T("✅ Session B sees only committed data (original $1000), not Session A's uncommitted -$500"),
 	}
 
-	time.Sleep(500 * time.Millisecond)
+	scenario.Pause(ctx)
 
 	// Step 4: Session A commits
-	err = mongo.WithSession(ctx, sessionA, func(sc mongo.SessionContext) error {
-		return sessionA.CommitTransaction(sc)
-	})
-	if err != nil {
+	if err := txA.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	committed, err := balanceOf(ctx, s.collection, bson.M{"account": "checking"})
+	if err != nil {
+		return err
+	}
 
-	output <- scenario.StepResult{
+	output <- verified(scenario.StepResult{
 		Session:     "Session A",
 		Step:        step,
-		Description: "Committing the transaction",
+		Description: i18n.T("Committing the transaction"),
+		Role:        scenario.RoleCommit,
 		Query:       "session.commitTransaction()",
-		Result:      "Transaction committed - balance change now permanent",
+		Result:      i18n.T("Transaction committed - balance change now permanent"),
 		Success:     true,
-	}
+	}, committed == cents(initial["balanceCents"])-50000, i18n.T("Balance: %s", i18n.Money(committed)))
 	step++
 
-	time.Sleep(500 * time.Millisecond)
+	scenario.Pause(ctx)
 
 	// Step 5: Session B reads again
-	err = collWithReadConcern.FindOne(ctx, bson.M{"account": "checking"}).Decode(&resultB)
+	resultB, err = collWithReadConcern.FindOne(ctx, bson.M{"account": "checking"})
 	if err != nil {
 		return fmt.Errorf("failed to read after commit: %w", err)
 	}
+	scenario.Assert(ctx, "committed update becomes visible",
+		cents(resultB["balanceCents"]) == cents(initial["balanceCents"])-50000,
+		fmt.Sprintf("balance %s after commit", i18n.Money(cents(resultB["balanceCents"]))))
 
 	output <- scenario.StepResult{
 		Session:     "Session B",
 		Step:        step,
-		Description: "Reading account again after Session A committed",
+		Description: i18n.T("Reading account again after Session A committed"),
+		Role:        scenario.RoleReread,
 		Query:       `db.read_committed_demo.findOne({account: "checking"}).readConcern("majority")`,
-		Result:      fmt.Sprintf("Balance: $%.2f (UPDATED value now visible)", resultB["balance"]),
+		Result:      i18n.T("Balance: %s (UPDATED value now visible)", i18n.Money(cents(resultB["balanceCents"]))),
 		Success:     true,
 	}
 
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "🎉 After commit, Session B now sees the updated balance of $500",
+		Description: i18n.T("🎉 After commit, Session B now sees the updated balance of $500"),
 	}
 
 	return nil
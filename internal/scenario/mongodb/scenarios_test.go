@@ -0,0 +1,169 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/scenariotest"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// fastParams runs scenarios without pauses
+func fastParams() scenario.Params {
+	p := scenario.DefaultParams()
+	p.Pacing = 0
+	return p
+}
+
+func TestScenarios_ConformOnFakeDatabase(t *testing.T) {
+	db := newFakeDatabase()
+	leftovers := scenariotest.WithLeftovers(func(ctx context.Context, s scenario.Scenario) ([]string, error) {
+		return db.nonEmpty(), nil
+	})
+	scenariotest.Conformance(t, func() scenario.Scenario { return newDirtyReadScenario(db.handle()) }, leftovers)
+	scenariotest.Conformance(t, func() scenario.Scenario { return newReadCommittedScenario(db.handle()) }, leftovers)
+	scenariotest.Conformance(t, func() scenario.Scenario { return newSnapshotIsolationScenario(db.handle()) }, leftovers)
+	scenariotest.Conformance(t, func() scenario.Scenario { return newWriteConflictScenario(db.handle()) }, leftovers)
+}
+
+func TestWriteConflictScenario_Branches(t *testing.T) {
+	tests := []struct {
+		name     string
+		db       func() *fakeDatabase
+		rejected string // Description of the step telling Session A it lost; "" when nothing stops it
+		verdict  report.Verdict
+	}{
+		{
+			name:     "update conflicts",
+			db:       newFakeDatabase,
+			rejected: "Update rejected",
+			verdict:  report.VerdictPass,
+		},
+		{
+			name: "update conflicts without fail points",
+			db: func() *fakeDatabase {
+				db := newFakeDatabase()
+				db.failPoints = false
+				return db
+			},
+			rejected: "Update rejected",
+			verdict:  report.VerdictPass,
+		},
+		{
+			name: "commit conflicts",
+			db: func() *fakeDatabase {
+				db := newFakeDatabase()
+				db.detectConflicts, db.failPoints = false, false
+				db.failNext("commitTransaction", nil) // Session B's
+				db.failNext("commitTransaction", writeConflict())
+				return db
+			},
+			rejected: "Attempting to commit transaction",
+			verdict:  report.VerdictPass,
+		},
+		{
+			name: "no conflict",
+			db: func() *fakeDatabase {
+				db := newFakeDatabase()
+				db.detectConflicts, db.failPoints = false, false
+				return db
+			},
+			verdict: report.VerdictFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.db()
+			run := headless.Record(context.Background(), newWriteConflictScenario(db.handle()), fastParams(), nil)
+			if run.Err != nil {
+				t.Fatalf("Expected the run to complete, got %v", run.Err)
+			}
+			if v := run.Verdict(); v != tt.verdict {
+				t.Fatalf("Expected verdict %s, got %s: %+v", tt.verdict, v, run.Assertions)
+			}
+
+			var lost *scenario.StepResult
+			for i, step := range run.Steps {
+				if step.Session == "Session A" && !step.Success {
+					lost = &run.Steps[i]
+					break
+				}
+			}
+			switch {
+			case tt.rejected == "" && lost != nil && strings.Contains(lost.Result, "WriteConflict"):
+				t.Fatalf("Expected Session A to get through, got %+v", lost)
+			case tt.rejected != "" && (lost == nil || lost.Description != tt.rejected):
+				t.Fatalf("Expected Session A to be stopped at %q, got %+v", tt.rejected, lost)
+			case tt.rejected != "" && !strings.Contains(lost.Result, "TransientTransactionError"):
+				t.Fatalf("Expected the error labels in the result, got %q", lost.Result)
+			}
+		})
+	}
+}
+
+func TestWriteConflictScenario_ScopesItsFailPoint(t *testing.T) {
+	db := newFakeDatabase()
+	s := newWriteConflictScenario(db.handle())
+	ctx := scenario.WithParams(context.Background(), fastParams())
+	if err := s.Setup(ctx); err != nil {
+		t.Fatalf("Expected Setup to succeed, got %v", err)
+	}
+	if len(db.enabled) != 0 {
+		t.Fatalf("Expected Setup to leave fail points off, got %v", db.enabled)
+	}
+	if err := s.Cleanup(ctx); err != nil {
+		t.Fatalf("Expected Cleanup to succeed, got %v", err)
+	}
+
+	run := headless.Record(context.Background(), s, fastParams(), nil)
+	if run.Err != nil {
+		t.Fatalf("Expected the run to complete, got %v", run.Err)
+	}
+	if len(db.enabled) != 1 {
+		t.Fatalf("Expected the fail point turned on once, got %v", db.enabled)
+	}
+	cmd := db.enabled[0]
+	data := cmd["data"].(bson.D).Map()
+	if mode := cmd["mode"].(bson.D).Map(); mode["times"] != 1 || mode["skip"] != nil {
+		t.Fatalf("Expected the fail point to fail one command, got mode %v", mode)
+	}
+	if data["appName"] != AppName || data["namespace"] != "txviewer.write_conflict_demo" {
+		t.Fatalf("Expected the fail point scoped to the app and the collection, got %v", data)
+	}
+	if db.failCommand != nil {
+		t.Fatal("Expected the fail point off after the run")
+	}
+}
+
+func TestDirtyReadScenario_HidesTheUncommittedInsert(t *testing.T) {
+	db := newFakeDatabase()
+	run := headless.Record(context.Background(), newDirtyReadScenario(db.handle()), fastParams(), nil)
+	if run.Err != nil {
+		t.Fatalf("Expected the run to complete, got %v", run.Err)
+	}
+	if v := run.Verdict(); v != report.VerdictPass {
+		t.Fatalf("Expected the run to pass, got %s: %+v", v, run.Assertions)
+	}
+	if len(run.Assertions) != 3 {
+		t.Fatalf("Expected 3 assertions, got %+v", run.Assertions)
+	}
+
+	// Session B reads before and after Session A commits
+	var reads []string
+	for _, step := range run.Steps {
+		if step.Session == "Session B" && strings.HasPrefix(step.Result, "Documents found") {
+			reads = append(reads, step.Result)
+		}
+	}
+	if len(reads) != 2 || !strings.HasPrefix(reads[0], "Documents found: 0") || !strings.HasPrefix(reads[1], "Documents found: 1") {
+		t.Fatalf("Expected Session B to find nothing, then the document, got %q", reads)
+	}
+	if left := db.nonEmpty(); len(left) > 0 {
+		t.Fatalf("Expected Cleanup to drop the collection, got %v", left)
+	}
+}
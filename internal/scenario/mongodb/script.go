@@ -0,0 +1,544 @@
+package mongodb
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"gopkg.in/yaml.v3"
+)
+
+// Script operations
+const (
+	opStart  = "start"
+	opInsert = "insert"
+	opUpdate = "update"
+	opFind   = "find"
+	opCommit = "commit"
+	opAbort  = "abort"
+	opSleep  = "sleep"
+	opAssert = "assert"
+)
+
+// opKeys lists the keys each operation accepts besides op, session, describe and role
+var opKeys = map[string][]string{
+	opStart:  {"readConcern", "writeConcern"},
+	opInsert: {"document", "expectError"},
+	opUpdate: {"filter", "update", "expectError"},
+	opFind:   {"filter", "expectError"},
+	opCommit: {"expectError"},
+	opAbort:  nil,
+	opSleep:  {"duration"},
+	opAssert: {"filter", "count", "expect"},
+}
+
+// readConcerns and the levels they accept in startTransaction options
+var readConcerns = []string{"local", "majority", "snapshot"}
+
+//go:embed scripts/*.yaml
+var exampleScripts embed.FS
+
+// Script is a scenario declared in a YAML file: named sessions and the operations they run in order
+type Script struct {
+	Path        string
+	Name        string
+	Description string
+	Isolation   string
+	Collection  string
+	Anomaly     string // Anomaly key shared with the same demonstration on other providers
+	Tags        []string
+	Sessions    []string
+	Setup       []bson.D
+	Steps       []ScriptStep
+}
+
+// ScriptStep is a single operation of a script
+type ScriptStep struct {
+	Line         int // Line of the step in the file, for errors raised while running it
+	Op           string
+	Session      string // Empty runs the operation outside any session
+	Describe     string
+	Role         string
+	Filter       bson.D
+	Update       bson.D
+	Document     bson.D
+	ReadConcern  string
+	WriteConcern string
+	Duration     time.Duration
+	Count        *int
+	Expect       bson.D
+	ExpectError  string // The operation must fail with an error containing this text or carrying this label
+}
+
+// LoadScript reads and parses the script file at path
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+	return ParseScript(data, path)
+}
+
+// LoadScriptDir parses every .yaml and .yml file in dir in name order. A missing directory has no scripts.
+func LoadScriptDir(dir string) ([]*Script, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario directory: %w", err)
+	}
+
+	var scripts []*Script
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		s, err := LoadScript(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, s)
+	}
+	return scripts, nil
+}
+
+// ExampleScripts returns the scripts shipped with txviewer
+func ExampleScripts() ([]*Script, error) {
+	names, err := fs.Glob(exampleScripts, "scripts/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	scripts := make([]*Script, 0, len(names))
+	for _, name := range names {
+		data, err := exampleScripts.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		s, err := ParseScript(data, name)
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, s)
+	}
+	return scripts, nil
+}
+
+// ParseScript parses a script. Errors name path and the line of the offending value.
+func ParseScript(data []byte, path string) (*Script, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, fmt.Errorf("%s: empty scenario file", path)
+	}
+
+	p := &scriptParser{path: path}
+	s, err := p.script(root.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	s.Path = path
+	return s, nil
+}
+
+// scriptParser builds a Script from YAML nodes
+type scriptParser struct {
+	path string
+}
+
+// errorf returns an error pointing at the line of n
+func (p *scriptParser) errorf(n *yaml.Node, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", p.path, n.Line, fmt.Sprintf(format, args...))
+}
+
+// fields returns the values of mapping n by key, rejecting keys that aren't allowed
+func (p *scriptParser) fields(n *yaml.Node, what string, allowed ...string) (map[string]*yaml.Node, error) {
+	if n.Kind != yaml.MappingNode {
+		return nil, p.errorf(n, "%s must be a mapping", what)
+	}
+	values := make(map[string]*yaml.Node, len(n.Content)/2)
+	for i := 0; i < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if !slices.Contains(allowed, key.Value) {
+			return nil, p.errorf(key, "unknown key %q in %s (valid: %s)", key.Value, what, strings.Join(allowed, ", "))
+		}
+		if _, ok := values[key.Value]; ok {
+			return nil, p.errorf(key, "duplicate key %q in %s", key.Value, what)
+		}
+		values[key.Value] = value
+	}
+	return values, nil
+}
+
+// str returns the string value of a scalar node
+func (p *scriptParser) str(n *yaml.Node, key string) (string, error) {
+	if n.Kind != yaml.ScalarNode {
+		return "", p.errorf(n, "%s must be a string", key)
+	}
+	return n.Value, nil
+}
+
+// script parses the top-level mapping
+func (p *scriptParser) script(n *yaml.Node) (*Script, error) {
+	f, err := p.fields(n, "scenario", "name", "description", "isolation", "collection", "anomaly", "tags", "setup", "sessions", "steps")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Script{Isolation: "Custom"}
+	for key, dst := range map[string]*string{"name": &s.Name, "description": &s.Description, "isolation": &s.Isolation, "collection": &s.Collection, "anomaly": &s.Anomaly} {
+		if v, ok := f[key]; ok {
+			if *dst, err = p.str(v, key); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		return nil, p.errorf(n, "scenario needs a name")
+	}
+	s.Description = strings.TrimSpace(s.Description)
+
+	if v, ok := f["collection"]; ok {
+		if err := validateCollectionName(s.Collection); err != nil {
+			return nil, p.errorf(v, "%v", err)
+		}
+	} else {
+		s.Collection = "custom_" + slug(s.Name)
+	}
+
+	if v, ok := f["tags"]; ok {
+		if s.Tags, err = p.strings(v, "tags"); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := f["setup"]; ok {
+		if v.Kind != yaml.SequenceNode {
+			return nil, p.errorf(v, "setup must be a list of documents")
+		}
+		for _, d := range v.Content {
+			doc, err := p.document(d, "setup document")
+			if err != nil {
+				return nil, err
+			}
+			s.Setup = append(s.Setup, doc)
+		}
+	}
+
+	v, ok := f["sessions"]
+	if !ok {
+		return nil, p.errorf(n, "scenario needs sessions")
+	}
+	if s.Sessions, err = p.strings(v, "sessions"); err != nil {
+		return nil, err
+	}
+	if len(s.Sessions) == 0 {
+		return nil, p.errorf(v, "scenario needs at least one session")
+	}
+	for i, name := range s.Sessions {
+		if slices.Contains(s.Sessions[:i], name) {
+			return nil, p.errorf(v.Content[i], "duplicate session %q", name)
+		}
+	}
+
+	v, ok = f["steps"]
+	if !ok {
+		return nil, p.errorf(n, "scenario needs steps")
+	}
+	if v.Kind != yaml.SequenceNode || len(v.Content) == 0 {
+		return nil, p.errorf(v, "steps must be a non-empty list")
+	}
+
+	// Track open transactions so misplaced commits are reported here rather than mid-run
+	inTransaction := make(map[string]bool)
+	for _, stepNode := range v.Content {
+		step, err := p.step(stepNode, s.Sessions, inTransaction)
+		if err != nil {
+			return nil, err
+		}
+		s.Steps = append(s.Steps, step)
+	}
+	return s, nil
+}
+
+// strings parses a list of strings
+func (p *scriptParser) strings(n *yaml.Node, key string) ([]string, error) {
+	if n.Kind != yaml.SequenceNode {
+		return nil, p.errorf(n, "%s must be a list", key)
+	}
+	values := make([]string, 0, len(n.Content))
+	for _, item := range n.Content {
+		v, err := p.str(item, key)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// step parses one entry of steps
+func (p *scriptParser) step(n *yaml.Node, sessions []string, inTransaction map[string]bool) (ScriptStep, error) {
+	step := ScriptStep{Line: n.Line}
+	if n.Kind != yaml.MappingNode {
+		return step, p.errorf(n, "step must be a mapping")
+	}
+
+	// Look up op first so the allowed keys depend on it
+	var opNode *yaml.Node
+	for i := 0; i < len(n.Content); i += 2 {
+		if n.Content[i].Value == "op" {
+			opNode = n.Content[i+1]
+		}
+	}
+	if opNode == nil {
+		return step, p.errorf(n, "step needs an op")
+	}
+	extra, ok := opKeys[opNode.Value]
+	if !ok {
+		ops := make([]string, 0, len(opKeys))
+		for op := range opKeys {
+			ops = append(ops, op)
+		}
+		slices.Sort(ops)
+		return step, p.errorf(opNode, "unknown op %q (valid: %s)", opNode.Value, strings.Join(ops, ", "))
+	}
+	step.Op = opNode.Value
+
+	allowed := []string{"op", "describe", "role"}
+	if step.Op != opSleep {
+		allowed = append(allowed, "session")
+	}
+	f, err := p.fields(n, step.Op+" step", append(allowed, extra...)...)
+	if err != nil {
+		return step, err
+	}
+
+	if v, ok := f["describe"]; ok {
+		if step.Describe, err = p.str(v, "describe"); err != nil {
+			return step, err
+		}
+	}
+	if v, ok := f["role"]; ok {
+		if step.Role, err = p.str(v, "role"); err != nil {
+			return step, err
+		}
+	}
+	if v, ok := f["session"]; ok {
+		if step.Session, err = p.str(v, "session"); err != nil {
+			return step, err
+		}
+		if !slices.Contains(sessions, step.Session) {
+			return step, p.errorf(v, "unknown session %q (declared: %s)", step.Session, strings.Join(sessions, ", "))
+		}
+	}
+	if v, ok := f["expectError"]; ok {
+		if step.ExpectError, err = p.str(v, "expectError"); err != nil {
+			return step, err
+		}
+	}
+
+	switch step.Op {
+	case opStart, opCommit, opAbort:
+		if step.Session == "" {
+			return step, p.errorf(n, "%s step needs a session", step.Op)
+		}
+		open := inTransaction[step.Session]
+		if step.Op == opStart && open {
+			return step, p.errorf(opNode, "session %q already has a transaction in progress", step.Session)
+		}
+		if step.Op != opStart && !open {
+			return step, p.errorf(opNode, "session %q has no transaction to %s", step.Session, step.Op)
+		}
+		inTransaction[step.Session] = step.Op == opStart
+	}
+
+	switch step.Op {
+	case opStart:
+		if v, ok := f["readConcern"]; ok {
+			if step.ReadConcern, err = p.str(v, "readConcern"); err != nil {
+				return step, err
+			}
+			if !slices.Contains(readConcerns, step.ReadConcern) {
+				return step, p.errorf(v, "unknown readConcern %q (valid: %s)", step.ReadConcern, strings.Join(readConcerns, ", "))
+			}
+		}
+		if v, ok := f["writeConcern"]; ok {
+			if step.WriteConcern, err = p.str(v, "writeConcern"); err != nil {
+				return step, err
+			}
+			if step.WriteConcern != "majority" && !isNumber(step.WriteConcern) {
+				return step, p.errorf(v, "writeConcern must be majority or a number of members")
+			}
+		}
+
+	case opInsert:
+		v, ok := f["document"]
+		if !ok {
+			return step, p.errorf(n, "insert step needs a document")
+		}
+		if step.Document, err = p.document(v, "document"); err != nil {
+			return step, err
+		}
+
+	case opUpdate:
+		if step.Filter, err = p.optionalDocument(f, "filter"); err != nil {
+			return step, err
+		}
+		v, ok := f["update"]
+		if !ok {
+			return step, p.errorf(n, "update step needs an update")
+		}
+		if step.Update, err = p.document(v, "update"); err != nil {
+			return step, err
+		}
+		if v.Kind == yaml.AliasNode {
+			v = v.Alias // The keys are those of the anchored mapping
+		}
+		for i, e := range step.Update {
+			if !strings.HasPrefix(e.Key, "$") {
+				return step, p.errorf(v.Content[2*i], "update keys must be operators such as $set or $inc, got %q", e.Key)
+			}
+		}
+
+	case opFind:
+		if step.Filter, err = p.optionalDocument(f, "filter"); err != nil {
+			return step, err
+		}
+
+	case opSleep:
+		v, ok := f["duration"]
+		if !ok {
+			return step, p.errorf(n, "sleep step needs a duration")
+		}
+		if step.Duration, err = time.ParseDuration(v.Value); err != nil || step.Duration < 0 {
+			return step, p.errorf(v, "invalid duration %q, expected e.g. 500ms or 2s", v.Value)
+		}
+
+	case opAssert:
+		if step.Filter, err = p.optionalDocument(f, "filter"); err != nil {
+			return step, err
+		}
+		if v, ok := f["count"]; ok {
+			var count int
+			if err := v.Decode(&count); err != nil || count < 0 {
+				return step, p.errorf(v, "count must be a non-negative number")
+			}
+			step.Count = &count
+		}
+		if step.Expect, err = p.optionalDocument(f, "expect"); err != nil {
+			return step, err
+		}
+		if step.Count == nil && step.Expect == nil {
+			return step, p.errorf(n, "assert step needs a count or expect")
+		}
+	}
+	return step, nil
+}
+
+// optionalDocument parses the document under key, or returns nil when it is absent
+func (p *scriptParser) optionalDocument(f map[string]*yaml.Node, key string) (bson.D, error) {
+	v, ok := f[key]
+	if !ok {
+		return nil, nil
+	}
+	return p.document(v, key)
+}
+
+// document converts a mapping into a bson.D, keeping the key order of the file
+func (p *scriptParser) document(n *yaml.Node, what string) (bson.D, error) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind != yaml.MappingNode {
+		return nil, p.errorf(n, "%s must be a mapping", what)
+	}
+	doc := make(bson.D, 0, len(n.Content)/2)
+	for i := 0; i < len(n.Content); i += 2 {
+		v, err := p.value(n.Content[i+1], what)
+		if err != nil {
+			return nil, err
+		}
+		doc = append(doc, bson.E{Key: n.Content[i].Value, Value: v})
+	}
+	return doc, nil
+}
+
+// value converts any node into a BSON value
+func (p *scriptParser) value(n *yaml.Node, what string) (any, error) {
+	switch n.Kind {
+	case yaml.AliasNode:
+		return p.value(n.Alias, what)
+	case yaml.MappingNode:
+		return p.document(n, what)
+	case yaml.SequenceNode:
+		arr := make(bson.A, 0, len(n.Content))
+		for _, item := range n.Content {
+			v, err := p.value(item, what)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	}
+
+	var v any
+	if err := n.Decode(&v); err != nil {
+		return nil, p.errorf(n, "invalid value in %s: %v", what, err)
+	}
+	return v, nil
+}
+
+// isNumber returns whether s is a positive integer
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validateCollectionName rejects names MongoDB doesn't allow or reserves
+func validateCollectionName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("collection must not be empty")
+	case strings.ContainsAny(name, "$\x00"):
+		return fmt.Errorf("collection %q must not contain $ or null characters", name)
+	case strings.HasPrefix(name, "system."):
+		return fmt.Errorf("collection %q is reserved", name)
+	}
+	return nil
+}
+
+// slug turns a scenario name into a lowercase identifier for its default collection
+func slug(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
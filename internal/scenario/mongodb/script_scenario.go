@@ -0,0 +1,409 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// ScriptScenario runs a Script against the provider
+type ScriptScenario struct {
+	conn
+	script *Script
+}
+
+// NewScriptScenario creates a scenario that runs script
+func NewScriptScenario(handle Handle, script *Script) *ScriptScenario {
+	return &ScriptScenario{
+		conn:   newConn(handle, script.Collection),
+		script: script,
+	}
+}
+
+func (s *ScriptScenario) Name() string {
+	return s.script.Name
+}
+
+func (s *ScriptScenario) Description() string {
+	if s.script.Description == "" {
+		return fmt.Sprintf("Custom scenario from %s", s.script.Path)
+	}
+	return s.script.Description
+}
+
+func (s *ScriptScenario) IsolationLevel() string {
+	return s.script.Isolation
+}
+
+// Requires derives the capabilities from the operations the script uses
+func (s *ScriptScenario) Requires() []scenario.Capability {
+	var transactions, snapshot bool
+	for _, step := range s.script.Steps {
+		transactions = transactions || step.Op == opStart
+		snapshot = snapshot || step.ReadConcern == "snapshot"
+	}
+
+	var caps []scenario.Capability
+	if transactions {
+		caps = append(caps, scenario.CapMultiDocumentTransactions)
+	}
+	if snapshot {
+		caps = append(caps, scenario.CapSnapshotReads)
+	}
+	return caps
+}
+
+// Metadata tags the scenario as custom and estimates its run time from its steps and sleeps
+func (s *ScriptScenario) Metadata() scenario.Metadata {
+	estimate := time.Duration(len(s.script.Steps)) * scenario.DefaultPacing
+	for _, step := range s.script.Steps {
+		estimate += step.Duration
+	}
+	return scenario.Metadata{
+		Tags:              append([]string{scenario.TagCustom}, s.script.Tags...),
+		EstimatedDuration: estimate,
+	}
+}
+
+// AnomalyKey returns the anomaly the script declares, if any
+func (s *ScriptScenario) AnomalyKey() string {
+	return s.script.Anomaly
+}
+
+func (s *ScriptScenario) Setup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	if err := dropCollection(ctx, s.collection); err != nil {
+		return err
+	}
+	if len(s.script.Setup) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(s.script.Setup))
+	for i, d := range s.script.Setup {
+		docs[i] = d
+	}
+	_, err := s.collection.InsertMany(ctx, docs)
+	return err
+}
+
+func (s *ScriptScenario) Cleanup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	return dropCollection(ctx, s.collection)
+}
+
+func (s *ScriptScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	output <- scenario.StepResult{
+		IsHeader:    true,
+		Description: "📜 " + s.script.Name,
+	}
+
+	sessions := make(map[string]mongo.Session, len(s.script.Sessions))
+	for _, name := range s.script.Sessions {
+		session, err := s.client.StartSession()
+		if err != nil {
+			return fmt.Errorf("failed to start session %s: %w", name, err)
+		}
+		defer session.EndSession(ctx)
+		sessions[name] = session
+	}
+
+	for i, step := range s.script.Steps {
+		if i > 0 {
+			scenario.Pause(ctx)
+		}
+
+		// Operations of a session run in its context so they join its transaction
+		opCtx := ctx
+		if step.Session != "" {
+			opCtx = mongo.NewSessionContext(ctx, sessions[step.Session])
+		}
+
+		result, err := s.exec(opCtx, step, sessions[step.Session])
+		out := scenario.StepResult{
+			Session:     stepSession(step),
+			Step:        i + 1,
+			Description: stepDescription(step),
+			Query:       s.query(step),
+			Result:      result,
+			Success:     err == nil,
+			Role:        step.Role,
+		}
+
+		switch {
+		case err != nil && step.ExpectError != "" && errorMatches(err, step.ExpectError):
+			out.Result = "Failed as expected: " + err.Error()
+			scenario.Assert(ctx, fmt.Sprintf("step %d fails with %s", i+1, step.ExpectError), true, err.Error())
+		case err != nil:
+			out.Result = err.Error()
+			output <- out
+			return fmt.Errorf("step %d (%s:%d) failed: %w", i+1, s.script.Path, step.Line, err)
+		case step.ExpectError != "":
+			scenario.Assert(ctx, fmt.Sprintf("step %d fails with %s", i+1, step.ExpectError), false,
+				"the operation succeeded")
+		}
+		output <- out
+	}
+	return nil
+}
+
+// exec runs one step and describes its outcome
+func (s *ScriptScenario) exec(ctx context.Context, step ScriptStep, session mongo.Session) (string, error) {
+	switch step.Op {
+	case opStart:
+		if err := session.StartTransaction(transactionOptions(step)); err != nil {
+			return "", err
+		}
+		return "Transaction started", nil
+
+	case opInsert:
+		if _, err := s.collection.InsertOne(ctx, step.Document); err != nil {
+			return "", err
+		}
+		return "Inserted 1 document", nil
+
+	case opUpdate:
+		res, err := s.collection.UpdateOne(ctx, filterOf(step), step.Update)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Matched %d, modified %d", res.MatchedCount, res.ModifiedCount), nil
+
+	case opFind:
+		docs, err := s.find(ctx, step)
+		if err != nil {
+			return "", err
+		}
+		return formatDocuments(docs), nil
+
+	case opCommit:
+		if err := session.CommitTransaction(ctx); err != nil {
+			return "", err
+		}
+		return "Transaction committed", nil
+
+	case opAbort:
+		if err := session.AbortTransaction(ctx); err != nil {
+			return "", err
+		}
+		return "Transaction aborted", nil
+
+	case opSleep:
+		select {
+		case <-time.After(step.Duration):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		return "Slept " + step.Duration.String(), nil
+
+	case opAssert:
+		docs, err := s.find(ctx, step)
+		if err != nil {
+			return "", err
+		}
+		held, detail := check(step, docs)
+		scenario.Assert(ctx, stepDescription(step), held, detail)
+		if held {
+			return "✅ " + detail, nil
+		}
+		return "❌ " + detail, nil
+	}
+	return "", fmt.Errorf("unknown op %q", step.Op)
+}
+
+// find returns the documents matching the step's filter
+func (s *ScriptScenario) find(ctx context.Context, step ScriptStep) ([]bson.M, error) {
+	cursor, err := s.collection.Find(ctx, filterOf(step))
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode results: %w", err)
+	}
+	return docs, nil
+}
+
+// query renders the step as the shell command it corresponds to
+func (s *ScriptScenario) query(step ScriptStep) string {
+	coll := "db." + s.script.Collection
+	switch step.Op {
+	case opStart:
+		var opts []string
+		if step.ReadConcern != "" {
+			opts = append(opts, fmt.Sprintf("readConcern: {level: %q}", step.ReadConcern))
+		}
+		if step.WriteConcern != "" {
+			w := step.WriteConcern
+			if w == "majority" {
+				w = `"majority"`
+			}
+			opts = append(opts, "writeConcern: {w: "+w+"}")
+		}
+		if len(opts) == 0 {
+			return "session.startTransaction()"
+		}
+		return "session.startTransaction({" + strings.Join(opts, ", ") + "})"
+	case opInsert:
+		return fmt.Sprintf("%s.insertOne(%s)", coll, extJSON(step.Document))
+	case opUpdate:
+		return fmt.Sprintf("%s.updateOne(%s, %s)", coll, extJSON(filterOf(step)), extJSON(step.Update))
+	case opFind, opAssert:
+		return fmt.Sprintf("%s.find(%s)", coll, extJSON(filterOf(step)))
+	case opCommit:
+		return "session.commitTransaction()"
+	case opAbort:
+		return "session.abortTransaction()"
+	case opSleep:
+		return fmt.Sprintf("sleep(%d)", step.Duration.Milliseconds())
+	}
+	return ""
+}
+
+// check compares the documents an assert step found with its expectations
+func check(step ScriptStep, docs []bson.M) (bool, string) {
+	if step.Count != nil && len(docs) != *step.Count {
+		return false, fmt.Sprintf("expected %d documents, found %d", *step.Count, len(docs))
+	}
+	if step.Expect == nil {
+		return true, fmt.Sprintf("found %d documents", len(docs))
+	}
+
+	if len(docs) == 0 {
+		return false, fmt.Sprintf("expected %s, found no documents", extJSON(step.Expect))
+	}
+	for _, e := range step.Expect {
+		if got := docs[0][e.Key]; !valuesEqual(e.Value, got) {
+			return false, fmt.Sprintf("expected %s = %v, found %v", e.Key, e.Value, got)
+		}
+	}
+	return true, "found " + extJSON(step.Expect)
+}
+
+// valuesEqual compares a value from a script with one decoded from the database,
+// treating all numeric types alike
+func valuesEqual(want, got any) bool {
+	if isNumeric(want) && isNumeric(got) {
+		return number(want) == number(got)
+	}
+	return fmt.Sprint(want) == fmt.Sprint(got)
+}
+
+// isNumeric returns whether v is a number number() understands
+func isNumeric(v any) bool {
+	switch v.(type) {
+	case int, int32, int64, float64:
+		return true
+	}
+	return false
+}
+
+// errorMatches returns whether err carries the label want or mentions it
+func errorMatches(err error, want string) bool {
+	var se mongo.ServerError
+	if errors.As(err, &se) && se.HasErrorLabel(want) {
+		return true
+	}
+	return strings.Contains(err.Error(), want)
+}
+
+// transactionOptions builds the startTransaction options of a step
+func transactionOptions(step ScriptStep) *options.TransactionOptions {
+	opts := options.Transaction()
+	switch step.ReadConcern {
+	case "local":
+		opts.SetReadConcern(readconcern.Local())
+	case "majority":
+		opts.SetReadConcern(readconcern.Majority())
+	case "snapshot":
+		opts.SetReadConcern(readconcern.Snapshot())
+	}
+	if step.WriteConcern == "majority" {
+		opts.SetWriteConcern(writeconcern.Majority())
+	} else if w, err := strconv.Atoi(step.WriteConcern); err == nil {
+		opts.SetWriteConcern(&writeconcern.WriteConcern{W: w})
+	}
+	return opts
+}
+
+// filterOf returns the step's filter, matching every document when it has none
+func filterOf(step ScriptStep) bson.D {
+	if step.Filter == nil {
+		return bson.D{}
+	}
+	return step.Filter
+}
+
+// stepSession labels the step with its session
+func stepSession(step ScriptStep) string {
+	switch {
+	case step.Session != "":
+		return "Session " + step.Session
+	case step.Op == opAssert:
+		return "Result"
+	}
+	return "Observer"
+}
+
+// stepDescription returns the step's own description or one derived from its op
+func stepDescription(step ScriptStep) string {
+	if step.Describe != "" {
+		return step.Describe
+	}
+	switch step.Op {
+	case opStart:
+		return "Starting a transaction"
+	case opInsert:
+		return "Inserting a document"
+	case opUpdate:
+		return "Updating a document"
+	case opFind:
+		return "Reading documents"
+	case opCommit:
+		return "Committing the transaction"
+	case opAbort:
+		return "Aborting the transaction"
+	case opSleep:
+		return "Waiting " + step.Duration.String()
+	}
+	return "Checking " + extJSON(filterOf(step))
+}
+
+// formatDocuments summarizes the documents a find returned
+func formatDocuments(docs []bson.M) string {
+	parts := make([]string, len(docs))
+	for i, d := range docs {
+		parts[i] = extJSON(d)
+	}
+	return fmt.Sprintf("Documents found: %d %s", len(docs), strings.Join(parts, " "))
+}
+
+// extJSON renders a document as relaxed extended JSON
+func extJSON(doc any) string {
+	data, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return fmt.Sprint(doc)
+	}
+	return string(data)
+}
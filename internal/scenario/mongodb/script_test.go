@@ -0,0 +1,107 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseScript(t *testing.T) {
+	data := `name: Withdraw
+sessions: [A]
+setup:
+  - {account: 1, balance: 100}
+steps:
+  - {session: A, op: start, readConcern: snapshot}
+  - {session: A, op: update, filter: {account: 1}, update: {$inc: {balance: -30}}}
+  - {session: A, op: commit}
+  - {op: assert, filter: {account: 1}, expect: {balance: 70}}
+`
+	s, err := ParseScript([]byte(data), "withdraw.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if s.Collection != "custom_withdraw" {
+		t.Fatalf("Expected default collection custom_withdraw, got %q", s.Collection)
+	}
+	if len(s.Steps) != 4 || s.Steps[1].Line != 7 {
+		t.Fatalf("Expected 4 steps with the update on line 7, got %+v", s.Steps)
+	}
+	if s.Steps[1].Update[0].Key != "$inc" {
+		t.Fatalf("Expected update $inc, got %v", s.Steps[1].Update)
+	}
+
+	held, detail := check(s.Steps[3], []bson.M{{"balance": int32(70)}})
+	if !held {
+		t.Fatalf("Expected int32 70 to match 70, got %s", detail)
+	}
+}
+
+func TestParseScript_ErrorLine(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "unknown op",
+			data: "name: X\nsessions: [A]\nsteps:\n  - session: A\n    op: upsert\n",
+			want: "test.yaml:5: unknown op \"upsert\"",
+		},
+		{
+			name: "unknown session",
+			data: "name: X\nsessions: [A]\nsteps:\n  - {session: C, op: start}\n",
+			want: "test.yaml:4: unknown session \"C\"",
+		},
+		{
+			name: "commit without start",
+			data: "name: X\nsessions: [A]\nsteps:\n  - session: A\n    op: commit\n",
+			want: "test.yaml:5: session \"A\" has no transaction to commit",
+		},
+		{
+			name: "key of another op",
+			data: "name: X\nsessions: [A]\nsteps:\n  - op: sleep\n    duration: 1s\n    filter: {a: 1}\n",
+			want: "test.yaml:6: unknown key \"filter\" in sleep step",
+		},
+		{
+			name: "update without operator",
+			data: "name: X\nsessions: [A]\nsteps:\n  - op: update\n    update: {balance: 1}\n",
+			want: "test.yaml:5: update keys must be operators",
+		},
+		{
+			name: "aliased update without operator",
+			data: "name: X\nsessions: [A]\nsetup:\n  - &doc {balance: 1}\nsteps:\n  - op: update\n    update: *doc\n",
+			want: "test.yaml:4: update keys must be operators",
+		},
+		{
+			name: "readConcern not a string",
+			data: "name: X\nsessions: [A]\nsteps:\n  - {session: A, op: start, readConcern: {level: snapshot}}\n",
+			want: "test.yaml:4: readConcern must be a string",
+		},
+		{
+			name: "writeConcern not a string",
+			data: "name: X\nsessions: [A]\nsteps:\n  - {session: A, op: start, writeConcern: [majority]}\n",
+			want: "test.yaml:4: writeConcern must be a string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseScript([]byte(tt.data), "test.yaml")
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Fatalf("Expected error starting with %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestExampleScripts(t *testing.T) {
+	scripts, err := ExampleScripts()
+	if err != nil {
+		t.Fatalf("Expected the examples to parse, got %v", err)
+	}
+	if len(scripts) == 0 {
+		t.Fatal("Expected example scripts")
+	}
+}
@@ -0,0 +1,59 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/sync/errgroup"
+)
+
+// seedBatchSize bounds each InsertMany when seeding large datasets
+const seedBatchSize = 10000
+
+// seedParallelism bounds the batches inserted at once across all collections being seeded.
+// Overridable for benchmarks.
+var seedParallelism = 4
+
+// seedSet is the documents to insert into one collection
+type seedSet struct {
+	name       string // Shown in the setup progress, e.g. "orders"
+	collection *mongo.Collection
+	docs       []any
+}
+
+// seed inserts every set's documents, batches of all sets running concurrently up to
+// seedParallelism. Batches are unordered, so a batch doesn't wait on its documents one by one and
+// the server may apply them in any order. Progress is reported per collection as batches land;
+// the first failure cancels the batches still running.
+func seed(ctx context.Context, sets ...seedSet) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(seedParallelism)
+
+	for _, set := range sets {
+		var (
+			mu       sync.Mutex // Keeps the reported counts increasing
+			inserted int
+		)
+		scenario.ReportSetup(ctx, i18n.T("Seeding %s: %d/%d", set.name, 0, len(set.docs)))
+		for start := 0; start < len(set.docs); start += seedBatchSize {
+			batch := set.docs[start:min(start+seedBatchSize, len(set.docs))]
+			g.Go(func() error {
+				if _, err := set.collection.InsertMany(ctx, batch, options.InsertMany().SetOrdered(false)); err != nil {
+					return fmt.Errorf("failed to seed %s: %w", set.name, err)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				inserted += len(batch)
+				scenario.ReportSetup(ctx, i18n.T("Seeding %s: %d/%d", set.name, inserted, len(set.docs)))
+				return nil
+			})
+		}
+	}
+	return g.Wait()
+}
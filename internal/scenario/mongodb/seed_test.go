@@ -0,0 +1,61 @@
+package mongodb
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BenchmarkSeed_100k seeds the large dataset one batch at a time, as Setup used to, and with the
+// default parallelism. It needs a server: set TXVIEWER_BENCH_MONGODB_URI, e.g. to
+// mongodb://localhost:27017.
+func BenchmarkSeed_100k(b *testing.B) {
+	uri := os.Getenv("TXVIEWER_BENCH_MONGODB_URI")
+	if uri == "" {
+		b.Skip("TXVIEWER_BENCH_MONGODB_URI is not set")
+	}
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		b.Fatalf("Expected to connect, got %v", err)
+	}
+	defer client.Disconnect(ctx)
+	coll := client.Database("txviewer_bench").Collection("seed")
+	defer coll.Drop(ctx)
+
+	records := scenario.GenerateDataset(scenario.DatasetLarge, rand.New(rand.NewSource(1)))
+	docs := make([]any, 0, len(records))
+	for _, r := range records {
+		docs = append(docs, bson.M{"_id": r.ID, "amount": r.Amount, "region": r.Region})
+	}
+
+	for _, bm := range []struct {
+		name        string
+		parallelism int
+	}{
+		{"sequential", 1},
+		{"parallel", seedParallelism},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			defer func(p int) { seedParallelism = p }(seedParallelism)
+			seedParallelism = bm.parallelism
+			for b.Loop() {
+				b.StopTimer()
+				if err := coll.Drop(ctx); err != nil {
+					b.Fatalf("Expected to drop the collection, got %v", err)
+				}
+				b.StartTimer()
+				if err := seed(ctx, seedSet{name: "orders", collection: coll, docs: docs}); err != nil {
+					b.Fatalf("Expected to seed, got %v", err)
+				}
+			}
+		})
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
@@ -16,17 +16,18 @@ import (
 
 // SnapshotIsolationScenario demonstrates snapshot isolation in MongoDB
 type SnapshotIsolationScenario struct {
-	client     *mongo.Client
-	db         *mongo.Database
-	collection *mongo.Collection
+	dbConn
 }
 
 // NewSnapshotIsolationScenario creates a new snapshot isolation demonstration scenario
-func NewSnapshotIsolationScenario(client *mongo.Client, db *mongo.Database) *SnapshotIsolationScenario {
+func NewSnapshotIsolationScenario(handle Handle) *SnapshotIsolationScenario {
+	return newSnapshotIsolationScenario(Driver(handle))
+}
+
+// newSnapshotIsolationScenario creates the scenario on the database open resolves
+func newSnapshotIsolationScenario(open DatabaseHandle) *SnapshotIsolationScenario {
 	return &SnapshotIsolationScenario{
-		client:     client,
-		db:         db,
-		collection: db.Collection("snapshot_demo"),
+		dbConn: newDBConn(open, "snapshot_demo"),
 	}
 }
 
@@ -35,7 +36,7 @@ func (s *SnapshotIsolationScenario) Name() string {
 }
 
 func (s *SnapshotIsolationScenario) Description() string {
-	return `Demonstrates Snapshot Isolation using MongoDB's readConcern: "snapshot".
+	return i18n.T(`Demonstrates Snapshot Isolation using MongoDB's readConcern: "snapshot".
 
 This is the strongest isolation level in MongoDB. With snapshot isolation:
 - All reads in a transaction see data from the same point in time
@@ -48,16 +49,65 @@ This scenario shows:
 3. Session A reads inventory - sees 3 products
 4. Session B adds a new product and COMMITS immediately
 5. Session A reads again - STILL sees only 3 products (snapshot!)
-6. After Session A ends, new product becomes visible`
+6. After Session A ends, new product becomes visible`)
 }
 
 func (s *SnapshotIsolationScenario) IsolationLevel() string {
 	return "Snapshot (Repeatable Read)"
 }
 
+func (s *SnapshotIsolationScenario) Requires() []scenario.Capability {
+	return []scenario.Capability{
+		scenario.CapMultiDocumentTransactions,
+		scenario.CapSnapshotReads,
+	}
+}
+
+// Metadata returns listing tags and the typical run time
+func (s *SnapshotIsolationScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{Tags: []string{"snapshot", "repeatable-read"}, EstimatedDuration: 4 * time.Second}
+}
+
+// AnomalyKey identifies the scenario for comparisons across providers
+func (s *SnapshotIsolationScenario) AnomalyKey() string {
+	return scenario.AnomalyReadSkew
+}
+
+// Quiz asks about the run that just finished
+func (s *SnapshotIsolationScenario) Quiz() []scenario.Question {
+	return []scenario.Question{
+		{
+			Prompt: i18n.T("Why did Session A still count 3 products at step 6?"),
+			Choices: []string{
+				i18n.T("Session B's insert had not committed yet"),
+				i18n.T("Its transaction reads from the snapshot taken when it started"),
+				i18n.T("The driver cached the first count"),
+			},
+			Answer:      1,
+			Explanation: i18n.T("Session B committed the new product at step 4 and saw it at step 5, but every read in Session A's transaction uses the snapshot from its start."),
+			Steps:       []int{3, 4, 5, 6},
+		},
+		{
+			Prompt: i18n.T("When does Session A see the Ultra Gadget?"),
+			Choices: []string{
+				i18n.T("As soon as Session B commits"),
+				i18n.T("At its next read inside the same transaction"),
+				i18n.T("Only after its own transaction ends"),
+			},
+			Answer:      2,
+			Explanation: i18n.T("The snapshot lasts until the transaction ends. After Session A committed at step 7, its next read at step 8 counted 4 products."),
+			Steps:       []int{7, 8},
+		},
+	}
+}
+
 func (s *SnapshotIsolationScenario) Setup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
 	// Drop and recreate with initial data
-	if err := s.collection.Drop(ctx); err != nil {
+	if err := dropCollection(ctx, s.collection); err != nil {
 		return err
 	}
 
@@ -71,16 +121,22 @@ func (s *SnapshotIsolationScenario) Setup(ctx context.Context) error {
 }
 
 func (s *SnapshotIsolationScenario) Cleanup(ctx context.Context) error {
-	return s.collection.Drop(ctx)
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
+	return dropCollection(ctx, s.collection)
 }
 
 func (s *SnapshotIsolationScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
-	defer close(output)
+	if err := s.resolve(); err != nil {
+		return err
+	}
 
 	// Header
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "📸 Snapshot Isolation Demonstration",
+		Description: i18n.T("📸 Snapshot Isolation Demonstration"),
 	}
 
 	step := 1
@@ -94,15 +150,15 @@ func (s *SnapshotIsolationScenario) Run(ctx context.Context, output chan<- scena
 	output <- scenario.StepResult{
 		Session:     "Setup",
 		Step:        step,
-		Description: "Initial inventory state",
+		Description: i18n.T("Initial inventory state"),
 		Query:       "db.snapshot_demo.countDocuments({})",
-		Result:      fmt.Sprintf("Product count: %d (Blue Widget, Red Widget, Super Gadget)", count),
+		Result:      i18n.T("Product count: %d (Blue Widget, Red Widget, Super Gadget)", count),
 		Success:     true,
 	}
 	step++
 
 	// Step 2: Session A starts transaction with snapshot isolation
-	sessionA, err := s.client.StartSession()
+	sessionA, err := s.db.StartSession()
 	if err != nil {
 		return fmt.Errorf("failed to start session A: %w", err)
 	}
@@ -114,7 +170,7 @@ func (s *SnapshotIsolationScenario) Run(ctx context.Context, output chan<- scena
 
 	var snapshotCount int64
 
-	err = mongo.WithSession(ctx, sessionA, func(sc mongo.SessionContext) error {
+	err = withSession(ctx, sessionA, func(sc context.Context) error {
 		if err := sessionA.StartTransaction(txnOpts); err != nil {
 			return err
 		}
@@ -122,15 +178,16 @@ func (s *SnapshotIsolationScenario) Run(ctx context.Context, output chan<- scena
 		output <- scenario.StepResult{
 			Session:     "Session A",
 			Step:        step,
-			Description: "Starting transaction with SNAPSHOT isolation",
+			Description: i18n.T("Starting transaction with SNAPSHOT isolation"),
+			Role:        scenario.RoleBegin,
 			Query:       "session.startTransaction({readConcern: 'snapshot'})",
-			Result:      "Transaction started - snapshot of database taken NOW",
+			Result:      i18n.T("Transaction started - snapshot of database taken NOW"),
 			Success:     true,
 		}
 		step++
 
 		// Read count within transaction
-		snapshotCount, err = s.collection.CountDocuments(sc, bson.M{})
+		snapshotCount, err = countByFind(sc, s.collection, bson.M{})
 		if err != nil {
 			return err
 		}
@@ -138,20 +195,22 @@ func (s *SnapshotIsolationScenario) Run(ctx context.Context, output chan<- scena
 		output <- scenario.StepResult{
 			Session:     "Session A",
 			Step:        step,
-			Description: "Reading product count within snapshot transaction",
-			Query:       "db.snapshot_demo.countDocuments({})",
-			Result:      fmt.Sprintf("Product count: %d", snapshotCount),
+			Description: i18n.T("Reading product count within snapshot transaction"),
+			Role:        scenario.RoleRead,
+			Query:       "db.snapshot_demo.find({}, {_id: 1}).itcount()",
+			Result:      i18n.T("Product count: %d", snapshotCount),
 			Success:     true,
 		}
 		step++
 
-		time.Sleep(500 * time.Millisecond)
+		scenario.Pause(ctx)
 
 		// Session B (outside transaction) inserts a new product
 		output <- scenario.StepResult{
 			Session:     "Session B",
 			Step:        step,
-			Description: "Inserting NEW product (outside of Session A's transaction)",
+			Description: i18n.T("Inserting NEW product (outside of Session A's transaction)"),
+			Role:        scenario.RoleConcurrentWrite,
 			Query:       `db.snapshot_demo.insertOne({sku: "GADGET-002", name: "Ultra Gadget", quantity: 10})`,
 			Result:      "",
 			Success:     true,
@@ -166,18 +225,22 @@ func (s *SnapshotIsolationScenario) Run(ctx context.Context, output chan<- scena
 		if err != nil {
 			return fmt.Errorf("session B insert failed: %w", err)
 		}
+		inserted, err := s.collection.CountDocuments(ctx, bson.M{"sku": "GADGET-002"})
+		if err != nil {
+			return fmt.Errorf("failed to read back the insert: %w", err)
+		}
 
-		output <- scenario.StepResult{
+		output <- verified(scenario.StepResult{
 			Session:     "Session B",
 			Step:        step,
-			Description: "New product inserted and COMMITTED immediately",
+			Description: i18n.T("New product inserted and COMMITTED immediately"),
 			Query:       "Insert completed with default write concern",
-			Result:      "New product 'Ultra Gadget' is now in the database",
+			Result:      i18n.T("New product 'Ultra Gadget' is now in the database"),
 			Success:     true,
-		}
+		}, inserted == 1, i18n.T("Count: %d", inserted))
 		step++
 
-		time.Sleep(500 * time.Millisecond)
+		scenario.Pause(ctx)
 
 		// Verify Session B can see it (outside transaction)
 		totalCount, err := s.collection.CountDocuments(ctx, bson.M{})
@@ -185,37 +248,41 @@ func (s *SnapshotIsolationScenario) Run(ctx context.Context, output chan<- scena
 			return err
 		}
 
-		output <- scenario.StepResult{
+		output <- verified(scenario.StepResult{
 			Session:     "Session B",
 			Step:        step,
-			Description: "Session B verifies new product exists",
+			Description: i18n.T("Session B verifies new product exists"),
+			Role:        scenario.RoleRead,
 			Query:       "db.snapshot_demo.countDocuments({})",
-			Result:      fmt.Sprintf("Product count: %d (Session B sees 4 products)", totalCount),
+			Result:      i18n.T("Product count: %d (Session B sees 4 products)", totalCount),
 			Success:     true,
-		}
+		}, totalCount == count+1, i18n.T("Product count: %d", totalCount))
 		step++
 
-		time.Sleep(500 * time.Millisecond)
+		scenario.Pause(ctx)
 
 		// Session A reads again - should STILL see old snapshot
-		snapshotCount, err = s.collection.CountDocuments(sc, bson.M{})
+		snapshotCount, err = countByFind(sc, s.collection, bson.M{})
 		if err != nil {
 			return err
 		}
+		scenario.Assert(ctx, "snapshot does not see inserts committed after it started", snapshotCount == count,
+			fmt.Sprintf("snapshot counts %d products, %d before the insert", snapshotCount, count))
 
 		output <- scenario.StepResult{
 			Session:     "Session A",
 			Step:        step,
-			Description: "Session A reads product count AGAIN (still in same transaction)",
-			Query:       "db.snapshot_demo.countDocuments({})",
-			Result:      fmt.Sprintf("Product count: %d (SNAPSHOT - doesn't see new product!)", snapshotCount),
+			Description: i18n.T("Session A reads product count AGAIN (still in same transaction)"),
+			Role:        scenario.RoleReread,
+			Query:       "db.snapshot_demo.find({}, {_id: 1}).itcount()",
+			Result:      i18n.T("Product count: %d (SNAPSHOT - doesn't see new product!)", snapshotCount),
 			Success:     true,
 		}
 		step++
 
 		output <- scenario.StepResult{
 			IsHeader:    true,
-			Description: "✅ Snapshot isolation in action! Session A still sees 3 products, even though Session B committed 4th",
+			Description: i18n.T("✅ Snapshot isolation in action! Session A still sees 3 products, even though Session B committed 4th"),
 		}
 
 		// Commit Session A's transaction
@@ -228,33 +295,37 @@ func (s *SnapshotIsolationScenario) Run(ctx context.Context, output chan<- scena
 	output <- scenario.StepResult{
 		Session:     "Session A",
 		Step:        step,
-		Description: "Committing Session A's transaction",
+		Description: i18n.T("Committing Session A's transaction"),
+		Role:        scenario.RoleCommit,
 		Query:       "session.commitTransaction()",
-		Result:      "Transaction committed - snapshot released",
+		Result:      i18n.T("Transaction committed - snapshot released"),
 		Success:     true,
 	}
 	step++
 
-	time.Sleep(500 * time.Millisecond)
+	scenario.Pause(ctx)
 
 	// Now read outside any transaction
 	finalCount, err := s.collection.CountDocuments(ctx, bson.M{})
 	if err != nil {
 		return fmt.Errorf("failed to count final: %w", err)
 	}
+	scenario.Assert(ctx, "reads after the transaction see the insert", finalCount == count+1,
+		fmt.Sprintf("%d products after commit", finalCount))
 
 	output <- scenario.StepResult{
 		Session:     "Session A",
 		Step:        step,
-		Description: "Session A reads after transaction ends",
+		Description: i18n.T("Session A reads after transaction ends"),
+		Role:        scenario.RoleVerify,
 		Query:       "db.snapshot_demo.countDocuments({})",
-		Result:      fmt.Sprintf("Product count: %d (Now sees all products including Ultra Gadget)", finalCount),
+		Result:      i18n.T("Product count: %d (Now sees all products including Ultra Gadget)", finalCount),
 		Success:     true,
 	}
 
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "🎉 Snapshot isolation provides a consistent view throughout the entire transaction",
+		Description: i18n.T("🎉 Snapshot isolation provides a consistent view throughout the entire transaction"),
 	}
 
 	return nil
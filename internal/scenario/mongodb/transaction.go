@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// transaction holds a session and its context for the whole life of a transaction, so a scenario
+// can run operations in it between any of its steps
+type transaction struct {
+	session Session
+	ctx     context.Context // Context operations must use to run inside the transaction
+	done    bool            // Whether the transaction was committed or aborted
+}
+
+// startTransaction starts a session and a transaction in it. End must be called once the
+// scenario is done with it, whichever way it finishes.
+func (c *dbConn) startTransaction(ctx context.Context, opts ...*options.TransactionOptions) (*transaction, error) {
+	session, err := c.db.StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	if err := session.StartTransaction(opts...); err != nil {
+		session.EndSession(ctx)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	return &transaction{session: session, ctx: session.Context(ctx)}, nil
+}
+
+// Commit commits the transaction
+func (t *transaction) Commit() error {
+	t.done = true
+	return t.session.CommitTransaction(t.ctx)
+}
+
+// Abort aborts the transaction
+func (t *transaction) Abort() error {
+	t.done = true
+	return t.session.AbortTransaction(t.ctx)
+}
+
+// End aborts the transaction unless it was committed or aborted, e.g. when a step failed or the
+// run was cancelled, and ends the session
+func (t *transaction) End(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+	if !t.done {
+		_ = t.session.AbortTransaction(ctx)
+	}
+	t.session.EndSession(ctx)
+}
+
+// countByFind counts the documents matching filter by iterating a find cursor over their ids.
+// Unlike countDocuments, which runs an aggregation, a plain find is allowed in transactions on every
+// supported server version and reads the transaction's snapshot the same way as any other read.
+func countByFind(ctx context.Context, coll Collection, filter any) (int64, error) {
+	docs, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(docs)), nil
+}
+
+// withSession calls fn with ctx bound to session, so its operations run in the session's transaction
+func withSession(ctx context.Context, session Session, fn func(sc context.Context) error) error {
+	return fn(session.Context(ctx))
+}
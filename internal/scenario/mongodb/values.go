@@ -0,0 +1,29 @@
+package mongodb
+
+// number converts a numeric BSON value decoded into an interface to float64
+func number(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return 0
+}
+
+// cents converts an amount of cents decoded from BSON into an interface to int64
+func cents(v any) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int32:
+		return int64(n)
+	case int:
+		return int64(n)
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// verified returns step as narrated when the state it claims was read back, and otherwise fails it,
+// showing what was observed instead. Steps narrating a mutation go through it, so a server that
+// behaves differently never gets a story told about it that didn't happen.
+func verified(step scenario.StepResult, held bool, observed string) scenario.StepResult {
+	if held {
+		return step
+	}
+	step.Result = i18n.T("❌ Expected: %s\nObserved: %s", step.Result, observed)
+	step.Success = false
+	return step
+}
+
+// balanceOf reads the balance in cents of the document matching filter, as ctx sees it: inside a
+// session's transaction with its context, or the committed data otherwise
+func balanceOf(ctx context.Context, coll Collection, filter bson.M) (int64, error) {
+	doc, err := coll.FindOne(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read back the balance: %w", err)
+	}
+	return cents(doc["balanceCents"]), nil
+}
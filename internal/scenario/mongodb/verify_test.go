@@ -0,0 +1,24 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+func TestVerified(t *testing.T) {
+	step := scenario.StepResult{Session: "Session B", Result: "Balance now $300", Success: true}
+
+	if got := verified(step, true, "Balance: $300.00"); got != step {
+		t.Fatalf("Expected an observed state to leave the step alone, got %+v", got)
+	}
+
+	got := verified(step, false, "Balance: $1,000.00")
+	if got.Success {
+		t.Fatal("Expected a state that wasn't observed to fail the step")
+	}
+	if !strings.Contains(got.Result, "Balance now $300") || !strings.Contains(got.Result, "Balance: $1,000.00") {
+		t.Fatalf("Expected the narration and the observation, got %q", got.Result)
+	}
+}
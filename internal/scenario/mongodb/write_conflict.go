@@ -2,13 +2,15 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
@@ -16,17 +18,20 @@ import (
 
 // WriteConflictScenario demonstrates write conflicts in concurrent transactions
 type WriteConflictScenario struct {
-	client     *mongo.Client
-	db         *mongo.Database
-	collection *mongo.Collection
+	dbConn
+	failPoints bool       // The server has fail points, so Session A's update conflicts every run
+	failPoint  *failPoint // Turned on for Session A's update; nil while off
 }
 
 // NewWriteConflictScenario creates a new write conflict demonstration scenario
-func NewWriteConflictScenario(client *mongo.Client, db *mongo.Database) *WriteConflictScenario {
+func NewWriteConflictScenario(handle Handle) *WriteConflictScenario {
+	return newWriteConflictScenario(Driver(handle))
+}
+
+// newWriteConflictScenario creates the scenario on the database open resolves
+func newWriteConflictScenario(open DatabaseHandle) *WriteConflictScenario {
 	return &WriteConflictScenario{
-		client:     client,
-		db:         db,
-		collection: db.Collection("write_conflict_demo"),
+		dbConn: newDBConn(open, "write_conflict_demo"),
 	}
 }
 
@@ -35,7 +40,7 @@ func (s *WriteConflictScenario) Name() string {
 }
 
 func (s *WriteConflictScenario) Description() string {
-	return `Demonstrates how MongoDB detects and handles write conflicts between transactions.
+	return i18n.T(`Demonstrates how MongoDB detects and handles write conflicts between transactions.
 
 When two transactions try to modify the same document:
 - The first transaction to commit wins
@@ -47,46 +52,117 @@ This scenario shows:
 2. Session A starts transaction, reads balance, prepares to withdraw $600
 3. Session B starts transaction, reads balance, withdraws $700 and COMMITS
 4. Session A tries to commit its $600 withdrawal
-5. WriteConflict! Session A must retry with new balance`
+5. WriteConflict! Session A must retry with new balance`)
 }
 
 func (s *WriteConflictScenario) IsolationLevel() string {
 	return "Serializable (Write Conflicts)"
 }
 
+func (s *WriteConflictScenario) Requires() []scenario.Capability {
+	return []scenario.Capability{
+		scenario.CapMultiDocumentTransactions,
+		scenario.CapSnapshotReads,
+	}
+}
+
+// Metadata returns listing tags and the typical run time
+func (s *WriteConflictScenario) Metadata() scenario.Metadata {
+	return scenario.Metadata{Tags: []string{"write-conflict", "concurrency"}, EstimatedDuration: 3 * time.Second}
+}
+
+// AnomalyKey identifies the scenario for comparisons across providers
+func (s *WriteConflictScenario) AnomalyKey() string {
+	return scenario.AnomalyLostUpdate
+}
+
+// Quiz asks about the run that just finished
+func (s *WriteConflictScenario) Quiz() []scenario.Question {
+	return []scenario.Question{
+		{
+			Prompt: i18n.T("Why was Session A's withdrawal rejected?"),
+			Choices: []string{
+				i18n.T("The account did not have enough money"),
+				i18n.T("Session B committed a change to the same document after Session A's transaction started"),
+				i18n.T("Session A's transaction timed out"),
+			},
+			Answer:      1,
+			Explanation: i18n.T("Session B withdrew $700 and committed at steps 5 and 6. Session A's update at step 7 touched the same document, so its transaction failed with a WriteConflict at step 8."),
+			Steps:       []int{5, 6, 7, 8},
+		},
+		{
+			Prompt: i18n.T("How should an application handle the WriteConflict?"),
+			Choices: []string{
+				i18n.T("Retry the whole transaction, reading the balance again"),
+				i18n.T("Retry only the failed update in the same transaction"),
+				i18n.T("Ignore it - the update was applied anyway"),
+			},
+			Answer:      0,
+			Explanation: i18n.T("The error carries the TransientTransactionError label, and Session A aborts its transaction. A retry reads the new balance of $300, so the $600 withdrawal is refused instead of overdrawing the account."),
+			Steps:       []int{8, 9, 10},
+		},
+	}
+}
+
 func (s *WriteConflictScenario) Setup(ctx context.Context) error {
+	if err := s.resolve(); err != nil {
+		return err
+	}
+
 	// Drop and recreate with initial data
-	if err := s.collection.Drop(ctx); err != nil {
+	if err := dropCollection(ctx, s.collection); err != nil {
 		return err
 	}
 
 	// Insert account with balance
 	_, err := s.collection.InsertOne(ctx, bson.M{
-		"accountId": "ACC-12345",
-		"holder":    "John Doe",
-		"balance":   1000.00,
+		"accountId":    "ACC-12345",
+		"holder":       "John Doe",
+		"balanceCents": int64(100000), // Money is kept in integer cents, so it adds up exactly
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.failPoints = failPointsAvailable(ctx, s.db)
+	if !s.failPoints {
+		slog.InfoContext(ctx, "fail points unavailable; the write conflict relies on timing")
+	}
+	return nil
 }
 
 func (s *WriteConflictScenario) Cleanup(ctx context.Context) error {
-	return s.collection.Drop(ctx)
+	// Turned off first, so even a run stopped during Session A's update doesn't leave it armed
+	failPointErr := s.failPoint.disable(ctx)
+	s.failPoint = nil
+	if err := s.resolve(); err != nil {
+		return errors.Join(failPointErr, err)
+	}
+
+	return errors.Join(failPointErr, dropCollection(ctx, s.collection))
 }
 
 func (s *WriteConflictScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
-	defer close(output)
+	if err := s.resolve(); err != nil {
+		return err
+	}
 
 	// Header
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "⚔️ Write Conflict Detection Demonstration",
+		Description: i18n.T("⚔️ Write Conflict Detection Demonstration"),
+	}
+	if !s.failPoints {
+		output <- scenario.StepResult{
+			IsHeader:    true,
+			Description: i18n.T("⚠️ The server has no fail points, so whether the conflict shows depends on timing"),
+		}
 	}
 
 	step := 1
 
 	// Step 1: Show initial state
-	var initial bson.M
-	err := s.collection.FindOne(ctx, bson.M{"accountId": "ACC-12345"}).Decode(&initial)
+	initial, err := s.collection.FindOne(ctx, bson.M{"accountId": "ACC-12345"})
 	if err != nil {
 		return fmt.Errorf("failed to read initial: %w", err)
 	}
@@ -94,15 +170,15 @@ func (s *WriteConflictScenario) Run(ctx context.Context, output chan<- scenario.
 	output <- scenario.StepResult{
 		Session:     "Setup",
 		Step:        step,
-		Description: "Initial account state",
+		Description: i18n.T("Initial account state"),
 		Query:       `db.write_conflict_demo.findOne({accountId: "ACC-12345"})`,
-		Result:      fmt.Sprintf("Account: %s, Balance: $%.2f", initial["holder"], initial["balance"]),
+		Result:      i18n.T("Account: %s, Balance: %s", initial["holder"], i18n.Money(cents(initial["balanceCents"]))),
 		Success:     true,
 	}
 	step++
 
 	// Step 2: Session A starts transaction and reads balance
-	sessionA, err := s.client.StartSession()
+	sessionA, err := s.db.StartSession()
 	if err != nil {
 		return fmt.Errorf("failed to start session A: %w", err)
 	}
@@ -113,7 +189,7 @@ func (s *WriteConflictScenario) Run(ctx context.Context, output chan<- scenario.
 		SetWriteConcern(writeconcern.Majority())
 
 	// Start Session A transaction but don't commit yet
-	err = mongo.WithSession(ctx, sessionA, func(sc mongo.SessionContext) error {
+	err = withSession(ctx, sessionA, func(sc context.Context) error {
 		if err := sessionA.StartTransaction(txnOpts); err != nil {
 			return err
 		}
@@ -121,50 +197,53 @@ func (s *WriteConflictScenario) Run(ctx context.Context, output chan<- scenario.
 		output <- scenario.StepResult{
 			Session:     "Session A",
 			Step:        step,
-			Description: "Starting transaction (snapshot isolation)",
+			Description: i18n.T("Starting transaction (snapshot isolation)"),
+			Role:        scenario.RoleBegin,
 			Query:       "session.startTransaction({readConcern: 'snapshot'})",
-			Result:      "Transaction started - preparing $600 withdrawal",
+			Result:      i18n.T("Transaction started - preparing $600 withdrawal"),
 			Success:     true,
 		}
 		step++
 
 		// Read balance
-		var acct bson.M
-		if err := s.collection.FindOne(sc, bson.M{"accountId": "ACC-12345"}).Decode(&acct); err != nil {
+		acct, err := s.collection.FindOne(sc, bson.M{"accountId": "ACC-12345"})
+		if err != nil {
 			return err
 		}
 
 		output <- scenario.StepResult{
 			Session:     "Session A",
 			Step:        step,
-			Description: "Reading current balance",
+			Description: i18n.T("Reading current balance"),
+			Role:        scenario.RoleRead,
 			Query:       `db.write_conflict_demo.findOne({accountId: "ACC-12345"})`,
-			Result:      fmt.Sprintf("Balance: $%.2f - Will withdraw $600", acct["balance"]),
+			Result:      i18n.T("Balance: %s - Will withdraw $600", i18n.Money(cents(acct["balanceCents"]))),
 			Success:     true,
 		}
 		step++
 
-		time.Sleep(500 * time.Millisecond)
+		scenario.Pause(ctx)
 
 		// Session B jumps in and completes its transaction first
 		output <- scenario.StepResult{
 			Session:     "Session B",
 			Step:        step,
-			Description: "Starting SEPARATE transaction",
+			Description: i18n.T("Starting SEPARATE transaction"),
+			Role:        scenario.RoleBegin,
 			Query:       "session.startTransaction({readConcern: 'snapshot'})",
-			Result:      "Transaction started - will withdraw $700",
+			Result:      i18n.T("Transaction started - will withdraw $700"),
 			Success:     true,
 		}
 		step++
 
 		// Session B's transaction
-		sessionB, err := s.client.StartSession()
+		sessionB, err := s.db.StartSession()
 		if err != nil {
 			return fmt.Errorf("failed to start session B: %w", err)
 		}
 		defer sessionB.EndSession(ctx)
 
-		err = mongo.WithSession(ctx, sessionB, func(scB mongo.SessionContext) error {
+		err = withSession(ctx, sessionB, func(scB context.Context) error {
 			if err := sessionB.StartTransaction(txnOpts); err != nil {
 				return err
 			}
@@ -172,20 +251,25 @@ func (s *WriteConflictScenario) Run(ctx context.Context, output chan<- scenario.
 			// Session B withdraws $700
 			_, err := s.collection.UpdateOne(scB,
 				bson.M{"accountId": "ACC-12345"},
-				bson.M{"$inc": bson.M{"balance": -700.00}},
+				bson.M{"$inc": bson.M{"balanceCents": int64(-70000)}},
 			)
 			if err != nil {
 				return err
 			}
+			balance, err := balanceOf(scB, s.collection, bson.M{"accountId": "ACC-12345"})
+			if err != nil {
+				return err
+			}
 
-			output <- scenario.StepResult{
+			output <- verified(scenario.StepResult{
 				Session:     "Session B",
 				Step:        step,
-				Description: "Withdrawing $700 from account",
-				Query:       `db.write_conflict_demo.updateOne({accountId: "ACC-12345"}, {$inc: {balance: -700}})`,
-				Result:      "Update applied in transaction",
+				Description: i18n.T("Withdrawing $700 from account"),
+				Role:        scenario.RoleConcurrentWrite,
+				Query:       `db.write_conflict_demo.updateOne({accountId: "ACC-12345"}, {$inc: {balanceCents: NumberLong(-70000)}})`,
+				Result:      i18n.T("Update applied in transaction"),
 				Success:     true,
-			}
+			}, balance == cents(initial["balanceCents"])-70000, i18n.T("Balance: %s", i18n.Money(balance)))
 			step++
 
 			// Commit Session B
@@ -194,91 +278,161 @@ func (s *WriteConflictScenario) Run(ctx context.Context, output chan<- scenario.
 		if err != nil {
 			return fmt.Errorf("session B failed: %w", err)
 		}
+		committed, err := balanceOf(ctx, s.collection, bson.M{"accountId": "ACC-12345"})
+		if err != nil {
+			return err
+		}
 
-		output <- scenario.StepResult{
+		output <- verified(scenario.StepResult{
 			Session:     "Session B",
 			Step:        step,
-			Description: "Committing transaction",
+			Description: i18n.T("Committing transaction"),
+			Role:        scenario.RoleCommit,
 			Query:       "session.commitTransaction()",
-			Result:      "✓ Transaction committed! Balance now $300",
+			Result:      i18n.T("✓ Transaction committed! Balance now $300"),
 			Success:     true,
-		}
+		}, committed == 30000, i18n.T("Balance: %s", i18n.Money(committed)))
 		step++
 
-		time.Sleep(500 * time.Millisecond)
+		scenario.Pause(ctx)
 
 		// Session A now tries to do its update
 		output <- scenario.StepResult{
 			Session:     "Session A",
 			Step:        step,
-			Description: "Now attempting to withdraw $600 (Session A's original plan)",
-			Query:       `db.write_conflict_demo.updateOne({accountId: "ACC-12345"}, {$inc: {balance: -600}})`,
-			Result:      "Attempting update...",
+			Description: i18n.T("Now attempting to withdraw $600 (Session A's original plan)"),
+			Role:        scenario.RoleWrite,
+			Query:       `db.write_conflict_demo.updateOne({accountId: "ACC-12345"}, {$inc: {balanceCents: NumberLong(-60000)}})`,
+			Result:      i18n.T("Attempting update..."),
 			Success:     true,
 		}
 		step++
 
-		// This should cause a write conflict
-		_, err = s.collection.UpdateOne(sc,
+		// This should cause a write conflict: Session B changed the document after Session A's
+		// snapshot. The fail point makes sure it does whatever the timing.
+		if s.failPoints {
+			s.failPoint, err = failNextUpdateWithWriteConflict(ctx, s.db, s.collectionName)
+			if err != nil {
+				slog.InfoContext(ctx, "fail point refused; the write conflict relies on timing", "error", err)
+			}
+		}
+		_, updateErr := s.collection.UpdateOne(sc,
 			bson.M{"accountId": "ACC-12345"},
-			bson.M{"$inc": bson.M{"balance": -600.00}},
+			bson.M{"$inc": bson.M{"balanceCents": int64(-60000)}},
 		)
+		logDriverError(ctx, "updateOne", updateErr)
+		if err := s.failPoint.disable(ctx); err != nil {
+			return err
+		}
+		s.failPoint = nil
+
+		if updateErr != nil {
+			// A transaction whose operation failed can't commit, so end it explicitly
+			abortErr := sessionA.AbortTransaction(sc)
+			logDriverError(ctx, "abortTransaction", abortErr)
+			if !isWriteConflict(updateErr) {
+				return fmt.Errorf("session A update failed: %w", updateErr)
+			}
+			scenario.Assert(ctx, "second writer to the same document is rejected", true, "")
 
-		// Try to commit - this will fail with write conflict
-		commitErr := sessionA.CommitTransaction(sc)
-
-		if commitErr != nil || err != nil {
 			output <- scenario.StepResult{
 				Session:     "Session A",
 				Step:        step,
-				Description: "Attempting to commit transaction",
-				Query:       "session.commitTransaction()",
-				Result:      "❌ WriteConflict! Document was modified by another transaction",
+				Description: i18n.T("Update rejected"),
+				Query:       `db.write_conflict_demo.updateOne({accountId: "ACC-12345"}, {$inc: {balanceCents: NumberLong(-60000)}})`,
+				Result:      i18n.T("❌ WriteConflict! Document was modified by another transaction (%s)", labelsDetail(updateErr)),
 				Success:     false,
 			}
 			step++
 
+			output <- scenario.StepResult{
+				Session:     "Session A",
+				Step:        step,
+				Description: i18n.T("Aborting the transaction"),
+				Role:        scenario.RoleAbort,
+				Query:       "session.abortTransaction()",
+				Result:      abortResult(abortErr),
+				Success:     abortErr == nil,
+			}
+			step++
+
 			output <- scenario.StepResult{
 				IsHeader:    true,
-				Description: "🛡️ Write conflict detected! Session A's withdrawal prevented to avoid overdraft",
+				Description: i18n.T("🛡️ Write conflict detected! Session A's withdrawal prevented to avoid overdraft"),
 			}
-		} else {
-			// In case it somehow succeeded (shouldn't happen with snapshot isolation)
+			return nil
+		}
+
+		// Some servers only detect the conflict when committing
+		commitErr := sessionA.CommitTransaction(sc)
+		logDriverError(ctx, "commitTransaction", commitErr)
+		if commitErr != nil {
+			if !isWriteConflict(commitErr) {
+				return fmt.Errorf("session A commit failed: %w", commitErr)
+			}
+			scenario.Assert(ctx, "second writer to the same document is rejected", true, "")
+
 			output <- scenario.StepResult{
 				Session:     "Session A",
 				Step:        step,
-				Description: "Transaction result",
+				Description: i18n.T("Attempting to commit transaction"),
+				Role:        scenario.RoleCommit,
 				Query:       "session.commitTransaction()",
-				Result:      "Transaction completed (conflict handling may vary by timing)",
-				Success:     true,
+				Result:      i18n.T("❌ Commit failed with WriteConflict (%s)", labelsDetail(commitErr)),
+				Success:     false,
 			}
 			step++
+
+			output <- scenario.StepResult{
+				IsHeader:    true,
+				Description: i18n.T("🛡️ Write conflict detected! Session A's withdrawal prevented to avoid overdraft"),
+			}
+			return nil
+		}
+
+		scenario.Assert(ctx, "second writer to the same document is rejected", false,
+			"the update and the commit both succeeded")
+		output <- scenario.StepResult{
+			Session:     "Session A",
+			Step:        step,
+			Description: i18n.T("Attempting to commit transaction"),
+			Role:        scenario.RoleCommit,
+			Query:       "session.commitTransaction()",
+			Result:      i18n.T("Transaction committed - the conflict was not detected"),
+			Success:     false,
 		}
+		step++
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	time.Sleep(500 * time.Millisecond)
+	scenario.Pause(ctx)
 
 	// Show final state
-	var final bson.M
-	err = s.collection.FindOne(ctx, bson.M{"accountId": "ACC-12345"}).Decode(&final)
+	final, err := s.collection.FindOne(ctx, bson.M{"accountId": "ACC-12345"})
 	if err != nil {
 		return fmt.Errorf("failed to read final state: %w", err)
 	}
+	scenario.Assert(ctx, "only the first committed withdrawal is applied",
+		cents(final["balanceCents"]) == cents(initial["balanceCents"])-70000,
+		fmt.Sprintf("final balance %s", i18n.Money(cents(final["balanceCents"]))))
 
 	output <- scenario.StepResult{
 		Session:     "Result",
 		Step:        step,
-		Description: "Final account state",
+		Description: i18n.T("Final account state"),
+		Role:        scenario.RoleVerify,
 		Query:       `db.write_conflict_demo.findOne({accountId: "ACC-12345"})`,
-		Result:      fmt.Sprintf("Balance: $%.2f (Only Session B's $700 withdrawal applied)", final["balance"]),
+		Result:      i18n.T("Balance: %s (Only Session B's $700 withdrawal applied)", i18n.Money(cents(final["balanceCents"]))),
 		Success:     true,
 	}
 
 	output <- scenario.StepResult{
 		IsHeader:    true,
-		Description: "🎉 Write conflict detection prevented a potential $300 overdraft!",
+		Description: i18n.T("🎉 Write conflict detection prevented a potential $300 overdraft!"),
 	}
 
 	return nil
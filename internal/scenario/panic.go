@@ -0,0 +1,31 @@
+package scenario
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is a panic raised while running a scenario, converted into an error
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error describes the panic value
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("scenario panicked: %v", e.Value)
+}
+
+// Detail returns the stack trace captured where the panic was recovered
+func (e *PanicError) Detail() string {
+	return string(e.Stack)
+}
+
+// Recover converts a panic into a *PanicError stored in err. Use it directly as a deferred call:
+//
+//	defer scenario.Recover(&err)
+func Recover(err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Value: r, Stack: debug.Stack()}
+	}
+}
@@ -0,0 +1,118 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// DatasetSize selects how much seed data range-oriented scenarios generate
+type DatasetSize string
+
+const (
+	DatasetSmall  DatasetSize = "small"
+	DatasetMedium DatasetSize = "medium"
+	DatasetLarge  DatasetSize = "large"
+)
+
+// DatasetSizes lists the dataset sizes in display order
+var DatasetSizes = []DatasetSize{DatasetSmall, DatasetMedium, DatasetLarge}
+
+// ParseDatasetSize converts a name into a DatasetSize
+func ParseDatasetSize(s string) (DatasetSize, error) {
+	for _, d := range DatasetSizes {
+		if string(d) == s {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("unknown dataset size %q (valid: small, medium, large)", s)
+}
+
+// Count returns the number of records generated for the size
+func (d DatasetSize) Count() int {
+	switch d {
+	case DatasetMedium:
+		return 1000
+	case DatasetLarge:
+		return 100000
+	}
+	return 10
+}
+
+// DefaultPacing is the pause between narrated steps, long enough to follow along
+const DefaultPacing = 500 * time.Millisecond
+
+// defaultPacing is the pacing of DefaultParams, see SetDefaultPacing
+var defaultPacing atomic.Int64
+
+func init() {
+	defaultPacing.Store(int64(DefaultPacing))
+}
+
+// SetDefaultPacing sets the pacing DefaultParams returns; 0 or less runs at full speed.
+// It is meant to be set once at startup, before providers are created.
+func SetDefaultPacing(d time.Duration) {
+	defaultPacing.Store(int64(max(d, 0)))
+}
+
+// DefaultSeed keeps generated data identical between runs unless another seed is chosen
+const DefaultSeed = 42
+
+// Params carries user-chosen options into scenario Setup and Run
+type Params struct {
+	DatasetSize DatasetSize
+
+	// Pacing is the pause between narrated steps; 0 runs at full speed, e.g. in CI
+	Pacing time.Duration
+
+	// Seed makes everything random in a run reproducible, see Rand
+	Seed int64
+
+	// Timeout bounds every run, overriding the scenarios' own limits; 0 lets each scenario's
+	// metadata decide, see TimeoutOf
+	Timeout time.Duration
+}
+
+// DefaultParams returns the params used when none were chosen
+func DefaultParams() Params {
+	return Params{DatasetSize: DatasetSmall, Pacing: time.Duration(defaultPacing.Load()), Seed: DefaultSeed}
+}
+
+// Rand returns a source of randomness seeded with Seed. Scenarios use it instead of the global
+// rand functions so a run with the same seed generates the same data and delays.
+func (p Params) Rand() *rand.Rand {
+	return rand.New(rand.NewSource(p.Seed))
+}
+
+type paramsKey struct{}
+
+// WithParams returns a context carrying params for the scenario being run
+func WithParams(ctx context.Context, p Params) context.Context {
+	return context.WithValue(ctx, paramsKey{}, p)
+}
+
+// ParamsFromContext returns the params attached to ctx, or the defaults
+func ParamsFromContext(ctx context.Context) Params {
+	if p, ok := ctx.Value(paramsKey{}).(Params); ok {
+		return p
+	}
+	return DefaultParams()
+}
+
+// Pause waits for the pacing configured in ctx's params so steps can be followed,
+// returning early when ctx is done
+func Pause(ctx context.Context) {
+	d := ParamsFromContext(ctx).Pacing
+	if d <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
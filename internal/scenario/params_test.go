@@ -0,0 +1,60 @@
+package scenario
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParamsFromContext(t *testing.T) {
+	if got := ParamsFromContext(context.Background()); got.DatasetSize != DatasetSmall {
+		t.Fatalf("Expected default dataset size, got %q", got.DatasetSize)
+	}
+
+	ctx := WithParams(context.Background(), Params{DatasetSize: DatasetLarge})
+	if got := ParamsFromContext(ctx); got.DatasetSize != DatasetLarge {
+		t.Fatalf("Expected large dataset size, got %q", got.DatasetSize)
+	}
+}
+
+func TestGenerateDataset(t *testing.T) {
+	for _, size := range DatasetSizes {
+		records := GenerateDataset(size, DefaultParams().Rand())
+		if len(records) != size.Count() {
+			t.Fatalf("Expected %d records for %s, got %d", size.Count(), size, len(records))
+		}
+		for _, r := range records {
+			if r.Amount < 1 || r.Amount > 200 {
+				t.Fatalf("Amount out of range: %v", r.Amount)
+			}
+		}
+	}
+
+	// Same seed, same data; another seed, other data
+	params := Params{Seed: 7}
+	a, b := GenerateDataset(DatasetMedium, params.Rand()), GenerateDataset(DatasetMedium, params.Rand())
+	if a[500] != b[500] {
+		t.Fatalf("Expected deterministic records, got %+v and %+v", a[500], b[500])
+	}
+	params.Seed = 8
+	if c := GenerateDataset(DatasetMedium, params.Rand()); c[500] == a[500] {
+		t.Fatalf("Expected another seed to change the records, got %+v twice", c[500])
+	}
+}
+
+func TestSetDefaultPacing(t *testing.T) {
+	t.Cleanup(func() { SetDefaultPacing(DefaultPacing) })
+
+	SetDefaultPacing(0)
+	if got := DefaultParams().Pacing; got != 0 {
+		t.Fatalf("Expected full speed, got %s", got)
+	}
+	SetDefaultPacing(-time.Second)
+	if got := DefaultParams().Pacing; got != 0 {
+		t.Fatalf("Expected a negative pacing to run at full speed, got %s", got)
+	}
+	SetDefaultPacing(2 * time.Second)
+	if got := ParamsFromContext(context.Background()).Pacing; got != 2*time.Second {
+		t.Fatalf("Expected the default pacing in the context fallback, got %s", got)
+	}
+}
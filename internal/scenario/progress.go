@@ -0,0 +1,26 @@
+package scenario
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SetupProgressFunc receives a human-readable description of what Setup is doing, e.g. how far
+// seeding got
+type SetupProgressFunc func(stage string)
+
+type setupProgressKey struct{}
+
+// WithSetupProgress returns a context whose setup progress is reported to fn. fn may be called
+// from several goroutines at once and must not block.
+func WithSetupProgress(ctx context.Context, fn SetupProgressFunc) context.Context {
+	return context.WithValue(ctx, setupProgressKey{}, fn)
+}
+
+// ReportSetup logs a setup stage and announces it to the context's progress callback, if any
+func ReportSetup(ctx context.Context, stage string) {
+	slog.DebugContext(ctx, "scenario setup progress", "stage", stage)
+	if fn, ok := ctx.Value(setupProgressKey{}).(SetupProgressFunc); ok && fn != nil {
+		fn(stage)
+	}
+}
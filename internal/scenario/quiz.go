@@ -0,0 +1,24 @@
+package scenario
+
+// Question is a multiple-choice question asked after a scenario ran
+type Question struct {
+	Prompt      string
+	Choices     []string
+	Answer      int    // Index of the correct choice
+	Explanation string // Why the answer is correct
+	Steps       []int  // Steps of the run the explanation refers to
+}
+
+// Quizzer is implemented by scenarios that quiz the user about what a run showed
+type Quizzer interface {
+	// Quiz returns a few questions about the scenario
+	Quiz() []Question
+}
+
+// QuizOf returns a scenario's questions, or nil if it has none
+func QuizOf(s Scenario) []Question {
+	if q, ok := s.(Quizzer); ok {
+		return q.Quiz()
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package scenario
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -60,3 +62,49 @@ func TestRegistry_Clear(t *testing.T) {
 		t.Fatalf("Expected 1 scenario, got %d", len(r.GetAll()))
 	}
 }
+
+func TestRegistry_ConcurrentRegisterAndGetAll(t *testing.T) {
+	r := NewRegistry()
+
+	const writers, perWriter = 4, 50
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				r.Register(&MockScenario{name: fmt.Sprintf("Scenario %d-%d", w, i)})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range perWriter {
+				all := r.GetAll()
+				if len(all) > 0 {
+					all[0] = nil // Must not reach the registry
+				}
+				r.GetByName("Scenario 0-0")
+				r.Suggest("scenario")
+			}
+		}()
+	}
+	wg.Wait()
+
+	all := r.GetAll()
+	if len(all) != writers*perWriter {
+		t.Fatalf("Expected %d scenarios, got %d", writers*perWriter, len(all))
+	}
+	// Each writer's scenarios keep the order they were registered in
+	next := make(map[int]int)
+	for _, s := range all {
+		if s == nil {
+			t.Fatal("Expected GetAll to return a copy, got the registry's own slice")
+		}
+		var w, i int
+		fmt.Sscanf(s.Name(), "Scenario %d-%d", &w, &i)
+		if i != next[w] {
+			t.Fatalf("Expected scenario %d-%d next, got %s", w, next[w], s.Name())
+		}
+		next[w]++
+	}
+}
@@ -2,17 +2,48 @@ package scenario
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/slug"
 )
 
 // StepResult represents the result of a single step in a scenario
 type StepResult struct {
-	Session     string // Which session/transaction this step belongs to (e.g., "Session A", "Session B")
-	Step        int
-	Description string
-	Query       string // The operation being performed
-	Result      string // The result of the operation
-	Success     bool
-	IsHeader    bool // Whether this is a section header
+	Session     string `json:"session,omitempty"` // Which session/transaction this step belongs to (e.g., "Session A", "Session B")
+	Step        int    `json:"step"`
+	Description string `json:"description"`
+	Query       string `json:"query,omitempty"`  // The operation being performed
+	Result      string `json:"result,omitempty"` // The result of the operation
+	Success     bool   `json:"success"`
+	IsHeader    bool   `json:"header,omitempty"` // Whether this is a section header
+	Role        string `json:"role,omitempty"`   // What the step does in the demonstration, e.g. RoleReread
+
+	// OriginalStep is the number the scenario gave a step Execute renumbered because it repeated or
+	// went back; 0 when the step kept its number
+	OriginalStep int `json:"original_step,omitempty"`
+
+	// Pending marks a step still in progress, e.g. a session blocked on a lock. The session's next
+	// step with the same number is its outcome, which replaces it in place.
+	Pending bool `json:"pending,omitempty"`
+
+	// Waited is how long a step was pending before its outcome arrived; Execute sets it on the outcome
+	Waited time.Duration `json:"waited,omitempty"`
+}
+
+// SourceStep returns the number the scenario gave the step, which is what quizzes refer to
+func (r StepResult) SourceStep() int {
+	if r.OriginalStep != 0 {
+		return r.OriginalStep
+	}
+	return r.Step
+}
+
+// Completes returns whether r is the outcome of pending, a step still in progress
+func (r StepResult) Completes(pending StepResult) bool {
+	return pending.Pending && !r.Pending && !r.IsHeader && r.Session == pending.Session && r.Step == pending.Step
 }
 
 // Scenario defines the interface for transaction isolation demonstrations
@@ -29,16 +60,21 @@ type Scenario interface {
 	// Setup prepares any necessary data before running the scenario
 	Setup(ctx context.Context) error
 
-	// Run executes the scenario and sends step results to the output channel
+	// Run executes the scenario and sends step results to the output channel. A send blocks until the
+	// consumer catches up; under Execute it never blocks past the cancellation of ctx.
+	// Run must not close output: whoever calls Run closes it once Run returns, as Execute does.
 	Run(ctx context.Context, output chan<- StepResult) error
 
 	// Cleanup removes any data created during the scenario
 	Cleanup(ctx context.Context) error
 }
 
-// Registry holds all registered scenarios
+// Registry holds all registered scenarios. It is safe for concurrent use, and scenarios are
+// always listed in the order they were registered.
 type Registry struct {
+	mu        sync.RWMutex
 	scenarios []Scenario
+	slugs     []string // Slug of each scenario's name, in the same order
 }
 
 // NewRegistry creates a new scenario registry
@@ -50,25 +86,47 @@ func NewRegistry() *Registry {
 
 // Clear removes all registered scenarios
 func (r *Registry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.scenarios = make([]Scenario, 0)
+	r.slugs = nil
 }
 
-// Register adds a scenario to the registry
+// Register adds a scenario to the registry, after those already registered
 func (r *Registry) Register(s Scenario) {
+	name := slug.Make(s.Name())
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.scenarios = append(r.scenarios, s)
+	r.slugs = append(r.slugs, name)
 }
 
-// GetAll returns all registered scenarios
+// GetAll returns a copy of the registered scenarios in registration order
 func (r *Registry) GetAll() []Scenario {
-	return r.scenarios
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.scenarios)
 }
 
-// GetByName returns a scenario by name
+// GetByName returns a scenario by its name in any case or by its slug
 func (r *Registry) GetByName(name string) Scenario {
-	for _, s := range r.scenarios {
-		if s.Name() == name {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i, s := range r.scenarios {
+		if slug.Matches(name, s.Name(), r.slugs[i]) {
 			return s
 		}
 	}
 	return nil
 }
+
+// Suggest returns the slugs of the scenarios whose names are close to name, for when GetByName
+// finds none
+func (r *Registry) Suggest(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slug.Suggest(name, r.slugs)
+}
+
+// ErrProviderNotStarted is returned by scenarios used before their provider is running
+var ErrProviderNotStarted = errors.New("provider not started")
@@ -0,0 +1,266 @@
+// Package scenariotest checks the contract every scenario.Scenario shares, whatever its database.
+// A provider's tests call Conformance once per scenario, against a provider they started.
+package scenariotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// errClosed is returned by run when the scenario closed its output channel
+var errClosed = errors.New("Run closed its output channel")
+
+// CancelBound is how long Run may keep going once its context is cancelled. Overridable for slow
+// databases.
+var CancelBound = 5 * time.Second
+
+// Factory creates the scenario under test. Each check gets its own, so state a scenario keeps
+// between calls can't carry over from one check to the next.
+type Factory func() scenario.Scenario
+
+// Leftovers returns what of s is still in the database, e.g. the collections it owns that exist
+type Leftovers func(ctx context.Context, s scenario.Scenario) ([]string, error)
+
+// Option configures Conformance
+type Option func(*config)
+
+type config struct {
+	leftovers Leftovers
+	caps      scenario.CapabilitySet
+	params    scenario.Params
+}
+
+// WithLeftovers checks that Cleanup leaves nothing behind, as leftovers sees it. Without it the
+// check is skipped.
+func WithLeftovers(leftovers Leftovers) Option {
+	return func(c *config) {
+		c.leftovers = leftovers
+	}
+}
+
+// WithCapabilities skips scenarios requiring a capability missing from caps, as the app would
+// hide them
+func WithCapabilities(caps scenario.CapabilitySet) Option {
+	return func(c *config) {
+		c.caps = caps
+	}
+}
+
+// WithParams runs the scenario with p, e.g. a provider's ScenarioParams. Pacing is always
+// dropped, except where a check needs the scenario to still be running.
+func WithParams(p scenario.Params) Option {
+	return func(c *config) {
+		c.params = p
+	}
+}
+
+// Conformance runs the checks every scenario must pass, as subtests named after it:
+//   - Setup can run twice in a row
+//   - Run leaves its output channel open for its caller to close, and Execute closes it exactly
+//     once, whether the run completes or is cancelled
+//   - every step but headers has a session and a description, and step numbers never go back
+//   - Cleanup leaves nothing behind, when WithLeftovers tells how to look
+//   - Run returns within CancelBound of its context being cancelled
+func Conformance(t *testing.T, factory Factory, opts ...Option) {
+	t.Helper()
+	cfg := config{params: scenario.DefaultParams()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.params.Pacing = 0
+
+	name := factory().Name()
+	t.Run(name, func(t *testing.T) {
+		if cfg.caps != nil {
+			if missing := cfg.caps.Missing(scenario.Requirements(factory())); len(missing) > 0 {
+				t.Skipf("Requires %v", missing)
+			}
+		}
+		t.Run("SetupIsIdempotent", func(t *testing.T) { checkSetup(t, factory(), cfg) })
+		t.Run("Steps", func(t *testing.T) { checkSteps(t, factory(), cfg) })
+		t.Run("Cleanup", func(t *testing.T) { checkCleanup(t, factory(), cfg) })
+		t.Run("Execute", func(t *testing.T) { checkExecute(t, factory(), cfg) })
+		t.Run("Cancel", func(t *testing.T) { checkCancel(t, factory(), cfg) })
+	})
+}
+
+func checkSetup(t *testing.T, s scenario.Scenario, cfg config) {
+	ctx := scenario.WithParams(context.Background(), cfg.params)
+	for i := 1; i <= 2; i++ {
+		if err := s.Setup(ctx); err != nil {
+			t.Fatalf("Expected Setup %d to succeed, got %v", i, err)
+		}
+	}
+	defer cleanup(t, s)
+
+	steps, err := run(ctx, s)
+	if err != nil {
+		t.Fatalf("Expected Run after a repeated Setup to succeed, got %v", err)
+	}
+	if len(steps) == 0 {
+		t.Fatal("Expected Run after a repeated Setup to send steps")
+	}
+}
+
+func checkSteps(t *testing.T, s scenario.Scenario, cfg config) {
+	ctx := scenario.WithParams(context.Background(), cfg.params)
+	if err := s.Setup(ctx); err != nil {
+		t.Fatalf("Expected Setup to succeed, got %v", err)
+	}
+	defer cleanup(t, s)
+
+	steps, err := run(ctx, s)
+	if err != nil {
+		t.Fatalf("Expected Run to succeed, got %v", err)
+	}
+	last, sent := 0, 0
+	for i, step := range steps {
+		if step.IsHeader {
+			continue
+		}
+		sent++
+		if step.Session == "" || step.Description == "" {
+			t.Fatalf("Expected step %d to have a session and a description, got %+v", i, step)
+		}
+		// A step without a number is numbered by Execute, after the previous one. A number may
+		// repeat for the outcome of the step before, as in "attempting" and then "completed".
+		if step.Step == 0 {
+			continue
+		}
+		if step.Step < last {
+			t.Fatalf("Expected step numbers never to go back, got %d after %d", step.Step, last)
+		}
+		last = step.Step
+	}
+	if sent == 0 {
+		t.Fatal("Expected Run to send steps besides headers")
+	}
+}
+
+func checkCleanup(t *testing.T, s scenario.Scenario, cfg config) {
+	if cfg.leftovers == nil {
+		t.Skip("No way to look for leftovers")
+	}
+	ctx := scenario.WithParams(context.Background(), cfg.params)
+	if err := s.Setup(ctx); err != nil {
+		t.Fatalf("Expected Setup to succeed, got %v", err)
+	}
+	if _, err := run(ctx, s); err != nil {
+		t.Fatalf("Expected Run to succeed, got %v", err)
+	}
+	if err := s.Cleanup(ctx); err != nil {
+		t.Fatalf("Expected Cleanup to succeed, got %v", err)
+	}
+
+	left, err := cfg.leftovers(ctx, s)
+	if err != nil {
+		t.Fatalf("Failed to look for leftovers: %v", err)
+	}
+	if len(left) > 0 {
+		t.Fatalf("Expected Cleanup to leave nothing behind, got %v", left)
+	}
+}
+
+func checkExecute(t *testing.T, s scenario.Scenario, cfg config) {
+	ctx := scenario.WithParams(context.Background(), cfg.params)
+	output := make(chan scenario.StepResult, 10)
+	done := make(chan scenario.Outcome, 1)
+	go func() {
+		done <- scenario.Execute(ctx, s, output)
+	}()
+
+	// Ranging ends only once output is closed; a second close would panic in Execute
+	for range output {
+	}
+	outcome := <-done
+	if outcome.Err != nil {
+		t.Fatalf("Expected Execute to succeed, got %v", outcome.Err)
+	}
+	if outcome.Cleanup != nil {
+		t.Fatalf("Expected Cleanup to succeed, got %v", outcome.Cleanup)
+	}
+}
+
+func checkCancel(t *testing.T, s scenario.Scenario, cfg config) {
+	params := cfg.params
+	params.Pacing = scenario.DefaultPacing // Keeps the run going until it is cancelled
+	ctx, cancel := context.WithCancel(scenario.WithParams(context.Background(), params))
+	defer cancel()
+	if err := s.Setup(ctx); err != nil {
+		t.Fatalf("Expected Setup to succeed, got %v", err)
+	}
+	defer cleanup(t, s)
+
+	output := make(chan scenario.StepResult)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(ctx, output)
+	}()
+
+	var cancelled time.Time
+	for cancelled.IsZero() {
+		select {
+		case step := <-output:
+			if !step.IsHeader {
+				cancel()
+				cancelled = time.Now()
+			}
+		case <-done:
+			t.Skip("Finished before its first step; nothing to cancel")
+		}
+	}
+
+	// Steps are still taken, so only the scenario itself can keep Run from returning
+	timeout := time.After(CancelBound)
+	for {
+		select {
+		case <-output:
+		case <-done:
+			return
+		case <-timeout:
+			t.Fatalf("Expected Run to return within %s of cancellation, still running after %s",
+				CancelBound, time.Since(cancelled))
+		}
+	}
+}
+
+// run runs s to completion, returning the steps it sent, or errClosed when Run closed output,
+// which is its caller's to close
+func run(ctx context.Context, s scenario.Scenario) ([]scenario.StepResult, error) {
+	output := make(chan scenario.StepResult)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(ctx, output)
+	}()
+
+	var steps []scenario.StepResult
+	for {
+		select {
+		case step, ok := <-output:
+			if !ok {
+				return steps, errClosed
+			}
+			steps = append(steps, step)
+		case err := <-done:
+			// Nothing is left to send on an unbuffered channel, so only a closed one is ready
+			select {
+			case <-output:
+				return steps, errClosed
+			default:
+			}
+			return steps, err
+		}
+	}
+}
+
+// cleanup runs Cleanup, failing the test when it fails
+func cleanup(t *testing.T, s scenario.Scenario) {
+	t.Helper()
+	if err := s.Cleanup(context.Background()); err != nil {
+		t.Fatalf("Expected Cleanup to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package scenariotest
+
+import "strings"
+
+// Texts seeds fuzz targets for whatever renders step text: strings the scenarios send, emoji
+// and variation selectors included, and strings a database or a plugin may hand back
+var Texts = []string{
+	"🔒 Dirty Read Prevention Demonstration",
+	"⚔️ Write Conflict Detection Demonstration",
+	"✅ Dirty read prevented! Session B cannot see Session A's uncommitted data",
+	"❌ WriteConflict! Document was modified by another transaction (Code: 112)",
+	"🛡️ Write conflict detected! Session A's withdrawal prevented to avoid overdraft",
+	"Alice (eu) → $100.00\nBob (us) → $50.00",
+	"⏱️ Scenario timed out after 30s",
+	"db.accounts.updateOne({_id: 1}, {$inc: {balance: -30}})",
+	"\x1b[31mred\x1b[0m \x1b]0;title\x07 \x1b[2J",
+	"مرحبا بالعالم ‏שלום‎",
+	"👨‍👩‍👧‍👦 é a​b",
+	"\xff\xfe\xc3(",
+	`</td><script>alert("x")</script><img src=x onerror=alert(1)>`,
+	"| a | b |\n---\n# heading `code` [link](javascript:alert(1))",
+	strings.Repeat("{_id: 1, balance: 100}, ", 500),
+}
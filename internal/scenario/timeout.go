@@ -0,0 +1,46 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultTimeout bounds runs of scenarios that estimate no duration. Overridable for tests.
+var DefaultTimeout = 2 * time.Minute
+
+// CleanupTimeout bounds every scenario cleanup, including one after a run timed out or was
+// cancelled, so a degraded database can't hang it too. Overridable for tests.
+var CleanupTimeout = 15 * time.Second
+
+// timeoutMargin is added to a scenario's estimated duration, leaving room for slow machines and
+// larger datasets
+const timeoutMargin = time.Minute
+
+// TimeoutOf returns how long a run of s may take: the timeout chosen in p, else the scenario's own,
+// else its estimated duration plus a margin, else DefaultTimeout
+func TimeoutOf(s Scenario, p Params) time.Duration {
+	meta := MetadataOf(s)
+	switch {
+	case p.Timeout > 0:
+		return p.Timeout
+	case meta.Timeout > 0:
+		return meta.Timeout
+	case meta.EstimatedDuration > 0:
+		return meta.EstimatedDuration + timeoutMargin
+	}
+	return DefaultTimeout
+}
+
+// TimeoutError reports that a scenario ran past its time limit and was cancelled
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("scenario timed out after %s", e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
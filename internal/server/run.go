@@ -0,0 +1,122 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// run collects the events of one scenario run for any number of streaming clients.
+// It is the StepWriter and RunObserver the run is recorded with.
+type run struct {
+	id       string
+	provider string
+	scenario string
+
+	mu       sync.Mutex
+	events   []headless.Event
+	verdict  report.Verdict
+	finished bool
+	changed  chan struct{} // Closed and replaced whenever an event is added
+}
+
+// newRun creates a run that hasn't produced any events yet
+func newRun(id, providerName, scenarioName string) *run {
+	return &run{
+		id:       id,
+		provider: providerName,
+		scenario: scenarioName,
+		changed:  make(chan struct{}),
+	}
+}
+
+// RunStarted implements headless.RunObserver
+func (r *run) RunStarted(rec *report.Run) error {
+	r.add(headless.StartedEvent(rec), false)
+	return nil
+}
+
+// WriteStep implements headless.StepWriter
+func (r *run) WriteStep(step scenario.StepResult) error {
+	r.add(headless.StepEvent(r.scenario, step), false)
+	return nil
+}
+
+// RunFinished implements headless.RunObserver
+func (r *run) RunFinished(rec *report.Run) error {
+	r.mu.Lock()
+	r.verdict = rec.Verdict()
+	r.mu.Unlock()
+	r.add(headless.FinishedEvent(rec), true)
+	return nil
+}
+
+// add appends an event and wakes up the streams waiting for it
+func (r *run) add(e headless.Event, last bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e.Time = time.Now().UTC()
+	r.events = append(r.events, e)
+	r.finished = r.finished || last
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// since returns the events from index next on, whether the run has finished and a channel
+// that is closed when more events arrive
+func (r *run) since(next int) ([]headless.Event, bool, <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []headless.Event
+	if next < len(r.events) {
+		events = append(events, r.events[next:]...)
+	}
+	return events, r.finished, r.changed
+}
+
+// isFinished returns whether the run-finished event was added
+func (r *run) isFinished() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.finished
+}
+
+// runStatus is the body of POST /runs and GET /runs/{id}
+type runStatus struct {
+	ID       string         `json:"id"`
+	Provider string         `json:"provider"`
+	Scenario string         `json:"scenario"`
+	State    string         `json:"state"` // running or finished
+	Verdict  report.Verdict `json:"verdict,omitempty"`
+	Steps    int            `json:"steps"`
+	Events   string         `json:"events"` // URL of the event stream
+}
+
+// status summarizes the run
+func (r *run) status() runStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st := runStatus{
+		ID:       r.id,
+		Provider: r.provider,
+		Scenario: r.scenario,
+		State:    "running",
+		Verdict:  r.verdict,
+		Events:   "/runs/" + r.id + "/events",
+	}
+	if r.finished {
+		st.State = "finished"
+	}
+	for _, e := range r.events {
+		if e.Type == headless.EventStep {
+			st.Steps++
+		}
+	}
+	return st
+}
@@ -0,0 +1,410 @@
+// Package server exposes providers and scenario runs over HTTP, streaming run events as
+// server-sent events so the demos can be embedded in a web page.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/headless"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// DefaultIdleTimeout is how long a provider started by the server stays up without runs
+const DefaultIdleTimeout = 10 * time.Minute
+
+// maxRuns is how many finished runs are kept for GET /runs/{id}
+const maxRuns = 100
+
+// StartFunc starts a provider and returns the params its scenarios run with
+type StartFunc func(ctx context.Context, p provider.Provider) (scenario.Params, error)
+
+// Server runs one scenario per provider at a time. Providers are started on their first run
+// and stopped again once idle for the idle timeout.
+type Server struct {
+	providers   *provider.Registry
+	start       StartFunc
+	idleTimeout time.Duration
+
+	ctx    context.Context // Cancelled by Close to stop runs and event streams
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	slots  map[string]*slot
+	runs   map[string]*run
+	order  []string // Run IDs, oldest first
+	nextID int
+}
+
+// slot is the server's state of one provider
+type slot struct {
+	p provider.Provider
+
+	lifecycle sync.Mutex       // Serializes Start and Stop
+	params    *scenario.Params // Set while the server has the provider started
+
+	// Guarded by Server.mu
+	active *run
+	idle   *time.Timer
+}
+
+// New creates a server for providers, starting them with start
+func New(providers *provider.Registry, start StartFunc, idleTimeout time.Duration) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		providers:   providers,
+		start:       start,
+		idleTimeout: idleTimeout,
+		ctx:         ctx,
+		cancel:      cancel,
+		slots:       make(map[string]*slot),
+		runs:        make(map[string]*run),
+	}
+	for _, p := range providers.GetAll() {
+		s.slots[p.Name()] = &slot{p: p}
+	}
+	return s
+}
+
+// Handler returns the HTTP API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /providers", s.handleProviders)
+	mux.HandleFunc("GET /providers/{name}/scenarios", s.handleScenarios)
+	mux.HandleFunc("POST /runs", s.handleStartRun)
+	mux.HandleFunc("GET /runs/{id}", s.handleRun)
+	mux.HandleFunc("GET /runs/{id}/events", s.handleEvents)
+	return mux
+}
+
+// Close cancels runs in progress, ends event streams and stops every provider the server started
+func (s *Server) Close(ctx context.Context) error {
+	s.cancel()
+	s.wg.Wait()
+
+	var errs []error
+	for _, sl := range s.slots {
+		s.mu.Lock()
+		if sl.idle != nil {
+			sl.idle.Stop()
+		}
+		s.mu.Unlock()
+		if err := s.stop(ctx, sl); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// providerListing is one entry of GET /providers
+type providerListing struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Running     bool   `json:"running"`
+	Scenarios   int    `json:"scenarios"`
+	ActiveRun   string `json:"active_run,omitempty"`
+}
+
+// scenarioListing is one entry of GET /providers/{name}/scenarios
+type scenarioListing struct {
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	IsolationLevel   string   `json:"isolation_level"`
+	Tags             []string `json:"tags"`
+	EstimatedSeconds float64  `json:"estimated_seconds"`
+}
+
+func (s *Server) handleProviders(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := make([]providerListing, 0, len(s.slots))
+	for _, p := range s.providers.GetAll() {
+		row := providerListing{
+			Name:        p.Name(),
+			Description: p.Description(),
+			Running:     p.IsRunning(),
+			Scenarios:   len(p.GetScenarios().GetAll()),
+		}
+		if active := s.slots[p.Name()].active; active != nil {
+			row.ActiveRun = active.id
+		}
+		rows = append(rows, row)
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+func (s *Server) handleScenarios(w http.ResponseWriter, r *http.Request) {
+	p := s.providers.GetByName(r.PathValue("name"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown provider %q", r.PathValue("name")))
+		return
+	}
+
+	scenarios := p.GetScenarios().GetAll()
+	rows := make([]scenarioListing, len(scenarios))
+	for i, sc := range scenarios {
+		meta := scenario.MetadataOf(sc)
+		rows[i] = scenarioListing{
+			Name:             sc.Name(),
+			Description:      sc.Description(),
+			IsolationLevel:   sc.IsolationLevel(),
+			Tags:             meta.Tags,
+			EstimatedSeconds: meta.EstimatedDuration.Seconds(),
+		}
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+// runRequest is the body of POST /runs
+type runRequest struct {
+	Provider string `json:"provider"`
+	Scenario string `json:"scenario"`
+}
+
+func (s *Server) handleStartRun(w http.ResponseWriter, r *http.Request) {
+	var req runRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	p := s.providers.GetByName(req.Provider)
+	if p == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown provider %q", req.Provider))
+		return
+	}
+	sc := p.GetScenarios().GetByName(req.Scenario)
+	if sc == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown scenario %q for %s", req.Scenario, p.Name()))
+		return
+	}
+
+	s.mu.Lock()
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, errors.New("server is shutting down"))
+		return
+	}
+	sl := s.slots[p.Name()]
+	if sl.active != nil {
+		active := sl.active.id
+		s.mu.Unlock()
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"error":      fmt.Sprintf("%s is busy with run %s", p.Name(), active),
+			"active_run": active,
+		})
+		return
+	}
+	if sl.idle != nil {
+		sl.idle.Stop()
+		sl.idle = nil
+	}
+	s.nextID++
+	rn := newRun(strconv.Itoa(s.nextID), p.Name(), sc.Name())
+	sl.active = rn
+	s.addRun(rn)
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go s.execute(sl, sc, rn)
+
+	w.Header().Set("Location", "/runs/"+rn.id)
+	writeJSON(w, http.StatusAccepted, rn.status())
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	rn := s.lookupRun(r.PathValue("id"))
+	if rn == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown run %q", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, rn.status())
+}
+
+// handleEvents streams a run's events from the beginning, or after Last-Event-ID when a client reconnects
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	rn := s.lookupRun(r.PathValue("id"))
+	if rn == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown run %q", r.PathValue("id")))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	next := 0
+	if last, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil {
+		next = last + 1
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		events, finished, changed := rn.since(next)
+		for _, e := range events {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", next, e.Type, data); err != nil {
+				return
+			}
+			next++
+		}
+		flusher.Flush()
+		if finished {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// execute starts the provider if needed and records the run, then arms the idle timer
+func (s *Server) execute(sl *slot, sc scenario.Scenario, rn *run) {
+	defer s.wg.Done()
+	defer s.release(sl)
+
+	log := slog.Default().With("run", rn.id, "provider", rn.provider, "scenario", rn.scenario)
+	log.Info("run started")
+
+	params, err := s.ensureStarted(sl)
+	if err == nil {
+		if missing := sl.p.Capabilities().Missing(scenario.Requirements(sc)); len(missing) > 0 {
+			err = fmt.Errorf("%s does not support %v", sl.p.Name(), missing)
+		}
+	}
+	if err != nil {
+		// Report why the scenario never ran the same way a failed run is reported
+		failed := report.NewRun(sc)
+		failed.Err = err
+		_ = rn.RunStarted(failed)
+		_ = rn.RunFinished(failed)
+		log.Error("run failed", "error", err)
+		return
+	}
+
+	result := headless.Record(s.ctx, sc, params, rn)
+	_ = result.Close() // The events streamed to clients are the record of the run
+	log.Info("run finished", "verdict", result.Verdict(), "duration", result.Duration)
+}
+
+// ensureStarted starts the slot's provider unless the server already did
+func (s *Server) ensureStarted(sl *slot) (scenario.Params, error) {
+	sl.lifecycle.Lock()
+	defer sl.lifecycle.Unlock()
+
+	if sl.params != nil && sl.p.IsRunning() {
+		return *sl.params, nil
+	}
+	params, err := s.startProvider(sl.p)
+	if err != nil {
+		return scenario.Params{}, err
+	}
+	sl.params = &params
+	return params, nil
+}
+
+// startProvider calls start, converting a panic, e.g. from an unreachable Docker daemon, into an
+// error so one failed start doesn't take the server down
+func (s *Server) startProvider(p provider.Provider) (params scenario.Params, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to start %s: %v", p.Name(), r)
+		}
+	}()
+	return s.start(s.ctx, p)
+}
+
+// release frees the slot for the next run and stops the provider once it has been idle for idleTimeout
+func (s *Server) release(sl *slot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sl.active = nil
+	if s.ctx.Err() != nil || s.idleTimeout <= 0 {
+		return
+	}
+	sl.idle = time.AfterFunc(s.idleTimeout, func() {
+		s.mu.Lock()
+		busy := sl.active != nil
+		s.mu.Unlock()
+		if busy {
+			return
+		}
+
+		slog.Info("stopping idle provider", "provider", sl.p.Name(), "idle", s.idleTimeout)
+		if err := s.stop(context.Background(), sl); err != nil {
+			slog.Warn("failed to stop idle provider", "provider", sl.p.Name(), "error", err)
+		}
+	})
+}
+
+// stop stops the slot's provider if the server started it. A run that starts meanwhile waits
+// on lifecycle and starts the provider again.
+func (s *Server) stop(ctx context.Context, sl *slot) error {
+	sl.lifecycle.Lock()
+	defer sl.lifecycle.Unlock()
+
+	if sl.params == nil {
+		return nil
+	}
+	sl.params = nil
+	if err := sl.p.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop %s: %w", sl.p.Name(), err)
+	}
+	return nil
+}
+
+// addRun stores rn, forgetting the oldest finished runs beyond maxRuns; s.mu must be held
+func (s *Server) addRun(rn *run) {
+	s.runs[rn.id] = rn
+	s.order = append(s.order, rn.id)
+	for len(s.order) > maxRuns {
+		oldest := s.runs[s.order[0]]
+		if !oldest.isFinished() {
+			break
+		}
+		delete(s.runs, oldest.id)
+		s.order = s.order[1:]
+	}
+}
+
+// lookupRun returns the run with id, or nil
+func (s *Server) lookupRun(id string) *run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs[id]
+}
+
+// writeJSON writes v with status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes {"error": ...} with status
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
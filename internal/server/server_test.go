@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// MockProvider counts how often it was started
+type MockProvider struct {
+	scenarios *scenario.Registry
+
+	mu      sync.Mutex
+	running bool
+	starts  int
+}
+
+func (m *MockProvider) Name() string                     { return "Mock" }
+func (m *MockProvider) Description() string              { return "Mock Description" }
+func (m *MockProvider) GetScenarios() *scenario.Registry { return m.scenarios }
+func (m *MockProvider) ConnectionInfo() string           { return "" }
+func (m *MockProvider) Capabilities() scenario.CapabilitySet {
+	return scenario.NewCapabilitySet()
+}
+
+func (m *MockProvider) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = true
+	m.starts++
+	return nil
+}
+
+func (m *MockProvider) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = false
+	return nil
+}
+
+func (m *MockProvider) startCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.starts
+}
+
+func (m *MockProvider) IsRunning() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running
+}
+
+// gatedScenario emits one step, then waits for release before finishing
+type gatedScenario struct {
+	release chan struct{}
+}
+
+func (s *gatedScenario) Name() string                      { return "Gated" }
+func (s *gatedScenario) Description() string               { return "Waits to be released" }
+func (s *gatedScenario) IsolationLevel() string            { return "None" }
+func (s *gatedScenario) Setup(ctx context.Context) error   { return nil }
+func (s *gatedScenario) Cleanup(ctx context.Context) error { return nil }
+
+func (s *gatedScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	output <- scenario.StepResult{Session: "Session A", Step: 1, Description: "Waiting", Success: true}
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	scenario.Assert(ctx, "released", true, "")
+	return nil
+}
+
+// newTestServer serves a mock provider with the gated scenario
+func newTestServer(t *testing.T, idle time.Duration) (*httptest.Server, *MockProvider, *gatedScenario) {
+	t.Helper()
+	sc := &gatedScenario{release: make(chan struct{})}
+	mock := &MockProvider{scenarios: scenario.NewRegistry()}
+	mock.scenarios.Register(sc)
+	providers := provider.NewRegistry()
+	providers.Register(mock)
+
+	start := func(ctx context.Context, p provider.Provider) (scenario.Params, error) {
+		params := scenario.DefaultParams()
+		params.Pacing = 0
+		return params, p.Start(ctx)
+	}
+	srv := New(providers, start, idle)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(func() {
+		ts.Close()
+		_ = srv.Close(context.Background())
+	})
+	return ts, mock, sc
+}
+
+// postRun starts the gated scenario and returns the response status
+func postRun(t *testing.T, ts *httptest.Server) int {
+	t.Helper()
+	resp, err := http.Post(ts.URL+"/runs", "application/json",
+		strings.NewReader(`{"provider": "mock", "scenario": "Gated"}`))
+	if err != nil {
+		t.Fatalf("Failed to post run: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestServer_RunAndStream(t *testing.T) {
+	ts, mock, sc := newTestServer(t, 0)
+
+	if status := postRun(t, ts); status != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d", status)
+	}
+	// Only one run per provider at a time
+	if status := postRun(t, ts); status != http.StatusConflict {
+		t.Fatalf("Expected 409 while the first run is active, got %d", status)
+	}
+
+	resp, err := http.Get(ts.URL + "/runs/1/events")
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %q", ct)
+	}
+
+	var types []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+			types = append(types, name)
+			if name == "step" {
+				close(sc.release)
+			}
+		}
+		if strings.Contains(scanner.Text(), `"verdict":"PASS"`) {
+			break
+		}
+	}
+
+	want := "run-started,step,run-finished"
+	if got := strings.Join(types, ","); got != want {
+		t.Fatalf("Expected events %s, got %s", want, got)
+	}
+	if n := mock.startCount(); n != 1 {
+		t.Fatalf("Expected the provider to start once, got %d", n)
+	}
+}
+
+func TestServer_IdleShutdown(t *testing.T) {
+	ts, mock, sc := newTestServer(t, 50*time.Millisecond)
+	close(sc.release)
+
+	if status := postRun(t, ts); status != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d", status)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for mock.IsRunning() || mock.startCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the idle provider to be stopped")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,89 @@
+// Package slug turns display names into stable identifiers for flags, configs and scripts
+package slug
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// maxSuggestions caps the did-you-mean list
+const maxSuggestions = 3
+
+// Make returns the slug of name: lowercase letters and digits, with every run of spaces and
+// punctuation turned into one dash. Emoji and apostrophes are dropped, so "Don't Panic 🚀"
+// becomes "dont-panic".
+func Make(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’' || unicode.IsMark(r):
+			// Part of the word
+		case unicode.IsSpace(r) || unicode.IsPunct(r):
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// Matches returns whether query names the entry with the display name and slug given: the
+// display name in any case, the slug, or anything with the same slug
+func Matches(query, name, slug string) bool {
+	return strings.EqualFold(query, name) || Make(query) == slug
+}
+
+// Suggest returns the slugs closest to query's, nearest first, for a did-you-mean hint. Slugs
+// too far from query to be a typo are left out, so the result may be empty.
+func Suggest(query string, slugs []string) []string {
+	q := Make(query)
+	type candidate struct {
+		slug     string
+		distance int
+	}
+	var candidates []candidate
+	for _, s := range slugs {
+		d := distance(q, s)
+		if strings.Contains(s, q) && q != "" {
+			d = 0 // A fragment like "phantom" suggests every slug containing it
+		}
+		if d <= max(2, len(q)/3) {
+			candidates = append(candidates, candidate{s, d})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int { return a.distance - b.distance })
+
+	var out []string
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		out = append(out, c.slug)
+	}
+	return out
+}
+
+// distance returns the Levenshtein distance between a and b in runes
+func distance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
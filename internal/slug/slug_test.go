@@ -0,0 +1,37 @@
+package slug
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := map[string]string{
+		"Write Conflict Detection":       "write-conflict-detection",
+		"Read Committed (Dirty Read)":    "read-committed-dirty-read",
+		"  Snapshot  Isolation -- Skew ": "snapshot-isolation-skew",
+		"Don't Panic 🚀":                  "dont-panic",
+		"MongoDB":                        "mongodb",
+		"Фантомное чтение":               "фантомное-чтение",
+		"write-conflict-detection":       "write-conflict-detection",
+	}
+	for name, want := range tests {
+		if got := Make(name); got != want {
+			t.Fatalf("Expected Make(%q) = %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	slugs := []string{"write-conflict-detection", "read-committed", "phantom-read", "lost-update"}
+
+	if got := Suggest("write conflict detecton", slugs); !slices.Equal(got, []string{"write-conflict-detection"}) {
+		t.Fatalf("Expected the typo to suggest write-conflict-detection, got %v", got)
+	}
+	if got := Suggest("read", slugs); !slices.Equal(got, []string{"read-committed", "phantom-read"}) {
+		t.Fatalf("Expected a fragment to suggest every slug containing it, got %v", got)
+	}
+	if got := Suggest("serializable", slugs); len(got) != 0 {
+		t.Fatalf("Expected no suggestions for an unrelated name, got %v", got)
+	}
+}
@@ -2,42 +2,106 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/containerruntime"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/history"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/imagepull"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// shutdownTimeout bounds stopping providers while quitting, and waiting for scenarios to clean up
+// before a provider stops; a provider still stopping after it is left behind. Overridable for tests.
+var shutdownTimeout = 30 * time.Second
+
 // View represents the current view in the application
 type View int
 
 const (
 	ViewMenu View = iota
 	ViewProviderSelect
+	ViewProviderOptions
 	ViewLoading
 	ViewScenarioList
 	ViewRunner
 	ViewHelp
+	ViewRuntimeSetup
+	ViewImagePull
+	ViewQuiz
 )
 
+// viewNames names each View in logs
+var viewNames = [...]string{
+	ViewMenu:            "menu",
+	ViewProviderSelect:  "provider-select",
+	ViewProviderOptions: "provider-options",
+	ViewLoading:         "loading",
+	ViewScenarioList:    "scenario-list",
+	ViewRunner:          "runner",
+	ViewHelp:            "help",
+	ViewRuntimeSetup:    "runtime-setup",
+	ViewImagePull:       "image-pull",
+	ViewQuiz:            "quiz",
+}
+
+// String returns the view's name
+func (v View) String() string {
+	if v < 0 || int(v) >= len(viewNames) {
+		return fmt.Sprintf("view(%d)", int(v))
+	}
+	return viewNames[v]
+}
+
 // App is the main application model
 type App struct {
 	providers    *provider.Registry
 	currentView  View
 	menu         *MenuModel
 	providerList *ProviderListModel
+	options      *ProviderOptionsModel
 	loading      *LoadingModel
 	scenarioList *ScenarioListModel
 	runner       *RunnerModel
 	help         *HelpModel
+	runtimeSetup *RuntimeSetupModel
+	imagePull    *ImagePullModel
+	quiz         *QuizModel
+
+	detector        *containerruntime.Detector
+	runtime         *containerruntime.Runtime // nil until the pre-flight check found one
+	runtimeErr      error                     // Why the last probe found no usable runtime; nil when it found one or none ran
+	setupFrom       View                      // The screen the runtime setup was opened from, returned to on esc
+	next            func() tea.Cmd            // Continues to the screen that needed the runtime
+	autoStart       provider.Provider         // Started from Init, skipping the menus
+	prewarmProvider provider.Provider         // Started in the background from Init, see Prewarm
+	prewarm         *prewarm                  // The background start, until the loading view attaches to it; nil when none
+	history         *history.Store            // nil disables startup history and quiz scores
+	logPath         string                    // Shown on error screens; empty when logging is off
+	seed            int64                     // Seeds every scenario run
+	listener        RunListener               // Mirrors scenario runs; nil when not broadcasting
+	cleanups        *scenario.Cleanups        // Runs not cleaned up yet, waited for before providers stop on quit
 
 	selectedProvider provider.Provider
+	cancelStart      context.CancelFunc // Cancels the provider start in flight; nil when none is
+	startAbandoned   bool               // The loading screen was left, so the start's outcome is discarded
+	startFrom        View               // Where the provider start was launched; returned to when it fails or is left
 	width            int
 	height           int
-	err              error
-	quitting         bool
+	err              error    // Shown over errBack until dismissed
+	errBack          View     // The view the error was raised from, shown again once it is dismissed
+	stopErr          error    // Stop failure during quit, reported after the TUI exits
+	quitting         bool     // Shutting down; a second ctrl+c quits right away
+	shutdown         []string // Progress of stopping providers, shown while quitting
 }
 
 // NewApp creates a new application
@@ -47,8 +111,12 @@ func NewApp(providers *provider.Registry) *App {
 		currentView: ViewMenu,
 		width:       80,
 		height:      24,
+		seed:        scenario.DefaultSeed,
+		cleanups:    scenario.NewCleanups(),
 	}
 
+	keys = newKeyMap() // Help texts in the chosen language
+	app.detector = containerruntime.NewDetector()
 	app.menu = NewMenuModel()
 	app.help = NewHelpModel()
 	app.providerList = NewProviderListModel(providers)
@@ -56,46 +124,166 @@ func NewApp(providers *provider.Registry) *App {
 	return app
 }
 
+// AutoStart makes the app boot straight into starting p instead of showing the menu
+func (a *App) AutoStart(p provider.Provider) {
+	a.autoStart = p
+}
+
+// SetHistory enables recording provider startup metrics in store
+func (a *App) SetHistory(store *history.Store) {
+	a.history = store
+	a.refreshStartHistory()
+}
+
+// SetLogPath records where diagnostics are logged so error screens can point there
+func (a *App) SetLogPath(path string) {
+	a.logPath = path
+}
+
+// SetRunListener mirrors every scenario run to l, e.g. a broadcast to an audience
+func (a *App) SetRunListener(l RunListener) {
+	a.listener = l
+}
+
+// SetSeed seeds every scenario run, so a recorded seed reproduces a run
+func (a *App) SetSeed(seed int64) {
+	a.seed = seed
+}
+
 // Init implements tea.Model
 func (a *App) Init() tea.Cmd {
+	if a.autoStart != nil {
+		p := a.autoStart
+		if !provider.NeedsRuntime(p) {
+			return a.startProvider(p)
+		}
+		return a.withRuntime(func() tea.Cmd { return a.startProvider(p) })
+	}
+	if a.prewarmProvider != nil {
+		return a.beginPrewarm()
+	}
 	return nil
 }
 
 // Update implements tea.Model
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer a.logTransition(a.currentView)
+
+	model, cmd := a.update(msg)
+	if a.runner != nil {
+		// The spinner only ticks while the runner is on screen
+		cmd = tea.Batch(cmd, a.runner.SetVisible(a.currentView == ViewRunner && a.err == nil && !a.quitting))
+	}
+	return model, cmd
+}
+
+// update handles msg for Update
+func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
+		a.resize()
 		return a, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			a.quitting = true
-			return a, a.cleanup()
-		case "q":
+		if key.Matches(msg, keys.Quit) {
+			return a, a.quit()
+		}
+		if a.quitting {
+			return a, nil
+		}
+		if a.err != nil {
+			return a, a.updateError(msg)
+		}
+		if a.focused() {
+			// The view's text field gets every other key, so typing q can't navigate away
+			break
+		}
+		switch {
+		case key.Matches(msg, keys.Leave):
 			if a.currentView == ViewMenu {
-				a.quitting = true
-				return a, a.cleanup()
+				return a, a.quit()
 			}
 			// Go back
 			return a, a.goBack()
-		case "esc":
+		case key.Matches(msg, keys.Back):
 			return a, a.goBack()
 		}
 
 	case ProviderStartedMsg:
+		if a.loading != nil {
+			a.loading.SetDone() // Stops its ticks
+		}
+		if a.cancelStart != nil {
+			a.cancelStart() // Releases the start's context
+			a.cancelStart = nil
+		}
+		if a.quitting {
+			// Quitting cancelled the start; stop whatever it got to before exiting
+			a.selectedProvider = msg.Provider
+			return a, a.stopAll()
+		}
+		if a.startAbandoned {
+			if msg.Err != nil {
+				return a, nil
+			}
+			// The start won the race with its cancellation
+			a.selectedProvider = msg.Provider
+			return a, a.stopProvider()
+		}
 		if msg.Err != nil {
-			a.err = msg.Err
-			a.currentView = ViewProviderSelect
+			a.fail(msg.Err, a.startFrom)
 			return a, nil
 		}
 		a.selectedProvider = msg.Provider
+		a.providerList.SetStopError(msg.Provider, nil)
 		a.scenarioList = NewScenarioListModel(msg.Provider)
+		a.scenarioList.SetSize(a.width, a.height)
+		a.scenarioList.SetStartup(msg.Metrics)
+		a.recordStart(msg.Metrics)
+		a.refreshQuizScores()
 		a.currentView = ViewScenarioList
 		return a, nil
 
+	case prewarmDoneMsg:
+		if msg.prewarm.err != nil && a.prewarm == msg.prewarm {
+			// Nobody attached yet; selecting the provider starts it afresh and shows what goes wrong
+			slog.Warn("prewarming provider failed", "provider", msg.prewarm.provider.Name(), "error", msg.prewarm.err)
+			a.prewarm = nil
+		}
+		return a, nil
+
+	case GoBackMsg:
+		return a, a.goBack()
+
+	case ErrorMsg:
+		a.fail(msg.Err, a.currentView)
+		return a, nil
+
+	case RuntimeSelectedMsg:
+		if err := msg.Runtime.Apply(); err != nil {
+			a.fail(err, a.currentView)
+			return a, nil
+		}
+		a.setRuntime(msg.Runtime)
+		return a, a.next()
+
+	case ProviderProgressMsg:
+		if a.loading != nil {
+			a.loading.AddMessage(msg.Stage)
+		}
+		return a, waitForProgress(msg.progress)
+
+	case ImagePullMsg, ImagePullDoneMsg:
+		// Keep draining pulls even after leaving the screen
+		if a.imagePull != nil {
+			var cmd tea.Cmd
+			a.imagePull, cmd = a.imagePull.Update(msg)
+			return a, cmd
+		}
+		return a, nil
+
 	case loadingTickMsg:
 		if a.loading != nil {
 			var cmd tea.Cmd
@@ -104,21 +292,77 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 
+	case providerListTickMsg:
+		var cmd tea.Cmd
+		a.providerList, cmd = a.providerList.Update(msg)
+		return a, cmd
+
 	case ProviderStoppedMsg:
-		a.selectedProvider = nil
+		a.providerList.StopDone(msg.Provider)
 		if a.quitting {
-			return a, tea.Quit
+			// The shutdown stops this provider too and quits once everything is down
+			return a, nil
 		}
+		if a.selectedProvider == msg.Provider {
+			// Unless another provider was started meanwhile
+			a.selectedProvider = nil
+		}
+		if msg.Err != nil {
+			slog.Warn("provider stop failed", "provider", msg.Provider.Name(), "error", msg.Err)
+		}
+		a.providerList.SetStopError(msg.Provider, msg.Err)
 		return a, nil
 
+	case shutdownProgressMsg:
+		a.shutdown = append(a.shutdown, msg.line)
+		return a, waitForShutdown(msg.events)
+
+	case shutdownDoneMsg:
+		a.selectedProvider = nil
+		a.stopErr = msg.err
+		return a, tea.Quit
+
 	case ScenarioSelectedMsg:
-		a.runner = NewRunnerModel(msg.Scenario)
+		if a.quitting {
+			return a, nil
+		}
+		if a.runner != nil && a.runner.Active() {
+			// Two copies of a scenario would race on the same collections
+			slog.Warn("ignoring scenario selection during a run", "scenario", msg.Scenario.Name(), "running", a.runner.scenario.Name())
+			return a, nil
+		}
+		params := scenario.DefaultParams()
+		if ps, ok := a.selectedProvider.(provider.ParamSource); ok {
+			params = ps.ScenarioParams()
+		}
+		params.Seed = a.seed
+		a.runner = NewRunnerModel(msg.Scenario, params)
+		a.runner.SetSize(a.width, a.height)
+		a.runner.SetProvider(a.selectedProvider)
+		a.runner.SetLogPath(a.logPath)
+		a.runner.SetListener(a.listener)
+		a.runner.SetCleanups(a.cleanups)
 		a.currentView = ViewRunner
 		return a, a.runner.Start()
 
+	case tea.FocusMsg, tea.BlurMsg:
+		if a.runner != nil {
+			_, isFocus := msg.(tea.FocusMsg)
+			return a, a.runner.SetFocused(isFocus)
+		}
+		return a, nil
+
+	case runnerSetupMsg, runnerStepMsg, runnerCompleteMsg, runnerTickMsg:
+		// Runs keep draining after leaving the screen; each message goes to the runner that started it
+		return a, a.updateRun(msg)
+
 	case RunnerDoneMsg:
 		// Stay on runner view to show results
 		return a, nil
+
+	case QuizFinishedMsg:
+		a.recordQuizScore(msg)
+		return a, nil
 	}
 
 	// Delegate to current view
@@ -128,6 +372,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd = a.updateMenu(msg)
 	case ViewProviderSelect:
 		cmd = a.updateProviderList(msg)
+	case ViewProviderOptions:
+		cmd = a.updateProviderOptions(msg)
 	case ViewLoading:
 		// Loading view handles its own updates via loadingTickMsg
 	case ViewScenarioList:
@@ -136,6 +382,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd = a.updateRunner(msg)
 	case ViewHelp:
 		cmd = a.updateHelp(msg)
+	case ViewRuntimeSetup:
+		a.runtimeSetup, cmd = a.runtimeSetup.Update(msg)
+	case ViewImagePull:
+		a.imagePull, cmd = a.imagePull.Update(msg)
+	case ViewQuiz:
+		a.quiz, cmd = a.quiz.Update(msg)
 	}
 
 	return a, cmd
@@ -144,16 +396,18 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (a *App) updateMenu(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, keys.Select):
 			switch a.menu.Selected() {
 			case 0: // Select Database
-				a.currentView = ViewProviderSelect
-			case 1: // Help
+				return a.openProviderSelect()
+			case 1: // Prepare images
+				return a.withRuntime(a.openImagePull)
+			case 2: // Help
+				a.help.GotoTop()
 				a.currentView = ViewHelp
-			case 2: // Quit
-				a.quitting = true
-				return a.cleanup()
+			case 3: // Quit
+				return a.quit()
 			}
 		}
 	}
@@ -163,15 +417,118 @@ func (a *App) updateMenu(msg tea.Msg) tea.Cmd {
 	return cmd
 }
 
+// withRuntime runs the container runtime pre-flight check before continuing with next, asking
+// for a socket when there is no usable runtime
+func (a *App) withRuntime(next func() tea.Cmd) tea.Cmd {
+	a.probeRuntime()
+	if a.runtime == nil {
+		return a.openRuntimeSetup(next)
+	}
+	return next()
+}
+
+// probeRuntime looks for a usable container runtime until one is found. A failed probe is kept,
+// so the provider list can disable the providers that need a container and say why.
+func (a *App) probeRuntime() {
+	if a.runtime != nil {
+		return
+	}
+	r, err := a.detector.Probe()
+	if err == nil {
+		err = r.Apply()
+	}
+	if err != nil {
+		a.runtimeErr = err
+		a.providerList.SetRuntimeError(err)
+		return
+	}
+	a.setRuntime(r)
+}
+
+// openRuntimeSetup asks for the socket of a container runtime, continuing with next once one is entered
+func (a *App) openRuntimeSetup(next func() tea.Cmd) tea.Cmd {
+	a.next = next
+	a.setupFrom = a.currentView
+	a.runtimeSetup = NewRuntimeSetupModel(a.detector)
+	a.currentView = ViewRuntimeSetup
+	return a.runtimeSetup.Init()
+}
+
+// openProviderSelect shows the provider list, probing for a container runtime first so providers
+// that need one are disabled up front instead of failing to start
+func (a *App) openProviderSelect() tea.Cmd {
+	a.probeRuntime()
+	a.currentView = ViewProviderSelect
+	return nil
+}
+
+func (a *App) openImagePull() tea.Cmd {
+	// Resume the previous screen's progress rather than starting over
+	if a.imagePull == nil {
+		a.imagePull = NewImagePullModel(a.providerImages())
+	}
+	a.currentView = ViewImagePull
+	return a.imagePull.Start()
+}
+
+// providerImages returns the distinct images used by all registered providers
+func (a *App) providerImages() []string {
+	var images []string
+	seen := make(map[string]bool)
+	for _, p := range a.providers.GetAll() {
+		ip, ok := p.(provider.ImageProvider)
+		if !ok {
+			continue
+		}
+		for _, ref := range ip.Images() {
+			if !seen[ref] {
+				seen[ref] = true
+				images = append(images, ref)
+			}
+		}
+	}
+	return images
+}
+
+// setRuntime records the runtime used for this session
+func (a *App) setRuntime(r containerruntime.Runtime) {
+	a.runtime = &r
+	a.runtimeErr = nil
+	a.providerList.SetRuntime(r)
+}
+
 func (a *App) updateProviderList(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, keys.Select):
 			selected := a.providerList.Selected()
+			if selected != nil && a.providerList.Stopping(selected) {
+				// Its container is still being torn down; starting now would race with that
+				return nil
+			}
+			if selected != nil && a.runtimeErr != nil && provider.NeedsRuntime(selected) {
+				// Starting would only fail deep inside testcontainers; show what the probe found instead
+				a.fail(fmt.Errorf("%s needs a container runtime: %w", selected.Name(), a.runtimeErr), ViewProviderSelect)
+				return nil
+			}
+			if selected != nil && a.prewarmed(selected) {
+				// Its options were fixed when the background start began
+				return a.startProvider(selected)
+			}
 			if selected != nil {
+				// Offer the options screen first for providers that have settings
+				if c, ok := selected.(provider.Configurable); ok && len(c.Settings()) > 0 {
+					a.options = NewProviderOptionsModel(selected, c)
+					a.currentView = ViewProviderOptions
+					return nil
+				}
 				return a.startProvider(selected)
 			}
+		case key.Matches(msg, keys.SetupRuntime):
+			if a.runtimeErr != nil {
+				return a.openRuntimeSetup(a.openProviderSelect)
+			}
 		}
 	}
 
@@ -180,16 +537,27 @@ func (a *App) updateProviderList(msg tea.Msg) tea.Cmd {
 	return cmd
 }
 
+func (a *App) updateProviderOptions(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Select):
+			return a.startProvider(a.options.Provider())
+		}
+	}
+
+	var cmd tea.Cmd
+	a.options, cmd = a.options.Update(msg)
+	return cmd
+}
+
 func (a *App) updateScenarioList(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
-			scenario := a.scenarioList.Selected()
-			if scenario != nil {
-				return func() tea.Msg {
-					return ScenarioSelectedMsg{Scenario: scenario}
-				}
+		switch {
+		case key.Matches(msg, keys.Select):
+			if !a.scenarioList.Focused() {
+				return a.scenarioList.Launch()
 			}
 		}
 	}
@@ -199,7 +567,37 @@ func (a *App) updateScenarioList(msg tea.Msg) tea.Cmd {
 	return cmd
 }
 
+// updateRun hands a setup stage, a step, the outcome of a run or a spinner tick to the runner that
+// started it
+func (a *App) updateRun(msg tea.Msg) tea.Cmd {
+	var runner *RunnerModel
+	switch msg := msg.(type) {
+	case runnerSetupMsg:
+		runner = msg.runner
+	case runnerStepMsg:
+		runner = msg.runner
+	case runnerCompleteMsg:
+		runner = msg.runner
+		if runner == a.runner && a.scenarioList != nil {
+			a.scenarioList.Finished()
+		}
+	case runnerTickMsg:
+		runner = msg.runner
+	}
+	_, cmd := runner.Update(msg)
+	return cmd
+}
+
 func (a *App) updateRunner(msg tea.Msg) tea.Cmd {
+	if km, ok := msg.(tea.KeyMsg); ok && key.Matches(km, keys.Quiz) {
+		if quiz := a.runner.NewQuiz(); quiz != nil {
+			a.quiz = quiz
+			a.quiz.SetSize(a.width, a.height)
+			a.currentView = ViewQuiz
+			return nil
+		}
+	}
+
 	var cmd tea.Cmd
 	a.runner, cmd = a.runner.Update(msg)
 	return cmd
@@ -213,20 +611,31 @@ func (a *App) updateHelp(msg tea.Msg) tea.Cmd {
 
 // View implements tea.Model
 func (a *App) View() string {
+	return i18n.Render(a.view())
+}
+
+// view renders the current screen
+func (a *App) view() string {
 	if a.quitting {
-		return "\n  Cleaning up containers...\n\n"
+		return a.quittingView()
 	}
 
 	if a.err != nil {
-		return fmt.Sprintf("\n  %s\n\n  Press esc to go back.\n",
-			ErrorStyle.Render(fmt.Sprintf("Error: %v", a.err)))
+		var offline *imagepull.OfflineError
+		if errors.As(a.err, &offline) {
+			return offlineView(offline) + logHint(a.logPath)
+		}
+		return fmt.Sprintf("\n  %s\n\n  %s\n",
+			ErrorStyle.Render(i18n.T("Error: %v", a.err)), i18n.T("Press esc to go back.")) + logHint(a.logPath)
 	}
 
 	switch a.currentView {
 	case ViewMenu:
-		return a.menu.View()
+		return a.menu.View() + a.prewarmStatus()
 	case ViewProviderSelect:
-		return a.providerList.View()
+		return a.providerList.View() + a.prewarmStatus()
+	case ViewProviderOptions:
+		return a.options.View()
 	case ViewLoading:
 		if a.loading != nil {
 			return a.loading.View()
@@ -237,22 +646,109 @@ func (a *App) View() string {
 		return a.runner.View()
 	case ViewHelp:
 		return a.help.View()
+	case ViewRuntimeSetup:
+		return a.runtimeSetup.View()
+	case ViewImagePull:
+		return a.imagePull.View()
+	case ViewQuiz:
+		return a.quiz.View()
 	}
 
 	return ""
 }
 
-func (a *App) goBack() tea.Cmd {
-	// Clear any error when going back
-	a.err = nil
+// quittingView shows the shutdown's progress and how to skip waiting for it
+func (a *App) quittingView() string {
+	var b strings.Builder
+	b.WriteString("\n  " + i18n.T("Cleaning up containers...") + "\n\n")
+	for _, line := range a.shutdown {
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("\n  " + HelpStyle.Render(i18n.T("Press ctrl+c again to quit without waiting")) + "\n")
+	return b.String()
+}
 
+// focused returns whether the current view has a focused text field
+func (a *App) focused() bool {
+	var view any
+	switch a.currentView {
+	case ViewScenarioList:
+		view = a.scenarioList
+	case ViewRuntimeSetup:
+		view = a.runtimeSetup
+	}
+	f, ok := view.(Focuser)
+	return ok && f.Focused()
+}
+
+// resize passes the terminal size to every live screen; screens created later get it from their creator
+func (a *App) resize() {
+	size := tea.WindowSizeMsg{Width: a.width, Height: a.height}
+	a.help, _ = a.help.Update(size)
+	if a.loading != nil {
+		a.loading.SetSize(a.width, a.height)
+	}
+	if a.scenarioList != nil {
+		a.scenarioList.SetSize(a.width, a.height)
+	}
+	if a.runner != nil {
+		a.runner.SetSize(a.width, a.height)
+	}
+	if a.quiz != nil {
+		a.quiz.SetSize(a.width, a.height)
+	}
+}
+
+// logTransition logs a change of the current view made while handling a message
+func (a *App) logTransition(from View) {
+	if a.currentView != from {
+		slog.Info("view transition", "from", from, "to", a.currentView)
+	}
+}
+
+// logHint points at the log file from an error screen
+func logHint(path string) string {
+	if path == "" {
+		return ""
+	}
+	return "\n  " + HelpStyle.Render(i18n.T("Details are logged to %s", path)) + "\n"
+}
+
+// fail shows err in place of the current screen; dismissing it returns to back, which keeps the
+// state it had, such as its cursor
+func (a *App) fail(err error, back View) {
+	a.err = err
+	a.errBack = back
+	a.currentView = back
+}
+
+// updateError handles keys while an error is shown: esc, q and enter dismiss it and the rest are
+// ignored, so they can't act on the screen hidden behind it
+func (a *App) updateError(msg tea.KeyMsg) tea.Cmd {
+	if key.Matches(msg, keys.Back, keys.Leave, keys.Select) {
+		a.err = nil
+		a.currentView = a.errBack
+	}
+	return nil
+}
+
+func (a *App) goBack() tea.Cmd {
 	switch a.currentView {
 	case ViewProviderSelect:
 		a.currentView = ViewMenu
-	case ViewLoading:
-		// Can't go back while loading, but clear loading state
-		a.loading = nil
+	case ViewProviderOptions:
 		a.currentView = ViewProviderSelect
+	case ViewLoading:
+		// Cancel the start; its ProviderStartedMsg cleans up whatever it created
+		if a.loading != nil {
+			a.loading.SetDone()
+			a.loading = nil
+		}
+		a.currentView = a.startFrom
+		if a.cancelStart != nil {
+			a.cancelStart()
+			a.startAbandoned = true
+		}
 	case ViewScenarioList:
 		a.currentView = ViewProviderSelect
 		// Stop the provider
@@ -260,59 +756,323 @@ func (a *App) goBack() tea.Cmd {
 			return a.stopProvider()
 		}
 	case ViewRunner:
+		// A run left behind is cancelled; Execute still cleans up after it
+		a.runner.Cancel()
 		a.currentView = ViewScenarioList
 	case ViewHelp:
 		a.currentView = ViewMenu
+	case ViewRuntimeSetup:
+		a.currentView = a.setupFrom
+	case ViewImagePull:
+		a.imagePull.Cancel()
+		a.currentView = ViewMenu
+	case ViewQuiz:
+		a.currentView = ViewRunner
 	}
 	return nil
 }
 
 func (a *App) startProvider(p provider.Provider) tea.Cmd {
-	// Create loading view
-	a.loading = NewLoadingModel(fmt.Sprintf("Starting %s...", p.Name()))
-	a.loading.AddMessage("Initializing container...")
-	a.currentView = ViewLoading
+	if a.prewarmed(p) {
+		if !a.prewarm.finished() || a.prewarm.err == nil {
+			return a.attachPrewarm()
+		}
+		a.prewarm = nil // Failed; start it afresh
+	}
+
+	a.beginLoading(p)
+	a.loading.AddMessage(i18n.T("Initializing container..."))
+
+	// Quitting or leaving the loading screen cancels the start
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelStart = cancel
+
+	// Forward startup stages to the loading view as they happen
+	progress := make(chan string, 16)
+	ctx = provider.WithProgress(ctx, func(stage string) {
+		select {
+		case progress <- stage:
+		default:
+			// Never let a slow UI stall the startup
+		}
+	})
+
+	// Time each startup phase for the metrics shown after start
+	timer := provider.NewStartupTimer()
+	ctx = provider.WithTimer(ctx, timer)
 
-	// Return batch command: start ticker and start provider
+	// Return batch command: start ticker, listen for progress and start provider
 	return tea.Batch(
 		a.loading.Tick(),
+		waitForProgress(progress),
 		func() tea.Msg {
-			ctx := context.Background()
 			err := p.Start(ctx)
-			return ProviderStartedMsg{Provider: p, Err: err}
+			close(progress)
+			return ProviderStartedMsg{Provider: p, Metrics: timer.Finish(p.Name()), Err: err}
 		},
 	)
 }
 
-func (a *App) stopProvider() tea.Cmd {
-	p := a.selectedProvider
-	return func() tea.Msg {
-		if p != nil {
-			ctx := context.Background()
-			_ = p.Stop(ctx)
+// beginLoading shows the loading view for a start of p
+func (a *App) beginLoading(p provider.Provider) {
+	a.startFrom = a.currentView
+	if a.startFrom != ViewProviderOptions {
+		// Started from the provider list, or straight from the menus with --provider
+		a.startFrom = ViewProviderSelect
+	}
+	a.loading = NewLoadingModel(i18n.T("Starting %s...", p.Name()))
+	a.loading.SetSize(a.width, a.height)
+	a.currentView = ViewLoading
+	a.startAbandoned = false
+}
+
+// recordStart persists startup metrics and refreshes the provider list history
+func (a *App) recordStart(m provider.StartupMetrics) {
+	if a.history == nil {
+		return
+	}
+	// History is best effort; a read-only home directory shouldn't break the demo
+	_ = a.history.AddStart(m)
+	a.refreshStartHistory()
+}
+
+// refreshStartHistory loads recent startups of every provider into the provider list
+func (a *App) refreshStartHistory() {
+	if a.history == nil {
+		return
+	}
+	for _, p := range a.providers.GetAll() {
+		if starts, err := a.history.Starts(p.Name()); err == nil {
+			a.providerList.SetStartHistory(p.Name(), starts)
 		}
-		return ProviderStoppedMsg{}
 	}
 }
 
-func (a *App) cleanup() tea.Cmd {
-	p := a.selectedProvider
+// recordQuizScore persists a quiz result and shows it in the scenario list
+func (a *App) recordQuizScore(msg QuizFinishedMsg) {
+	if a.history == nil {
+		return
+	}
+	// Like startup history, scores are best effort
+	_ = a.history.AddQuizScore(history.QuizScore{
+		Scenario: msg.Scenario,
+		Correct:  msg.Correct,
+		Total:    msg.Total,
+		At:       time.Now(),
+	})
+	a.refreshQuizScores()
+}
+
+// refreshQuizScores loads the quiz results of every scenario into the scenario list
+func (a *App) refreshQuizScores() {
+	if a.history == nil || a.scenarioList == nil || a.selectedProvider == nil {
+		return
+	}
+	for _, s := range a.selectedProvider.GetScenarios().GetAll() {
+		if scores, err := a.history.QuizScores(s.Name()); err == nil {
+			a.scenarioList.SetQuizScores(s.Name(), scores)
+		}
+	}
+}
+
+// waitForProgress returns a command that delivers the next startup stage
+func waitForProgress(progress <-chan string) tea.Cmd {
 	return func() tea.Msg {
-		if p != nil {
-			ctx := context.Background()
-			_ = p.Stop(ctx)
+		stage, ok := <-progress
+		if !ok {
+			return nil
 		}
-		return tea.Quit()
+		return ProviderProgressMsg{Stage: stage, progress: progress}
 	}
 }
 
+// stopProvider stops the selected provider in the background, once scenarios still cleaning up
+// are done or shutdownTimeout passed. Until ProviderStoppedMsg arrives the provider list shows it
+// as stopping and won't start it again.
+func (a *App) stopProvider() tea.Cmd {
+	p, cleanups := a.selectedProvider, a.cleanups
+	return tea.Batch(
+		func() tea.Msg {
+			// A cancelled run is still cleaning up; its database has to stay up until it is done
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := cleanups.Wait(ctx); err != nil {
+				slog.Warn("stopping the provider before the scenario cleaned up", "provider", p.Name(), "error", err)
+			}
+			return ProviderStoppedMsg{Provider: p, Err: stop(context.Background(), p)}
+		},
+		a.providerList.StartStopping(p),
+	)
+}
+
+// quit shuts the app down: it cancels the run and the provider start in flight, stops every
+// running provider and quits once they are down. Called again during the shutdown, e.g. by a
+// second ctrl+c while a provider hangs, it quits right away.
+func (a *App) quit() tea.Cmd {
+	if a.quitting {
+		slog.Warn("quitting without waiting for the shutdown")
+		return tea.Quit
+	}
+	a.quitting = true
+	if a.runner != nil {
+		a.runner.Cancel()
+	}
+	if a.imagePull != nil {
+		a.imagePull.Cancel()
+	}
+	if a.prewarm != nil {
+		// stopAll waits for the cancelled background start before stopping what it got to
+		a.prewarm.cancel()
+	}
+	if a.cancelStart != nil {
+		// ProviderStartedMsg continues the shutdown once the cancelled start returns
+		a.cancelStart()
+		return nil
+	}
+	return a.stopAll()
+}
+
+// stopAll waits for scenarios still cleaning up, then stops the selected provider, which may be
+// half started, and every other running one concurrently, reporting each as it goes. A background
+// start is waited for and stopped likewise. Cleanups and providers still going after
+// shutdownTimeout are reported as failed so the app can quit.
+func (a *App) stopAll() tea.Cmd {
+	var providers []provider.Provider
+	if a.selectedProvider != nil {
+		providers = append(providers, a.selectedProvider)
+	}
+	pw := a.prewarm
+	if pw != nil && pw.provider != a.selectedProvider {
+		providers = append(providers, pw.provider)
+	}
+	for _, p := range a.providers.GetAll() {
+		// A provider whose stop failed is tried again, so a container left behind is reported on exit
+		if p != a.selectedProvider && !a.prewarmed(p) && (p.IsRunning() || a.providerList.StopFailed(p)) {
+			providers = append(providers, p)
+		}
+	}
+
+	// Room for every message, so no sender ever waits on the UI loop
+	events := make(chan tea.Msg, 2*len(providers)+3)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	cleanups := a.cleanups
+	go func() {
+		defer cancel()
+
+		// A cancelled run is still cleaning up; its database has to stay up until it is done
+		var cleanupErr error
+		if cleanups.Pending() > 0 {
+			events <- shutdownProgressMsg{line: i18n.T("Cleaning up the scenario..."), events: events}
+			if cleanupErr = cleanups.Wait(ctx); cleanupErr != nil {
+				events <- shutdownProgressMsg{line: ErrorStyle.Render("❌ " + cleanupErr.Error()), events: events}
+			}
+		}
+
+		if pw != nil {
+			select {
+			case <-pw.done:
+			case <-ctx.Done():
+			}
+		}
+
+		errs := make([]error, len(providers))
+		var wg sync.WaitGroup
+		for i, p := range providers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				events <- shutdownProgressMsg{line: i18n.T("Stopping %s...", p.Name()), events: events}
+				if errs[i] = stopWithin(ctx, p); errs[i] != nil {
+					events <- shutdownProgressMsg{line: ErrorStyle.Render("❌ " + errs[i].Error()), events: events}
+					return
+				}
+				events <- shutdownProgressMsg{line: SuccessStyle.Render(i18n.T("✓ %s stopped", p.Name())), events: events}
+			}()
+		}
+		wg.Wait()
+		events <- shutdownDoneMsg{err: errors.Join(append(errs, cleanupErr)...)}
+	}()
+	return waitForShutdown(events)
+}
+
+// waitForShutdown returns a command that delivers the next shutdown message
+func waitForShutdown(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// stopWithin stops p, giving up once ctx is done even if the provider's Stop doesn't
+func stopWithin(ctx context.Context, p provider.Provider) error {
+	done := make(chan error, 1)
+	go func() { done <- stop(ctx, p) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("failed to stop %s: %w", p.Name(), ctx.Err())
+	}
+}
+
+// stop stops a provider, wrapping any failure with the provider name
+func stop(ctx context.Context, p provider.Provider) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop %s: %w", p.Name(), err)
+	}
+	return nil
+}
+
+// StopErr returns the error from stopping the provider while quitting, if any
+func (a *App) StopErr() error {
+	return a.stopErr
+}
+
+// Focuser is implemented by views with a text field. While it is focused the app hands the view
+// every key but ctrl+c, so keys like q and esc edit the field instead of navigating; the view leaves
+// the field itself, or sends GoBackMsg to leave the screen.
+type Focuser interface {
+	Focused() bool
+}
+
 // Message types
 type ProviderStartedMsg struct {
 	Provider provider.Provider
+	Metrics  provider.StartupMetrics
 	Err      error
 }
 
-type ProviderStoppedMsg struct{}
+type ProviderProgressMsg struct {
+	Stage    string
+	progress <-chan string
+}
+
+type ProviderStoppedMsg struct {
+	Provider provider.Provider
+	Err      error
+}
+
+// shutdownProgressMsg reports a provider stopping while quitting
+type shutdownProgressMsg struct {
+	line   string
+	events <-chan tea.Msg // The rest of the shutdown
+}
+
+// shutdownDoneMsg reports that every provider stopped, or was given up on, while quitting
+type shutdownDoneMsg struct {
+	err error
+}
+
+// GoBackMsg asks the app to leave the current view, like esc does without a focused text field
+type GoBackMsg struct{}
+
+// ErrorMsg asks the app to show Err over the current view, which is shown again once the error is
+// dismissed
+type ErrorMsg struct {
+	Err error
+}
 
 type ScenarioSelectedMsg struct {
 	Scenario scenario.Scenario
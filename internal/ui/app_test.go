@@ -0,0 +1,691 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+	"unicode"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/containerruntime"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/mongodb"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestApp_DoubleEnterLaunchesOnce(t *testing.T) {
+	p := &stoppableProvider{scenarios: scenario.NewRegistry(), running: true}
+	p.scenarios.Register(&chattyScenario{n: 3})
+	a := NewApp(provider.NewRegistry())
+	a.Update(ProviderStartedMsg{Provider: p})
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	var selections []tea.Msg
+	for range 2 {
+		if _, cmd := a.Update(enter); cmd != nil {
+			selections = append(selections, cmd())
+		}
+	}
+	if len(selections) != 1 {
+		t.Fatalf("Expected one scenario selection, got %d", len(selections))
+	}
+
+	a.Update(selections[0])
+	runner := a.runner
+	if runner == nil || !runner.Active() {
+		t.Fatal("Expected a runner to be started")
+	}
+
+	// A selection arriving anyway must not replace the active run
+	a.Update(ScenarioSelectedMsg{Scenario: &chattyScenario{n: 3}})
+	if a.runner != runner {
+		t.Fatal("Expected the active runner to be kept")
+	}
+}
+
+// blockingProvider starts only once its context is cancelled, like a slow image pull
+type blockingProvider struct {
+	stoppableProvider
+	stopped bool
+}
+
+func (p *blockingProvider) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (p *blockingProvider) Stop(ctx context.Context) error {
+	p.stopped = true
+	return nil
+}
+
+func TestApp_QuitCancelsProviderStart(t *testing.T) {
+	p := &blockingProvider{stoppableProvider: stoppableProvider{scenarios: scenario.NewRegistry()}}
+	a := NewApp(provider.NewRegistry())
+	batch, ok := a.startProvider(p)().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("Expected the start to be batched with the loading ticks")
+	}
+
+	started := make(chan tea.Msg, 1)
+	go func() { started <- batch[2]() }()
+
+	if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd != nil {
+		t.Fatal("Expected quitting to wait for the cancelled start")
+	}
+
+	var msg tea.Msg
+	select {
+	case msg = <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected quitting to cancel the provider start")
+	}
+	if !errors.Is(msg.(ProviderStartedMsg).Err, context.Canceled) {
+		t.Fatalf("Expected the start to be cancelled, got %v", msg.(ProviderStartedMsg).Err)
+	}
+
+	_, cmd := a.Update(msg)
+	runUntilQuit(t, a, cmd)
+	if !p.stopped {
+		t.Fatal("Expected the provider to be stopped after the cancelled start")
+	}
+}
+
+func TestApp_LoadingStopsTickingOnceLeft(t *testing.T) {
+	a := NewApp(provider.NewRegistry())
+	p := &stoppableProvider{scenarios: scenario.NewRegistry()}
+
+	batch := a.startProvider(p)().(tea.BatchMsg)
+	tick := batch[0]()
+	_, cmd := a.Update(tick)
+	if cmd == nil {
+		t.Fatal("Expected the spinner to keep ticking while starting")
+	}
+	a.Update(batch[2]())
+	if _, cmd := a.Update(tick); cmd != nil {
+		t.Fatal("Expected no more ticks once the provider started")
+	}
+
+	// Leaving a start doesn't hand its ticks to the next loading screen
+	a.currentView = ViewProviderSelect
+	batch = a.startProvider(p)().(tea.BatchMsg)
+	abandoned := batch[0]()
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	a.startProvider(p)
+	if _, cmd := a.Update(abandoned); cmd != nil {
+		t.Fatal("Expected the abandoned loading screen to stop ticking")
+	}
+}
+
+func TestApp_ProviderStoppingCannotBeStarted(t *testing.T) {
+	p := &stoppableProvider{scenarios: scenario.NewRegistry(), running: true}
+	providers := provider.NewRegistry()
+	providers.Register(p)
+	a := NewApp(providers)
+	a.Update(ProviderStartedMsg{Provider: p})
+
+	_, stopCmd := a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.currentView != ViewProviderSelect {
+		t.Fatalf("Expected esc to go to the provider list, got %v", a.currentView)
+	}
+	if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || a.currentView != ViewProviderSelect {
+		t.Fatalf("Expected enter to be ignored while %s is stopping, got %v", p.Name(), a.currentView)
+	}
+	if !strings.Contains(a.View(), "stopping") {
+		t.Fatalf("Expected the provider to be shown as stopping, got:\n%s", a.View())
+	}
+
+	var stopped tea.Msg
+	for _, cmd := range stopCmd().(tea.BatchMsg) {
+		if msg, ok := cmd().(ProviderStoppedMsg); ok {
+			stopped = msg
+		}
+	}
+	a.Update(stopped)
+	if _, cmd := a.Update(providerListTickMsg{}); cmd != nil {
+		t.Fatal("Expected the spinner to stop once the stop finished")
+	}
+	if a.Update(tea.KeyMsg{Type: tea.KeyEnter}); a.currentView != ViewLoading {
+		t.Fatalf("Expected enter to start the provider once stopped, got %v", a.currentView)
+	}
+}
+
+// runUntilQuit runs cmd and feeds the app the messages that follow until it quits
+func runUntilQuit(t *testing.T, a *App, cmd tea.Cmd) {
+	t.Helper()
+	for cmd != nil {
+		msg := cmd()
+		if _, ok := msg.(tea.QuitMsg); ok {
+			return
+		}
+		_, cmd = a.Update(msg)
+	}
+	t.Fatal("Expected the app to quit")
+}
+
+func TestApp_QuitFromEveryView(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(a *App, p provider.Provider)
+	}{
+		{"menu", func(a *App, p provider.Provider) {}},
+		{"provider select", func(a *App, p provider.Provider) { a.currentView = ViewProviderSelect }},
+		{"help", func(a *App, p provider.Provider) { a.currentView = ViewHelp }},
+		{"scenario list", func(a *App, p provider.Provider) { a.Update(ProviderStartedMsg{Provider: p}) }},
+		{"runner", func(a *App, p provider.Provider) {
+			a.Update(ProviderStartedMsg{Provider: p})
+			a.Update(ScenarioSelectedMsg{Scenario: &chattyScenario{n: 1}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &stoppableProvider{scenarios: scenario.NewRegistry(), running: true}
+			providers := provider.NewRegistry()
+			providers.Register(p)
+			a := NewApp(providers)
+			tt.open(a, p)
+
+			_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+			runUntilQuit(t, a, cmd)
+			if p.IsRunning() || a.StopErr() != nil {
+				t.Fatalf("Expected the provider to be stopped, running %v with error %v", p.IsRunning(), a.StopErr())
+			}
+		})
+	}
+}
+
+// waitingScenario runs until it is cancelled
+type waitingScenario struct {
+	chattyScenario
+}
+
+func (s *waitingScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestApp_QuitMidRunCancelsScenario(t *testing.T) {
+	p := &stoppableProvider{scenarios: scenario.NewRegistry(), running: true}
+	a := NewApp(provider.NewRegistry())
+	a.Update(ProviderStartedMsg{Provider: p})
+	_, cmd := a.Update(ScenarioSelectedMsg{Scenario: &waitingScenario{}})
+	_, cmd = a.Update(cmd())
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("Expected the run to be batched with the runner ticks")
+	}
+
+	outcome := make(chan tea.Msg, 1)
+	go func() { outcome <- batch[0]() }()
+
+	_, cmd = a.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	runUntilQuit(t, a, cmd)
+
+	select {
+	case msg := <-outcome:
+		complete, ok := msg.(runnerCompleteMsg)
+		if !ok || !errors.Is(complete.err, context.Canceled) {
+			t.Fatalf("Expected the run to be cancelled, got %#v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected quitting to cancel the run")
+	}
+}
+
+// tidyScenario sends a step, then runs until it is cancelled, counting its cleanups
+type tidyScenario struct {
+	chattyScenario
+	cleanups atomic.Int32
+}
+
+func (s *tidyScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	output <- scenario.StepResult{Session: "Session A", Step: 1, Description: "Step", Success: true}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (s *tidyScenario) Cleanup(ctx context.Context) error {
+	time.Sleep(50 * time.Millisecond) // Long enough for a provider stopped without waiting to stop first
+	s.cleanups.Add(1)
+	return nil
+}
+
+// watchedProvider calls onStop as it stops
+type watchedProvider struct {
+	stoppableProvider
+	onStop func()
+}
+
+func (p *watchedProvider) Stop(ctx context.Context) error {
+	p.onStop()
+	return p.stoppableProvider.Stop(ctx)
+}
+
+func TestApp_CleansUpRunLeftMidway(t *testing.T) {
+	tests := []struct {
+		name  string
+		leave func(t *testing.T, a *App)
+	}{
+		{"navigation", func(t *testing.T, a *App) {
+			a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			if a.currentView != ViewScenarioList {
+				t.Fatalf("Expected esc to go back to the scenarios, got %v", a.currentView)
+			}
+		}},
+		{"navigation to the providers", func(t *testing.T, a *App) {
+			a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			if a.currentView != ViewProviderSelect || cmd == nil {
+				t.Fatalf("Expected a second esc to stop the provider, got %v", a.currentView)
+			}
+			for _, cmd := range cmd().(tea.BatchMsg) {
+				if msg, ok := cmd().(ProviderStoppedMsg); ok {
+					a.Update(msg)
+					return
+				}
+			}
+			t.Fatal("Expected the provider to stop")
+		}},
+		{"quit", func(t *testing.T, a *App) {
+			_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+			runUntilQuit(t, a, cmd)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &tidyScenario{}
+			p := &watchedProvider{stoppableProvider: stoppableProvider{scenarios: scenario.NewRegistry(), running: true}}
+			p.onStop = func() {
+				if got := s.cleanups.Load(); got != 1 {
+					t.Errorf("Expected the scenario cleaned up before the provider stops, got %d cleanups", got)
+				}
+			}
+			a := NewApp(provider.NewRegistry())
+			a.Update(ProviderStartedMsg{Provider: p})
+			_, cmd := a.Update(ScenarioSelectedMsg{Scenario: s})
+			_, cmd = a.Update(cmd())
+			batch := cmd().(tea.BatchMsg)
+			if _, ok := batch[0]().(runnerStepMsg); !ok {
+				t.Fatal("Expected the run to start")
+			}
+
+			tt.leave(t, a)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := a.cleanups.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.cleanups.Load(); got != 1 {
+				t.Fatalf("Expected the scenario to be cleaned up once, got %d", got)
+			}
+		})
+	}
+}
+
+// hangingProvider never finishes stopping until released
+type hangingProvider struct {
+	stoppableProvider
+	release chan struct{}
+}
+
+func (p *hangingProvider) Stop(ctx context.Context) error {
+	<-p.release
+	return nil
+}
+
+func TestApp_SecondCtrlCForcesQuit(t *testing.T) {
+	p := &hangingProvider{stoppableProvider: stoppableProvider{scenarios: scenario.NewRegistry()}, release: make(chan struct{})}
+	defer close(p.release)
+	a := NewApp(provider.NewRegistry())
+	a.Update(ProviderStartedMsg{Provider: p})
+
+	if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd == nil {
+		t.Fatal("Expected the shutdown to start")
+	}
+	_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatal("Expected a second ctrl+c to quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("Expected a second ctrl+c to quit without waiting for the provider")
+	}
+}
+
+func TestApp_ShutdownGivesUpOnHangingProvider(t *testing.T) {
+	defer func(timeout time.Duration) { shutdownTimeout = timeout }(shutdownTimeout)
+	shutdownTimeout = 10 * time.Millisecond
+
+	p := &hangingProvider{stoppableProvider: stoppableProvider{scenarios: scenario.NewRegistry()}, release: make(chan struct{})}
+	defer close(p.release)
+	a := NewApp(provider.NewRegistry())
+	a.Update(ProviderStartedMsg{Provider: p})
+
+	_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	runUntilQuit(t, a, cmd)
+	if !errors.Is(a.StopErr(), context.DeadlineExceeded) {
+		t.Fatalf("Expected the stop to time out, got %v", a.StopErr())
+	}
+}
+
+func TestApp_SizesLateCreatedRunner(t *testing.T) {
+	a := NewApp(provider.NewRegistry())
+	a.Update(tea.WindowSizeMsg{Width: 150, Height: 40})
+	a.Update(ScenarioSelectedMsg{Scenario: &chattyScenario{n: 1}})
+
+	if a.runner.width != 150 || a.runner.height != 40 {
+		t.Fatalf("Expected the runner to be created at 150x40, got %dx%d", a.runner.width, a.runner.height)
+	}
+}
+
+func TestApp_HelpFitsAndScrolls(t *testing.T) {
+	a := NewApp(provider.NewRegistry())
+	a.Update(tea.WindowSizeMsg{Width: 40, Height: 15})
+	a.currentView = ViewHelp
+
+	before := a.View()
+	if lines := strings.Count(before, "\n") + 1; lines > 15 {
+		t.Fatalf("Expected the help to fit in 15 lines, got %d:\n%s", lines, before)
+	}
+	for _, line := range strings.Split(before, "\n") {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Fatalf("Expected the help to wrap to 40 columns, got %d in %q", w, line)
+		}
+	}
+	if !strings.Contains(before, "0%") {
+		t.Fatalf("Expected the scroll percentage in the footer, got:\n%s", before)
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if a.View() == before {
+		t.Fatal("Expected ↓ to scroll the help")
+	}
+
+	for _, binding := range []key.Binding{keys.Up, keys.Quiz, keys.RetryPull} {
+		if !strings.Contains(a.help.content(), binding.Help().Desc) {
+			t.Fatalf("Expected the help to list %q", binding.Help().Desc)
+		}
+	}
+}
+
+func TestApp_TypingInFilterDoesNotNavigate(t *testing.T) {
+	p := &stoppableProvider{scenarios: scenario.NewRegistry(), running: true}
+	p.scenarios.Register(&chattyScenario{n: 1})
+	a := NewApp(provider.NewRegistry())
+	a.Update(ProviderStartedMsg{Provider: p})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range "quit" {
+		a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if a.currentView != ViewScenarioList || a.scenarioList.filter.Value() != "quit" {
+		t.Fatalf("Expected to type into the filter on the scenario list, got %s with %q", a.currentView, a.scenarioList.filter.Value())
+	}
+	if a.scenarioList.Selected() != nil {
+		t.Fatal("Expected no scenario to match the filter")
+	}
+
+	// esc leaves the field, clearing the filter, and only then q navigates
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.currentView != ViewScenarioList || a.scenarioList.Focused() || a.scenarioList.Selected() == nil {
+		t.Fatalf("Expected esc to clear the filter and stay on the list, got %s", a.currentView)
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if a.currentView != ViewProviderSelect {
+		t.Fatalf("Expected q to go back once the filter is left, got %s", a.currentView)
+	}
+}
+
+// stuckProvider fails to stop, like a container the Docker API refuses to remove
+type stuckProvider struct {
+	stoppableProvider
+}
+
+func (p *stuckProvider) Stop(ctx context.Context) error { return errors.New("permission denied") }
+
+func TestApp_StopErrorIsShownAndReportedOnQuit(t *testing.T) {
+	p := &stuckProvider{stoppableProvider{scenarios: scenario.NewRegistry(), running: true}}
+	providers := provider.NewRegistry()
+	providers.Register(p)
+	a := NewApp(providers)
+	a.Update(ProviderStartedMsg{Provider: p})
+
+	_, stopCmd := a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	for _, cmd := range stopCmd().(tea.BatchMsg) {
+		if msg, ok := cmd().(ProviderStoppedMsg); ok {
+			a.Update(msg)
+		}
+	}
+	if a.currentView != ViewProviderSelect || a.err != nil {
+		t.Fatalf("Expected to stay on the provider list, got %v with error %v", a.currentView, a.err)
+	}
+	if view := a.View(); !strings.Contains(view, "failed to stop Fake: permission denied") {
+		t.Fatalf("Expected a warning naming the provider and the error, got:\n%s", view)
+	}
+
+	_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	runUntilQuit(t, a, cmd)
+	if err := a.StopErr(); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Fatalf("Expected the failed stop to be reported on exit, got %v", err)
+	}
+}
+
+// unstartableProvider fails to start, like a provider whose image can't be pulled
+type unstartableProvider struct {
+	stoppableProvider
+}
+
+func (p *unstartableProvider) Name() string                    { return "Unstartable" }
+func (p *unstartableProvider) Start(ctx context.Context) error { return errors.New("image not found") }
+
+func TestApp_StartErrorReturnsToProviderList(t *testing.T) {
+	providers := provider.NewRegistry()
+	providers.Register(&stoppableProvider{scenarios: scenario.NewRegistry()})
+	providers.Register(&unstartableProvider{})
+	a := NewApp(providers)
+	a.currentView = ViewProviderSelect
+	a.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if a.providerList.Selected().Name() != "Unstartable" {
+		t.Fatalf("Expected the failing provider to be selected, got %s", a.providerList.Selected().Name())
+	}
+
+	_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	a.Update(cmd().(tea.BatchMsg)[2]())
+	if !strings.Contains(a.View(), "image not found") {
+		t.Fatalf("Expected the start error to be shown, got %q", a.View())
+	}
+
+	// Keys other than the ones dismissing the error must not reach the list behind it
+	a.Update(tea.KeyMsg{Type: tea.KeyUp})
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.err != nil || a.currentView != ViewProviderSelect {
+		t.Fatalf("Expected esc to return to the provider list, got %s with error %v", a.currentView, a.err)
+	}
+	if a.providerList.Selected().Name() != "Unstartable" {
+		t.Fatalf("Expected the cursor to stay on the failed provider, got %s", a.providerList.Selected().Name())
+	}
+}
+
+func TestApp_ExportErrorReturnsToFailedRun(t *testing.T) {
+	p := &stoppableProvider{scenarios: scenario.NewRegistry(), running: true}
+	p.scenarios.Register(&chattyScenario{n: 1})
+	p.scenarios.Register(&failingScenario{})
+	a := NewApp(provider.NewRegistry())
+	a.Update(ProviderStartedMsg{Provider: p})
+	a.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = a.Update(cmd())
+	_, cmd = a.Update(cmd())
+	a.Update(cmd().(tea.BatchMsg)[0]())
+	if a.runner.Active() || a.runner.err == nil {
+		t.Fatalf("Expected the run to fail, got error %v", a.runner.err)
+	}
+
+	_, cmd = a.Update(exportDoneMsg{err: errors.New("permission denied")})
+	a.Update(cmd())
+	if !strings.Contains(a.View(), "permission denied") {
+		t.Fatalf("Expected the export error to be shown, got %q", a.View())
+	}
+
+	// Dismissing the error shows the failed run again, and leaving it the list at the same scenario
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.currentView != ViewRunner || !strings.Contains(a.View(), "connection refused") {
+		t.Fatalf("Expected esc to return to the failed run, got %s", a.currentView)
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.currentView != ViewScenarioList || a.scenarioList.Selected().Name() != "Failing" {
+		t.Fatalf("Expected to return to the failed scenario in the list, got %s", a.currentView)
+	}
+}
+
+// remoteProvider connects to an existing database, so it needs no container runtime
+type remoteProvider struct {
+	stoppableProvider
+}
+
+func (p *remoteProvider) Name() string       { return "Remote" }
+func (p *remoteProvider) NeedsRuntime() bool { return false }
+
+func TestApp_ProviderListDisablesProvidersWithoutRuntime(t *testing.T) {
+	providers := provider.NewRegistry()
+	providers.Register(&stoppableProvider{scenarios: scenario.NewRegistry()})
+	providers.Register(&remoteProvider{stoppableProvider{scenarios: scenario.NewRegistry()}})
+	a := NewApp(providers)
+	a.detector = &containerruntime.Detector{FS: fstest.MapFS{}, Getenv: func(string) string { return "" }}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if a.currentView != ViewProviderSelect {
+		t.Fatalf("Expected the provider list without a runtime, got %s", a.currentView)
+	}
+	if n := strings.Count(a.View(), "Docker unavailable"); n != 1 {
+		t.Fatalf("Expected only the container provider to be tagged, got %d tags", n)
+	}
+
+	// Selecting the disabled provider explains the probe failure instead of starting it
+	if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || a.currentView == ViewLoading {
+		t.Fatal("Expected the disabled provider not to start")
+	}
+	if !strings.Contains(a.View(), "no container runtime found") {
+		t.Fatalf("Expected the probe failure to be shown, got %q", a.View())
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	a.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || a.currentView != ViewLoading {
+		t.Fatalf("Expected the provider needing no runtime to start, got %s", a.currentView)
+	}
+}
+
+func TestApp_ASCIIModeRendersEveryView(t *testing.T) {
+	i18n.SetASCII(true)
+	defer i18n.SetASCII(false)
+
+	p := mongodb.NewProvider()
+	providers := provider.NewRegistry()
+	providers.Register(p)
+	a := NewApp(providers)
+
+	var s scenario.Scenario
+	for _, candidate := range p.GetScenarios().GetAll() {
+		if len(scenario.QuizOf(candidate)) > 0 {
+			s = candidate
+			break
+		}
+	}
+	steps := []scenario.StepResult{
+		{IsHeader: true, Description: "⚔️ Part 1"},
+		{Session: "Session A", Step: 1, Description: "Reading", Query: "db.demo.find()", Result: "✅ 1 document", Success: true},
+		{Session: "Session B", Step: 2, Description: "Writing", Result: "❌ WriteConflict", Success: false},
+	}
+
+	a.options = NewProviderOptionsModel(p, p)
+	a.loading = NewLoadingModel("Starting MongoDB...")
+	a.loading.AddMessage("🐳 Pulling image")
+	a.scenarioList = NewScenarioListModel(p)
+	a.runner = NewRunnerModel(s, scenario.DefaultParams())
+	a.runner.record.Steps, a.runner.running = steps, true
+	a.runtimeSetup = NewRuntimeSetupModel(a.detector)
+	a.imagePull = NewImagePullModel(a.providerImages())
+	a.quiz = NewQuizModel(s.Name(), scenario.QuizOf(s), steps)
+
+	screens := map[string]func(){}
+	for view := ViewMenu; view <= ViewQuiz; view++ {
+		screens[view.String()] = func() { a.currentView = view }
+	}
+	screens["finished run"] = func() {
+		a.currentView = ViewRunner
+		a.runner.running, a.runner.done = false, true
+	}
+	screens["error"] = func() { a.err = errors.New("connection refused") }
+	screens["quitting"] = func() {
+		a.quitting = true
+		a.shutdown = []string{SuccessStyle.Render(i18n.T("✓ %s stopped", "MongoDB"))}
+	}
+
+	for _, name := range []string{"menu", "provider-select", "provider-options", "loading", "scenario-list", "runner", "help",
+		"runtime-setup", "image-pull", "quiz", "finished run", "error", "quitting"} {
+		screens[name]()
+		for _, r := range a.View() {
+			if r > unicode.MaxASCII {
+				t.Fatalf("Expected only ASCII on the %s screen, got %q in:\n%s", name, r, a.View())
+			}
+		}
+	}
+}
+
+// gatedProvider starts once release is closed, counting its starts
+type gatedProvider struct {
+	remoteProvider
+	release chan struct{}
+	starts  atomic.Int32
+}
+
+func (p *gatedProvider) Start(ctx context.Context) error {
+	p.starts.Add(1)
+	select {
+	case <-p.release:
+		p.running = true
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestApp_SelectingPrewarmedProviderAttachesToItsStart(t *testing.T) {
+	p := &gatedProvider{remoteProvider: remoteProvider{stoppableProvider{scenarios: scenario.NewRegistry()}}, release: make(chan struct{})}
+	providers := provider.NewRegistry()
+	providers.Register(p)
+	a := NewApp(providers)
+	a.Prewarm(p)
+
+	warmed := a.Init()
+	if warmed == nil || !strings.Contains(a.View(), "Starting Remote in the background") {
+		t.Fatalf("Expected the provider to start in the background, got %q", a.View())
+	}
+
+	a.currentView = ViewProviderSelect
+	_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || a.currentView != ViewLoading {
+		t.Fatalf("Expected the loading view, got %s", a.currentView)
+	}
+	batch := cmd().(tea.BatchMsg)
+
+	close(p.release)
+	a.Update(warmed())
+	a.Update(batch[2]())
+	if a.currentView != ViewScenarioList || a.selectedProvider != p {
+		t.Fatalf("Expected the attached start to open the scenario list, got %s", a.currentView)
+	}
+	if n := p.starts.Load(); n != 1 {
+		t.Fatalf("Expected one start shared by both paths, got %d", n)
+	}
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+// castWidth is the terminal width recorded in casts; the height grows to fit the longest frame
+const (
+	castWidth     = 100
+	castMinHeight = 24
+)
+
+// WriteCast replays a recorded run through the runner view, one frame per step,
+// and writes it as an asciinema v2 recording
+func WriteCast(w io.Writer, run *report.Run) error {
+	run, err := run.Whole()
+	if err != nil {
+		return err
+	}
+	params := scenario.DefaultParams()
+	params.Seed = run.Seed
+	r := NewRunnerModel(recordedScenario{run}, params)
+	r.running = true
+
+	frames := []report.CastFrame{{At: 0, Screen: r.View()}}
+	for i := range run.Steps {
+		r.record.Steps = run.Steps[:i+1]
+		r.frame++
+		frames = append(frames, report.CastFrame{At: run.StepOffset(i), Screen: r.View()})
+	}
+
+	r.running, r.done, r.err = false, true, run.Err
+	end := run.Duration
+	if n := len(run.Steps); n > 0 && end < run.StepOffset(n-1) {
+		end = run.StepOffset(n - 1)
+	}
+	frames = append(frames, report.CastFrame{At: end, Screen: r.View()})
+
+	height := castMinHeight
+	for _, f := range frames {
+		height = max(height, strings.Count(f.Screen, "\n")+1)
+	}
+	return report.WriteCast(w, castWidth, height, run.Scenario, run.Started, frames)
+}
+
+// recordedScenario presents a finished run as a scenario so the runner view can render it
+type recordedScenario struct {
+	run *report.Run
+}
+
+func (s recordedScenario) Name() string                      { return s.run.Scenario }
+func (s recordedScenario) Description() string               { return s.run.Description }
+func (s recordedScenario) IsolationLevel() string            { return s.run.IsolationLevel }
+func (s recordedScenario) Setup(ctx context.Context) error   { return nil }
+func (s recordedScenario) Cleanup(ctx context.Context) error { return nil }
+
+// Run replays nothing; the recorded steps are set on the runner directly
+func (s recordedScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	return nil
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+)
+
+func TestWriteCast_OneFramePerStep(t *testing.T) {
+	run := &report.Run{
+		Scenario:       "Write Conflict Detection",
+		IsolationLevel: "Serializable",
+		Started:        time.Unix(1700000000, 0),
+		Duration:       2 * time.Second,
+		Steps: []scenario.StepResult{
+			{Session: "Session A", Step: 1, Description: "Update", Success: true},
+			{Session: "Session B", Step: 2, Description: "Conflicting update", Success: false},
+		},
+		Offsets: []time.Duration{500 * time.Millisecond, time.Second},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCast(&buf, run); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	// Header, the empty runner, one frame per step and the finished view
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[2], "[0.5,") || !strings.Contains(lines[2], "Session A") {
+		t.Fatalf("Expected first step frame at 0.5s, got %s", lines[2])
+	}
+	if !strings.Contains(lines[4], "Complete") {
+		t.Fatalf("Expected final frame to show completion, got %s", lines[4])
+	}
+}
@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// clipboardMsg reports the outcome of copying text to the clipboard
+type clipboardMsg struct {
+	text string
+	err  error
+}
+
+// copyToClipboard copies text with the system clipboard tool, falling back to an
+// OSC 52 escape sequence, which terminals honor even over SSH
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err != nil {
+			termenv.Copy(text)
+		}
+		return clipboardMsg{text: text}
+	}
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compareGutter is the width of the divergence marker in front of each row
+const compareGutter = 3
+
+// RenderComparison lays out two runs of the same anomaly side by side in width columns,
+// marking the rows where their outcomes diverge
+func RenderComparison(c *report.Comparison, width int) string {
+	column := max((width-compareGutter-2)/2, 20)
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryColor).
+		Render(i18n.T("⚖️  Comparison: %s", c.Anomaly)))
+	b.WriteString("\n\n")
+
+	b.WriteString(compareRow("", column,
+		compareSide(c.Left, column),
+		compareSide(c.Right, column)))
+	b.WriteString("\n")
+	b.WriteString(muted.Render(strings.Repeat("─", compareGutter+2*column+2)))
+	b.WriteString("\n")
+
+	var steps []scenario.StepResult
+	for _, r := range c.Rows {
+		for _, step := range []*scenario.StepResult{r.Left, r.Right} {
+			if step != nil {
+				steps = append(steps, *step)
+			}
+		}
+	}
+	sessions := sessionWidth(steps)
+
+	for _, r := range c.Rows {
+		marker := ""
+		if r.Diverges() {
+			marker = ErrorStyle.Render("≠")
+		}
+		b.WriteString(compareRow(marker, column,
+			compareStep(r.Left, column, sessions),
+			compareStep(r.Right, column, sessions)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if c.Diverged() {
+		b.WriteString(WarningStyle.Render(i18n.T("≠ marks where the runs diverge")))
+	} else {
+		b.WriteString(SuccessStyle.Render(i18n.T("The runs had the same outcome at every step")))
+	}
+	b.WriteString("\n")
+	return i18n.Render(b.String())
+}
+
+// compareRow joins the two columns of a row behind the marker
+func compareRow(marker string, column int, left, right string) string {
+	cell := lipgloss.NewStyle().Width(column).MarginRight(2)
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		fit(lipgloss.NewStyle().Width(compareGutter), marker),
+		fit(cell, left),
+		fit(lipgloss.NewStyle().Width(column), right))
+}
+
+// compareSide renders the heading of one run: provider, scenario and verdict
+func compareSide(side report.Side, column int) string {
+	verdict := SuccessStyle
+	if v := side.Run.Verdict(); v == report.VerdictFail || v == report.VerdictError {
+		verdict = ErrorStyle
+	}
+	heading := fmt.Sprintf("%s\n%s\n%s",
+		lipgloss.NewStyle().Bold(true).Render(side.Provider),
+		lipgloss.NewStyle().Foreground(mutedColor).Render(fmt.Sprintf("%s · %s", side.Run.Scenario, side.Run.IsolationLevel)),
+		verdict.Render(string(side.Run.Verdict())))
+	if side.Run.Err != nil {
+		heading += "\n" + fit(ErrorStyle.Width(column), side.Run.Err.Error())
+	}
+	return heading
+}
+
+// compareStep renders one step of a row with its session padded to sessions columns, or a dash
+// when the run has no matching step
+func compareStep(step *scenario.StepResult, column, sessions int) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	if step == nil {
+		return muted.Render("—")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s",
+		muted.Render(fmt.Sprintf("[%d]", step.Step)),
+		SessionStyle(step.Session).Render(sessionLabel(step.Session, sessions)),
+		step.Description)
+	if step.Result != "" {
+		style := ResultStyle
+		mark := "✓"
+		if !step.Success {
+			style = ErrorStyle
+			mark = "✗"
+		}
+		b.WriteString("\n" + fit(style.Width(column), mark+" "+step.Result))
+	}
+	return b.String()
+}
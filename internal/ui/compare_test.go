@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// update returns whether -update asks to rewrite golden files. The flag is registered by
+// teatest's golden package, which this package's tests import.
+func update() bool {
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+func TestRenderComparison_EmojiKeepsColumnsAligned(t *testing.T) {
+	left := &report.Run{Scenario: "Write Conflict", IsolationLevel: "snapshot", Steps: []scenario.StepResult{
+		{Session: "Session A", Step: 1, Description: "⚔️ Update the 👨‍👩‍👧 family account", Result: "✅ Updated ≠ committed", Role: scenario.RoleRead, Success: true},
+		{Session: "🧑‍💻 Session B", Step: 2, Description: "🔒 Update the same document", Result: "❌ WriteConflict 💥", Role: scenario.RoleConcurrentWrite, Success: false},
+	}}
+	right := &report.Run{Scenario: "Write Conflict", IsolationLevel: "serializable", Steps: []scenario.StepResult{
+		{Session: "Session A", Step: 1, Description: "⚔️ Update the 👨‍👩‍👧 family account", Result: "✅ Updated", Role: scenario.RoleRead, Success: true},
+		{Session: "🧑‍💻 Session B", Step: 2, Description: "⏳ Waits for the lock ← 🔒", Result: "✅ Updated after A committed", Role: scenario.RoleConcurrentWrite, Success: true},
+	}, Err: errors.New("⚠️ slow lock")}
+	c := report.NewComparison(scenario.AnomalyLostUpdate, report.Side{Provider: "🍃 MongoDB", Run: left}, report.Side{Provider: "🐘 Other", Run: right})
+
+	for _, mode := range []struct {
+		name  string
+		ascii bool
+	}{{"comparison", false}, {"comparison.ascii", true}} {
+		t.Run(mode.name, func(t *testing.T) {
+			i18n.SetASCII(mode.ascii)
+			defer i18n.SetASCII(false)
+
+			const width = 80
+			got := RenderComparison(c, width)
+
+			// Between the rule under the headings and the blank line before the legend, every line
+			// is a row padded to the full width; a miscounted emoji would shift its right column
+			lines := strings.Split(got, "\n")
+			rows := false
+			for _, line := range lines {
+				switch {
+				case strings.HasPrefix(line, i18n.Render("─")):
+					rows = true
+					fallthrough
+				case rows && line != "":
+					if w := ansi.StringWidth(line); w != width-1 {
+						t.Fatalf("Expected every row %d columns wide, got %d in %q", width-1, w, line)
+					}
+				case rows:
+					rows = false
+				}
+			}
+			if mode.ascii && i18n.Render(got) != got {
+				t.Fatalf("Expected only ASCII in ASCII mode, got %q", got)
+			}
+
+			checkGolden(t, mode.name, got)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/fake"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+// countingFake is the fake provider, counting its stops
+type countingFake struct {
+	*fake.Provider
+	stops atomic.Int32
+}
+
+func (p *countingFake) Stop(ctx context.Context) error {
+	p.stops.Add(1)
+	return p.Provider.Stop(ctx)
+}
+
+// countingListener counts the runs the app starts
+type countingListener struct {
+	started atomic.Int32
+}
+
+func (l *countingListener) RunStarted(run *report.Run) error {
+	l.started.Add(1)
+	return nil
+}
+
+func (l *countingListener) WriteStep(step scenario.StepResult) error { return nil }
+func (l *countingListener) RunFinished(run *report.Run) error        { return nil }
+
+// e2e drives the whole app in a bubbletea program, as a user at a terminal would
+type e2e struct {
+	t  *testing.T
+	tm *teatest.TestModel
+}
+
+func newE2E(t *testing.T, a *App) *e2e {
+	t.Helper()
+	return &e2e{t: t, tm: teatest.NewTestModel(t, a, teatest.WithInitialTermSize(100, 40))}
+}
+
+// waitFor blocks until the screen printed every text since the last wait. Output read while
+// waiting is gone for the next wait, so texts of one frame are waited for together.
+func (e *e2e) waitFor(texts ...string) {
+	e.t.Helper()
+	teatest.WaitFor(e.t, e.tm.Output(), func(out []byte) bool {
+		for _, text := range texts {
+			if !bytes.Contains(out, []byte(text)) {
+				return false
+			}
+		}
+		return true
+	}, teatest.WithDuration(5*time.Second), teatest.WithCheckInterval(10*time.Millisecond))
+}
+
+// press sends keys one by one
+func (e *e2e) press(keys ...tea.KeyType) {
+	for _, k := range keys {
+		e.tm.Send(tea.KeyMsg{Type: k})
+	}
+}
+
+func TestE2E_RunAScenarioOnTheFakeProvider(t *testing.T) {
+	p := &countingFake{Provider: fake.NewProvider()}
+	if err := p.ApplySetting("pacing", "none"); err != nil {
+		t.Fatal(err)
+	}
+	providers := provider.NewRegistry()
+	providers.Register(p)
+	runs := &countingListener{}
+	a := NewApp(providers)
+	a.SetRunListener(runs)
+	e := newE2E(t, a)
+
+	e.waitFor("Select Database")
+	e.press(tea.KeyEnter) // Menu: Select Database
+	e.waitFor("Fake")
+	e.press(tea.KeyEnter) // Provider list: Fake
+	e.waitFor("Pacing")
+	e.press(tea.KeyEnter) // Options: start
+	e.waitFor("Dirty Read Prevention")
+
+	// Typing into the filter must not navigate, even keys that do elsewhere
+	e.tm.Type("/q")
+	e.waitFor("Filter: q")
+	e.press(tea.KeyEsc) // Clears the filter and leaves it
+	e.waitFor("Lost Update Prevention")
+
+	// A second enter before the run opens must not launch it again
+	e.press(tea.KeyEnter, tea.KeyEnter)
+	e.waitFor("Value: 100", "Complete")
+	if n := runs.started.Load(); n != 1 {
+		t.Fatalf("Expected one run, got %d", n)
+	}
+
+	e.press(tea.KeyEsc) // Back to the scenarios
+	e.waitFor("Lost Update Prevention")
+	e.press(tea.KeyEsc) // Back to the providers, stopping Fake
+	e.waitFor("Fake")
+	e.press(tea.KeyEsc) // Back to the menu
+	e.waitFor("Select Database")
+	e.tm.Type("q")
+	e.tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+
+	if n := p.stops.Load(); n == 0 {
+		t.Fatal("Expected the provider to be stopped")
+	}
+	if p.IsRunning() {
+		t.Fatal("Expected the provider to be stopped on quit")
+	}
+}
+
+func TestE2E_QuitDuringARunStopsTheProvider(t *testing.T) {
+	p := &countingFake{Provider: fake.NewProvider()}
+	providers := provider.NewRegistry()
+	providers.Register(p)
+	e := newE2E(t, NewApp(providers))
+
+	e.waitFor("Select Database")
+	e.press(tea.KeyEnter)
+	e.waitFor("Fake")
+	e.press(tea.KeyEnter)
+	e.waitFor("Pacing")
+	e.press(tea.KeyEnter)
+	e.waitFor("Dirty Read Prevention")
+	e.press(tea.KeyEnter) // Runs at the normal pace, so it is still running when quitting
+	e.waitFor("Running")
+
+	e.press(tea.KeyCtrlC)
+	e.tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+	if p.stops.Load() == 0 || p.IsRunning() {
+		t.Fatal("Expected quitting to stop the provider")
+	}
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportDoneMsg reports where an exported run was written
+type exportDoneMsg struct {
+	path string
+	err  error
+}
+
+// exportSuite writes the suite as a document to a new file in the working directory
+func exportSuite(suite *report.Suite, format report.Format) tea.Cmd {
+	return exportFile(suite.FileName(format.Ext()), func(w io.Writer) error {
+		return report.Write(w, suite, format)
+	})
+}
+
+// exportCast writes the suite's only run as an asciinema recording in the working directory
+func exportCast(suite *report.Suite) tea.Cmd {
+	return exportFile(suite.FileName(".cast"), func(w io.Writer) error {
+		return WriteCast(w, suite.Runs[0])
+	})
+}
+
+// exportFile creates path and fills it with write
+func exportFile(path string, write func(w io.Writer) error) tea.Cmd {
+	return func() tea.Msg {
+		f, err := os.Create(path)
+		if err != nil {
+			return exportDoneMsg{err: fmt.Errorf("failed to create %s: %w", path, err)}
+		}
+		if err := write(f); err != nil {
+			f.Close()
+			return exportDoneMsg{err: fmt.Errorf("failed to write %s: %w", path, err)}
+		}
+		return exportDoneMsg{path: path, err: f.Close()}
+	}
+}
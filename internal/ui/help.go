@@ -3,30 +3,91 @@ package ui
 import (
 	"strings"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// helpChrome is the number of lines around the scrolled content: the header with its margin and
+// the footer with its margin
+const helpChrome = 4
+
+// helpKeyWidth is the width of the key column in the key binding sections
+const helpKeyWidth = 10
+
 // HelpModel represents the help and about screen
 type HelpModel struct {
-	viewportHeight int
-	viewportWidth  int
+	viewport viewport.Model
+	width    int
+	height   int
 }
 
 // NewHelpModel creates a new help model
 func NewHelpModel() *HelpModel {
-	return &HelpModel{}
+	m := &HelpModel{viewport: viewport.New(0, 0)}
+	m.SetSize(80, 24)
+	return m
+}
+
+// SetSize fits the help into the terminal, wrapping it to the width and scrolling what doesn't
+// fit in the height
+func (m *HelpModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = width
+	m.viewport.Height = max(height-helpChrome, 1)
+	m.viewport.SetContent(m.content())
+}
+
+// GotoTop scrolls back to the start of the help
+func (m *HelpModel) GotoTop() {
+	m.viewport.GotoTop()
 }
 
 // Update handles help input
 func (m *HelpModel) Update(msg tea.Msg) (*HelpModel, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.viewportWidth = msg.Width
-		m.viewportHeight = msg.Height
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.SetSize(size.Width, size.Height)
+		return m, nil
 	}
-	// Main app handles navigation back with Esc/q
-	return m, nil
+	// Main app handles navigation back with Esc/q; the rest scrolls
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// content returns the about text followed by the key bindings, wrapped to the width
+func (m *HelpModel) content() string {
+	width := textWidth(m.width, 2, 0)
+	text := lipgloss.NewStyle().Width(width)
+	indent := lipgloss.NewStyle().PaddingLeft(2)
+
+	about := i18n.T(`TxDemo is an interactive CLI tool for demonstrating database transaction isolation levels.
+
+It helps developers visualize and understand:
+• Dirty Reads
+• Non-Repeatable Reads
+• Phantom Reads
+• Serialization Anomalies
+
+Created for educational purposes.`)
+
+	var b strings.Builder
+	b.WriteString(indent.Render(fit(text, about)) + "\n")
+
+	keyColumn := lipgloss.NewStyle().Width(helpKeyWidth).Foreground(lipgloss.Color("#7C3AED"))
+	desc := lipgloss.NewStyle().Width(max(width-helpKeyWidth, 10))
+	for _, s := range keys.sections() {
+		b.WriteString("\n" + indent.Render(lipgloss.NewStyle().Bold(true).Render(s.title)) + "\n")
+		for _, binding := range s.bindings {
+			h := binding.Help()
+			row := lipgloss.JoinHorizontal(lipgloss.Top, fit(keyColumn, h.Key), fit(desc, h.Desc))
+			b.WriteString(indent.Render(row) + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
 }
 
 // View renders the help screen
@@ -38,40 +99,17 @@ func (m *HelpModel) View() string {
 		Bold(true).
 		Foreground(lipgloss.Color("#7C3AED")).
 		MarginBottom(1).
-		Render("❓ Help & About")
+		Render(i18n.T("❓ Help & About"))
 
 	b.WriteString(header + "\n")
+	b.WriteString(m.viewport.View() + "\n")
 
-	// Content
-	content := `
-TxDemo is an interactive CLI tool for demonstrating database transaction isolation levels.
-
-It helps developers visualize and understand:
-• Dirty Reads
-• Non-Repeatable Reads
-• Phantom Reads
-• Serialization Anomalies
-
-navigation:
-• Use ↑/↓ to navigate menus
-• Press Enter to select items
-• Press Esc to go back
-• Press q to quit
-
-Created for educational purposes.
-`
-	// Simple indentation for content
-	lines := strings.Split(strings.TrimSpace(content), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			b.WriteString("\n")
-		} else {
-			b.WriteString("  " + line + "\n")
-		}
+	if m.viewport.TotalLineCount() > m.viewport.Height {
+		percent := int(m.viewport.ScrollPercent() * 100)
+		b.WriteString(HelpStyle.Render(i18n.T("↑/↓ scroll • %d%% • esc/q back", percent)))
+	} else {
+		b.WriteString(HelpStyle.Render(i18n.T("esc/q back")))
 	}
 
-	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("esc back • q quit"))
-
 	return b.String()
 }
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/imagepull"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ImagePullModel pulls provider images ahead of time and shows per-image progress
+type ImagePullModel struct {
+	images  []string
+	state   map[string]imagepull.Progress
+	bar     progress.Model
+	updates <-chan imagepull.Progress // Channel of the current pull run
+	cancel  context.CancelFunc
+	running bool
+}
+
+// NewImagePullModel creates a new image pull model
+func NewImagePullModel(images []string) *ImagePullModel {
+	return &ImagePullModel{
+		images: images,
+		state:  make(map[string]imagepull.Progress),
+		bar:    progress.New(progress.WithDefaultGradient(), progress.WithWidth(40)),
+	}
+}
+
+// ImagePullMsg delivers one progress update from a pull run
+type ImagePullMsg struct {
+	Progress imagepull.Progress
+	updates  <-chan imagepull.Progress
+}
+
+// ImagePullDoneMsg is sent when every pull of a run has finished
+type ImagePullDoneMsg struct {
+	updates <-chan imagepull.Progress
+}
+
+// Start pulls every image that isn't complete yet
+func (m *ImagePullModel) Start() tea.Cmd {
+	if m.running {
+		return nil
+	}
+
+	var pending []string
+	for _, ref := range m.images {
+		if s, ok := m.state[ref]; !ok || !s.Done || s.Err != nil {
+			pending = append(pending, ref)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan imagepull.Progress, 64)
+	m.updates = updates
+	m.cancel = cancel
+	m.running = true
+
+	go imagepull.PullAll(ctx, pending, updates)
+	return waitForPull(updates)
+}
+
+// Cancel aborts the running pulls; completed layers are kept so Start resumes from them
+func (m *ImagePullModel) Cancel() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+// waitForPull returns a command that delivers the next pull update
+func waitForPull(updates <-chan imagepull.Progress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-updates
+		if !ok {
+			return ImagePullDoneMsg{updates: updates}
+		}
+		return ImagePullMsg{Progress: p, updates: updates}
+	}
+}
+
+// Update handles image pull input and progress
+func (m *ImagePullModel) Update(msg tea.Msg) (*ImagePullModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ImagePullMsg:
+		// Updates from a cancelled run are drained but no longer shown
+		if msg.updates == m.updates {
+			m.state[msg.Progress.Image] = msg.Progress
+		}
+		return m, waitForPull(msg.updates)
+
+	case ImagePullDoneMsg:
+		if msg.updates == m.updates {
+			m.running = false
+			m.cancel()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.CancelPull):
+			m.Cancel()
+		case key.Matches(msg, keys.RetryPull):
+			return m, m.Start()
+		}
+	}
+	return m, nil
+}
+
+// View renders the image pull screen
+func (m *ImagePullModel) View() string {
+	var b strings.Builder
+
+	// Header
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7C3AED")).
+		MarginBottom(1).
+		Render(i18n.T("📦 Prepare Images"))
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Render(i18n.T("Pull provider images now so later starts don't wait on downloads"))
+
+	b.WriteString("\n")
+	b.WriteString(title)
+	b.WriteString("\n")
+	b.WriteString(subtitle)
+	b.WriteString("\n\n")
+
+	if len(m.images) == 0 {
+		b.WriteString("  " + i18n.T("No registered provider uses container images.") + "\n")
+	}
+
+	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	for _, ref := range m.images {
+		s := m.state[ref]
+
+		status := s.Status
+		switch {
+		case s.Err != nil && s.Status == "Failed":
+			status = ErrorStyle.Render(s.Err.Error())
+		case s.Done && s.Err == nil:
+			status = SuccessStyle.Render(i18n.T("✓ Ready"))
+		case status == "":
+			status = i18n.T("Waiting...")
+		}
+
+		b.WriteString(fmt.Sprintf("  %s\n", ref))
+		b.WriteString(fmt.Sprintf("  %s\n", m.bar.ViewAs(s.Fraction)))
+		b.WriteString(fmt.Sprintf("  %s\n\n", statusStyle.Render(status)))
+	}
+
+	// Help
+	help := i18n.T("r resume • esc back")
+	if m.running {
+		help = i18n.T("c cancel • esc cancel and go back")
+	}
+	b.WriteString(HelpStyle.Render(help))
+
+	return b.String()
+}
+
+// offlineView explains a missing image that can't be pulled and how to get it
+func offlineView(err *imagepull.OfflineError) string {
+	var b strings.Builder
+	b.WriteString("\n  ")
+	b.WriteString(ErrorStyle.Render(i18n.T("📡 Image unavailable offline")))
+	b.WriteString("\n\n  ")
+	b.WriteString(i18n.T("%s is not present locally and the registry %s is unreachable.", err.Image, err.Registry))
+	b.WriteString("\n  " + i18n.T("Connect to the network, or pre-pull the image on a connected machine:") + "\n\n    ")
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#A78BFA")).Render("docker pull " + err.Image))
+	b.WriteString("\n\n  ")
+	b.WriteString(HelpStyle.Render(i18n.T("Registry check: %v", err.Err)))
+	b.WriteString("\n\n  " + i18n.T("Press esc to go back.") + "\n")
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds every key binding of the TUI, so the screens handling keys and the help screen
+// documenting them can't disagree
+type KeyMap struct {
+	// Everywhere
+	Up     key.Binding
+	Down   key.Binding
+	Select key.Binding
+	Back   key.Binding
+	Leave  key.Binding
+	Quit   key.Binding
+
+	// Provider list and options
+	SetupRuntime key.Binding
+	Prev         key.Binding
+	Next         key.Binding
+
+	// Scenario list
+	Filter    key.Binding
+	Reveal    key.Binding
+	CopyShell key.Binding
+
+	// Runner and quiz
+	Export         key.Binding
+	ExportMarkdown key.Binding
+	ExportHTML     key.Binding
+	ExportCast     key.Binding
+	Quiz           key.Binding
+	Answer         key.Binding
+	PageUp         key.Binding
+	PageDown       key.Binding
+	NextFailure    key.Binding
+
+	// Image pull
+	CancelPull key.Binding
+	RetryPull  key.Binding
+}
+
+// keys holds the bindings in use. NewApp rebuilds it, so the help texts are in the language chosen
+// at startup.
+var keys = newKeyMap()
+
+// newKeyMap returns the key bindings with help texts in the current language
+func newKeyMap() KeyMap {
+	return KeyMap{
+		Up:     key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", i18n.T("move up"))),
+		Down:   key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", i18n.T("move down"))),
+		Select: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", i18n.T("select"))),
+		Back:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", i18n.T("go back, or leave a text field"))),
+		Leave:  key.NewBinding(key.WithKeys("q"), key.WithHelp("q", i18n.T("go back; quits from the main menu"))),
+		Quit:   key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", i18n.T("quit from any screen; press again to skip cleanup"))),
+
+		SetupRuntime: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", i18n.T("enter a container runtime socket when none was found"))),
+		Prev:         key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", i18n.T("previous value of an option"))),
+		Next:         key.NewBinding(key.WithKeys("right", "l", " "), key.WithHelp("→/l", i18n.T("next value of an option"))),
+
+		Filter:    key.NewBinding(key.WithKeys("/"), key.WithHelp("/", i18n.T("filter scenarios; enter keeps the filter, esc clears it"))),
+		Reveal:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", i18n.T("show or hide connection credentials"))),
+		CopyShell: key.NewBinding(key.WithKeys("y"), key.WithHelp("y", i18n.T("copy the database shell command"))),
+
+		Export:         key.NewBinding(key.WithKeys("x"), key.WithHelp("x", i18n.T("export a finished run"))),
+		ExportMarkdown: key.NewBinding(key.WithKeys("m"), key.WithHelp("x m", i18n.T("export as Markdown"))),
+		ExportHTML:     key.NewBinding(key.WithKeys("h"), key.WithHelp("x h", i18n.T("export as HTML"))),
+		ExportCast:     key.NewBinding(key.WithKeys("c"), key.WithHelp("x c", i18n.T("export as an asciinema cast"))),
+		Quiz:           key.NewBinding(key.WithKeys("z"), key.WithHelp("z", i18n.T("quiz about a finished run"))),
+		Answer:         key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", i18n.T("answer a quiz question"))),
+		PageUp:         key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", i18n.T("scroll the steps a page up"))),
+		PageDown:       key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdown", i18n.T("scroll the steps a page down"))),
+		NextFailure:    key.NewBinding(key.WithKeys("f"), key.WithHelp("f", i18n.T("jump to the next failed step"))),
+
+		CancelPull: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", i18n.T("cancel pulling images"))),
+		RetryPull:  key.NewBinding(key.WithKeys("r"), key.WithHelp("r", i18n.T("pull images again"))),
+	}
+}
+
+// keySection is a group of bindings documented together on the help screen
+type keySection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// sections groups the bindings by the screens they work on
+func (k KeyMap) sections() []keySection {
+	return []keySection{
+		{i18n.T("Everywhere"), []key.Binding{k.Up, k.Down, k.Select, k.Back, k.Leave, k.Quit}},
+		{i18n.T("Providers"), []key.Binding{k.SetupRuntime, k.Prev, k.Next}},
+		{i18n.T("Scenarios"), []key.Binding{k.Filter, k.Reveal, k.CopyShell}},
+		{i18n.T("Runs"), []key.Binding{k.Export, k.ExportMarkdown, k.ExportHTML, k.ExportCast, k.Quiz, k.Answer, k.PageUp, k.PageDown, k.NextFailure}},
+		{i18n.T("Images"), []key.Binding{k.CancelPull, k.RetryPull}},
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -15,6 +17,8 @@ type LoadingModel struct {
 	messages []string
 	frame    int
 	done     bool
+	width    int // Terminal size; 0 until the first WindowSizeMsg
+	height   int
 }
 
 // NewLoadingModel creates a new loading model
@@ -31,28 +35,41 @@ func (l *LoadingModel) AddMessage(msg string) {
 	l.messages = append(l.messages, msg)
 }
 
-// SetDone marks loading as complete
+// SetSize records the terminal size, so long status messages wrap to its width
+func (l *LoadingModel) SetSize(width, height int) {
+	l.width = width
+	l.height = height
+}
+
+// SetDone marks loading as complete, which stops the spinner's ticks
 func (l *LoadingModel) SetDone() {
 	l.done = true
 }
 
-type loadingTickMsg struct{}
+// loadingTickMsg advances the spinner of the loading model that scheduled it
+type loadingTickMsg struct {
+	loading *LoadingModel
+}
 
 // Tick returns a command that ticks the spinner
 func (l *LoadingModel) Tick() tea.Cmd {
 	return tea.Tick(80*time.Millisecond, func(t time.Time) tea.Msg {
-		return loadingTickMsg{}
+		return loadingTickMsg{loading: l}
 	})
 }
 
 // Update handles loading model updates
 func (l *LoadingModel) Update(msg tea.Msg) (*LoadingModel, tea.Cmd) {
-	switch msg.(type) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		l.SetSize(msg.Width, msg.Height)
 	case loadingTickMsg:
-		l.frame++
-		if !l.done {
-			return l, l.Tick()
+		if msg.loading != l || l.done {
+			// A tick of an abandoned loading screen, or one scheduled before loading finished
+			return l, nil
 		}
+		l.frame++
+		return l, l.Tick()
 	}
 	return l, nil
 }
@@ -79,7 +96,7 @@ func (l *LoadingModel) View() string {
 
 	// Status messages
 	checkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981"))
-	msgStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF"))
+	msgStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Width(textWidth(l.width, 4, 0))
 
 	for i, msg := range l.messages {
 		if i < len(l.messages)-1 || l.done {
@@ -102,9 +119,9 @@ func (l *LoadingModel) View() string {
 		Italic(true)
 
 	tips := []string{
-		"💡 MongoDB requires a replica set for multi-document transactions",
-		"💡 First container pull may take a minute or two",
-		"💡 Subsequent runs will be much faster",
+		i18n.T("💡 MongoDB requires a replica set for multi-document transactions"),
+		i18n.T("💡 First container pull may take a minute or two"),
+		i18n.T("💡 Subsequent runs will be much faster"),
 	}
 
 	tipIndex := (l.frame / 30) % len(tips)
@@ -113,12 +130,3 @@ func (l *LoadingModel) View() string {
 
 	return b.String()
 }
-
-// Predefined loading messages for container startup
-var ContainerStartupMessages = []string{
-	"Pulling MongoDB 7.0 image...",
-	"Starting container...",
-	"Initializing replica set...",
-	"Waiting for MongoDB to be ready...",
-	"Connecting to database...",
-}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -19,9 +22,10 @@ type MenuModel struct {
 func NewMenuModel() *MenuModel {
 	return &MenuModel{
 		items: []string{
-			"🗄️  Select Database Provider",
-			"❓ Help & About",
-			"🚪 Quit",
+			i18n.T("🗄️  Select Database Provider"),
+			i18n.T("📦 Prepare Images"),
+			i18n.T("❓ Help & About"),
+			i18n.T("🚪 Quit"),
 		},
 		cursor: 0,
 	}
@@ -31,12 +35,12 @@ func NewMenuModel() *MenuModel {
 func (m *MenuModel) Update(msg tea.Msg) (*MenuModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		switch {
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			if m.cursor < len(m.items)-1 {
 				m.cursor++
 			}
@@ -59,12 +63,12 @@ func (m *MenuModel) View() string {
 		Bold(true).
 		Foreground(lipgloss.Color("#7C3AED")).
 		MarginBottom(1).
-		Render("🔄 Transaction Isolation Levels Demo")
+		Render(i18n.T("🔄 Transaction Isolation Levels Demo"))
 
 	subtitle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#6B7280")).
 		MarginBottom(2).
-		Render("Learn how database isolation levels work with live demonstrations")
+		Render(i18n.T("Learn how database isolation levels work with live demonstrations"))
 
 	b.WriteString("\n")
 	b.WriteString(title)
@@ -87,7 +91,7 @@ func (m *MenuModel) View() string {
 
 	// Help
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("↑/↓ navigate • enter select • q quit"))
+	b.WriteString(HelpStyle.Render(i18n.T("↑/↓ navigate • enter select • q quit")))
 
 	return b.String()
 }
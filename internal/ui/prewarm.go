@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prewarm is a provider start begun in the background when the app opens, before anyone chose the
+// provider. Selecting it attaches the loading view to this start instead of beginning another.
+type prewarm struct {
+	provider provider.Provider
+	cancel   context.CancelFunc
+	progress chan string   // Startup stages, shown once the loading view attaches
+	done     chan struct{} // Closed once Start returned; metrics and err are set by then
+	metrics  provider.StartupMetrics
+	err      error
+}
+
+// startPrewarm starts p with the options from flags and config, keeping the stages for later
+func startPrewarm(p provider.Provider) *prewarm {
+	ctx, cancel := context.WithCancel(context.Background())
+	pw := &prewarm{
+		provider: p,
+		cancel:   cancel,
+		progress: make(chan string, 16),
+		done:     make(chan struct{}),
+	}
+	ctx = provider.WithProgress(ctx, func(stage string) {
+		select {
+		case pw.progress <- stage:
+		default:
+			// Until the loading view attaches, stages past the buffer are dropped
+		}
+	})
+	timer := provider.NewStartupTimer()
+	ctx = provider.WithTimer(ctx, timer)
+
+	go func() {
+		defer close(pw.done)
+		err := p.Start(ctx)
+		close(pw.progress)
+		pw.metrics, pw.err = timer.Finish(p.Name()), err
+	}()
+	return pw
+}
+
+// finished returns whether Start returned
+func (pw *prewarm) finished() bool {
+	select {
+	case <-pw.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait returns a command delivering prewarmDoneMsg once Start returned
+func (pw *prewarm) wait() tea.Cmd {
+	return func() tea.Msg {
+		<-pw.done
+		return prewarmDoneMsg{prewarm: pw}
+	}
+}
+
+// attach returns a command delivering the start's ProviderStartedMsg, as a start of its own would
+func (pw *prewarm) attach() tea.Cmd {
+	return func() tea.Msg {
+		<-pw.done
+		return ProviderStartedMsg{Provider: pw.provider, Metrics: pw.metrics, Err: pw.err}
+	}
+}
+
+// status returns the line shown under the menus about the start, or "" once it failed
+func (pw *prewarm) status() string {
+	switch {
+	case !pw.finished():
+		return HelpStyle.Render(i18n.T("⏳ Starting %s in the background...", pw.provider.Name()))
+	case pw.err == nil:
+		return HelpStyle.Render(i18n.T("✓ %s is ready", pw.provider.Name()))
+	}
+	return ""
+}
+
+// prewarmDoneMsg reports that a background start returned
+type prewarmDoneMsg struct {
+	prewarm *prewarm
+}
+
+// Prewarm makes the app start p in the background as soon as it opens, so the container is up by
+// the time p is chosen. It starts with the options from flags and config, so choosing p skips
+// the options screen.
+func (a *App) Prewarm(p provider.Provider) {
+	a.prewarmProvider = p
+}
+
+// beginPrewarm starts the provider set by Prewarm, unless it needs a container runtime and none
+// was found; the setup screen is left for when a provider is actually chosen
+func (a *App) beginPrewarm() tea.Cmd {
+	p := a.prewarmProvider
+	if provider.NeedsRuntime(p) {
+		a.probeRuntime()
+		if a.runtime == nil {
+			slog.Info("not prewarming without a container runtime", "provider", p.Name(), "error", a.runtimeErr)
+			return nil
+		}
+	}
+	slog.Info("prewarming provider", "provider", p.Name())
+	a.prewarm = startPrewarm(p)
+	return a.prewarm.wait()
+}
+
+// prewarmed returns whether p is being started, or was started, in the background
+func (a *App) prewarmed(p provider.Provider) bool {
+	return a.prewarm != nil && a.prewarm.provider == p
+}
+
+// attachPrewarm shows the loading view for the background start of the selected provider,
+// handing its cancellation over like a start of its own. A start that already finished goes
+// straight to the scenario list.
+func (a *App) attachPrewarm() tea.Cmd {
+	pw := a.prewarm
+	a.prewarm = nil
+	if pw.finished() {
+		a.startAbandoned = false
+		return pw.attach()
+	}
+
+	a.beginLoading(pw.provider)
+	a.loading.AddMessage(i18n.T("Continuing the start begun in the background..."))
+	a.cancelStart = pw.cancel
+	return tea.Batch(a.loading.Tick(), waitForProgress(pw.progress), pw.attach())
+}
+
+// prewarmStatus returns the line about the background start shown under the menus, if any
+func (a *App) prewarmStatus() string {
+	if a.prewarm == nil {
+		return ""
+	}
+	if status := a.prewarm.status(); status != "" {
+		return "\n  " + status + "\n"
+	}
+	return ""
+}
@@ -3,25 +3,36 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/containerruntime"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // ProviderListModel represents the provider selection view
 type ProviderListModel struct {
-	providers    *provider.Registry
-	cursor       int
-	loading      bool
-	loadingFrame int
+	providers  *provider.Registry
+	runtime    containerruntime.Runtime
+	runtimeErr error                                // Why no runtime is usable; providers needing a container are disabled while set
+	starts     map[string][]provider.StartupMetrics // Recent startups by provider name
+	stopping   map[string]bool                      // Providers by name whose stop is still in flight
+	stopErrs   map[string]error                     // Providers by name whose last stop failed, so a container may be left behind
+	cursor     int
+	frame      int // Spinner frame shown next to stopping providers
 }
 
 // NewProviderListModel creates a new provider list model
 func NewProviderListModel(providers *provider.Registry) *ProviderListModel {
 	return &ProviderListModel{
 		providers: providers,
+		starts:    make(map[string][]provider.StartupMetrics),
+		stopping:  make(map[string]bool),
+		stopErrs:  make(map[string]error),
 		cursor:    0,
 	}
 }
@@ -30,21 +41,95 @@ func NewProviderListModel(providers *provider.Registry) *ProviderListModel {
 func (m *ProviderListModel) Update(msg tea.Msg) (*ProviderListModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		switch {
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			providers := m.providers.GetAll()
 			if m.cursor < len(providers)-1 {
 				m.cursor++
 			}
 		}
+	case providerListTickMsg:
+		if len(m.stopping) == 0 {
+			return m, nil
+		}
+		m.frame++
+		return m, m.tick()
 	}
 	return m, nil
 }
 
+// providerListTickMsg advances the spinner of the stopping providers
+type providerListTickMsg struct{}
+
+// tick returns a command that ticks the spinner
+func (m *ProviderListModel) tick() tea.Cmd {
+	return tea.Tick(80*time.Millisecond, func(t time.Time) tea.Msg {
+		return providerListTickMsg{}
+	})
+}
+
+// StartStopping marks p as stopping until StopDone, so it can't be started again mid-teardown. It
+// returns the command ticking the spinner when no other provider is already stopping.
+func (m *ProviderListModel) StartStopping(p provider.Provider) tea.Cmd {
+	ticking := len(m.stopping) > 0
+	m.stopping[p.Name()] = true
+	if ticking {
+		return nil
+	}
+	return m.tick()
+}
+
+// StopDone marks the stop of p as finished, whether it succeeded or not
+func (m *ProviderListModel) StopDone(p provider.Provider) {
+	delete(m.stopping, p.Name())
+}
+
+// SetStopError records that stopping p failed, warning that its container may be left behind until
+// a later start or stop of p succeeds and clears it with a nil err
+func (m *ProviderListModel) SetStopError(p provider.Provider, err error) {
+	if err == nil {
+		delete(m.stopErrs, p.Name())
+		return
+	}
+	m.stopErrs[p.Name()] = err
+}
+
+// StopFailed returns whether the last stop of p failed
+func (m *ProviderListModel) StopFailed(p provider.Provider) bool {
+	return m.stopErrs[p.Name()] != nil
+}
+
+// Stopping returns whether the stop of p is still in flight
+func (m *ProviderListModel) Stopping(p provider.Provider) bool {
+	return m.stopping[p.Name()]
+}
+
+// SetRuntime records the container runtime found by the pre-flight check
+func (m *ProviderListModel) SetRuntime(r containerruntime.Runtime) {
+	m.runtime = r
+	m.runtimeErr = nil
+}
+
+// SetRuntimeError records why the pre-flight check found no usable runtime, disabling the providers
+// that need a container
+func (m *ProviderListModel) SetRuntimeError(err error) {
+	m.runtimeErr = err
+}
+
+// disabled returns whether p can't start for lack of a container runtime
+func (m *ProviderListModel) disabled(p provider.Provider) bool {
+	return m.runtimeErr != nil && provider.NeedsRuntime(p)
+}
+
+// SetStartHistory records recent startups of a provider, oldest first
+func (m *ProviderListModel) SetStartHistory(name string, starts []provider.StartupMetrics) {
+	m.starts[name] = starts
+}
+
 // Selected returns the currently selected provider
 func (m *ProviderListModel) Selected() provider.Provider {
 	providers := m.providers.GetAll()
@@ -63,12 +148,12 @@ func (m *ProviderListModel) View() string {
 		Bold(true).
 		Foreground(lipgloss.Color("#7C3AED")).
 		MarginBottom(1).
-		Render("🗄️ Select Database Provider")
+		Render(i18n.T("🗄️ Select Database Provider"))
 
 	subtitle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#6B7280")).
 		MarginBottom(2).
-		Render("Choose a database to explore its isolation levels")
+		Render(i18n.T("Choose a database to explore its isolation levels"))
 
 	b.WriteString("\n")
 	b.WriteString(title)
@@ -77,9 +162,15 @@ func (m *ProviderListModel) View() string {
 	b.WriteString("\n\n")
 
 	providers := m.providers.GetAll()
+	for _, p := range providers {
+		if err := m.stopErrs[p.Name()]; err != nil {
+			b.WriteString(WarningStyle.Render(i18n.T("⚠️  %v; its container may still be running", err)))
+			b.WriteString("\n\n")
+		}
+	}
 
 	if len(providers) == 0 {
-		b.WriteString(WarningStyle.Render("  No providers registered"))
+		b.WriteString(WarningStyle.Render(i18n.T("  No providers registered")))
 		return b.String()
 	}
 
@@ -93,6 +184,9 @@ func (m *ProviderListModel) View() string {
 			cursor = "▸ "
 			nameStyle = SelectedStyle
 		}
+		if m.disabled(p) || m.Stopping(p) {
+			nameStyle = nameStyle.Foreground(lipgloss.Color("#6B7280"))
+		}
 
 		// Provider icon based on name
 		icon := "📦"
@@ -105,25 +199,63 @@ func (m *ProviderListModel) View() string {
 			icon = "🐬"
 		}
 
-		b.WriteString(fmt.Sprintf("%s%s %s\n",
+		// Attached providers use an existing database instead of starting a container
+		badge := ""
+		if m.Stopping(p) {
+			badge = " " + WarningStyle.Render(i18n.T("%s stopping...", SpinnerFrames[m.frame%len(SpinnerFrames)]))
+		} else if m.disabled(p) {
+			badge = " " + Badge(i18n.T("Docker unavailable"), lipgloss.Color("#EF4444"))
+		} else if a, ok := p.(provider.Attachable); ok && a.AttachedTo() != "" {
+			badge = " " + Badge(i18n.T("ATTACHED"), lipgloss.Color("#0EA5E9"))
+		}
+
+		b.WriteString(fmt.Sprintf("%s%s %s%s\n",
 			CursorStyle.Render(cursor),
 			icon,
-			nameStyle.Render(p.Name())))
+			nameStyle.Render(p.Name()),
+			badge))
 		b.WriteString(descStyle.Render(p.Description()))
-		b.WriteString("\n\n")
+		b.WriteString("\n")
+
+		// Startup history of the highlighted provider
+		if starts := m.starts[p.Name()]; i == m.cursor && len(starts) > 0 {
+			totals := make([]float64, len(starts))
+			for j, s := range starts {
+				totals[j] = s.Total.Seconds()
+			}
+			b.WriteString(descStyle.Render(i18n.T("Recent starts %s  last %s",
+				sparkline(totals), starts[len(starts)-1].Summary())))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
 	}
 
 	// Note about container
 	note := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#F59E0B")).
 		Italic(true).
-		Render("⚠️  This will start a Docker container using testcontainers")
+		Render(i18n.T("⚠️  This will start a Docker container using testcontainers"))
 
 	b.WriteString(note)
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+
+	if m.runtimeErr != nil {
+		b.WriteString(ErrorStyle.Render(i18n.T("🐳 No usable container runtime")))
+		b.WriteString("\n")
+	} else if m.runtime.Found() {
+		b.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")).
+			Render(i18n.T("🐳 Container runtime: %s", m.runtime.String())))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	// Help
-	b.WriteString(HelpStyle.Render("↑/↓ navigate • enter select • esc/q back"))
+	if m.runtimeErr != nil {
+		b.WriteString(HelpStyle.Render(i18n.T("↑/↓ navigate • enter select • s set up container runtime • esc/q back")))
+	} else {
+		b.WriteString(HelpStyle.Render(i18n.T("↑/↓ navigate • enter select • esc/q back")))
+	}
 
 	return b.String()
 }
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ProviderOptionsModel lets the user adjust provider settings before starting it
+type ProviderOptionsModel struct {
+	provider provider.Provider
+	config   provider.Configurable
+	settings []provider.Setting
+	cursor   int
+	err      error
+}
+
+// NewProviderOptionsModel creates a new provider options model
+func NewProviderOptionsModel(p provider.Provider, c provider.Configurable) *ProviderOptionsModel {
+	return &ProviderOptionsModel{
+		provider: p,
+		config:   c,
+		settings: c.Settings(),
+		cursor:   0,
+	}
+}
+
+// Update handles provider options input
+func (m *ProviderOptionsModel) Update(msg tea.Msg) (*ProviderOptionsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, keys.Down):
+			if m.cursor < len(m.settings)-1 {
+				m.cursor++
+			}
+		case key.Matches(msg, keys.Prev):
+			m.cycle(-1)
+		case key.Matches(msg, keys.Next):
+			m.cycle(1)
+		}
+	}
+	return m, nil
+}
+
+// cycle moves the selected setting to the previous/next choice and applies it
+func (m *ProviderOptionsModel) cycle(delta int) {
+	if m.cursor < 0 || m.cursor >= len(m.settings) {
+		return
+	}
+	s := m.settings[m.cursor]
+	if len(s.Choices) == 0 {
+		return
+	}
+
+	idx := 0
+	for i, c := range s.Choices {
+		if c == s.Value {
+			idx = i
+			break
+		}
+	}
+	idx = (idx + delta + len(s.Choices)) % len(s.Choices)
+
+	m.err = m.config.ApplySetting(s.Key, s.Choices[idx])
+	m.settings = m.config.Settings()
+}
+
+// Provider returns the provider being configured
+func (m *ProviderOptionsModel) Provider() provider.Provider {
+	return m.provider
+}
+
+// View renders the provider options
+func (m *ProviderOptionsModel) View() string {
+	var b strings.Builder
+
+	// Header
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7C3AED")).
+		MarginBottom(1).
+		Render(i18n.T("⚙️  %s Options", m.provider.Name()))
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		MarginBottom(2).
+		Render(i18n.T("Adjust how the database is launched, then press enter to start"))
+
+	b.WriteString("\n")
+	b.WriteString(title)
+	b.WriteString("\n")
+	b.WriteString(subtitle)
+	b.WriteString("\n\n")
+
+	for i, s := range m.settings {
+		cursor := "  "
+		nameStyle := NormalStyle
+
+		if i == m.cursor {
+			cursor = "▸ "
+			nameStyle = SelectedStyle
+		}
+
+		// Render every choice, highlighting the current one
+		choices := make([]string, len(s.Choices))
+		for j, c := range s.Choices {
+			if c == s.Value {
+				choices[j] = Badge(c, lipgloss.Color("#7C3AED"))
+			} else {
+				choices[j] = lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Padding(0, 1).Render(c)
+			}
+		}
+
+		b.WriteString(fmt.Sprintf("%s%s  %s\n",
+			CursorStyle.Render(cursor),
+			nameStyle.Render(s.Name),
+			strings.Join(choices, " ")))
+
+		if s.Description != "" {
+			b.WriteString(lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#6B7280")).
+				MarginLeft(4).
+				Render(s.Description))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if m.err != nil {
+		b.WriteString(ErrorStyle.Render(i18n.T("Error: %v", m.err)))
+		b.WriteString("\n")
+	}
+
+	// Help
+	b.WriteString(HelpStyle.Render(i18n.T("↑/↓ navigate • ←/→ change • enter start • esc/q back")))
+
+	return b.String()
+}
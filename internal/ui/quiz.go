@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// QuizModel asks a scenario's questions after a run and explains each answer with the
+// steps of that run
+type QuizModel struct {
+	scenario  string
+	questions []scenario.Question
+	steps     []scenario.StepResult // The run the questions are about
+	current   int                   // Index of the question being asked
+	cursor    int
+	chosen    int // Choice given for the current question; -1 until answered
+	correct   int
+	finished  bool
+	width     int // Terminal size; 0 until the first WindowSizeMsg
+	height    int
+}
+
+// QuizFinishedMsg reports the score once the last question was answered
+type QuizFinishedMsg struct {
+	Scenario string
+	Correct  int
+	Total    int
+}
+
+// NewQuizModel creates a quiz about a run of the named scenario that produced steps
+func NewQuizModel(name string, questions []scenario.Question, steps []scenario.StepResult) *QuizModel {
+	return &QuizModel{
+		scenario:  name,
+		questions: questions,
+		steps:     steps,
+		chosen:    -1,
+	}
+}
+
+// SetSize records the terminal size, so questions and explanations wrap to its width
+func (m *QuizModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Update handles quiz input
+func (m *QuizModel) Update(msg tea.Msg) (*QuizModel, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.SetSize(size.Width, size.Height)
+		return m, nil
+	}
+	km, ok := msg.(tea.KeyMsg)
+	if !ok || m.finished {
+		return m, nil
+	}
+	q := m.questions[m.current]
+
+	switch {
+	case key.Matches(km, keys.Up):
+		if m.chosen < 0 && m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(km, keys.Down):
+		if m.chosen < 0 && m.cursor < len(q.Choices)-1 {
+			m.cursor++
+		}
+	case key.Matches(km, keys.Answer):
+		if i := int(km.String()[0] - '1'); m.chosen < 0 && i < len(q.Choices) {
+			m.cursor = i
+			m.answer()
+		}
+	case key.Matches(km, keys.Select):
+		if m.chosen < 0 {
+			m.answer()
+			return m, nil
+		}
+		return m, m.next()
+	}
+	return m, nil
+}
+
+// answer gives the choice under the cursor for the current question
+func (m *QuizModel) answer() {
+	m.chosen = m.cursor
+	if m.chosen == m.questions[m.current].Answer {
+		m.correct++
+	}
+}
+
+// next moves on to the following question, finishing the quiz after the last one
+func (m *QuizModel) next() tea.Cmd {
+	m.current++
+	m.cursor = 0
+	m.chosen = -1
+	if m.current < len(m.questions) {
+		return nil
+	}
+
+	m.finished = true
+	score := QuizFinishedMsg{Scenario: m.scenario, Correct: m.correct, Total: len(m.questions)}
+	return func() tea.Msg { return score }
+}
+
+// View renders the current question, or the score once the quiz is finished
+func (m *QuizModel) View() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryColor).
+		Render(i18n.T("🧠 Quiz: %s", m.scenario))
+	b.WriteString("\n")
+	b.WriteString(title)
+
+	if m.finished {
+		b.WriteString("\n\n")
+		b.WriteString(SuccessStyle.Render(i18n.T("Score: %d of %d", m.correct, len(m.questions))))
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render(i18n.T("esc back to results")))
+		return b.String()
+	}
+
+	q := m.questions[m.current]
+	b.WriteString(lipgloss.NewStyle().
+		Foreground(mutedColor).
+		Render("  " + i18n.T("Question %d of %d", m.current+1, len(m.questions))))
+	b.WriteString("\n\n")
+	b.WriteString(lipgloss.NewStyle().Bold(true).Width(textWidth(m.width, 0, 70)).Render(q.Prompt))
+	b.WriteString("\n\n")
+
+	for i, choice := range q.Choices {
+		cursor := "  "
+		style := NormalStyle
+		switch {
+		case m.chosen >= 0 && i == q.Answer:
+			style = NormalStyle.Foreground(secondaryColor)
+		case m.chosen >= 0 && i == m.chosen:
+			style = NormalStyle.Foreground(errorColor)
+		case m.chosen < 0 && i == m.cursor:
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+		b.WriteString(fmt.Sprintf("%s%s\n", CursorStyle.Render(cursor), style.Render(fmt.Sprintf("%d. %s", i+1, choice))))
+	}
+	b.WriteString("\n")
+
+	if m.chosen < 0 {
+		b.WriteString(HelpStyle.Render(i18n.T("↑/↓ navigate • enter or 1-%d answer • esc back to results", len(q.Choices))))
+		return b.String()
+	}
+
+	if m.chosen == q.Answer {
+		b.WriteString(SuccessStyle.Render(i18n.T("✓ Correct!")))
+	} else {
+		b.WriteString(ErrorStyle.Render(i18n.T("✗ Not quite - the answer is %d.", q.Answer+1)))
+	}
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Width(textWidth(m.width, 0, 70)).Render(q.Explanation))
+	b.WriteString("\n")
+	b.WriteString(m.viewSteps(q.Steps))
+
+	b.WriteString("\n")
+	if m.current == len(m.questions)-1 {
+		b.WriteString(HelpStyle.Render(i18n.T("enter see score • esc back to results")))
+	} else {
+		b.WriteString(HelpStyle.Render(i18n.T("enter next question • esc back to results")))
+	}
+	return b.String()
+}
+
+// viewSteps quotes the steps of the run an explanation refers to
+func (m *QuizModel) viewSteps(numbers []int) string {
+	var b strings.Builder
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	sessions := sessionWidth(m.steps)
+	for _, n := range numbers {
+		for _, step := range m.steps {
+			if step.IsHeader || step.SourceStep() != n {
+				continue
+			}
+			b.WriteString(fmt.Sprintf("\n%s %s  %s",
+				muted.Render(fmt.Sprintf("[%d]", step.Step)),
+				SessionStyle(step.Session).Render(sessionLabel(step.Session, sessions)),
+				step.Description))
+			if step.Result != "" {
+				b.WriteString("\n" + muted.MarginLeft(4).Render("→ "+step.Result))
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\n" + muted.Render(i18n.T("From this run:")) + b.String() + "\n"
+}
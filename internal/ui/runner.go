@@ -2,35 +2,132 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // RunnerModel displays the scenario execution
 type RunnerModel struct {
-	scenario scenario.Scenario
-	results  []scenario.StepResult
-	running  bool
-	done     bool
-	err      error
-	frame    int
+	scenario   scenario.Scenario
+	params     scenario.Params
+	record     *report.Run // Steps as they arrive; beyond report.StepLimit the oldest spill to disk
+	running    bool
+	done       bool
+	err        error
+	cleanupErr error // Cleanup failed after the run, leaving data behind
+	frame      int
+	setup      string             // Latest stage reported by the scenario's Setup, shown until the first step
+	cancel     context.CancelFunc // Cancels the run in flight; nil until it starts
+	width      int                // Terminal size; 0 until the first WindowSizeMsg
+	height     int
+
+	// Recorded for exports
+	provider   provider.Provider
+	started    time.Time
+	duration   time.Duration
+	assertions []scenario.Assertion
+
+	exporting bool   // Export menu is open
+	exported  string // Path of the last export
+
+	logPath  string             // Shown with errors; empty when logging is off
+	listener RunListener        // Mirrors the run, e.g. to a broadcast; may be nil
+	cleanups *scenario.Cleanups // Tracks the run until its Cleanup ran; may be nil
+
+	steps   stepCache // Measures the results and renders those in view
+	offset  int       // First line of the steps in view
+	page    int       // Lines of steps in view in the last frame
+	follow  bool      // Keep the latest step in view; scrolling up stops it until the end is reached again
+	waiting []int     // Steps still pending, by index in the run counting spilled ones; timed on each tick
+	changes int       // Steps changed in place, e.g. a pending step by its outcome
+
+	visible bool        // The runner is on screen; ticks only run while it is
+	blurred bool        // The terminal reported losing focus; the spinner slows down
+	ticking bool        // A tick is scheduled
+	shown   runnerFrame // What the last rendered frame showed
+	view    string      // The last rendered frame, reused while nothing in it changed
+	renders int         // Frames rendered rather than reused
+}
+
+// Spinner tick intervals while the terminal is focused and while it isn't
+const (
+	tickInterval        = 100 * time.Millisecond
+	blurredTickInterval = 250 * time.Millisecond
+)
+
+// runnerFrame is the state a frame of the runner shows. View renders again only when it changed,
+// so ticks and messages that leave it untouched cost no rendering.
+type runnerFrame struct {
+	record          *report.Run
+	steps, spilled  int
+	changes         int
+	spinner         int
+	setup           string
+	running, done   bool
+	err, cleanupErr string
+	exporting       bool
+	exported        string
+	offset          int
+	follow          bool
+	width, height   int
+	ascii           bool
+}
+
+// RunListener follows the runs of the TUI, e.g. to mirror them to an audience. It is called from
+// the UI loop, so implementations must not block and must be safe for use alongside other goroutines.
+type RunListener interface {
+	RunStarted(run *report.Run) error
+	WriteStep(step scenario.StepResult) error
+	RunFinished(run *report.Run) error
 }
 
 // NewRunnerModel creates a new runner model
-func NewRunnerModel(s scenario.Scenario) *RunnerModel {
+func NewRunnerModel(s scenario.Scenario, params scenario.Params) *RunnerModel {
 	return &RunnerModel{
 		scenario: s,
-		results:  make([]scenario.StepResult, 0),
+		params:   params,
+		record:   report.NewRun(s),
 		running:  false,
+		follow:   true,
+		visible:  true,
 	}
 }
 
+// SetProvider records which provider the scenario runs against, for exports
+func (r *RunnerModel) SetProvider(p provider.Provider) {
+	r.provider = p
+}
+
+// SetLogPath records where diagnostics are logged so a failed run can point there
+func (r *RunnerModel) SetLogPath(path string) {
+	r.logPath = path
+}
+
+// SetListener mirrors the run to l
+func (r *RunnerModel) SetListener(l RunListener) {
+	r.listener = l
+}
+
+// SetCleanups tracks the run in c until its scenario is cleaned up
+func (r *RunnerModel) SetCleanups(c *scenario.Cleanups) {
+	r.cleanups = c
+}
+
 // Start begins the scenario execution
 func (r *RunnerModel) Start() tea.Cmd {
 	return func() tea.Msg {
@@ -39,82 +136,401 @@ func (r *RunnerModel) Start() tea.Cmd {
 }
 
 type runnerStartMsg struct{}
+
+// SetSize records the terminal size, so steps wrap to its width
+func (r *RunnerModel) SetSize(width, height int) {
+	r.width = width
+	r.height = height
+}
+
+// Active returns whether the runner's run hasn't completed yet. A new runner counts as active
+// before its start message arrives, so a second selection can't slip in while the first starts.
+func (r *RunnerModel) Active() bool {
+	return !r.done
+}
+
+// Cancel cancels the run in flight, if any; its outcome still arrives as a message
+func (r *RunnerModel) Cancel() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+// runnerStepMsg delivers a step of the run started by runner
 type runnerStepMsg struct {
+	runner *RunnerModel
 	result scenario.StepResult
+	offset time.Duration  // When the step arrived, relative to the start of the run
+	events <-chan tea.Msg // The rest of the run
+}
+
+// runnerSetupMsg delivers a stage of the scenario's Setup in the run started by runner
+type runnerSetupMsg struct {
+	runner *RunnerModel
+	stage  string
+	events <-chan tea.Msg // The rest of the run
 }
+
+// runnerCompleteMsg delivers the outcome of the run started by runner
 type runnerCompleteMsg struct {
-	err error
+	runner     *RunnerModel
+	err        error
+	cleanupErr error
+	assertions []scenario.Assertion
+}
+
+// runnerTickMsg advances the spinner of runner
+type runnerTickMsg struct {
+	runner *RunnerModel
 }
-type runnerTickMsg struct{}
 
 // Update handles runner updates
 func (r *RunnerModel) Update(msg tea.Msg) (*RunnerModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Only the layout changes; the run and its results are untouched
+		r.SetSize(msg.Width, msg.Height)
+		return r, nil
+
 	case runnerStartMsg:
 		r.running = true
-		r.results = nil
-		return r, tea.Batch(r.runScenario(), r.tick())
+		_ = r.record.Close()
+		r.record = report.NewRun(r.scenario)
+		r.setup = ""
+		r.steps.reset()
+		r.offset, r.follow = 0, true
+		r.waiting = nil
+		r.started = time.Now()
+		if r.listener != nil {
+			run := report.NewRun(r.scenario)
+			run.Seed = r.params.Seed
+			_ = r.listener.RunStarted(run)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		r.cancel = cancel
+		return r, tea.Batch(r.runScenario(ctx), r.resumeTicks())
 
 	case runnerStepMsg:
-		r.results = append(r.results, msg.result)
-		return r, nil
+		if i := r.record.PendingStep(msg.result); i >= 0 {
+			r.steps.forget(i)
+			r.changes++
+			r.waiting = slices.DeleteFunc(r.waiting, func(w int) bool { return w == r.record.Spilled()+i })
+		}
+		r.record.AddStepAt(msg.result, msg.offset)
+		if msg.result.Pending {
+			r.waiting = append(r.waiting, r.record.Spilled()+len(r.record.Steps)-1)
+		}
+		if r.listener != nil {
+			_ = r.listener.WriteStep(msg.result)
+		}
+		return r, waitForRunner(msg.events)
+
+	case runnerSetupMsg:
+		r.setup = msg.stage
+		return r, waitForRunner(msg.events)
 
 	case runnerCompleteMsg:
+		r.Cancel() // Releases the run's context
 		r.running = false
 		r.done = true
 		r.err = msg.err
+		r.cleanupErr = msg.cleanupErr
+		r.assertions = msg.assertions
+		r.duration = time.Since(r.started)
+		if r.listener != nil {
+			_ = r.listener.RunFinished(r.suite().Runs[0])
+		}
 		return r, func() tea.Msg { return RunnerDoneMsg{} }
 
-	case runnerTickMsg:
-		r.frame++
-		if r.running {
-			return r, r.tick()
+	case tea.KeyMsg:
+		if !r.exporting && r.scroll(msg) {
+			return r, nil
+		}
+		if !r.done {
+			return r, nil
+		}
+		if !r.exporting {
+			r.exporting = key.Matches(msg, keys.Export)
+			return r, nil
+		}
+		r.exporting = false
+		switch {
+		case key.Matches(msg, keys.ExportMarkdown):
+			return r, exportSuite(r.suite(), report.FormatMarkdown)
+		case key.Matches(msg, keys.ExportHTML):
+			return r, exportSuite(r.suite(), report.FormatHTML)
+		case key.Matches(msg, keys.ExportCast):
+			return r, exportCast(r.suite())
+		}
+		return r, nil
+
+	case exportDoneMsg:
+		if msg.err != nil {
+			// Shown over the results, which stay for another try
+			return r, func() tea.Msg { return ErrorMsg{Err: msg.err} }
 		}
+		r.exported = msg.path
 		return r, nil
+
+	case runnerTickMsg:
+		r.ticking = false
+		if !r.running || !r.visible {
+			// Nothing to animate; SetVisible or the next run resumes the ticks
+			return r, nil
+		}
+		r.frame++
+		r.timeWaiting()
+		return r, r.resumeTicks()
 	}
 
 	return r, nil
 }
 
-func (r *RunnerModel) tick() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
-		return runnerTickMsg{}
-	})
+// timeWaiting sets how long the pending steps in memory have waited so far, for them to show it
+func (r *RunnerModel) timeWaiting() {
+	// Steps spilled to disk are out of view; their outcome replaces them there
+	r.waiting = slices.DeleteFunc(r.waiting, func(w int) bool { return w < r.record.Spilled() })
+	elapsed := time.Since(r.started)
+	for _, w := range r.waiting {
+		i := w - r.record.Spilled()
+		if i >= len(r.record.Offsets) {
+			continue
+		}
+		r.record.Steps[i].Waited = elapsed - r.record.Offsets[i]
+		r.steps.forget(i)
+		r.changes++
+	}
 }
 
-func (r *RunnerModel) runScenario() tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-		output := make(chan scenario.StepResult, 100)
-
-		// Setup
-		if err := r.scenario.Setup(ctx); err != nil {
-			return runnerCompleteMsg{err: err}
+// scroll moves the steps in view for the scrolling keys, reporting whether msg was one. View
+// keeps the offset within the steps.
+func (r *RunnerModel) scroll(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, keys.Up):
+		r.offset--
+	case key.Matches(msg, keys.Down):
+		r.offset++
+	case key.Matches(msg, keys.PageUp):
+		r.offset -= max(r.page-1, 1)
+	case key.Matches(msg, keys.PageDown):
+		r.offset += max(r.page-1, 1)
+	case key.Matches(msg, keys.NextFailure):
+		r.measure()
+		line, ok := r.steps.nextFailure(r.record.Steps, r.offset)
+		if !ok {
+			return true
 		}
+		r.offset = line
+	default:
+		return false
+	}
+	r.follow = false
+	return true
+}
+
+// NewQuiz returns a quiz about the finished run, or nil if the scenario has no questions or
+// the run didn't complete
+func (r *RunnerModel) NewQuiz() *QuizModel {
+	questions := scenario.QuizOf(r.scenario)
+	if !r.done || r.err != nil || r.exporting || len(questions) == 0 {
+		return nil
+	}
+	run, err := r.record.Whole()
+	if err != nil {
+		slog.Warn("quiz shows only the steps in memory", "error", err)
+		run = r.record
+	}
+	return NewQuizModel(r.scenario.Name(), questions, run.Steps)
+}
+
+// suite packages the finished run for an exporter
+func (r *RunnerModel) suite() *report.Suite {
+	run := r.record
+	run.Started = r.started
+	run.Duration = r.duration
+	run.Err = r.err
+	run.Seed = r.params.Seed
+	run.Assertions = r.assertions
+
+	var name, info string
+	if r.provider != nil {
+		name, info = r.provider.Name(), r.provider.ConnectionInfo()
+	}
+	return report.SuiteOf(name, info, run)
+}
+
+// SetVisible records whether the runner is on screen, resuming the spinner's ticks when it comes
+// back into view during a run
+func (r *RunnerModel) SetVisible(visible bool) tea.Cmd {
+	r.visible = visible
+	return r.resumeTicks()
+}
+
+// SetFocused records whether the terminal has focus; without it the spinner ticks less often
+func (r *RunnerModel) SetFocused(focused bool) tea.Cmd {
+	r.blurred = !focused
+	return r.resumeTicks()
+}
+
+// resumeTicks schedules a tick unless one is scheduled already or there is nothing to animate
+func (r *RunnerModel) resumeTicks() tea.Cmd {
+	if r.ticking || !r.running || !r.visible {
+		return nil
+	}
+	r.ticking = true
+	return tea.Tick(r.tickInterval(), func(t time.Time) tea.Msg {
+		return runnerTickMsg{runner: r}
+	})
+}
+
+// tickInterval returns how long the spinner shows each frame
+func (r *RunnerModel) tickInterval() time.Duration {
+	if r.blurred {
+		return blurredTickInterval
+	}
+	return tickInterval
+}
 
-		// Run in goroutine
-		var runErr error
+// runScenario starts the scenario and returns a command delivering its first step. Steps and the
+// outcome arrive as messages, so the results are only ever changed by Update on the UI loop.
+// Cancelling ctx stops the run.
+func (r *RunnerModel) runScenario(ctx context.Context) tea.Cmd {
+	s, params, started := r.scenario, r.params, r.started
+	if r.cleanups != nil {
+		ctx = scenario.WithCleanups(ctx, r.cleanups)
+	}
+	return func() tea.Msg {
+		events := make(chan tea.Msg, 100)
 		go func() {
-			runErr = r.scenario.Run(ctx, output)
+			var assertions scenario.Assertions
+			ctx := scenario.WithParams(ctx, params)
+			ctx = scenario.WithAssertions(ctx, &assertions)
+			ctx = scenario.WithSetupProgress(ctx, func(stage string) {
+				select {
+				case events <- runnerSetupMsg{runner: r, stage: stage, events: events}:
+				default:
+					// A later stage supersedes a dropped one
+				}
+			})
+			output := make(chan scenario.StepResult, 100)
+
+			outcome := make(chan scenario.Outcome, 1)
+			go func() {
+				outcome <- scenario.Execute(ctx, s, output)
+			}()
+
+			for result := range output {
+				events <- runnerStepMsg{runner: r, result: result, offset: time.Since(started), events: events}
+			}
+			o := <-outcome
+			events <- runnerCompleteMsg{runner: r, err: o.Err, cleanupErr: o.Cleanup, assertions: assertions.All()}
 		}()
+		return <-events
+	}
+}
 
-		// Collect results
-		for result := range output {
-			// Send each result as a message
-			// Note: This is a simplified approach; in a real app we'd need
-			// a proper channel-based message system
-			r.results = append(r.results, result)
-		}
-
-		// Cleanup
-		_ = r.scenario.Cleanup(ctx)
+// cleanupWarning describes a failed cleanup with the collections it left behind
+func cleanupWarning(err error) string {
+	var cleanupErr *scenario.CleanupError
+	if errors.As(err, &cleanupErr) && len(cleanupErr.Collections) > 0 {
+		return i18n.T("⚠️ Cleanup failed, left behind: %s (%v)", strings.Join(cleanupErr.Collections, ", "), cleanupErr.Err)
+	}
+	return i18n.T("⚠️ Cleanup failed: %v", err)
+}
 
-		return runnerCompleteMsg{err: runErr}
+// waitForRunner returns a command that delivers the next step or the outcome of a run
+func waitForRunner(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
 	}
 }
 
-// View renders the runner
+// View renders the runner, reusing the last frame while nothing it shows changed
 func (r *RunnerModel) View() string {
+	if frame := r.frameState(); frame != r.shown || r.renders == 0 {
+		r.view = r.render()
+		r.renders++
+		// Rendering clamps the offset, so the state is taken again for the next frame to match
+		r.shown = r.frameState()
+	}
+	return r.view
+}
+
+// frameState returns what a frame rendered now would show
+func (r *RunnerModel) frameState() runnerFrame {
+	frame := runnerFrame{
+		record:    r.record,
+		steps:     len(r.record.Steps),
+		spilled:   r.record.Spilled(),
+		changes:   r.changes,
+		setup:     r.setup,
+		running:   r.running,
+		done:      r.done,
+		exporting: r.exporting,
+		exported:  r.exported,
+		offset:    r.offset,
+		follow:    r.follow,
+		width:     r.width,
+		height:    r.height,
+		ascii:     i18n.ASCIIMode(),
+	}
+	if r.running {
+		frame.spinner = r.frame % len(SpinnerFrames)
+	}
+	if r.err != nil {
+		frame.err = r.err.Error()
+	}
+	if r.cleanupErr != nil {
+		frame.cleanupErr = r.cleanupErr.Error()
+	}
+	return frame
+}
+
+// render draws the runner. Only the steps in view are drawn; the others scroll into it.
+func (r *RunnerModel) render() string {
+	header := r.headerView()
+	footer := r.footerView()
+	steps := r.record.Steps
+	if len(steps) == 0 {
+		return header + r.preparingView() + footer
+	}
+
+	r.measure()
+	if spilled := r.record.Spilled(); spilled > 0 {
+		header += HelpStyle.Render("  "+i18n.T("Showing the last %d of %d steps", len(steps), spilled+len(steps))) + "\n"
+	}
+	total := r.steps.lines()
+	if r.height <= 0 {
+		// Size unknown, e.g. rendering a cast: show every step
+		return header + r.steps.window(steps, 0, total) + footer
+	}
+
+	// The header ends on the line the steps start on; the footer takes the lines after them
+	r.page = max(r.height-strings.Count(header, "\n")-strings.Count(footer, "\n")-1, 1)
+	if total > r.page {
+		r.page = max(r.page-1, 1) // Room for the scroll hint
+	}
+	last := max(total-r.page, 0)
+	if r.follow || r.offset >= last {
+		r.offset, r.follow = last, true
+	}
+	r.offset = max(r.offset, 0)
+
+	body := r.steps.window(steps, r.offset, r.page)
+	if total > r.page {
+		footer = HelpStyle.Render(i18n.T("↑/↓ pgup/pgdown scroll • f next failure • %d%%", 100*r.offset/last)) + footer
+	}
+	return header + body + footer
+}
+
+// measure brings the step cache up to date with the steps in memory. Steps spilled to disk since
+// the last frame leave the view, so the offset moves up by the lines they took.
+func (r *RunnerModel) measure() {
+	r.offset -= r.steps.measure(r.record.Steps, r.record.Spilled(), r.width)
+}
+
+// headerView renders the title, the status and the isolation level, ending where the steps start
+func (r *RunnerModel) headerView() string {
 	var b strings.Builder
 
 	// Header
@@ -131,18 +547,18 @@ func (r *RunnerModel) View() string {
 		spinner := SpinnerFrames[r.frame%len(SpinnerFrames)]
 		status := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#F59E0B")).
-			Render(fmt.Sprintf("  %s Running...", spinner))
+			Render(i18n.T("  %s Running...", spinner))
 		b.WriteString(status)
 	} else if r.done {
 		if r.err != nil {
 			status := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#EF4444")).
-				Render("  ❌ Error")
+				Render(i18n.T("  ❌ Error"))
 			b.WriteString(status)
 		} else {
 			status := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#10B981")).
-				Render("  ✓ Complete")
+				Render(i18n.T("  ✓ Complete"))
 			b.WriteString(status)
 		}
 	}
@@ -152,88 +568,287 @@ func (r *RunnerModel) View() string {
 	// Isolation level badge
 	levelBadge := Badge(r.scenario.IsolationLevel(), lipgloss.Color("#7C3AED"))
 	b.WriteString(levelBadge)
+	b.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Render("  " + i18n.T("seed %s", strconv.FormatInt(r.params.Seed, 10))))
 	b.WriteString("\n\n")
+	return b.String()
+}
 
-	// Results
-	if len(r.results) == 0 && r.running {
-		b.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
-			Italic(true).
-			Render("  Preparing scenario..."))
-		b.WriteString("\n")
+// preparingView renders the placeholder shown until the first step arrives
+func (r *RunnerModel) preparingView() string {
+	if !r.running {
+		return ""
 	}
+	text := i18n.T("  Preparing scenario...")
+	if r.setup != "" {
+		text += " " + r.setup
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true).
+		Render(text) + "\n"
+}
 
-	for _, result := range r.results {
-		if result.IsHeader {
-			// Section header
-			headerStyle := lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#F9FAFB")).
-				Background(lipgloss.Color("#374151")).
-				Padding(0, 1).
-				MarginTop(1).
-				MarginBottom(1)
-			b.WriteString(headerStyle.Render(result.Description))
-			b.WriteString("\n\n")
-			continue
-		}
+// footerView renders the error, the export status and the key help shown under the steps
+func (r *RunnerModel) footerView() string {
+	var b strings.Builder
+
+	// Error message
+	if r.err != nil {
+		b.WriteString(ErrorStyle.Render("\n" + i18n.T("Error: %v", r.err)))
+		b.WriteString("\n")
 
-		// Step
-		sessionStyle := SessionStyle(result.Session)
-		stepNum := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
-			Render(fmt.Sprintf("[%d]", result.Step))
-
-		b.WriteString(fmt.Sprintf("%s %s  %s\n",
-			stepNum,
-			sessionStyle.Render(fmt.Sprintf("%-10s", result.Session)),
-			DescriptionStyle.Render(result.Description)))
-
-		// Query
-		if result.Query != "" {
-			queryStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#A78BFA")).
-				MarginLeft(4).
-				Italic(true)
-			b.WriteString(queryStyle.Render("→ " + result.Query))
+		var panicErr *scenario.PanicError
+		if errors.As(r.err, &panicErr) {
+			b.WriteString(lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#6B7280")).
+				Render(panicErr.Detail()))
 			b.WriteString("\n")
 		}
+		if r.logPath != "" {
+			b.WriteString(HelpStyle.Render(i18n.T("Details are logged to %s", r.logPath)))
+			b.WriteString("\n")
+		}
+	}
 
-		// Result
-		if result.Result != "" {
-			resultStyle := lipgloss.NewStyle().
-				MarginLeft(4)
+	if r.cleanupErr != nil {
+		b.WriteString(WarningStyle.Render("\n" + cleanupWarning(r.cleanupErr)))
+		b.WriteString("\n")
+	}
 
-			if result.Success {
-				resultStyle = resultStyle.Foreground(lipgloss.Color("#10B981"))
-			} else {
-				resultStyle = resultStyle.Foreground(lipgloss.Color("#EF4444"))
-			}
+	// Help
+	b.WriteString("\n")
+	if r.exported != "" {
+		b.WriteString(SuccessStyle.Render(i18n.T("Saved to %s", r.exported)))
+		b.WriteString("\n")
+	}
+	switch {
+	case r.exporting:
+		b.WriteString(HelpStyle.Render(i18n.T("Export as: m Markdown • h HTML • c asciinema cast • any other key cancels")))
+	case r.done && r.err == nil && len(scenario.QuizOf(r.scenario)) > 0:
+		b.WriteString(HelpStyle.Render(i18n.T("z quiz • x export • esc/q back to scenarios")))
+	case r.done:
+		b.WriteString(HelpStyle.Render(i18n.T("x export • esc/q back to scenarios")))
+	default:
+		b.WriteString(HelpStyle.Render(i18n.T("Please wait for scenario to complete...")))
+	}
+
+	return b.String()
+}
+
+// maxRenderedSteps bounds the rendered steps a stepCache keeps beyond those in view
+const maxRenderedSteps = 256
+
+// stepCache measures the steps of a run as they arrive and keeps the steps around the viewport
+// rendered. A frame then renders only the steps that arrived or scrolled into view, and a run of
+// thousands of steps stays in memory as structs rather than rendered text.
+type stepCache struct {
+	width    int            // Terminal width the steps were measured for
+	sessions int            // Session column width they were measured with
+	ascii    bool           // Whether they were measured in ASCII mode
+	first    int            // Index in the run of results[0]; earlier steps were dropped from memory
+	ends     []int          // Line after each measured step, counting from the first step's
+	rendered map[int]string // Rendered steps by index in the run, at most those in the last window plus maxRenderedSteps
+}
+
+// measure brings the cache up to date with results, the steps of a run from its step first on,
+// rendering only the steps it hasn't seen to count their lines. It returns the lines of the steps
+// dropped from the front since the last call. Everything is measured again when the width, the
+// session column or ASCII mode changed, or when results no longer start with the steps measured.
+func (c *stepCache) measure(results []scenario.StepResult, first, width int) int {
+	dropped := 0
+	if n := first - c.first; n > 0 && n <= len(c.ends) {
+		dropped = c.ends[n-1]
+		c.ends = c.ends[n:]
+		for i := range c.ends {
+			c.ends[i] -= dropped
+		}
+		c.first = first
+	}
+	if first != c.first || len(results) < len(c.ends) {
+		c.reset()
+		c.first = first
+	}
+	sessions := max(c.sessions, sessionWidth(results[len(c.ends):]))
+	if width != c.width || sessions != c.sessions || i18n.ASCIIMode() != c.ascii {
+		c.reset()
+		c.width, c.sessions, c.ascii, c.first = width, sessions, i18n.ASCIIMode(), first
+	}
+	for i := len(c.ends); i < len(results); i++ {
+		c.ends = append(c.ends, c.lines()+strings.Count(c.step(results, i), "\n"))
+	}
+	return dropped
+}
 
-			// Handle multiline results
-			lines := strings.Split(result.Result, "\n")
-			for _, line := range lines {
-				b.WriteString(resultStyle.Render("  " + line))
-				b.WriteString("\n")
+// lines returns how many lines the measured steps take
+func (c *stepCache) lines() int {
+	if len(c.ends) == 0 {
+		return 0
+	}
+	return c.ends[len(c.ends)-1]
+}
+
+// start returns the line step i starts on
+func (c *stepCache) start(i int) int {
+	if i == 0 {
+		return 0
+	}
+	return c.ends[i-1]
+}
+
+// step returns step i of results rendered, rendering it unless it is cached
+func (c *stepCache) step(results []scenario.StepResult, i int) string {
+	if rendered, ok := c.rendered[c.first+i]; ok {
+		return rendered
+	}
+	if c.rendered == nil {
+		c.rendered = make(map[int]string)
+	}
+	rendered := renderStep(results[i], c.sessions, c.width)
+	c.rendered[c.first+i] = rendered
+	return rendered
+}
+
+// window returns height lines of the measured steps from line from on, rendering only the steps
+// they show and forgetting rendered steps far from them
+func (c *stepCache) window(results []scenario.StepResult, from, height int) string {
+	first := sort.SearchInts(c.ends, from+1) // The first step ending past from
+	last := first
+	var b strings.Builder
+	for ; last < len(c.ends) && c.start(last) < from+height; last++ {
+		b.WriteString(c.step(results, last))
+	}
+
+	if len(c.rendered) > maxRenderedSteps+last-first {
+		for i := range c.rendered {
+			if i < c.first+first || i >= c.first+last {
+				delete(c.rendered, i)
 			}
 		}
+	}
+
+	lines := strings.SplitAfter(b.String(), "\n")
+	skip := from - c.start(first)
+	return strings.Join(lines[min(skip, len(lines)):min(skip+height, len(lines))], "")
+}
+
+// forget forgets step i and those after it, which are measured again on the next frame
+func (c *stepCache) forget(i int) {
+	if i >= len(c.ends) {
+		return
+	}
+	c.ends = c.ends[:i]
+	for j := range c.rendered {
+		if j >= c.first+i {
+			delete(c.rendered, j)
+		}
+	}
+}
+
+// reset forgets the measured steps
+func (c *stepCache) reset() {
+	c.first = 0
+	c.ends = nil
+	c.rendered = nil
+	c.sessions = 0
+}
 
+// nextFailure returns the line the first failed step starting after line from starts on, wrapping
+// around to the first failed step of the run; false when no step failed
+func (c *stepCache) nextFailure(results []scenario.StepResult, from int) (int, bool) {
+	first := -1
+	for i, step := range results[:len(c.ends)] {
+		if step.IsHeader || step.Success {
+			continue
+		}
+		if c.start(i) > from {
+			return c.start(i), true
+		}
+		if first < 0 {
+			first = c.start(i)
+		}
+	}
+	return first, first >= 0
+}
+
+// renderStep renders a step or section header of a run, with the session padded to sessions
+// columns and the text wrapped to a terminal width columns wide
+func renderStep(result scenario.StepResult, sessions, width int) string {
+	var b strings.Builder
+	if result.IsHeader {
+		// Section header
+		headerStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#F9FAFB")).
+			Background(lipgloss.Color("#374151")).
+			Padding(0, 1).
+			MarginTop(1).
+			MarginBottom(1)
+		b.WriteString(headerStyle.Render(plain(result.Description)))
+		b.WriteString("\n\n")
+		return b.String()
+	}
+
+	// Step
+	sessionStyle := SessionStyle(result.Session)
+	stepNum := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Render(fmt.Sprintf("[%d]", result.Step))
+
+	prefix := fmt.Sprintf("%s %s  ", stepNum, sessionStyle.Render(sessionLabel(plain(result.Session), sessions)))
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		prefix,
+		fit(DescriptionStyle.Width(textWidth(width, lipgloss.Width(prefix), 0)), plain(result.Description))))
+	b.WriteString("\n")
+
+	// Query
+	if result.Query != "" {
+		queryStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#A78BFA")).
+			MarginLeft(4).
+			Width(textWidth(width, 4, 0)).
+			Italic(true)
+		b.WriteString(fit(queryStyle, "→ "+plain(result.Query)))
 		b.WriteString("\n")
 	}
 
-	// Error message
-	if r.err != nil {
-		b.WriteString(ErrorStyle.Render(fmt.Sprintf("\nError: %v", r.err)))
+	// How long the step waits, e.g. on a lock
+	if result.Pending || result.Waited > 0 {
+		waitStyle := lipgloss.NewStyle().
+			MarginLeft(4).
+			Width(textWidth(width, 4, 0)).
+			Foreground(lipgloss.Color("#6B7280"))
+		waited := result.Waited.Round(100 * time.Millisecond)
+		text := i18n.T("⏱️ waited %s", waited)
+		if result.Pending {
+			waitStyle = waitStyle.Foreground(lipgloss.Color("#F59E0B"))
+			text = i18n.T("⏳ waiting... %s", waited)
+		}
+		b.WriteString(fit(waitStyle, "  "+text))
 		b.WriteString("\n")
 	}
 
-	// Help
-	b.WriteString("\n")
-	if r.done {
-		b.WriteString(HelpStyle.Render("esc/q back to scenarios"))
-	} else {
-		b.WriteString(HelpStyle.Render("Please wait for scenario to complete..."))
+	// Result
+	if result.Result != "" {
+		resultStyle := lipgloss.NewStyle().
+			MarginLeft(4).
+			Width(textWidth(width, 4, 0))
+
+		if result.Success {
+			resultStyle = resultStyle.Foreground(lipgloss.Color("#10B981"))
+		} else {
+			resultStyle = resultStyle.Foreground(lipgloss.Color("#EF4444"))
+		}
+
+		// Handle multiline results
+		lines := strings.Split(plain(result.Result), "\n")
+		for _, line := range lines {
+			b.WriteString(fit(resultStyle, "  "+line))
+			b.WriteString("\n")
+		}
 	}
 
+	b.WriteString("\n")
 	return b.String()
 }
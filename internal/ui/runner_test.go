@@ -0,0 +1,751 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario/scenariotest"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// panickingScenario emits one step and then panics mid-run
+type panickingScenario struct {
+	cleanedUp bool
+}
+
+func (s *panickingScenario) Name() string                      { return "Panicking" }
+func (s *panickingScenario) Description() string               { return "Panics during Run" }
+func (s *panickingScenario) IsolationLevel() string            { return "None" }
+func (s *panickingScenario) Setup(ctx context.Context) error   { return nil }
+func (s *panickingScenario) Cleanup(ctx context.Context) error { s.cleanedUp = true; return nil }
+
+func (s *panickingScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	output <- scenario.StepResult{Session: "Session A", Step: 1, Description: "Before the panic"}
+	panic("boom")
+}
+
+// stoppableProvider records whether Stop was called
+type stoppableProvider struct {
+	scenarios *scenario.Registry
+	running   bool
+}
+
+func (p *stoppableProvider) Name() string                         { return "Fake" }
+func (p *stoppableProvider) Description() string                  { return "Fake provider" }
+func (p *stoppableProvider) Start(ctx context.Context) error      { p.running = true; return nil }
+func (p *stoppableProvider) Stop(ctx context.Context) error       { p.running = false; return nil }
+func (p *stoppableProvider) IsRunning() bool                      { return p.running }
+func (p *stoppableProvider) GetScenarios() *scenario.Registry     { return p.scenarios }
+func (p *stoppableProvider) Capabilities() scenario.CapabilitySet { return nil }
+func (p *stoppableProvider) ConnectionInfo() string               { return "" }
+
+// chattyScenario emits n steps as fast as it can
+type chattyScenario struct {
+	n int
+}
+
+func (s *chattyScenario) Name() string                      { return "Chatty" }
+func (s *chattyScenario) Description() string               { return "Emits many steps" }
+func (s *chattyScenario) IsolationLevel() string            { return "None" }
+func (s *chattyScenario) Setup(ctx context.Context) error   { return nil }
+func (s *chattyScenario) Cleanup(ctx context.Context) error { return nil }
+
+func (s *chattyScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	for i := 1; i <= s.n; i++ {
+		output <- scenario.StepResult{Session: "Session A", Step: i, Description: "Step", Success: true}
+	}
+	return nil
+}
+
+// program runs a runner the way a bubbletea program does: commands on goroutines of their own,
+// their messages handled by Update one at a time on the test's goroutine, rendering after each.
+// go test -race then catches any state the run shares with the UI loop.
+type program struct {
+	r    *RunnerModel
+	msgs chan tea.Msg
+}
+
+func newProgram(r *RunnerModel) *program {
+	return &program{r: r, msgs: make(chan tea.Msg, 10)}
+}
+
+// run runs cmd on its own goroutine, queueing the message it returns
+func (p *program) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				p.run(c)
+			}
+		case nil:
+		default:
+			p.msgs <- msg
+		}
+	}()
+}
+
+// untilDone starts the run and handles messages until it completes, calling onStep after each
+// step is handled. It fails the test when the run takes longer than timeout.
+func (p *program) untilDone(t *testing.T, timeout time.Duration, onStep func(r *RunnerModel)) {
+	t.Helper()
+	p.run(p.r.Start())
+	deadline := time.After(timeout)
+	for !p.r.done {
+		select {
+		case msg := <-p.msgs:
+			var cmd tea.Cmd
+			p.r, cmd = p.r.Update(msg)
+			p.run(cmd)
+			if _, ok := msg.(runnerStepMsg); ok && onStep != nil {
+				onStep(p.r)
+			}
+			_ = p.r.View()
+		case <-deadline:
+			t.Fatalf("Expected the run to finish, got %d steps", len(p.r.record.Steps))
+		}
+	}
+}
+
+// TestRunner_ViewDuringRun renders the runner while a scenario streams steps
+func TestRunner_ViewDuringRun(t *testing.T) {
+	p := newProgram(NewRunnerModel(&chattyScenario{n: 50}, scenario.DefaultParams()))
+	p.untilDone(t, 5*time.Second, nil)
+
+	r := p.r
+	if len(r.record.Steps) != 50 || len(r.record.Offsets) != 50 {
+		t.Fatalf("Expected 50 steps with offsets, got %d and %d", len(r.record.Steps), len(r.record.Offsets))
+	}
+}
+
+// streamingScenario emits n steps with random pauses of up to 100µs, like a database answering,
+// and fails with err once failAfter steps are out when err is set
+type streamingScenario struct {
+	chattyScenario
+	seed      int64
+	err       error
+	failAfter int
+	emitted   atomic.Int32 // Steps taken by the runner
+}
+
+func (s *streamingScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	rng := rand.New(rand.NewSource(s.seed))
+	for i := 1; i <= s.n; i++ {
+		if s.err != nil && i > s.failAfter {
+			return s.err
+		}
+		time.Sleep(time.Duration(rng.Int63n(int64(100 * time.Microsecond))))
+		select {
+		case output <- scenario.StepResult{Session: "Session A", Step: i, Description: fmt.Sprintf("Step %d", i), Success: true}:
+			s.emitted.Add(1)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// checkStepsInOrder fails the test unless the runner holds the steps the scenario emitted, once
+// each and in the order they were emitted. Unless all is set, the last ones may be missing, as
+// steps emitted once a run is cancelled may be dropped.
+func checkStepsInOrder(t *testing.T, r *RunnerModel, s *streamingScenario, all bool) {
+	t.Helper()
+	steps := r.record.Steps
+	if n := int(s.emitted.Load()); len(steps) > n || all && len(steps) != n {
+		t.Fatalf("Expected the %d steps emitted, got %d", n, len(steps))
+	}
+	for i, step := range steps {
+		if want := fmt.Sprintf("Step %d", i+1); step.Description != want || step.Step != i+1 {
+			t.Fatalf("Expected step %d to be %q, got %d %q", i+1, want, step.Step, step.Description)
+		}
+	}
+}
+
+// tick sends spinner ticks for r to p until stop is closed, as a fast timer would
+func tick(p *program, r *RunnerModel, stop <-chan struct{}) {
+	for {
+		select {
+		case p.msgs <- runnerTickMsg{runner: r}:
+		case <-stop:
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestRunner_StreamsEveryStepInOrder streams hundreds of steps while ticks and renders interleave
+// with them; run it with -race
+func TestRunner_StreamsEveryStepInOrder(t *testing.T) {
+	s := &streamingScenario{chattyScenario: chattyScenario{n: 500}, seed: time.Now().UnixNano()}
+	r := NewRunnerModel(s, scenario.DefaultParams())
+	r.SetSize(100, 30)
+	r.SetVisible(true)
+	p := newProgram(r)
+	stop := make(chan struct{})
+	defer close(stop)
+	go tick(p, r, stop)
+
+	p.untilDone(t, 30*time.Second, nil)
+	if p.r.err != nil {
+		t.Fatalf("Expected the run to succeed (seed %d), got %v", s.seed, p.r.err)
+	}
+	if n := s.emitted.Load(); n != 500 {
+		t.Fatalf("Expected 500 steps emitted, got %d", n)
+	}
+	checkStepsInOrder(t, p.r, s, true)
+}
+
+func TestRunner_AbortMidStream(t *testing.T) {
+	s := &streamingScenario{chattyScenario: chattyScenario{n: 500}, seed: time.Now().UnixNano()}
+	r := NewRunnerModel(s, scenario.DefaultParams())
+	r.SetVisible(true)
+	p := newProgram(r)
+	stop := make(chan struct{})
+	defer close(stop)
+	go tick(p, r, stop)
+
+	// Cancelled from the UI loop, as the abort key does
+	p.untilDone(t, 30*time.Second, func(r *RunnerModel) {
+		if len(r.record.Steps) == 100 {
+			r.Cancel()
+		}
+	})
+	if !errors.Is(p.r.err, context.Canceled) {
+		t.Fatalf("Expected the run to be cancelled (seed %d), got %v", s.seed, p.r.err)
+	}
+	if n := s.emitted.Load(); n < 100 || n == 500 {
+		t.Fatalf("Expected the run to stop after 100 steps and before the last, got %d", n)
+	}
+	checkStepsInOrder(t, p.r, s, false)
+	if n := len(p.r.record.Steps); n < 100 {
+		t.Fatalf("Expected the 100 steps before the abort, got %d", n)
+	}
+}
+
+func TestRunner_ScenarioErrorMidStream(t *testing.T) {
+	s := &streamingScenario{
+		chattyScenario: chattyScenario{n: 500},
+		seed:           time.Now().UnixNano(),
+		err:            errors.New("connection reset"),
+		failAfter:      250,
+	}
+	r := NewRunnerModel(s, scenario.DefaultParams())
+	r.SetVisible(true)
+	p := newProgram(r)
+	stop := make(chan struct{})
+	defer close(stop)
+	go tick(p, r, stop)
+
+	p.untilDone(t, 30*time.Second, nil)
+	if p.r.err == nil || p.r.err.Error() != "connection reset" {
+		t.Fatalf("Expected the scenario's error (seed %d), got %v", s.seed, p.r.err)
+	}
+	if n := s.emitted.Load(); n != 250 {
+		t.Fatalf("Expected 250 steps before the error, got %d", n)
+	}
+	checkStepsInOrder(t, p.r, s, true)
+}
+
+func TestRunner_RecoversScenarioPanic(t *testing.T) {
+	s := &panickingScenario{}
+	p := &stoppableProvider{scenarios: scenario.NewRegistry()}
+	p.scenarios.Register(s)
+
+	providers := provider.NewRegistry()
+	providers.Register(p)
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	r := NewRunnerModel(s, scenario.DefaultParams())
+	msg := r.runScenario(context.Background())()
+	for {
+		step, ok := msg.(runnerStepMsg)
+		if !ok {
+			break
+		}
+		var cmd tea.Cmd
+		r, cmd = r.Update(step)
+		msg = cmd()
+	}
+	complete, ok := msg.(runnerCompleteMsg)
+	if !ok {
+		t.Fatalf("Expected runnerCompleteMsg, got %T", msg)
+	}
+
+	var panicErr *scenario.PanicError
+	if !errors.As(complete.err, &panicErr) {
+		t.Fatalf("Expected a PanicError, got %v", complete.err)
+	}
+	if panicErr.Value != "boom" || !strings.Contains(panicErr.Detail(), "panickingScenario") {
+		t.Fatalf("Expected panic value and stack, got %v\n%s", panicErr.Value, panicErr.Detail())
+	}
+	if len(r.record.Steps) != 1 || !s.cleanedUp {
+		t.Fatalf("Expected steps before the panic and cleanup to run, got %d results (cleanup %t)", len(r.record.Steps), s.cleanedUp)
+	}
+
+	// The registry is still usable for teardown
+	if err := providers.StopAll(context.Background()); err != nil {
+		t.Fatalf("Expected no error stopping providers, got %v", err)
+	}
+	if p.IsRunning() {
+		t.Fatal("Expected provider to be stopped")
+	}
+}
+
+// failingScenario fails before emitting any step
+type failingScenario struct {
+	chattyScenario
+}
+
+func (s *failingScenario) Name() string { return "Failing" }
+
+func (s *failingScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	return errors.New("connection refused")
+}
+
+func TestRunner_FailsBeforeFirstStep(t *testing.T) {
+	r := NewRunnerModel(&failingScenario{}, scenario.DefaultParams())
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- r.runScenario(context.Background())() }()
+
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the run to complete")
+	}
+	complete, ok := msg.(runnerCompleteMsg)
+	if !ok {
+		t.Fatalf("Expected runnerCompleteMsg, got %T", msg)
+	}
+	if complete.err == nil || complete.err.Error() != "connection refused" {
+		t.Fatalf("Expected the run error, got %v", complete.err)
+	}
+
+	r, _ = r.Update(complete)
+	if !r.done || r.err == nil {
+		t.Fatalf("Expected a failed completion, got done %v with error %v", r.done, r.err)
+	}
+}
+
+// seedingScenario reports how far its Setup got
+type seedingScenario struct {
+	chattyScenario
+}
+
+func (s *seedingScenario) Setup(ctx context.Context) error {
+	scenario.ReportSetup(ctx, "Seeding orders: 5000/10000")
+	return nil
+}
+
+func TestRunner_ShowsSetupProgressUntilTheFirstStep(t *testing.T) {
+	r := NewRunnerModel(&seedingScenario{chattyScenario{n: 1}}, scenario.DefaultParams())
+	r.running = true
+
+	msg := r.runScenario(context.Background())()
+	setup, ok := msg.(runnerSetupMsg)
+	if !ok {
+		t.Fatalf("Expected runnerSetupMsg, got %T", msg)
+	}
+	r, cmd := r.Update(setup)
+	if view := r.View(); !strings.Contains(view, "Seeding orders: 5000/10000") {
+		t.Fatalf("Expected the setup stage in the view, got %q", view)
+	}
+
+	step, ok := cmd().(runnerStepMsg)
+	if !ok {
+		t.Fatal("Expected the first step after the setup stage")
+	}
+	r, _ = r.Update(step)
+	if view := r.View(); strings.Contains(view, "Seeding orders") {
+		t.Fatalf("Expected the setup stage to make way for the steps, got %q", view)
+	}
+}
+
+// widest returns the width of the widest line of s
+func widest(s string) int {
+	w := 0
+	for _, line := range strings.Split(s, "\n") {
+		w = max(w, lipgloss.Width(line))
+	}
+	return w
+}
+
+func TestRunner_ResizeMidRun(t *testing.T) {
+	r := NewRunnerModel(&chattyScenario{n: 1}, scenario.DefaultParams())
+	r, _ = r.Update(runnerStartMsg{})
+	r, _ = r.Update(runnerStepMsg{runner: r, result: scenario.StepResult{
+		Session:     "Session A",
+		Step:        1,
+		Description: "Reading every document",
+		Result:      strings.Repeat("document ", 40),
+		Success:     true,
+	}})
+
+	r, _ = r.Update(tea.WindowSizeMsg{Width: 200, Height: 50})
+	wide := widest(r.View())
+	if wide > 200 || wide <= 120 {
+		t.Fatalf("Expected the view to use the 200 columns, got %d", wide)
+	}
+
+	r, _ = r.Update(tea.WindowSizeMsg{Width: 60, Height: 50})
+	if narrow := widest(r.View()); narrow > 60 {
+		t.Fatalf("Expected the view to fit 60 columns, got %d", narrow)
+	}
+	if !r.running || len(r.record.Steps) != 1 {
+		t.Fatalf("Expected the resize to leave the run alone, got running %v with %d results", r.running, len(r.record.Steps))
+	}
+}
+
+// misnumberedScenario emits steps whose numbers repeat and go back
+type misnumberedScenario struct {
+	chattyScenario
+}
+
+func (s *misnumberedScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	for _, n := range []int{1, 2, 2, 1, 5, 3} {
+		output <- scenario.StepResult{Session: "Session A", Step: n, Description: "Step", Success: true}
+	}
+	return nil
+}
+
+func TestRunner_RenumbersMisorderedSteps(t *testing.T) {
+	r := NewRunnerModel(&misnumberedScenario{}, scenario.DefaultParams())
+	r.SetSize(120, 200)
+
+	msg := r.runScenario(context.Background())()
+	for !r.done {
+		var cmd tea.Cmd
+		r, cmd = r.Update(msg)
+		msg = cmd()
+	}
+
+	numbers := regexp.MustCompile(`\[(\d+)\]`).FindAllStringSubmatch(r.View(), -1)
+	if len(numbers) != 6 {
+		t.Fatalf("Expected 6 numbered steps, got %d", len(numbers))
+	}
+	last := 0
+	for _, m := range numbers {
+		n, _ := strconv.Atoi(m[1])
+		if n <= last {
+			t.Fatalf("Expected strictly increasing step numbers, got %d after %d", n, last)
+		}
+		last = n
+	}
+
+	// Exports keep what the scenario emitted
+	if got := r.record.Steps[3]; got.Step != 4 || got.SourceStep() != 1 {
+		t.Fatalf("Expected step 4 renumbered from 1, got %d from %d", got.Step, got.SourceStep())
+	}
+}
+
+func TestRunner_TimesPendingStepsUntilTheyComplete(t *testing.T) {
+	r := NewRunnerModel(&chattyScenario{}, scenario.DefaultParams())
+	r.SetSize(80, 40)
+	r, _ = r.Update(runnerStartMsg{})
+	r.Cancel()
+	lock := scenario.StepResult{Session: "Session B", Step: 2, Description: "Lock the row", Pending: true}
+	r, _ = r.Update(runnerStepMsg{runner: r, result: lock})
+	r, _ = r.Update(runnerStepMsg{runner: r, result: scenario.StepResult{Session: "Session A", Step: 3, Description: "Commit", Success: true}})
+
+	r.started = r.started.Add(-1500 * time.Millisecond)
+	r, _ = r.Update(runnerTickMsg{runner: r})
+	if view := r.View(); !strings.Contains(view, "waiting... 1.5s") {
+		t.Fatalf("Expected the pending step to show how long it waits, got\n%s", view)
+	}
+
+	lock.Pending, lock.Result, lock.Success, lock.Waited = false, "locked", true, 2*time.Second
+	r, _ = r.Update(runnerStepMsg{runner: r, result: lock})
+	view := r.View()
+	if strings.Contains(view, "waiting") || !strings.Contains(view, "waited 2s") || strings.Count(view, "[2]") != 1 {
+		t.Fatalf("Expected the outcome in place of the pending step, got\n%s", view)
+	}
+	if strings.Index(view, "locked") > strings.Index(view, "Commit") {
+		t.Fatalf("Expected the outcome where the pending step was, got\n%s", view)
+	}
+	if len(r.waiting) != 0 {
+		t.Fatalf("Expected no step left waiting, got %v", r.waiting)
+	}
+}
+
+func TestRunner_AlignsSessionsOfAnyLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions []string
+	}{
+		{"short", []string{"Tx1", "Session A"}},
+		{"ten columns", []string{"Сессия №10", "Tx1"}},
+		{"too long", []string{"Координатор транзакций Västra 1", "Tx1", "Session A"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRunnerModel(&chattyScenario{n: 1}, scenario.DefaultParams())
+			r.SetSize(120, 50)
+			r, _ = r.Update(runnerStartMsg{})
+			for i, session := range tt.sessions {
+				r, _ = r.Update(runnerStepMsg{runner: r, result: scenario.StepResult{
+					Session: session, Step: i + 1, Description: fmt.Sprintf("Description %d", i+1), Success: true,
+				}})
+			}
+
+			column := -1
+			for _, line := range strings.Split(r.View(), "\n") {
+				i := strings.Index(line, "Description ")
+				if i < 0 {
+					continue
+				}
+				if w := lipgloss.Width(line[:i]); column < 0 {
+					column = w
+				} else if w != column {
+					t.Fatalf("Expected every description at column %d, got %d in %q", column, w, line)
+				}
+			}
+			if column > len("[9] ")+maxSessionWidth+2 {
+				t.Fatalf("Expected the session column capped at %d, got descriptions at column %d", maxSessionWidth, column)
+			}
+		})
+	}
+}
+
+func TestRunner_CachedStepsMatchAFreshRender(t *testing.T) {
+	steps := []scenario.StepResult{
+		{IsHeader: true, Description: "Part 1"},
+		{Session: "Tx1", Step: 1, Description: "Reading", Result: "1 row", Success: true},
+		{Session: "Session A", Step: 2, Description: "A description long enough to wrap at forty columns", Success: true},
+	}
+	fresh := func(n, width int) string {
+		r := NewRunnerModel(&chattyScenario{}, scenario.DefaultParams())
+		r.SetSize(width, 40)
+		r.record.Steps = steps[:n]
+		return r.View()
+	}
+
+	r := NewRunnerModel(&chattyScenario{}, scenario.DefaultParams())
+	r.SetSize(80, 40)
+	for n := 1; n <= len(steps); n++ {
+		// The last step widens the session column of the steps already rendered
+		r.record.Steps = steps[:n]
+		if got, want := r.View(), fresh(n, 80); got != want {
+			t.Fatalf("Expected the cached view after %d steps to match a fresh render, got\n%s\nwant\n%s", n, got, want)
+		}
+	}
+	r.SetSize(40, 40)
+	if got, want := r.View(), fresh(len(steps), 40); got != want {
+		t.Fatalf("Expected the steps rendered again after a resize, got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRunner_RendersOnlyTheStepsInView(t *testing.T) {
+	r := NewRunnerModel(&chattyScenario{}, scenario.DefaultParams())
+	r.SetSize(80, 30)
+	r, _ = r.Update(runnerStartMsg{})
+	r.Cancel()
+	for i := range 5000 {
+		r, _ = r.Update(runnerStepMsg{runner: r, result: scenario.StepResult{
+			Session: "Session A", Step: i + 1, Description: fmt.Sprintf("Step %d", i+1), Result: "ok", Success: i != 1233,
+		}})
+	}
+
+	view := r.View()
+	if lines := strings.Count(view, "\n") + 1; lines != 30 {
+		t.Fatalf("Expected the view to fill the 30 lines of the terminal, got %d", lines)
+	}
+	if !strings.Contains(view, "Step 5000") || strings.Contains(view, "Step 4000") {
+		t.Fatalf("Expected the latest steps in view, got %q", view)
+	}
+	if n := len(r.steps.rendered); n > maxRenderedSteps+30 {
+		t.Fatalf("Expected only the steps around the view rendered, got %d", n)
+	}
+
+	r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if view := r.View(); !strings.Contains(view, "Step 1234") {
+		t.Fatalf("Expected f to jump to the failed step, got %q", view)
+	}
+	r.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if view := r.View(); strings.Contains(view, "Step 1234 ") || !strings.Contains(view, "Step 124") {
+		t.Fatalf("Expected pgdown to scroll past the failed step, got %q", view)
+	}
+
+	// A new step doesn't pull a reader who scrolled up back to the end
+	r, _ = r.Update(runnerStepMsg{runner: r, result: scenario.StepResult{Session: "Session A", Step: 5001, Description: "Step 5001", Success: true}})
+	if view := r.View(); strings.Contains(view, "Step 5001") {
+		t.Fatal("Expected the view to stay where the reader scrolled")
+	}
+	run, err := r.suite().Runs[0].Whole()
+	if err != nil || len(run.Steps) != 5001 {
+		t.Fatalf("Expected exports to get every step, got %d (%v)", len(run.Steps), err)
+	}
+}
+
+func TestRunner_SpillsTheOldestStepsBeyondTheLimit(t *testing.T) {
+	defer report.SetStepLimit(report.StepLimit())
+	report.SetStepLimit(100)
+
+	r := NewRunnerModel(&chattyScenario{}, scenario.DefaultParams())
+	r.SetSize(80, 30)
+	r, _ = r.Update(runnerStartMsg{})
+	r.Cancel()
+	defer r.record.Close()
+	for i := range 250 {
+		r, _ = r.Update(runnerStepMsg{runner: r, result: scenario.StepResult{
+			Session: "Session A", Step: i + 1, Description: fmt.Sprintf("Step %d", i+1), Success: true,
+		}})
+		r.View()
+	}
+
+	if n := len(r.record.Steps); n != 100 {
+		t.Fatalf("Expected 100 steps in memory, got %d", n)
+	}
+	if view := r.View(); !strings.Contains(view, "Showing the last 100 of 250 steps") || !strings.Contains(view, "Step 250") {
+		t.Fatalf("Expected the view to say it shows the latest steps only, got %q", view)
+	}
+
+	var b strings.Builder
+	if err := report.WriteMarkdown(&b, r.suite()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(b.String(), "**Session A** — Step 1\n") || !strings.Contains(b.String(), "— Step 250\n") {
+		t.Fatalf("Expected the export to read the spilled steps back, got %q", b.String())
+	}
+}
+
+// BenchmarkRunnerView renders one spinner frame of a running scenario, the most frequent redraw.
+// Only the steps on screen are rendered, so the cost follows the terminal size rather than the
+// step count. Baseline, go test -bench RunnerView -benchmem ./internal/ui on a Xeon VM:
+//
+//	steps   narrow/ascii      narrow/emoji      wide/ascii         wide/emoji
+//	10      29µs  8KB  84     29µs  8KB  84     42µs  52KB  73     46µs  52KB  73
+//	100     18µs  8KB  84     24µs  8KB  84     38µs  55KB  88     41µs  55KB  88
+//	1000    21µs  8KB  85     18µs  8KB  85     49µs  55KB  92     49µs  55KB  92
+//	10000   23µs  9KB 104     21µs  9KB 103     86µs  62KB 176     84µs  60KB 148
+//
+// (time, bytes and allocations per frame)
+func BenchmarkRunnerView(b *testing.B) {
+	sizes := []struct {
+		name          string
+		width, height int
+	}{{"narrow", 80, 24}, {"wide", 200, 60}}
+	contents := []struct {
+		name                string
+		description, result string
+	}{
+		{"ascii", "Reading the balance", "Balance: $100.00\nVersion: 2"},
+		{"emoji", "Reading the balance ✅", "💰 Balance: $100.00\n🔖 Version: 2"},
+	}
+	for _, steps := range []int{10, 100, 1000, 10000} {
+		for _, size := range sizes {
+			for _, content := range contents {
+				b.Run(fmt.Sprintf("%d/%s/%s", steps, size.name, content.name), func(b *testing.B) {
+					r := NewRunnerModel(&chattyScenario{}, scenario.DefaultParams())
+					r.SetSize(size.width, size.height)
+					r.running = true
+					for i := range steps {
+						r.record.Steps = append(r.record.Steps, scenario.StepResult{
+							Session:     []string{"Session A", "Session B"}[i%2],
+							Step:        i + 1,
+							Description: content.description,
+							Query:       "db.accounts.findOne({_id: 1})",
+							Result:      content.result,
+							Success:     true,
+						})
+					}
+
+					b.ResetTimer()
+					for range b.N {
+						r.frame++ // One spinner tick
+						r.View()
+					}
+				})
+			}
+		}
+	}
+}
+
+// TestRunner_CoalescesRedraws replays a scenario sleeping between two steps: ten ticks and a
+// burst of frames with nothing new. Every frame used to be rendered; now only the ones that show
+// something new are.
+func TestRunner_CoalescesRedraws(t *testing.T) {
+	r := NewRunnerModel(&chattyScenario{}, scenario.DefaultParams())
+	r.SetSize(80, 30)
+	r.running = true
+	step := func(i int) {
+		r, _ = r.Update(runnerStepMsg{runner: r, result: scenario.StepResult{Session: "A", Step: i, Description: "Step", Success: true}})
+	}
+
+	step(1)
+	for range 10 {
+		_ = r.View()
+	}
+	if r.renders != 1 {
+		t.Fatalf("Expected frames without changes to reuse the render, got %d renders", r.renders)
+	}
+
+	for range 10 {
+		r, _ = r.Update(runnerTickMsg{runner: r})
+		_ = r.View()
+	}
+	if r.renders != 11 {
+		t.Fatalf("Expected a render per spinner frame, got %d renders", r.renders)
+	}
+
+	r.SetVisible(false)
+	for range 10 {
+		if _, cmd := r.Update(runnerTickMsg{runner: r}); cmd != nil {
+			t.Fatal("Expected no ticks while the runner is off screen")
+		}
+	}
+	if cmd := r.SetVisible(true); cmd == nil {
+		t.Fatal("Expected the ticks to resume once the runner is back on screen")
+	}
+	_ = r.View()
+	step(2)
+	_ = r.View()
+	if r.renders != 12 {
+		t.Fatalf("Expected a render for the new step only, got %d renders", r.renders)
+	}
+
+	if r.SetFocused(false); r.tickInterval() != blurredTickInterval {
+		t.Fatalf("Expected the spinner to slow down without focus, got %v", r.tickInterval())
+	}
+}
+
+// FuzzRenderStep feeds arbitrary step text to the runner, which must lay it out within the terminal
+// whatever it holds
+func FuzzRenderStep(f *testing.F) {
+	for _, text := range scenariotest.Texts {
+		f.Add("Session A", text, text, text, uint8(80))
+	}
+	f.Fuzz(func(t *testing.T, session, description, query, result string, width uint8) {
+		// textWidth leaves text 20 columns after the 16 of the step prefix, however narrow the terminal
+		w := max(int(width), 36)
+		step := scenario.StepResult{Session: session, Step: 1, Description: description, Query: query, Result: result}
+		out := renderStep(step, 10, w)
+
+		for _, line := range strings.Split(out, "\n") {
+			if lw := lipgloss.Width(line); lw > w {
+				t.Fatalf("Expected lines at most %d wide, got %d: %q", w, lw, line)
+			}
+		}
+		in := len(session) + len(description) + len(query) + len(result)
+		if lines := strings.Count(out, "\n"); lines > in+8 {
+			t.Fatalf("Expected at most a line per byte of text, got %d lines for %d bytes", lines, in)
+		}
+	})
+}
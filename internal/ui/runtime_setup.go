@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/containerruntime"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RuntimeSetupModel explains how to make a container runtime reachable and accepts a socket path
+type RuntimeSetupModel struct {
+	detector *containerruntime.Detector
+	input    textinput.Model
+	err      error
+}
+
+// NewRuntimeSetupModel creates a new runtime setup model
+func NewRuntimeSetupModel(detector *containerruntime.Detector) *RuntimeSetupModel {
+	input := textinput.New()
+	input.Placeholder = "/path/to/docker.sock"
+	input.Prompt = i18n.T("Socket: ")
+	input.Width = 60
+	input.Focus()
+
+	return &RuntimeSetupModel{
+		detector: detector,
+		input:    input,
+	}
+}
+
+// Init starts the cursor blinking
+func (m *RuntimeSetupModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// RuntimeSelectedMsg is sent once the user entered a valid socket path
+type RuntimeSelectedMsg struct {
+	Runtime containerruntime.Runtime
+}
+
+// Focused returns whether the socket path is being typed into, which is always the case
+func (m *RuntimeSetupModel) Focused() bool {
+	return m.input.Focused()
+}
+
+// Update handles runtime setup input
+func (m *RuntimeSetupModel) Update(msg tea.Msg) (*RuntimeSetupModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Select):
+			r, err := m.detector.Custom(m.input.Value())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.err = nil
+			return m, func() tea.Msg { return RuntimeSelectedMsg{Runtime: r} }
+		case key.Matches(msg, keys.Back):
+			return m, func() tea.Msg { return GoBackMsg{} }
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// View renders the runtime setup screen
+func (m *RuntimeSetupModel) View() string {
+	var b strings.Builder
+
+	// Header
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7C3AED")).
+		MarginBottom(1).
+		Render(i18n.T("🐳 No Container Runtime Found"))
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Render(i18n.T("Database providers run in containers via testcontainers, which needs a Docker-compatible socket"))
+
+	b.WriteString("\n")
+	b.WriteString(title)
+	b.WriteString("\n")
+	b.WriteString(subtitle)
+	b.WriteString("\n\n")
+
+	// Guidance
+	guidance := []string{
+		i18n.T("• Docker Desktop / Docker Engine: make sure the daemon is running"),
+		i18n.T("• Podman: run `podman machine start` (macOS) or `systemctl --user start podman.socket`"),
+		i18n.T("• Colima: run `colima start`"),
+		i18n.T("• Or set DOCKER_HOST before launching txviewer"),
+	}
+	for _, line := range guidance {
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	// Where we looked
+	pathStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	b.WriteString(pathStyle.Render(i18n.T("  Looked for sockets at:")))
+	b.WriteString("\n")
+	for _, p := range m.detector.ProbedPaths() {
+		b.WriteString(pathStyle.Render("    " + p))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Prompt
+	b.WriteString("  " + i18n.T("Enter the socket path to use for this session:") + "\n\n")
+	b.WriteString("  " + m.input.View())
+	b.WriteString("\n")
+
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("  %v", m.err)))
+		b.WriteString("\n")
+	}
+
+	// Help
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render(i18n.T("enter use socket • esc back")))
+
+	return b.String()
+}
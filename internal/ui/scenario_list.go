@@ -2,49 +2,156 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/history"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/logging"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
 	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // ScenarioListModel represents the scenario selection view
 type ScenarioListModel struct {
-	provider  provider.Provider
-	scenarios []scenario.Scenario
-	cursor    int
+	provider   provider.Provider
+	all        []scenario.Scenario
+	allMissing [][]scenario.Capability // Missing capabilities per scenario in all
+	scenarios  []scenario.Scenario     // The scenarios matching the filter
+	missing    [][]scenario.Capability // Missing capabilities per scenario
+	filter     textinput.Model         // Narrows the list by name; focused while typing
+	startup    provider.StartupMetrics
+	quizzes    map[string][]history.QuizScore // Quiz results per scenario name, oldest first
+	copied     string                         // Shell command last copied to the clipboard
+	revealed   bool                           // Show the connection's credentials instead of redacting them
+	launched   scenario.Scenario              // Scenario whose run hasn't finished; nil when idle
+	cursor     int
+	width      int // Terminal size; 0 until the first WindowSizeMsg
+	height     int
 }
 
 // NewScenarioListModel creates a new scenario list model
 func NewScenarioListModel(p provider.Provider) *ScenarioListModel {
-	return &ScenarioListModel{
-		provider:  p,
-		scenarios: p.GetScenarios().GetAll(),
-		cursor:    0,
+	scenarios := p.GetScenarios().GetAll()
+	caps := p.Capabilities()
+
+	missing := make([][]scenario.Capability, len(scenarios))
+	for i, s := range scenarios {
+		missing[i] = caps.Missing(scenario.Requirements(s))
+	}
+
+	filter := textinput.New()
+	filter.Prompt = i18n.T("Filter: ")
+
+	m := &ScenarioListModel{
+		provider:   p,
+		all:        scenarios,
+		allMissing: missing,
+		filter:     filter,
+		quizzes:    make(map[string][]history.QuizScore),
+		cursor:     0,
+	}
+	m.applyFilter()
+	return m
+}
+
+// Focused returns whether the filter is being typed into
+func (m *ScenarioListModel) Focused() bool {
+	return m.filter.Focused()
+}
+
+// applyFilter lists the scenarios whose name contains the filter, ignoring case
+func (m *ScenarioListModel) applyFilter() {
+	query := strings.ToLower(strings.TrimSpace(m.filter.Value()))
+	m.scenarios, m.missing = nil, nil
+	for i, s := range m.all {
+		if strings.Contains(strings.ToLower(s.Name()), query) {
+			m.scenarios = append(m.scenarios, s)
+			m.missing = append(m.missing, m.allMissing[i])
+		}
 	}
+	m.cursor = min(m.cursor, max(len(m.scenarios)-1, 0))
+}
+
+// SetStartup records how long the provider took to start, for the header
+func (m *ScenarioListModel) SetStartup(metrics provider.StartupMetrics) {
+	m.startup = metrics
+}
+
+// SetSize records the terminal size, so descriptions wrap to its width
+func (m *ScenarioListModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetQuizScores records the quiz results of a scenario, shown under its description
+func (m *ScenarioListModel) SetQuizScores(name string, scores []history.QuizScore) {
+	m.quizzes[name] = scores
 }
 
 // Update handles scenario list input
 func (m *ScenarioListModel) Update(msg tea.Msg) (*ScenarioListModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		if m.filter.Focused() {
+			return m, m.updateFilter(msg)
+		}
+		switch {
+		case key.Matches(msg, keys.Filter):
+			m.filter.Focus()
+			return m, textinput.Blink
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			if m.cursor < len(m.scenarios)-1 {
 				m.cursor++
 			}
+		case key.Matches(msg, keys.Reveal):
+			m.revealed = !m.revealed
+		case key.Matches(msg, keys.CopyShell):
+			if sh, ok := m.provider.(provider.Shell); ok && sh.ShellCommand() != "" {
+				return m, copyToClipboard(sh.ShellCommand())
+			}
 		}
+	case clipboardMsg:
+		m.copied = msg.text
+	default:
+		// Keeps the filter's cursor blinking
+		var cmd tea.Cmd
+		m.filter, cmd = m.filter.Update(msg)
+		return m, cmd
 	}
 	return m, nil
 }
 
+// updateFilter types into the filter; enter keeps the filter and esc clears it, both leaving the field
+func (m *ScenarioListModel) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keys.Select):
+		m.filter.Blur()
+		return nil
+	case key.Matches(msg, keys.Back):
+		m.filter.Blur()
+		m.filter.SetValue("")
+		m.applyFilter()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.applyFilter()
+	return cmd
+}
+
 // Selected returns the currently selected scenario
 func (m *ScenarioListModel) Selected() scenario.Scenario {
 	if m.cursor >= 0 && m.cursor < len(m.scenarios) {
@@ -53,6 +160,32 @@ func (m *ScenarioListModel) Selected() scenario.Scenario {
 	return nil
 }
 
+// Launch returns a command selecting the scenario under the cursor to run. Until Finished is
+// called, further launches are ignored, so pressing enter twice can't start two runs.
+func (m *ScenarioListModel) Launch() tea.Cmd {
+	s := m.Selected()
+	if m.launched != nil || s == nil || !m.SelectedSupported() {
+		return nil
+	}
+	m.launched = s
+	return func() tea.Msg {
+		return ScenarioSelectedMsg{Scenario: s}
+	}
+}
+
+// Finished allows launching again once the launched run completed
+func (m *ScenarioListModel) Finished() {
+	m.launched = nil
+}
+
+// SelectedSupported returns whether the provider has every capability the selected scenario requires
+func (m *ScenarioListModel) SelectedSupported() bool {
+	if m.cursor >= 0 && m.cursor < len(m.missing) {
+		return len(m.missing[m.cursor]) == 0
+	}
+	return false
+}
+
 // View renders the scenario list
 func (m *ScenarioListModel) View() string {
 	var b strings.Builder
@@ -64,7 +197,7 @@ func (m *ScenarioListModel) View() string {
 		Bold(true).
 		Foreground(lipgloss.Color("#7C3AED")).
 		MarginBottom(1).
-		Render("📚 Select Demonstration Scenario")
+		Render(i18n.T("📚 Select Demonstration Scenario"))
 
 	b.WriteString("\n")
 	b.WriteString(title)
@@ -72,43 +205,115 @@ func (m *ScenarioListModel) View() string {
 	b.WriteString(providerBadge)
 	b.WriteString("\n\n")
 
-	// Connection info
+	// Connection info, with credentials hidden unless revealed
+	raw := m.provider.ConnectionInfo()
+	info := logging.Redact(raw)
+	hasCredentials := info != raw
+	if m.revealed {
+		info = raw
+	}
 	connInfo := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#6B7280")).
 		Italic(true).
-		Render(fmt.Sprintf("Connected: %s", m.provider.ConnectionInfo()))
+		Render(i18n.T("Connected: %s", info))
 	b.WriteString(connInfo)
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if hasCredentials {
+		hint := i18n.T("🔒 Credentials hidden • r reveal")
+		if m.revealed {
+			hint = i18n.T("🔓 Credentials shown • r hide")
+		}
+		b.WriteString(HelpStyle.Render(hint))
+		b.WriteString("\n")
+	}
 
-	if len(m.scenarios) == 0 {
-		b.WriteString(WarningStyle.Render("  No scenarios available"))
+	shell, hasShell := m.provider.(provider.Shell)
+	if hasShell && shell.ContainerID() != "" {
+		b.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")).
+			Render(i18n.T("🐳 Container %s", provider.ShortID(shell.ContainerID()))))
+		b.WriteString("\n")
+	}
+	if m.copied != "" {
+		b.WriteString(SuccessStyle.Render(i18n.T("📋 Copied: %s", m.copied)))
+		b.WriteString("\n")
+	}
+
+	if m.startup.Total > 0 {
+		b.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")).
+			Render(fmt.Sprintf("⏱  %s", m.startup.Summary())))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.filter.Focused() || m.filter.Value() != "" {
+		b.WriteString("  " + m.filter.View())
+		b.WriteString("\n\n")
+	}
+
+	if len(m.all) == 0 {
+		b.WriteString(WarningStyle.Render(i18n.T("  No scenarios available")))
 		return b.String()
 	}
+	if len(m.scenarios) == 0 {
+		b.WriteString(WarningStyle.Render(i18n.T("  No scenarios match %q", m.filter.Value())))
+		b.WriteString("\n\n")
+	}
 
 	// Scenario items
 	for i, s := range m.scenarios {
 		cursor := "  "
 		nameStyle := NormalStyle
 
+		supported := len(m.missing[i]) == 0
+
 		if i == m.cursor {
 			cursor = "▸ "
 			nameStyle = SelectedStyle
 		}
+		if !supported {
+			nameStyle = DimmedStyle
+			if i == m.cursor {
+				nameStyle = DimmedSelectedStyle
+			}
+		}
 
 		// Isolation level badge
-		levelBadge := Badge(s.IsolationLevel(), lipgloss.Color("#7C3AED"))
+		levelColor := lipgloss.Color("#7C3AED")
+		if !supported {
+			levelColor = mutedColor
+		}
+		levelBadge := Badge(s.IsolationLevel(), levelColor)
+		tags := scenario.MetadataOf(s).Tags
+		if slices.Contains(tags, scenario.TagCustom) {
+			levelBadge += " " + Badge(scenario.TagCustom, mutedColor)
+		}
+		if slices.Contains(tags, scenario.TagPlugin) {
+			levelBadge += " " + Badge(scenario.TagPlugin, pluginColor)
+		}
 
 		b.WriteString(fmt.Sprintf("%s%s  %s\n",
 			CursorStyle.Render(cursor),
 			nameStyle.Render(s.Name()),
 			levelBadge))
 
+		// Name what's missing so users know why it can't run
+		if !supported {
+			names := make([]string, len(m.missing[i]))
+			for j, c := range m.missing[i] {
+				names[j] = string(c)
+			}
+			b.WriteString(WarningStyle.MarginLeft(4).Render(i18n.T("Requires: %s", strings.Join(names, ", "))))
+			b.WriteString("\n")
+		}
+
 		// Show description for selected item
 		if i == m.cursor {
 			descStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#9CA3AF")).
 				MarginLeft(4).
-				Width(70)
+				Width(textWidth(m.width, 4, 70))
 
 			// First few lines of description
 			desc := s.Description()
@@ -118,12 +323,42 @@ func (m *ScenarioListModel) View() string {
 			}
 			b.WriteString(descStyle.Render(strings.Join(lines, "\n")))
 			b.WriteString("\n")
+
+			if scores := m.quizzes[s.Name()]; len(scores) > 0 {
+				b.WriteString(descStyle.Render(quizSummary(scores)))
+				b.WriteString("\n")
+			}
 		}
 		b.WriteString("\n")
 	}
 
+	if m.launched != nil {
+		b.WriteString(WarningStyle.Render(i18n.T("⏳ %s is still running", m.launched.Name())))
+		b.WriteString("\n")
+	}
+
 	// Help
-	b.WriteString(HelpStyle.Render("↑/↓ navigate • enter run scenario • esc/q back"))
+	help := i18n.T("↑/↓ navigate • enter run scenario • / filter • esc/q back")
+	if hasShell {
+		help = i18n.T("↑/↓ navigate • enter run scenario • / filter • y copy shell command • esc/q back")
+	}
+	if m.filter.Focused() {
+		help = i18n.T("enter keep filter • esc clear filter")
+	}
+	b.WriteString(HelpStyle.Render(help))
 
 	return b.String()
 }
+
+// quizSummary describes the latest and best of a scenario's quiz scores
+func quizSummary(scores []history.QuizScore) string {
+	last := scores[len(scores)-1]
+	best := last
+	for _, s := range scores {
+		if s.Correct*best.Total > best.Correct*s.Total {
+			best = s
+		}
+	}
+	return i18n.T("🧠 Quiz: last %d/%d • best %d/%d • %d attempts",
+		last.Correct, last.Total, best.Correct, best.Total, len(scores))
+}
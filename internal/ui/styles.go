@@ -1,7 +1,14 @@
 package ui
 
 import (
+	"strings"
+	"unicode"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // Colors
@@ -13,6 +20,7 @@ var (
 	mutedColor     = lipgloss.Color("#6B7280") // Gray
 	bgColor        = lipgloss.Color("#1F2937") // Dark gray
 	textColor      = lipgloss.Color("#F9FAFB") // Light
+	pluginColor    = lipgloss.Color("#0EA5E9") // Sky
 )
 
 // Session colors for differentiating concurrent operations
@@ -54,6 +62,17 @@ var (
 			Foreground(textColor).
 			Padding(0, 1)
 
+	// Item that can't be selected in the current context
+	DimmedStyle = lipgloss.NewStyle().
+			Foreground(mutedColor).
+			Padding(0, 1)
+
+	// Cursor on an item that can't be selected
+	DimmedSelectedStyle = lipgloss.NewStyle().
+				Foreground(mutedColor).
+				Background(bgColor).
+				Padding(0, 1)
+
 	// Cursor indicator
 	CursorStyle = lipgloss.NewStyle().
 			Foreground(secondaryColor).
@@ -121,6 +140,46 @@ func SessionStyle(session string) lipgloss.Style {
 		Bold(true)
 }
 
+// maxSessionWidth caps the session column; longer session names are cut short with an ellipsis
+const maxSessionWidth = 16
+
+// sessionWidth returns the width of the session column for steps: the widest translated session
+// name among them, at most maxSessionWidth
+func sessionWidth(steps []scenario.StepResult) int {
+	width := 0
+	for _, step := range steps {
+		if !step.IsHeader {
+			width = max(width, i18n.Width(i18n.T(step.Session)))
+		}
+	}
+	return min(width, maxSessionWidth)
+}
+
+// sessionLabel returns the translated session name padded or cut to width columns, so the rows of
+// a run stay aligned
+func sessionLabel(session string, width int) string {
+	return i18n.Pad(i18n.Truncate(i18n.T(session), width), width)
+}
+
+// plain makes text a scenario got from the database or a plugin safe to lay out: escape sequences
+// and control characters, which could restyle, move over or clear the screen, are dropped, and
+// invalid UTF-8, which lipgloss measures wrong, is replaced
+func plain(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, ansi.Strip(strings.ToValidUTF8(s, "\uFFFD")))
+}
+
+// fit renders text with a style that sets its width. The text is converted for the terminal
+// first, so lipgloss wraps and pads what is actually shown rather than symbols the ASCII mode
+// replaces with wider text after layout.
+func fit(style lipgloss.Style, text string) string {
+	return style.Render(i18n.Render(text))
+}
+
 // Badge creates a badge-style element
 func Badge(text string, color lipgloss.Color) string {
 	return lipgloss.NewStyle().
@@ -133,3 +192,17 @@ func Badge(text string, color lipgloss.Color) string {
 
 // Spinner frames for loading animation
 var SpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// textWidth returns the width text indented by indent columns may take in a terminal width
+// columns wide, at most limit unless it is 0. While the size is unknown it returns limit, so
+// a limit of 0 leaves the text unwrapped.
+func textWidth(width, indent, limit int) int {
+	if width <= 0 {
+		return limit
+	}
+	w := width - indent
+	if limit > 0 {
+		w = min(w, limit)
+	}
+	return max(w, 20)
+}
@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/report"
+)
+
+func TestSessionColorsMatchReports(t *testing.T) {
+	palette := map[string]string{
+		"Session A": string(sessionAColor),
+		"Session B": string(sessionBColor),
+		"Setup":     string(setupColor),
+		"Result":    string(resultColor),
+	}
+	for session, color := range palette {
+		if report.SessionColors[session] != color {
+			t.Fatalf("Expected exported %s color %s, got %s", session, color, report.SessionColors[session])
+		}
+	}
+}
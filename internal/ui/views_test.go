@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/i18n"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/provider/fake"
+	"github.com/ravilushqa/go-transaction-isolation-viewer/internal/scenario"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// goldenSizes are the terminal sizes every view is rendered at
+var goldenSizes = []struct{ width, height int }{{80, 24}, {120, 40}}
+
+// view is a screen of the app
+type view interface {
+	View() string
+}
+
+// sizer is implemented by the views that lay themselves out for the terminal
+type sizer interface {
+	SetSize(width, height int)
+}
+
+// screen returns what a terminal of the given size shows of view, as bubbletea draws it: lines
+// cut at the width and, when there are too many, only the last ones. Styling is stripped and
+// trailing blanks trimmed so golden files read as plain text.
+func screen(view string, width, height int) string {
+	lines := strings.Split(i18n.Render(view), "\n")
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(ansi.Strip(ansi.Truncate(line, width, "")), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkGolden compares got with testdata/name.golden, rewriting the file instead with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	golden := filepath.Join("testdata", name+".golden")
+	if update() {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatalf("Failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(expected) {
+		t.Fatalf("Output differs from %s; run go test ./internal/ui -update and review the diff", golden)
+	}
+}
+
+// cannedScenario is a scenario whose steps are fed to the runner by the test
+type cannedScenario struct{}
+
+func (s *cannedScenario) Name() string                      { return "Canned" }
+func (s *cannedScenario) Description() string               { return "Steps given by the test" }
+func (s *cannedScenario) IsolationLevel() string            { return "Snapshot" }
+func (s *cannedScenario) Setup(ctx context.Context) error   { return nil }
+func (s *cannedScenario) Cleanup(ctx context.Context) error { return nil }
+
+func (s *cannedScenario) Run(ctx context.Context, output chan<- scenario.StepResult) error {
+	return nil
+}
+
+// cannedSteps cover what the runner styles differently: headers, failed steps and results
+// spanning several lines, some longer than the narrower terminal
+var cannedSteps = []scenario.StepResult{
+	{IsHeader: true, Description: "🔒 Write conflict between two sessions"},
+	{Session: "Setup", Step: 1, Description: "Seeding the account", Query: `db.accounts.insertOne({_id: 1, balance: 100})`, Result: "Inserted 1 document", Success: true},
+	{Session: "Session A", Step: 2, Description: "Starting a transaction", Role: scenario.RoleBegin, Query: "session.startTransaction()", Result: "Transaction started", Success: true},
+	{Session: "Session B", Step: 3, Description: "Starting a transaction", Role: scenario.RoleBegin, Query: "session.startTransaction()", Result: "Transaction started", Success: true},
+	{Session: "Session A", Step: 4, Description: "Updating the balance within the transaction", Role: scenario.RoleWrite, Query: `db.accounts.updateOne({_id: 1}, {$inc: {balance: 50}})`, Result: "Modified 1 document", Success: true},
+	{Session: "Session B", Step: 5, Description: "Updating the same document concurrently", Role: scenario.RoleConcurrentWrite, Query: `db.accounts.updateOne({_id: 1}, {$inc: {balance: -30}})`, Result: "WriteConflict error: this operation conflicted with another operation. Please retry your operation or multi-document transaction.\nLabels: TransientTransactionError\nCode: 112", Success: false},
+	{IsHeader: true, Description: "📊 Result"},
+	{Session: "Verify", Step: 6, Description: "Reading the committed balance", Role: scenario.RoleVerify, Query: "db.accounts.findOne({_id: 1})", Result: "{\n  _id: 1,\n  balance: 150\n}", Success: true},
+}
+
+func TestViews_MatchGoldenFiles(t *testing.T) {
+	// Pinned whatever terminal runs the tests, so styles pad and wrap the same everywhere
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.TrueColor)
+
+	p := fake.NewProvider()
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatalf("Expected the fake provider to start, got %v", err)
+	}
+	defer p.Stop(context.Background())
+	providers := provider.NewRegistry()
+	providers.Register(p)
+
+	views := []struct {
+		name string
+		view func() view
+	}{
+		{"menu", func() view { return NewMenuModel() }},
+		{"provider_list", func() view { return NewProviderListModel(providers) }},
+		{"scenario_list", func() view { return NewScenarioListModel(p) }},
+		{"loading", func() view {
+			l := NewLoadingModel("Starting Fake...")
+			l.AddMessage("Creating the in-memory store...")
+			l.AddMessage("Waiting for the store to take writes, which takes a while on a slow machine...")
+			return l
+		}},
+		{"help", func() view { return NewHelpModel() }},
+		{"runner", func() view {
+			r := NewRunnerModel(&cannedScenario{}, scenario.DefaultParams())
+			for _, step := range cannedSteps {
+				r.Update(runnerStepMsg{runner: r, result: step})
+			}
+			r.Update(runnerCompleteMsg{runner: r, err: errors.New("session B: write conflict")})
+			return r
+		}},
+	}
+	for _, v := range views {
+		for _, size := range goldenSizes {
+			name := fmt.Sprintf("%s.%dx%d", v.name, size.width, size.height)
+			t.Run(name, func(t *testing.T) {
+				m := v.view()
+				if s, ok := m.(sizer); ok {
+					s.SetSize(size.width, size.height)
+				}
+				checkGolden(t, filepath.Join("views", name), screen(m.View(), size.width, size.height))
+			})
+		}
+	}
+}